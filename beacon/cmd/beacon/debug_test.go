@@ -358,7 +358,7 @@ probes:
     port: 53
     timeout_seconds: 3
     interval: 120
-    count: 5
+    count: 10
 `
 	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
 		t.Fatalf("Failed to create test config: %v", err)
@@ -0,0 +1,71 @@
+package beacon
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"beacon/internal/config"
+	"beacon/internal/logger"
+	"beacon/internal/responder"
+)
+
+var (
+	responderCmd = &cobra.Command{
+		Use:   "responder",
+		Short: "Run the UDP echo responder",
+		Long: `Run a UDP echo responder for udp_ping probes from other beacons.
+
+Probes with responder: true send sequence-numbered, timestamped packets which
+the responder returns with its own receive and send times, so the prober can
+measure exact loss, RTT without responder processing time, reordering,
+duplicates and (with NTP/PTP-synchronized clocks) one-way delay.
+
+The listen address defaults to responder.listen from the config file, or
+:7331 when no config file is available. A running beacon can answer probes
+itself by setting responder.enabled instead.`,
+		RunE: runResponder,
+	}
+)
+
+func runResponder(cmd *cobra.Command, args []string) error {
+	listen, _ := cmd.Flags().GetString("listen")
+
+	// The responder does not need a full beacon config; use its logging
+	// and listen settings when one is present
+	cfg, err := config.LoadConfig(configFile)
+	if err == nil {
+		if err := logger.InitLogger(cfg); err != nil {
+			return fmt.Errorf("error initializing logger: %w", err)
+		}
+		if !cmd.Flags().Changed("listen") {
+			listen = cfg.Responder.Listen
+		}
+	} else {
+		logger.Logger = logrus.New()
+		logger.Logger.SetOutput(cmd.OutOrStdout())
+		logger.WithField("config", configFile).Debug("No usable config file, logging to console")
+	}
+
+	server := responder.NewServer(listen)
+	if err := server.Start(); err != nil {
+		return fmt.Errorf("error starting responder: %w", err)
+	}
+	defer server.Stop()
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Responder listening on %s, press Ctrl+C to stop...\n", server.Addr())
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	<-sigChan
+
+	return nil
+}
+
+func init() {
+	responderCmd.Flags().String("listen", responder.DefaultListenAddress, "UDP address to listen on (host:port)")
+}
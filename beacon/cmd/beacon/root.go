@@ -33,6 +33,7 @@ func init() {
 	rootCmd.AddCommand(stopCmd)
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(debugCmd)
+	rootCmd.AddCommand(responderCmd)
 }
 
 // Execute runs the root command
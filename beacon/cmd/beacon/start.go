@@ -3,6 +3,7 @@ package beacon
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -10,13 +11,18 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"beacon/internal/api"
 	"beacon/internal/config"
 	"beacon/internal/logger"
 	"beacon/internal/metrics"
 	"beacon/internal/monitor"
-	"beacon/internal/process"
+	"beacon/internal/outbox"
 	"beacon/internal/probe"
+	"beacon/internal/probesync"
+	"beacon/internal/process"
+	"beacon/internal/registration"
 	"beacon/internal/reporter"
+	"beacon/internal/responder"
 )
 
 var startCmd = &cobra.Command{
@@ -51,6 +57,34 @@ func runStart(cmd *cobra.Command, args []string) error {
 		"config":    cfg.ConfigPath,
 	}).Info("Configuration loaded successfully")
 
+	// TLS settings shared by all Pulse clients (custom CA, mTLS)
+	tlsConfig, err := cfg.TLS.ClientTLSConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load TLS config: %w", err)
+	}
+	pulseHTTPClient := &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}
+
+	// Self-register with Pulse and persist the assigned node ID and API token.
+	// fileCfg keeps the config file values that hot reloads are compared against.
+	fileCfg := cfg
+	registrar := api.NewPulseClient(cfg.PulseServer, "", pulseHTTPClient)
+	registrar.SetEnrollmentToken(cfg.EnrollmentToken)
+	regCtx, regCancel := context.WithTimeout(context.Background(), 60*time.Second)
+	cfg, err = resolveNodeIdentity(regCtx, fileCfg, registrar)
+	regCancel()
+	if err != nil {
+		return err
+	}
+	if fileCfg.SelfRegisters() {
+		fmt.Fprintf(cmd.OutOrStdout(), "Registered Node ID: %s\n", cfg.NodeID)
+	}
+	if cfg.APIToken == "" {
+		logger.Warn("No API token configured, Pulse will reject heartbeats (set api_token, or omit node_id to self-register)")
+	}
+
 	// Create process manager
 	procMgr := process.NewManager(cfg)
 
@@ -78,29 +112,52 @@ func runStart(cmd *cobra.Command, args []string) error {
 	}
 	defer scheduler.Stop()
 
+	// Sync probes managed centrally in Pulse, merged with local probes
+	var probeSyncer *probesync.Syncer
+	syncClient := api.NewPulseClient(cfg.PulseServer, cfg.APIToken, pulseHTTPClient)
+	if cfg.ProbeSync.Enabled {
+		probeSyncer = probesync.NewSyncer(syncClient, scheduler, cfg.NodeID,
+			time.Duration(cfg.ProbeSync.IntervalSeconds)*time.Second, cfg.Probes)
+		go probeSyncer.Run(ctx)
+	}
+
 	// Create config watcher for hot reload (Story 3.13)
-	configWatcher, err := config.NewFileWatcher(cfg.ConfigPath, cfg, logger.GetLogger())
+	configWatcher, err := config.NewFileWatcher(cfg.ConfigPath, fileCfg, logger.GetLogger())
 	if err != nil {
 		logger.WithError(err).Warn("Failed to create config watcher, hot reload disabled")
 	} else {
-		// Register callback to reload probe config
+		// Register callback to reload probe config. It runs first: it is the
+		// only callback whose failure rejects (and rolls back) the new config,
+		// and it leaves the running probes untouched when it fails.
 		configWatcher.OnReload(func(newConfig *config.Config, changes []string) error {
 			logger.WithField("changes", changes).Info("Reloading probe configuration...")
-			if err := scheduler.ReloadConfig(newConfig.Probes); err != nil {
+			var report probe.ReloadReport
+			var err error
+			if probeSyncer != nil {
+				// Keep probes synced from Pulse when local probes change
+				report, err = probeSyncer.SetLocalProbes(newConfig.Probes)
+			} else {
+				report, err = scheduler.ReloadConfig(newConfig.Probes)
+			}
+			if err != nil {
 				return fmt.Errorf("failed to reload probe config: %w", err)
 			}
-			logger.Info("Probe configuration reloaded successfully")
+			if !report.HasChanges() {
+				logger.WithField("unchanged_count", report.Unchanged).Info("Probe configuration unchanged, probes keep running")
+				return nil
+			}
+			logger.WithFields(report.Fields()).Info("Probe configuration reloaded successfully")
 			return nil
 		})
 
-		// Start config watcher in goroutine
-		go func() {
-			if err := configWatcher.Start(ctx); err != nil {
-				logger.WithError(err).Error("Config watcher stopped with error")
+		// Log level (and debug_mode) apply without restart
+		configWatcher.OnReload(func(newConfig *config.Config, changes []string) error {
+			if err := logger.ApplyConfig(newConfig); err != nil {
+				// Not rolled back: the probes already run with the new config
+				logger.WithError(err).Warn("Failed to apply log configuration, keeping the current log level")
 			}
-		}()
-
-		logger.WithField("config_path", cfg.ConfigPath).Info("Config watcher started for hot reload")
+			return nil
+		})
 	}
 
 	logger.Info("Starting resource monitor...")
@@ -109,33 +166,7 @@ func runStart(cmd *cobra.Command, args []string) error {
 	var resourceMonitor monitor.Monitor
 	if cfg.ResourceMonitor.Enabled {
 		logAdapter := &monitor.LogrusLogger{}
-		monitorCfg := &monitor.ResourceMonitorConfig{
-			Enabled:              cfg.ResourceMonitor.Enabled,
-			CheckIntervalSeconds: cfg.ResourceMonitor.CheckIntervalSeconds,
-			Thresholds: monitor.ThresholdsConfig{
-				CPUMicrocores: cfg.ResourceMonitor.Thresholds.CPUMicrocores,
-				MemoryMB:      cfg.ResourceMonitor.Thresholds.MemoryMB,
-			},
-			Degradation: monitor.DegradationConfig{
-				DegradedLevel: monitor.DegradationLevelConfig{
-					CPUMicrocores:      cfg.ResourceMonitor.Degradation.DegradedLevel.CPUMicrocores,
-					MemoryMB:           cfg.ResourceMonitor.Degradation.DegradedLevel.MemoryMB,
-					IntervalMultiplier: cfg.ResourceMonitor.Degradation.DegradedLevel.IntervalMultiplier,
-				},
-				CriticalLevel: monitor.DegradationLevelConfig{
-					CPUMicrocores:      cfg.ResourceMonitor.Degradation.CriticalLevel.CPUMicrocores,
-					MemoryMB:           cfg.ResourceMonitor.Degradation.CriticalLevel.MemoryMB,
-					IntervalMultiplier: cfg.ResourceMonitor.Degradation.CriticalLevel.IntervalMultiplier,
-				},
-				Recovery: monitor.RecoveryConfig{
-					ConsecutiveNormalChecks: cfg.ResourceMonitor.Degradation.Recovery.ConsecutiveNormalChecks,
-				},
-			},
-			Alerting: monitor.AlertingConfig{
-				SuppressionWindowSeconds: cfg.ResourceMonitor.Alerting.SuppressionWindowSeconds,
-			},
-		}
-		resourceMonitor, err = monitor.NewMonitor(monitorCfg, scheduler, logAdapter)
+		resourceMonitor, err = monitor.NewMonitor(monitorConfig(cfg.ResourceMonitor), scheduler, logAdapter)
 		if err != nil {
 			logger.WithError(err).Warn("Failed to create resource monitor")
 		} else {
@@ -147,6 +178,27 @@ func runStart(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if configWatcher != nil && resourceMonitor != nil {
+		// Thresholds, degradation levels and alerting apply to the next check
+		configWatcher.OnReload(func(newConfig *config.Config, changes []string) error {
+			if err := resourceMonitor.UpdateConfig(monitorConfig(newConfig.ResourceMonitor)); err != nil {
+				// Not rolled back: the other subsystems already applied the new config
+				logger.WithError(err).Warn("Failed to apply resource monitor configuration, keeping the current thresholds")
+			}
+			return nil
+		})
+	}
+
+	// Answer udp_ping echo probes from other beacons
+	if cfg.Responder.Enabled {
+		echoResponder := responder.NewServer(cfg.Responder.Listen)
+		if err := echoResponder.Start(); err != nil {
+			logger.WithError(err).Warn("Failed to start UDP responder")
+		} else {
+			defer echoResponder.Stop()
+		}
+	}
+
 	logger.Info("Starting metrics server...")
 
 	// Create and start metrics server (Story 3.8)
@@ -156,13 +208,79 @@ func runStart(cmd *cobra.Command, args []string) error {
 	}
 	defer metricsServer.Stop()
 
+	if configWatcher != nil {
+		// Statistics and metrics_port apply without restart
+		configWatcher.OnReload(func(newConfig *config.Config, changes []string) error {
+			if err := metricsServer.ApplyConfig(newConfig); err != nil {
+				// Not rolled back: the other subsystems already applied the new config
+				logger.WithError(err).Warn("Failed to apply metrics configuration, metrics server keeps its current port")
+			}
+			return nil
+		})
+	}
+
 	logger.Info("Starting heartbeat reporter...")
 
 	// Create Pulse API client with 5 second timeout (NFR-PERF-001)
-	apiClient := reporter.NewPulseAPIClient(cfg.PulseServer, 5*time.Second)
+	apiClient := reporter.NewPulseAPIClientWithTLS(cfg.PulseServer, 5*time.Second, tlsConfig)
+	apiClient.SetAuthToken(cfg.APIToken)
 
 	// Create heartbeat reporter with scheduler integration
 	heartbeatReporter := reporter.NewHeartbeatReporter(apiClient, cfg.NodeID, scheduler)
+	heartbeatReporter.SetBackoffPolicy(reporter.BackoffPolicyFromConfig(cfg.Reconnect))
+	heartbeatReporter.SetStatistics(cfg.Statistics)
+	heartbeatReporter.SetReportSamples(cfg.ReportSamples)
+
+	// Expose upload health to `beacon debug` (status file) and Prometheus
+	if err := heartbeatReporter.SetStatusFile(reporter.ConnectionStatusPath(cfg)); err != nil {
+		logger.WithError(err).Warn("Failed to set up connection status file, debug output will lack upload status")
+	}
+	metricsServer.SetConnectionStateProvider(heartbeatReporter)
+
+	// Register again when Pulse no longer knows the node (e.g. deleted in Pulse)
+	identity := *cfg
+	heartbeatReporter.SetReregister(func(ctx context.Context) (string, string, error) {
+		state, err := registration.Reregister(ctx, registrar, &identity)
+		if err != nil {
+			return "", "", err
+		}
+		identity.NodeID, identity.APIToken = state.NodeID, state.APIToken
+		if probeSyncer != nil {
+			syncClient.SetAuthToken(state.APIToken)
+			probeSyncer.SetNodeID(state.NodeID)
+		}
+		return state.NodeID, state.APIToken, nil
+	})
+
+	if configWatcher != nil {
+		// Retry, statistics and sample settings apply to the next report without restart
+		configWatcher.OnReload(func(newConfig *config.Config, changes []string) error {
+			heartbeatReporter.SetBackoffPolicy(reporter.BackoffPolicyFromConfig(newConfig.Reconnect))
+			heartbeatReporter.SetStatistics(newConfig.Statistics)
+			heartbeatReporter.SetReportSamples(newConfig.ReportSamples)
+			return nil
+		})
+
+		// Start config watcher once every reload callback is registered, so
+		// no reload can skip a subsystem
+		go func() {
+			if err := configWatcher.Start(ctx); err != nil {
+				logger.WithError(err).Error("Config watcher stopped with error")
+			}
+		}()
+
+		logger.WithField("config_path", cfg.ConfigPath).Info("Config watcher started for hot reload")
+	}
+
+	// Queue undelivered heartbeats on disk while Pulse is unreachable
+	if cfg.Outbox.Enabled {
+		heartbeatOutbox, err := outbox.Open(cfg.Outbox.Path, cfg.Outbox.MaxItems, time.Duration(cfg.Outbox.MaxAgeHours)*time.Hour)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to open heartbeat outbox, undelivered heartbeats will be dropped")
+		} else {
+			heartbeatReporter.SetOutbox(heartbeatOutbox)
+		}
+	}
 
 	// Start heartbeat reporting (using existing context)
 	heartbeatReporter.StartReporting(ctx)
@@ -189,3 +307,53 @@ func runStart(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// resolveNodeIdentity returns the runtime configuration: a copy of fileCfg
+// with the node ID and API token assigned by self-registration (no node_id or
+// auto_register), or the API token persisted in the state file. fileCfg is
+// left unchanged.
+func resolveNodeIdentity(ctx context.Context, fileCfg *config.Config, registrar registration.Registrar) (*config.Config, error) {
+	cfg := *fileCfg
+	if !fileCfg.SelfRegisters() {
+		cfg.APIToken = registration.StoredAPIToken(fileCfg)
+		return &cfg, nil
+	}
+
+	state, err := registration.Register(ctx, registrar, fileCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register node: %w", err)
+	}
+	cfg.NodeID = state.NodeID
+	cfg.APIToken = state.APIToken
+	return &cfg, nil
+}
+
+// monitorConfig converts the resource_monitor settings to the monitor package configuration
+func monitorConfig(cfg config.ResourceMonitorConfig) *monitor.ResourceMonitorConfig {
+	return &monitor.ResourceMonitorConfig{
+		Enabled:              cfg.Enabled,
+		CheckIntervalSeconds: cfg.CheckIntervalSeconds,
+		Thresholds: monitor.ThresholdsConfig{
+			CPUMicrocores: cfg.Thresholds.CPUMicrocores,
+			MemoryMB:      cfg.Thresholds.MemoryMB,
+		},
+		Degradation: monitor.DegradationConfig{
+			DegradedLevel: monitor.DegradationLevelConfig{
+				CPUMicrocores:      cfg.Degradation.DegradedLevel.CPUMicrocores,
+				MemoryMB:           cfg.Degradation.DegradedLevel.MemoryMB,
+				IntervalMultiplier: cfg.Degradation.DegradedLevel.IntervalMultiplier,
+			},
+			CriticalLevel: monitor.DegradationLevelConfig{
+				CPUMicrocores:      cfg.Degradation.CriticalLevel.CPUMicrocores,
+				MemoryMB:           cfg.Degradation.CriticalLevel.MemoryMB,
+				IntervalMultiplier: cfg.Degradation.CriticalLevel.IntervalMultiplier,
+			},
+			Recovery: monitor.RecoveryConfig{
+				ConsecutiveNormalChecks: cfg.Degradation.Recovery.ConsecutiveNormalChecks,
+			},
+		},
+		Alerting: monitor.AlertingConfig{
+			SuppressionWindowSeconds: cfg.Alerting.SuppressionWindowSeconds,
+		},
+	}
+}
@@ -10,6 +10,12 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"beacon/internal/api"
+	"beacon/internal/config"
+	"beacon/internal/logger"
 )
 
 // executeWithTimeout executes the command with a timeout context
@@ -398,3 +404,91 @@ func isValidUUID(uuid string) bool {
 	}
 	return true
 }
+
+// stubRegistrar assigns a fixed node ID and API token
+type stubRegistrar struct {
+	nodeID   string
+	apiToken string
+}
+
+func (r *stubRegistrar) SetAuthToken(authToken string) {}
+
+func (r *stubRegistrar) RegisterNode(ctx context.Context, req *api.RegisterNodeRequest) (*api.RegisterNodeResponse, error) {
+	return &api.RegisterNodeResponse{Data: api.RegisterNodeData{ID: r.nodeID, APIToken: r.apiToken}}, nil
+}
+
+// TestResolveNodeIdentity_ReloadAfterRegistration tests that the node ID and
+// API token obtained by registration are not reported as config file changes
+// (and restart-required) on hot reload
+func TestResolveNodeIdentity_ReloadAfterRegistration(t *testing.T) {
+	// Arrange - self-registering beacon without node_id or api_token in its config file
+	tmpDir := t.TempDir()
+	if err := logger.InitLogger(&config.Config{LogLevel: "INFO", LogFile: filepath.Join(tmpDir, "beacon.log"), LogMaxSize: 10, LogMaxAge: 7, LogMaxBackups: 3}); err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+	configPath := filepath.Join(tmpDir, "beacon.yaml")
+	configContent := fmt.Sprintf(`pulse_server: "http://localhost:8080"
+node_name: "Test Node"
+node_ip: 10.0.0.1
+state_file: %s
+`, filepath.Join(tmpDir, "state.json"))
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+	fileCfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	// Act - register
+	registrar := &stubRegistrar{nodeID: "5f0c1c9e-8a0e-4f7c-9a51-2f4b7e0d3c11", apiToken: "npb_test"}
+	cfg, err := resolveNodeIdentity(context.Background(), fileCfg, registrar)
+	if err != nil {
+		t.Fatalf("resolveNodeIdentity failed: %v", err)
+	}
+
+	// Assert - runtime identity set, config file values kept
+	if cfg.NodeID != registrar.nodeID || cfg.APIToken != registrar.apiToken {
+		t.Errorf("Expected registered identity, got node_id=%q api_token=%q", cfg.NodeID, cfg.APIToken)
+	}
+	if fileCfg.NodeID != "" || fileCfg.APIToken != "" {
+		t.Errorf("Expected config file values unchanged, got node_id=%q api_token=%q", fileCfg.NodeID, fileCfg.APIToken)
+	}
+
+	// Act - reload after a log level change
+	watcher, err := config.NewFileWatcher(configPath, fileCfg, logrus.New())
+	if err != nil {
+		t.Fatalf("Failed to create file watcher: %v", err)
+	}
+	type reload struct {
+		changes []string
+		restart []string
+	}
+	reloaded := make(chan reload, 1)
+	watcher.OnReload(func(newConfig *config.Config, changes []string) error {
+		reloaded <- reload{changes: changes, restart: config.RestartRequiredChanges(fileCfg, newConfig)}
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Start(ctx)
+	time.Sleep(200 * time.Millisecond)
+
+	if err := os.WriteFile(configPath, []byte(configContent+"log_level: DEBUG\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert - only the log level changed
+	select {
+	case r := <-reloaded:
+		if len(r.restart) != 0 {
+			t.Errorf("Expected no restart-required fields, got %v", r.restart)
+		}
+		if len(r.changes) != 1 || !strings.HasPrefix(r.changes[0], "log_level:") {
+			t.Errorf("Expected only the log_level change, got %v", r.changes)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Config change did not trigger a reload")
+	}
+}
@@ -7,18 +7,26 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
 // PulseClient handles communication with Pulse API
 type PulseClient struct {
-	baseURL    string
-	authToken  string
-	httpClient *http.Client
+	baseURL         string
+	authToken       string // Guarded by mu, replaced on re-registration
+	enrollmentToken string
+	httpClient      *http.Client
+	mu              sync.Mutex
 }
 
+// EnrollmentTokenHeader carries the shared enrollment token Pulse requires
+// for self-registration when beacon mTLS is not used
+const EnrollmentTokenHeader = "X-Beacon-Enrollment-Token"
+
 // RegisterNodeRequest represents registration request body
 type RegisterNodeRequest struct {
+	NodeID   string   `json:"node_id,omitempty"` // Previously assigned ID, if any
 	NodeName string   `json:"node_name"`
 	IP       string   `json:"ip"`
 	Region   string   `json:"region"`
@@ -34,6 +42,7 @@ type RegisterNodeData struct {
 	Tags      string    `json:"tags,omitempty"` // JSONB stored as string (matches Pulse API response)
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	APIToken  string    `json:"api_token,omitempty"` // Only returned when Pulse issues a new token
 }
 
 // RegisterNodeResponse represents registration response from Pulse
@@ -65,6 +74,27 @@ func NewPulseClient(baseURL string, authToken string, httpClient *http.Client) *
 	}
 }
 
+// SetAuthToken sets the API token sent in the Authorization header
+func (c *PulseClient) SetAuthToken(authToken string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.authToken = authToken
+}
+
+// setAuthHeader adds the API token, if any, to a request
+func (c *PulseClient) setAuthHeader(req *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+}
+
+// SetEnrollmentToken sets the shared enrollment token sent on registration
+func (c *PulseClient) SetEnrollmentToken(enrollmentToken string) {
+	c.enrollmentToken = enrollmentToken
+}
+
 // RegisterNode sends registration request to Pulse with exponential backoff retry
 func (c *PulseClient) RegisterNode(ctx context.Context, req *RegisterNodeRequest) (*RegisterNodeResponse, error) {
 	const maxRetries = 3
@@ -103,7 +133,7 @@ func (c *PulseClient) RegisterNode(ctx context.Context, req *RegisterNodeRequest
 // doRegisterNode performs a single registration attempt
 func (c *PulseClient) doRegisterNode(ctx context.Context, req *RegisterNodeRequest) (*RegisterNodeResponse, error) {
 	// Build request URL
-	url := c.baseURL + "/api/v1/nodes"
+	url := c.baseURL + "/api/v1/beacon/register"
 
 	// Marshal request body
 	reqBody, err := json.Marshal(req)
@@ -120,8 +150,9 @@ func (c *PulseClient) doRegisterNode(ctx context.Context, req *RegisterNodeReque
 	// Set headers
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Accept", "application/json")
-	if c.authToken != "" {
-		httpReq.Header.Set("Authorization", "Bearer "+c.authToken)
+	c.setAuthHeader(httpReq)
+	if c.enrollmentToken != "" {
+		httpReq.Header.Set(EnrollmentTokenHeader, c.enrollmentToken)
 	}
 
 	// Send request
@@ -198,9 +229,11 @@ func (c *PulseClient) extractStatusCode(err error) (int, error) {
 		switch apiErr.Code {
 		case "ERR_INVALID_REQUEST":
 			return http.StatusBadRequest, apiErr
-		case "ERR_UNAUTHORIZED":
+		case "ERR_UNAUTHORIZED", "ERR_INVALID_ENROLLMENT_TOKEN", "ERR_INVALID_NODE_TOKEN", "ERR_NODE_TOKEN_REVOKED":
 			return http.StatusUnauthorized, apiErr
-		case "ERR_NODE_EXISTS":
+		case "ERR_REGISTRATION_DISABLED":
+			return http.StatusForbidden, apiErr
+		case "ERR_NODE_EXISTS", "ERR_NODE_TOKEN_REQUIRED":
 			return http.StatusConflict, apiErr
 		case "ERR_NODE_NOT_FOUND":
 			return http.StatusNotFound, apiErr
@@ -38,7 +38,7 @@ func TestPulseClient_RegisterNode_Success(t *testing.T) {
 
 	server := createTestServer(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, http.MethodPost, r.Method)
-		assert.Equal(t, "/api/v1/nodes", r.URL.Path)
+		assert.Equal(t, "/api/v1/beacon/register", r.URL.Path)
 		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
 
 		var req RegisterNodeRequest
@@ -400,6 +400,38 @@ func TestPulseClient_RegisterNode_AuthToken(t *testing.T) {
 	assert.Equal(t, "test-node-id", resp.Data.ID)
 }
 
+func TestPulseClient_RegisterNode_EnrollmentToken(t *testing.T) {
+	// Test: enrollment token header is sent, and its rejection is not retried
+	attemptCount := 0
+	server := createTestServer(func(w http.ResponseWriter, r *http.Request) {
+		attemptCount++
+		assert.Equal(t, "enroll-0123456789abcdef", r.Header.Get(EnrollmentTokenHeader))
+
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{
+			"code":    "ERR_INVALID_ENROLLMENT_TOKEN",
+			"message": "Valid beacon enrollment token required",
+		})
+	})
+	defer server.Close()
+
+	client := NewPulseClient(server.URL, "", &http.Client{
+		Timeout: 30 * time.Second,
+	})
+	client.SetEnrollmentToken("enroll-0123456789abcdef")
+
+	resp, err := client.RegisterNode(context.Background(), &RegisterNodeRequest{
+		NodeName: "测试节点",
+		IP:       "192.168.1.1",
+		Region:   "us-east",
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "ERR_INVALID_ENROLLMENT_TOKEN")
+	assert.Equal(t, 1, attemptCount, "Should not retry a rejected enrollment token")
+}
+
 func TestPulseClient_exponentialBackoff(t *testing.T) {
 	// Test: Verify exponential backoff intervals: 1s, 2s, 4s
 	tests := []struct {
@@ -420,6 +452,27 @@ func TestPulseClient_exponentialBackoff(t *testing.T) {
 	}
 }
 
+func TestPulseClient_extractStatusCode_NodeToken(t *testing.T) {
+	// Test: token rejections on re-registration need an admin and are not retried
+	tests := []struct {
+		code       string
+		statusCode int
+	}{
+		{"ERR_INVALID_NODE_TOKEN", http.StatusUnauthorized},
+		{"ERR_NODE_TOKEN_REVOKED", http.StatusUnauthorized},
+		{"ERR_NODE_TOKEN_REQUIRED", http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			client := &PulseClient{}
+			statusCode, err := client.extractStatusCode(&APIError{Code: tt.code})
+			assert.Equal(t, tt.statusCode, statusCode)
+			assert.False(t, client.isRetryableError(statusCode, err))
+		})
+	}
+}
+
 func TestPulseClient_isRetryableError(t *testing.T) {
 	// Test: Determine which errors should trigger retry
 	tests := []struct {
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrProbeConfigNotModified is returned when the probe config version is unchanged
+var ErrProbeConfigNotModified = errors.New("probe config not modified")
+
+// ProbeConfigItem represents a probe assigned to this node in Pulse
+type ProbeConfigItem struct {
+	ID             string `json:"id"`
+	Type           string `json:"type"` // tcp_ping or udp_ping
+	Target         string `json:"target"`
+	Port           int    `json:"port"`
+	Interval       int    `json:"interval"`
+	Count          int    `json:"count"`
+	TimeoutSeconds int    `json:"timeout_seconds"`
+	Responder      bool   `json:"responder,omitempty"` // udp_ping against a beacon responder (mesh probes)
+}
+
+// ProbeConfigData represents versioned probe configuration from Pulse
+type ProbeConfigData struct {
+	NodeID  string            `json:"node_id"`
+	Version string            `json:"version"`
+	Probes  []ProbeConfigItem `json:"probes"`
+}
+
+// FetchProbeConfig fetches the probes assigned to a node. When version matches
+// the current configuration in Pulse, ErrProbeConfigNotModified is returned.
+func (c *PulseClient) FetchProbeConfig(ctx context.Context, nodeID string, version string) (*ProbeConfigData, error) {
+	// Build request URL
+	reqURL := c.baseURL + "/api/v1/beacon/nodes/" + url.PathEscape(nodeID) + "/probes"
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers
+	httpReq.Header.Set("Accept", "application/json")
+	if version != "" {
+		httpReq.Header.Set("If-None-Match", `"`+version+`"`)
+	}
+	c.setAuthHeader(httpReq)
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode == http.StatusNotModified {
+		return nil, ErrProbeConfigNotModified
+	}
+
+	var responseBody struct {
+		Data    ProbeConfigData `json:"data"`
+		Message string          `json:"message"`
+		Code    string          `json:"code,omitempty"`
+		Details any             `json:"details,omitempty"`
+	}
+
+	if err := json.NewDecoder(httpResp.Body).Decode(&responseBody); err != nil {
+		return nil, fmt.Errorf("failed to decode response (status %d): %w", httpResp.StatusCode, err)
+	}
+
+	// Check response status
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, &APIError{
+			Code:    responseBody.Code,
+			Message: responseBody.Message,
+			Details: responseBody.Details,
+		}
+	}
+
+	return &responseBody.Data, nil
+}
@@ -11,6 +11,8 @@ import (
 	"strings"
 
 	"github.com/spf13/viper"
+
+	"beacon/internal/models"
 )
 
 // Config represents the complete Beacon configuration
@@ -24,12 +26,46 @@ type Config struct {
 	Region string   `mapstructure:"region" yaml:"region"`
 	Tags   []string `mapstructure:"tags" yaml:"tags"`
 
+	// Self-registration: without node_id, or always when auto_register is
+	// enabled, the node ID is obtained from Pulse and persisted to state_file
+	AutoRegister bool   `mapstructure:"auto_register" yaml:"auto_register"`
+	StateFile    string `mapstructure:"state_file" yaml:"state_file"` // Default /var/lib/beacon/state.json
+	NodeIP       string `mapstructure:"node_ip" yaml:"node_ip"`       // Reported IP (auto-detected if empty)
+
+	// Per-node API token sent to Pulse as "Authorization: Bearer <token>".
+	// When empty, the token issued at registration is read from state_file.
+	APIToken string `mapstructure:"api_token" yaml:"api_token"`
+
+	// Shared enrollment token Pulse requires for self-registration (sent as
+	// X-Beacon-Enrollment-Token); not needed when Pulse requires beacon mTLS
+	EnrollmentToken string `mapstructure:"enrollment_token" yaml:"enrollment_token"`
+
+	// TLS settings for Pulse connections (custom CA, mTLS client certificate)
+	TLS TLSConfig `mapstructure:"tls" yaml:"tls"`
+
 	// Probe configuration (for Story 3.3)
 	Probes []ProbeConfig `mapstructure:"probes" yaml:"probes"`
 
+	// Probe sync configuration (probes managed centrally in Pulse)
+	ProbeSync ProbeSyncConfig `mapstructure:"probe_sync" yaml:"probe_sync"`
+
+	// RTT statistics reported per probe on top of mean, median, variance and
+	// jitter: min, max, stddev, p90, p95, p99 or jitter_rfc3550
+	Statistics []string `mapstructure:"statistics" yaml:"statistics"`
+
+	// Report every attempt of a probe batch (time, success, RTT, error class)
+	// alongside the aggregates, for post-incident analysis
+	ReportSamples bool `mapstructure:"report_samples" yaml:"report_samples"`
+
 	// Reconnect configuration (for Story 2.6)
 	Reconnect ReconnectConfig `mapstructure:"reconnect" yaml:"reconnect"`
 
+	// Outbox configuration (durable queue for heartbeats while Pulse is unreachable)
+	Outbox OutboxConfig `mapstructure:"outbox" yaml:"outbox"`
+
+	// UDP echo responder for udp_ping probes from other beacons
+	Responder ResponderConfig `mapstructure:"responder" yaml:"responder"`
+
 	// Metrics configuration (for Story 3.8)
 	MetricsEnabled       bool `mapstructure:"metrics_enabled" yaml:"metrics_enabled"`
 	MetricsPort          int  `mapstructure:"metrics_port" yaml:"metrics_port"`
@@ -55,21 +91,58 @@ type Config struct {
 	Debug      bool   `mapstructure:"debug"`
 }
 
-// ProbeConfig represents a single probe configuration
+// ProbeConfig represents a single probe configuration. Settings other than
+// the ones shared by all probe types are kept in Options and parsed by the
+// registered probe type (see probe.RegisterProbeType).
 type ProbeConfig struct {
+	ID             string `mapstructure:"id" yaml:"id"` // Optional Pulse probe UUID used when reporting
 	Type           string `mapstructure:"type" yaml:"type"`
 	Target         string `mapstructure:"target" yaml:"target"`
 	Port           int    `mapstructure:"port" yaml:"port"`
 	TimeoutSeconds int    `mapstructure:"timeout_seconds" yaml:"timeout_seconds"`
 	Interval       int    `mapstructure:"interval" yaml:"interval"`
 	Count          int    `mapstructure:"count" yaml:"count"`
+
+	// Type-specific options by name, e.g. expected_status for http_probe
+	Options map[string]interface{} `mapstructure:",remain" yaml:",inline"`
+}
+
+// ProbeSyncConfig represents Pulse-driven probe configuration sync
+type ProbeSyncConfig struct {
+	Enabled         bool `mapstructure:"enabled" yaml:"enabled"`
+	IntervalSeconds int  `mapstructure:"interval_seconds" yaml:"interval_seconds"` // Poll interval (10-3600)
 }
 
-// ReconnectConfig represents connection retry configuration
+// ReconnectConfig represents connection retry configuration.
+// Drives heartbeat report retries and is hot-reloadable.
 type ReconnectConfig struct {
-	MaxRetries    int    `mapstructure:"max_retries" yaml:"max_retries"`
-	RetryInterval int    `mapstructure:"retry_interval" yaml:"retry_interval"`
-	Backoff        string `mapstructure:"backoff" yaml:"backoff"`
+	MaxRetries    int    `mapstructure:"max_retries" yaml:"max_retries"`       // Attempts per report (default 3)
+	RetryInterval int    `mapstructure:"retry_interval" yaml:"retry_interval"` // Base delay in seconds (default 1)
+	Backoff       string `mapstructure:"backoff" yaml:"backoff"`               // constant, linear, exponential, exponential_jitter
+	MaxBackoff    int    `mapstructure:"max_backoff" yaml:"max_backoff"`       // Delay cap in seconds (default 60)
+}
+
+// Backoff strategies for ReconnectConfig.Backoff
+const (
+	BackoffConstant          = "constant"
+	BackoffLinear            = "linear"
+	BackoffExponential       = "exponential"
+	BackoffExponentialJitter = "exponential_jitter"
+)
+
+// ResponderConfig represents the UDP echo responder answering udp_ping
+// probes that set responder: true
+type ResponderConfig struct {
+	Enabled bool   `mapstructure:"enabled" yaml:"enabled"`
+	Listen  string `mapstructure:"listen" yaml:"listen"` // Default :7331
+}
+
+// OutboxConfig represents the on-disk queue for undelivered heartbeats
+type OutboxConfig struct {
+	Enabled     bool   `mapstructure:"enabled" yaml:"enabled"`             // Default true
+	Path        string `mapstructure:"path" yaml:"path"`                   // Default outbox.jsonl next to state_file
+	MaxItems    int    `mapstructure:"max_items" yaml:"max_items"`         // Oldest entries dropped beyond this (default 10000)
+	MaxAgeHours int    `mapstructure:"max_age_hours" yaml:"max_age_hours"` // Entries older than this are dropped (default 24)
 }
 
 // LoadConfig loads configuration from file with validation
@@ -104,6 +177,7 @@ func LoadConfig(configPath string) (*Config, error) {
 	v := viper.New()
 	v.SetConfigFile(resolvedPath)
 	v.SetConfigType("yaml")
+	v.SetDefault("outbox.enabled", true)
 
 	if err := v.ReadInConfig(); err != nil {
 		// Extract line number from YAML parse error for UX-friendly messages
@@ -120,9 +194,6 @@ func LoadConfig(configPath string) (*Config, error) {
 	if config.PulseServer == "" {
 		return nil, errors.New("required field 'pulse_server' is missing (suggestion: add pulse_server: \"https://pulse.example.com\" to config)")
 	}
-	if config.NodeID == "" {
-		return nil, errors.New("required field 'node_id' is missing (suggestion: add node_id: \"your-node-id\" to config)")
-	}
 	if config.NodeName == "" {
 		return nil, errors.New("required field 'node_name' is missing (suggestion: add node_name: \"Your Node Name\" to config)")
 	}
@@ -139,10 +210,62 @@ func LoadConfig(configPath string) (*Config, error) {
 		}
 	}
 
+	// Set default state file for self-registration
+	if config.StateFile == "" {
+		config.StateFile = "/var/lib/beacon/state.json"
+	}
+	if config.NodeIP != "" && net.ParseIP(config.NodeIP) == nil {
+		return nil, fmt.Errorf("invalid node_ip '%s' (suggestion: use an IPv4 or IPv6 address)", config.NodeIP)
+	}
+
+	// Set defaults and validate outbox configuration
+	if config.Outbox.Path == "" {
+		config.Outbox.Path = filepath.Join(filepath.Dir(config.StateFile), "outbox.jsonl")
+	}
+	if config.Outbox.MaxItems == 0 {
+		config.Outbox.MaxItems = 10000 // Default 10000 heartbeats
+	}
+	if config.Outbox.MaxAgeHours == 0 {
+		config.Outbox.MaxAgeHours = 24 // Default 24 hours
+	}
+	if config.Outbox.MaxItems < 1 || config.Outbox.MaxItems > 1000000 {
+		return nil, fmt.Errorf("invalid outbox.max_items %d, must be between 1 and 1000000", config.Outbox.MaxItems)
+	}
+	if config.Outbox.MaxAgeHours < 1 || config.Outbox.MaxAgeHours > 720 {
+		return nil, fmt.Errorf("invalid outbox.max_age_hours %d, must be between 1 and 720", config.Outbox.MaxAgeHours)
+	}
+
+	// Set default and validate responder listen address
+	if config.Responder.Listen == "" {
+		config.Responder.Listen = ":7331"
+	}
+	if _, _, err := net.SplitHostPort(config.Responder.Listen); err != nil {
+		return nil, fmt.Errorf("invalid responder.listen '%s': %w (suggestion: use host:port, e.g. \":7331\")", config.Responder.Listen, err)
+	}
+
+	// Validate TLS files early so misconfiguration fails at startup
+	if _, err := config.TLS.ClientTLSConfig(); err != nil {
+		return nil, fmt.Errorf("invalid tls config: %w", err)
+	}
+
+	// Set default and validate probe sync configuration
+	if config.ProbeSync.IntervalSeconds == 0 {
+		config.ProbeSync.IntervalSeconds = 60 // Default 60 seconds
+	}
+	if config.ProbeSync.IntervalSeconds < 10 || config.ProbeSync.IntervalSeconds > 3600 {
+		return nil, fmt.Errorf("invalid probe_sync.interval_seconds %d, must be between 10 and 3600", config.ProbeSync.IntervalSeconds)
+	}
+
+	// Validate selected probe statistics
+	if err := validateStatistics(config.Statistics); err != nil {
+		return nil, err
+	}
+
 	// Validate reconnect configuration if present
 	if err := validateReconnectConfig(config.Reconnect); err != nil {
 		return nil, fmt.Errorf("reconnect configuration validation failed: %w", err)
 	}
+	applyReconnectDefaults(&config.Reconnect)
 
 	// Set default values for metrics configuration (Story 3.8)
 	// If metrics_port is not set, use default port and enable metrics
@@ -252,81 +375,54 @@ func resolveConfigPath(customPath string) (string, error) {
 	return "", errors.New("config file not found (checked /etc/beacon/beacon.yaml and ./beacon.yaml)")
 }
 
-// validateProbeConfig validates probe configuration
-func validateProbeConfig(probe ProbeConfig) error {
-	// Validate type
-	if probe.Type != "tcp_ping" && probe.Type != "udp_ping" {
-		return fmt.Errorf("invalid probe type '%s', must be 'tcp_ping' or 'udp_ping'", probe.Type)
-	}
-
-	// Validate target (IP address or hostname)
-	if probe.Target == "" {
-		return fmt.Errorf("probe target cannot be empty")
-	}
-	if net.ParseIP(probe.Target) == nil {
-		// Not an IP address, check if it's a valid hostname
-		if err := validateHostname(probe.Target); err != nil {
-			return fmt.Errorf("invalid probe target '%s': %w", probe.Target, err)
-		}
-	}
+// ProbeValidator checks a probe configuration entry against the rules of
+// its probe type
+type ProbeValidator func(ProbeConfig) error
 
-	// Validate port range (1-65535)
-	if probe.Port < 1 || probe.Port > 65535 {
-		return fmt.Errorf("invalid port %d, must be between 1 and 65535 (suggestion: check port number is valid)", probe.Port)
-	}
-
-	// Validate interval range (60-300)
-	if probe.Interval < 60 || probe.Interval > 300 {
-		return fmt.Errorf("invalid interval %d, must be between 60 and 300 seconds (suggestion: adjust interval to be within range)", probe.Interval)
-	}
+// probeValidator holds the probe type rules, installed by the probe package
+var probeValidator ProbeValidator
 
-	// Validate count range (1-100)
-	if probe.Count < 1 || probe.Count > 100 {
-		return fmt.Errorf("invalid count %d, must be between 1 and 100 (suggestion: adjust probe count to be within range)", probe.Count)
-	}
-
-	// Validate timeout range (1-30)
-	if probe.TimeoutSeconds < 1 || probe.TimeoutSeconds > 30 {
-		return fmt.Errorf("invalid timeout %d, must be between 1 and 30 seconds (suggestion: adjust timeout to be within range)", probe.TimeoutSeconds)
-	}
+// SetProbeValidator installs the probe type validation applied by LoadConfig
+// and ValidateProbeConfig. The probe package installs its type registry, the
+// single source of probe type names and rules, when it is initialized;
+// without it probe entries are not checked.
+func SetProbeValidator(validate ProbeValidator) {
+	probeValidator = validate
+}
 
-	return nil
+// ValidateProbeConfig validates a probe configuration received from outside
+// the config file (e.g. synced from Pulse)
+func ValidateProbeConfig(probe ProbeConfig) error {
+	return validateProbeConfig(probe)
 }
 
-// validateHostname validates hostname format
-func validateHostname(hostname string) error {
-	if len(hostname) > 253 {
-		return fmt.Errorf("hostname too long (max 253 characters)")
+// validateProbeConfig validates probe configuration
+func validateProbeConfig(probe ProbeConfig) error {
+	if probeValidator == nil {
+		return nil
 	}
+	return probeValidator(probe)
+}
 
-	// Check for invalid characters
-	for _, char := range hostname {
-		if !isValidHostnameChar(char) {
-			return fmt.Errorf("hostname contains invalid character '%c' (suggestion: use only letters, numbers, hyphens, underscores, and dots)", char)
+// validateStatistics validates the statistics selected for reporting
+func validateStatistics(statistics []string) error {
+	seen := make(map[string]bool, len(statistics))
+	for _, name := range statistics {
+		if !models.IsStatistic(name) {
+			return fmt.Errorf("invalid statistic '%s', must be one of %s", name, strings.Join(models.StatisticNames, ", "))
 		}
+		if seen[name] {
+			return fmt.Errorf("duplicate statistic '%s'", name)
+		}
+		seen[name] = true
 	}
-
-	// Check that hostname doesn't start or end with hyphen/dot/underscore
-	if strings.HasPrefix(hostname, "-") || strings.HasPrefix(hostname, ".") || strings.HasPrefix(hostname, "_") ||
-		strings.HasSuffix(hostname, "-") || strings.HasSuffix(hostname, ".") || strings.HasSuffix(hostname, "_") {
-		return fmt.Errorf("hostname cannot start or end with hyphen, dot, or underscore")
-	}
-
 	return nil
 }
 
-// isValidHostnameChar checks if a character is valid in hostname
-func isValidHostnameChar(r rune) bool {
-	return (r >= 'a' && r <= 'z') ||
-		(r >= 'A' && r <= 'Z') ||
-		(r >= '0' && r <= '9') ||
-		r == '-' || r == '.' || r == '_'
-}
-
 // validateReconnectConfig validates reconnect configuration
 func validateReconnectConfig(reconnect ReconnectConfig) error {
 	// Only validate if fields are set (zero values are OK for optional fields)
-	if reconnect.MaxRetries == 0 && reconnect.RetryInterval == 0 && reconnect.Backoff == "" {
+	if reconnect.MaxRetries == 0 && reconnect.RetryInterval == 0 && reconnect.Backoff == "" && reconnect.MaxBackoff == 0 {
 		return nil // All fields unset, validation passes
 	}
 
@@ -340,14 +436,38 @@ func validateReconnectConfig(reconnect ReconnectConfig) error {
 		return fmt.Errorf("invalid retry_interval %d, must be between 1 and 600", reconnect.RetryInterval)
 	}
 
+	// Validate max_backoff range (1-60); retries stop after one report interval
+	if reconnect.MaxBackoff != 0 && (reconnect.MaxBackoff < 1 || reconnect.MaxBackoff > 60) {
+		return fmt.Errorf("invalid max_backoff %d, must be between 1 and 60", reconnect.MaxBackoff)
+	}
+
 	// Validate backoff type
-	if reconnect.Backoff != "" && reconnect.Backoff != "exponential" && reconnect.Backoff != "linear" && reconnect.Backoff != "constant" {
-		return fmt.Errorf("invalid backoff '%s', must be 'exponential', 'linear', or 'constant'", reconnect.Backoff)
+	switch reconnect.Backoff {
+	case "", BackoffConstant, BackoffLinear, BackoffExponential, BackoffExponentialJitter:
+	default:
+		return fmt.Errorf("invalid backoff '%s', must be 'exponential', 'exponential_jitter', 'linear', or 'constant'", reconnect.Backoff)
 	}
 
 	return nil
 }
 
+// applyReconnectDefaults fills unset reconnect fields. The defaults keep the
+// original reporter behaviour: 3 attempts with 1s, 2s exponential backoff.
+func applyReconnectDefaults(reconnect *ReconnectConfig) {
+	if reconnect.MaxRetries == 0 {
+		reconnect.MaxRetries = 3
+	}
+	if reconnect.RetryInterval == 0 {
+		reconnect.RetryInterval = 1
+	}
+	if reconnect.Backoff == "" {
+		reconnect.Backoff = BackoffExponential
+	}
+	if reconnect.MaxBackoff == 0 {
+		reconnect.MaxBackoff = 60
+	}
+}
+
 // validateMetricsConfig validates metrics configuration (Story 3.8)
 func validateMetricsConfig(port int, updateSeconds int) error {
 	// Validate metrics port range (1024-65535)
@@ -456,9 +576,6 @@ func (c *Config) Validate() error {
 	if c.PulseServer == "" {
 		return errors.New("required field 'pulse_server' is missing")
 	}
-	if c.NodeID == "" {
-		return errors.New("required field 'node_id' is missing")
-	}
 	if c.NodeName == "" {
 		return errors.New("required field 'node_name' is missing")
 	}
@@ -475,6 +592,11 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Validate selected probe statistics
+	if err := validateStatistics(c.Statistics); err != nil {
+		return err
+	}
+
 	// Validate reconnect configuration
 	if err := validateReconnectConfig(c.Reconnect); err != nil {
 		return fmt.Errorf("reconnect configuration validation failed: %w", err)
@@ -493,6 +615,12 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// SelfRegisters reports whether the beacon registers with Pulse on start:
+// always with auto_register, otherwise only when no node_id is configured
+func (c *Config) SelfRegisters() bool {
+	return c.AutoRegister || c.NodeID == ""
+}
+
 // SaveConfig saves configuration to file (optional feature for MVP)
 // Note: MVP saves node_id to memory only. File write is optional for production use.
 func SaveConfig(cfg *Config, path string) error {
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -458,67 +459,6 @@ node_name: "Development Node"
 	}
 }
 
-func TestLoadConfig_ProbeInterval_OutOfRange(t *testing.T) {
-	// Test probe interval validation (should be 60-300)
-	tmpDir := t.TempDir()
-	configPath := filepath.Join(tmpDir, "beacon.yaml")
-
-	configContent := `
-pulse_server: "https://pulse.example.com"
-node_id: "us-east-01"
-node_name: "Test Node"
-probes:
-  - type: tcp_ping
-    target: "8.8.8.8"
-    port: 80
-    interval: 500  # Out of range (>300)
-    count: 10
-    timeout: 5
-`
-	err := os.WriteFile(configPath, []byte(configContent), 0644)
-	if err != nil {
-		t.Fatalf("Failed to create test config: %v", err)
-	}
-
-	// Load config
-	cfg, err := LoadConfig(configPath)
-	if err == nil {
-		t.Error("Expected error for probe interval out of range, got nil")
-	}
-	// Note: This validation will be implemented in Task 2
-	_ = cfg // Use variable to avoid unused variable warning
-}
-
-func TestLoadConfig_ProbePort_OutOfRange(t *testing.T) {
-	// Test probe port validation (should be 1-65535)
-	tmpDir := t.TempDir()
-	configPath := filepath.Join(tmpDir, "beacon.yaml")
-
-	configContent := `
-pulse_server: "https://pulse.example.com"
-node_id: "us-east-01"
-node_name: "Test Node"
-probes:
-  - type: tcp_ping
-    target: "8.8.8.8"
-    port: 70000  # Out of range (>65535)
-    interval: 300
-    count: 10
-    timeout: 5
-`
-	err := os.WriteFile(configPath, []byte(configContent), 0644)
-	if err != nil {
-		t.Fatalf("Failed to create test config: %v", err)
-	}
-
-	// Load config
-	cfg, err := LoadConfig(configPath)
-	if err == nil {
-		t.Error("Expected error for probe port out of range, got nil")
-	}
-	_ = cfg // Use variable to avoid unused variable warning
-}
-
 func TestLoadConfig_ReconnectMaxRetries_OutOfRange(t *testing.T) {
 	// Test reconnect max_retries validation (should be 1-100)
 	tmpDir := t.TempDir()
@@ -787,8 +727,8 @@ func TestLoadConfig_ErrorDetails_MissingField(t *testing.T) {
 	configPath := filepath.Join(tmpDir, "beacon.yaml")
 
 	configContent := `pulse_server: "https://pulse.example.com"
-# node_id is missing
-node_name: "Test Node"`
+node_id: "us-east-01"
+# node_name is missing`
 	err := os.WriteFile(configPath, []byte(configContent), 0644)
 	if err != nil {
 		t.Fatalf("Failed to create test config: %v", err)
@@ -797,13 +737,13 @@ node_name: "Test Node"`
 	// Load config
 	_, err = LoadConfig(configPath)
 	if err == nil {
-		t.Error("Expected error for missing node_id, got nil")
+		t.Fatal("Expected error for missing node_name, got nil")
 	}
 
 	// Verify error message mentions the missing field
 	errorMsg := err.Error()
-	if !contains(errorMsg, "node_id") {
-		t.Errorf("Expected error message to mention 'node_id', got: %s", errorMsg)
+	if !contains(errorMsg, "node_name") {
+		t.Errorf("Expected error message to mention 'node_name', got: %s", errorMsg)
 	}
 	// Verify error message says "missing" or "required"
 	missingOrRequiredFound := false
@@ -818,45 +758,6 @@ node_name: "Test Node"`
 	}
 }
 
-func TestLoadConfig_ErrorDetails_Validation(t *testing.T) {
-	// Test that validation errors are specific and helpful
-	tmpDir := t.TempDir()
-	configPath := filepath.Join(tmpDir, "beacon.yaml")
-
-	configContent := `pulse_server: "https://pulse.example.com"
-node_id: "us-east-01"
-node_name: "Test Node"
-probes:
-  - type: tcp_ping
-    target: "8.8.8.8"
-    port: 70000  # Invalid port
-    interval: 300
-    count: 10
-    timeout: 5`
-	err := os.WriteFile(configPath, []byte(configContent), 0644)
-	if err != nil {
-		t.Fatalf("Failed to create test config: %v", err)
-	}
-
-	// Load config
-	_, err = LoadConfig(configPath)
-	if err == nil {
-		t.Error("Expected error for invalid port, got nil")
-	}
-
-	// Verify error message mentions the problem and expected range
-	errorMsg := err.Error()
-	// Should mention "port" and "1 and 65535"
-	portMentioned := contains(errorMsg, "port")
-	rangeMentioned := contains(errorMsg, "65535")
-	if !portMentioned {
-		t.Errorf("Expected error message to mention 'port', got: %s", errorMsg)
-	}
-	if !rangeMentioned {
-		t.Errorf("Expected error message to mention valid range, got: %s", errorMsg)
-	}
-}
-
 // Helper function for string contains check
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && findSubstring(s, substr))
@@ -871,58 +772,6 @@ func findSubstring(s, substr string) bool {
 	return false
 }
 
-// Test hostname validation
-func TestValidateHostname_Valid(t *testing.T) {
-	testCases := []struct {
-		hostname string
-		valid    bool
-	}{
-		{"localhost", true},
-		{"example.com", true},
-		{"subdomain.example.com", true},
-		{"my-host-01", true},
-		{"host_name", true},
-		{"a", true}, // Single character
-	}
-
-	for _, tc := range testCases {
-		err := validateHostname(tc.hostname)
-		if tc.valid && err != nil {
-			t.Errorf("Expected hostname '%s' to be valid, got error: %v", tc.hostname, err)
-		}
-		if !tc.valid && err == nil {
-			t.Errorf("Expected hostname '%s' to be invalid, got nil", tc.hostname)
-		}
-	}
-}
-
-func TestValidateHostname_Invalid(t *testing.T) {
-	testCases := []struct {
-		hostname string
-		errContains string
-	}{
-		{"-invalid", "cannot start"},
-		{".invalid", "cannot start"},
-		{"_invalid", "cannot start"},
-		{"invalid-", "end"},
-		{"invalid.", "end"},
-		{"invalid_", "end"},
-		{"invalid host", "invalid character"},
-		{"invalid@host", "invalid character"},
-		{strings.Repeat("a", 300), "too long"},
-	}
-
-	for _, tc := range testCases {
-		err := validateHostname(tc.hostname)
-		if err == nil {
-			t.Errorf("Expected hostname '%s' to be invalid, got nil", tc.hostname)
-		}
-		if err != nil && !contains(err.Error(), tc.errContains) {
-			t.Errorf("Expected error for '%s' to contain '%s', got: %v", tc.hostname, tc.errContains, err)
-		}
-	}
-}
-
 func TestValidateReconnectConfig_Valid(t *testing.T) {
 	testCases := []ReconnectConfig{
 		{MaxRetries: 10, RetryInterval: 60, Backoff: "exponential"},
@@ -1031,7 +880,7 @@ func TestParseYAMLError_MappingValues(t *testing.T) {
 	}
 }
 
-func TestLoadConfig_ProbeTarget_Invalid(t *testing.T) {
+func TestLoadConfig_ProbeTarget_ValidIP(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "beacon.yaml")
 
@@ -1041,11 +890,11 @@ node_id: "us-east-01"
 node_name: "Test Node"
 probes:
   - type: tcp_ping
-    target: "invalid@host"
+    target: "8.8.8.8"
     port: 80
     interval: 300
     count: 10
-    timeout: 5
+    timeout_seconds: 5
 `
 	err := os.WriteFile(configPath, []byte(configContent), 0644)
 	if err != nil {
@@ -1053,18 +902,24 @@ probes:
 	}
 
 	cfg, err := LoadConfig(configPath)
-	if err == nil {
-		t.Error("Expected error for invalid hostname with @ symbol, got nil")
+	if err != nil {
+		t.Errorf("Expected no error for valid IP address target, got: %v", err)
 	}
-	_ = cfg // Use variable to avoid unused variable warning
 
-	errorMsg := err.Error()
-	if !contains(errorMsg, "invalid probe target") {
-		t.Errorf("Expected error message to mention invalid probe target, got: %s", errorMsg)
+	if cfg == nil {
+		t.Fatal("Expected config to be loaded, got nil")
+	}
+
+	if len(cfg.Probes) != 1 {
+		t.Errorf("Expected 1 probe, got: %d", len(cfg.Probes))
+	}
+
+	if cfg.Probes[0].Target != "8.8.8.8" {
+		t.Errorf("Expected probe target to be '8.8.8.8', got: %s", cfg.Probes[0].Target)
 	}
 }
 
-func TestLoadConfig_ProbeTarget_ValidIP(t *testing.T) {
+func TestLoadConfig_ProbeTarget_ValidHostname(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "beacon.yaml")
 
@@ -1074,7 +929,7 @@ node_id: "us-east-01"
 node_name: "Test Node"
 probes:
   - type: tcp_ping
-    target: "8.8.8.8"
+    target: "google.com"
     port: 80
     interval: 300
     count: 10
@@ -1087,7 +942,7 @@ probes:
 
 	cfg, err := LoadConfig(configPath)
 	if err != nil {
-		t.Errorf("Expected no error for valid IP address target, got: %v", err)
+		t.Errorf("Expected no error for valid hostname target, got: %v", err)
 	}
 
 	if cfg == nil {
@@ -1098,12 +953,101 @@ probes:
 		t.Errorf("Expected 1 probe, got: %d", len(cfg.Probes))
 	}
 
-	if cfg.Probes[0].Target != "8.8.8.8" {
-		t.Errorf("Expected probe target to be '8.8.8.8', got: %s", cfg.Probes[0].Target)
+	if cfg.Probes[0].Target != "google.com" {
+		t.Errorf("Expected probe target to be 'google.com', got: %s", cfg.Probes[0].Target)
 	}
 }
 
-func TestLoadConfig_ProbeTarget_ValidHostname(t *testing.T) {
+func TestLoadConfig_OutboxDefaults(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "beacon.yaml")
+
+	configContent := `
+pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+state_file: /var/lib/beacon-test/state.json
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Outbox is enabled by default and stored next to the state file
+	if !cfg.Outbox.Enabled {
+		t.Error("Expected outbox to be enabled by default")
+	}
+	if cfg.Outbox.Path != "/var/lib/beacon-test/outbox.jsonl" {
+		t.Errorf("Expected outbox path next to state_file, got: %s", cfg.Outbox.Path)
+	}
+	if cfg.Outbox.MaxItems != 10000 || cfg.Outbox.MaxAgeHours != 24 {
+		t.Errorf("Expected default limits 10000/24h, got %d/%dh", cfg.Outbox.MaxItems, cfg.Outbox.MaxAgeHours)
+	}
+}
+
+func TestLoadConfig_OutboxInvalidLimits(t *testing.T) {
+	tests := map[string]string{
+		"max_items too large": "outbox:\n  max_items: 2000000\n",
+		"negative max_age":    "outbox:\n  max_age_hours: -1\n",
+	}
+
+	for name, outboxContent := range tests {
+		t.Run(name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "beacon.yaml")
+			configContent := "pulse_server: \"https://pulse.example.com\"\nnode_id: \"us-east-01\"\nnode_name: \"Test Node\"\n" + outboxContent
+			if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+				t.Fatalf("Failed to create test config: %v", err)
+			}
+
+			if _, err := LoadConfig(configPath); err == nil || !strings.Contains(err.Error(), "outbox") {
+				t.Errorf("Expected outbox validation error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateReconnectConfig_JitterAndMaxBackoff(t *testing.T) {
+	valid := ReconnectConfig{MaxRetries: 5, RetryInterval: 1, Backoff: BackoffExponentialJitter, MaxBackoff: 30}
+	if err := validateReconnectConfig(valid); err != nil {
+		t.Errorf("Expected exponential_jitter to be valid, got: %v", err)
+	}
+
+	invalid := ReconnectConfig{MaxRetries: 5, RetryInterval: 1, Backoff: BackoffExponential, MaxBackoff: 7200}
+	if err := validateReconnectConfig(invalid); err == nil || !contains(err.Error(), "max_backoff") {
+		t.Errorf("Expected max_backoff range error, got: %v", err)
+	}
+}
+
+func TestLoadConfig_ReconnectDefaults(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "beacon.yaml")
+	configContent := `
+pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+reconnect:
+  backoff: linear
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Unset fields keep the original reporter behaviour
+	want := ReconnectConfig{MaxRetries: 3, RetryInterval: 1, Backoff: BackoffLinear, MaxBackoff: 60}
+	if cfg.Reconnect != want {
+		t.Errorf("Expected reconnect %+v, got %+v", want, cfg.Reconnect)
+	}
+}
+
+func TestLoadConfig_ICMPProbe(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "beacon.yaml")
 
@@ -1112,32 +1056,298 @@ pulse_server: "https://pulse.example.com"
 node_id: "us-east-01"
 node_name: "Test Node"
 probes:
-  - type: tcp_ping
-    target: "google.com"
-    port: 80
+  - type: icmp_ping
+    target: "10.0.0.1"
+    interval: 60
+    count: 10
+    timeout_seconds: 1
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Expected no error for icmp_ping probe without port, got: %v", err)
+	}
+	if len(cfg.Probes) != 1 || cfg.Probes[0].Type != "icmp_ping" || cfg.Probes[0].Port != 0 {
+		t.Errorf("Unexpected probes: %+v", cfg.Probes)
+	}
+}
+
+func TestLoadConfig_HTTPProbe(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "beacon.yaml")
+
+	configContent := `
+pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+probes:
+  - type: http_probe
+    target: "https://api.example.com/health"
+    interval: 60
+    count: 10
+    timeout_seconds: 5
+    expected_status: 204
+    body_regex: "^ok"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Expected no error for http_probe, got: %v", err)
+	}
+	probe := cfg.Probes[0]
+	if probe.Type != "http_probe" || probe.Options["expected_status"] != 204 || probe.Options["body_regex"] != "^ok" {
+		t.Errorf("Unexpected probe: %+v", probe)
+	}
+}
+
+func TestLoadConfig_DNSProbe(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "beacon.yaml")
+
+	configContent := `
+pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+probes:
+  - type: dns_probe
+    target: "10.0.0.53"
+    port: 53
+    query_name: "api.internal.example.com"
+    record_type: "A"
+    expected_answers: ["10.1.0.10", "10.1.0.11"]
+    interval: 60
+    count: 10
+    timeout_seconds: 2
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Expected no error for dns_probe, got: %v", err)
+	}
+	probe := cfg.Probes[0]
+	if probe.Options["query_name"] != "api.internal.example.com" || probe.Options["record_type"] != "A" || !reflect.DeepEqual(probe.Options["expected_answers"], []interface{}{"10.1.0.10", "10.1.0.11"}) {
+		t.Errorf("Unexpected probe: %+v", probe)
+	}
+}
+
+func TestLoadConfig_TLSProbe(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "beacon.yaml")
+
+	configContent := `
+pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+probes:
+  - type: tls_probe
+    target: "10.0.0.10"
+    port: 8443
+    server_name: "api.example.com"
     interval: 300
     count: 10
     timeout_seconds: 5
 `
-	err := os.WriteFile(configPath, []byte(configContent), 0644)
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
 	if err != nil {
+		t.Fatalf("Expected no error for tls_probe, got: %v", err)
+	}
+	if probe := cfg.Probes[0]; probe.Type != "tls_probe" || probe.Port != 8443 || probe.Options["server_name"] != "api.example.com" {
+		t.Errorf("Unexpected probe: %+v", probe)
+	}
+}
+
+func TestLoadConfig_PathProbe(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "beacon.yaml")
+
+	configContent := `
+pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+probes:
+  - type: path_probe
+    target: "8.8.8.8"
+    protocol: udp
+    max_hops: 20
+    interval: 300
+    count: 10
+    timeout_seconds: 2
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
 		t.Fatalf("Failed to create test config: %v", err)
 	}
 
 	cfg, err := LoadConfig(configPath)
 	if err != nil {
-		t.Errorf("Expected no error for valid hostname target, got: %v", err)
+		t.Fatalf("Expected no error for path_probe, got: %v", err)
 	}
+	if probe := cfg.Probes[0]; probe.Type != "path_probe" || probe.Port != 0 || probe.Options["protocol"] != "udp" || probe.Options["max_hops"] != 20 {
+		t.Errorf("Unexpected probe: %+v", probe)
+	}
+}
 
-	if cfg == nil {
-		t.Fatal("Expected config to be loaded, got nil")
+func TestLoadConfig_UDPResponder(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "beacon.yaml")
+
+	configContent := `
+pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+responder:
+  enabled: true
+probes:
+  - type: udp_ping
+    target: "10.0.0.20"
+    port: 7331
+    responder: true
+    one_way_delay: true
+    interval: 300
+    count: 10
+    timeout_seconds: 2
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
 	}
 
-	if len(cfg.Probes) != 1 {
-		t.Errorf("Expected 1 probe, got: %d", len(cfg.Probes))
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Expected no error for udp_ping responder probe, got: %v", err)
+	}
+	if !cfg.Responder.Enabled || cfg.Responder.Listen != ":7331" {
+		t.Errorf("Expected responder enabled on default address, got %+v", cfg.Responder)
 	}
+	if probe := cfg.Probes[0]; probe.Options["responder"] != true || probe.Options["one_way_delay"] != true {
+		t.Errorf("Unexpected probe: %+v", probe)
+	}
+}
 
-	if cfg.Probes[0].Target != "google.com" {
-		t.Errorf("Expected probe target to be 'google.com', got: %s", cfg.Probes[0].Target)
+func TestLoadConfig_Statistics(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "beacon.yaml")
+	configContent := `
+pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+statistics: [p95, p99, jitter_rfc3550]
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Expected no error for valid statistics, got: %v", err)
+	}
+	if strings.Join(cfg.Statistics, ",") != "p95,p99,jitter_rfc3550" {
+		t.Errorf("Expected statistics [p95 p99 jitter_rfc3550], got %v", cfg.Statistics)
+	}
+}
+
+func TestLoadConfig_Statistics_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "unknown statistic",
+			content: "statistics: [p95, p50]\n",
+			wantErr: "invalid statistic 'p50'",
+		},
+		{
+			name:    "duplicate statistic",
+			content: "statistics: [p95, p95]\n",
+			wantErr: "duplicate statistic 'p95'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "beacon.yaml")
+			configContent := `
+pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+` + tt.content
+			if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+				t.Fatalf("Failed to create test config: %v", err)
+			}
+
+			_, err := LoadConfig(configPath)
+			if err == nil {
+				t.Fatalf("Expected error containing %q, got nil", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_ReportSamples(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "beacon.yaml")
+	configContent := `
+pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+report_samples: true
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !cfg.ReportSamples {
+		t.Error("Expected report_samples to be enabled")
+	}
+}
+
+// TestValidate_SelfRegisterWithoutNodeID tests that a config without node_id
+// passes validation (as on hot reload) and self-registers
+func TestValidate_SelfRegisterWithoutNodeID(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "beacon.yaml")
+	configContent := `
+pulse_server: "http://localhost:8080"
+node_name: "Beacon East-01"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected config without node_id to be valid, got: %v", err)
+	}
+	if !cfg.SelfRegisters() {
+		t.Error("Expected config without node_id to self-register")
+	}
+
+	cfg.NodeID = "550e8400-e29b-41d4-a716-446655440000"
+	if cfg.SelfRegisters() {
+		t.Error("Expected config with node_id not to self-register")
+	}
+	cfg.AutoRegister = true
+	if !cfg.SelfRegisters() {
+		t.Error("Expected auto_register config to self-register")
 	}
 }
@@ -0,0 +1,426 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"beacon/internal/config"
+	_ "beacon/internal/probe" // Installs the probe type rules
+)
+
+func TestLoadConfig_ProbeInterval_OutOfRange(t *testing.T) {
+	// Test probe interval validation (should be 60-300)
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "beacon.yaml")
+
+	configContent := `
+pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+probes:
+  - type: tcp_ping
+    target: "8.8.8.8"
+    port: 80
+    interval: 500  # Out of range (>300)
+    count: 10
+    timeout: 5
+`
+	err := os.WriteFile(configPath, []byte(configContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	// Load config
+	cfg, err := config.LoadConfig(configPath)
+	if err == nil {
+		t.Error("Expected error for probe interval out of range, got nil")
+	}
+	// Note: This validation will be implemented in Task 2
+	_ = cfg // Use variable to avoid unused variable warning
+}
+
+func TestLoadConfig_ProbePort_OutOfRange(t *testing.T) {
+	// Test probe port validation (should be 1-65535)
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "beacon.yaml")
+
+	configContent := `
+pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+probes:
+  - type: tcp_ping
+    target: "8.8.8.8"
+    port: 70000  # Out of range (>65535)
+    interval: 300
+    count: 10
+    timeout: 5
+`
+	err := os.WriteFile(configPath, []byte(configContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	// Load config
+	cfg, err := config.LoadConfig(configPath)
+	if err == nil {
+		t.Error("Expected error for probe port out of range, got nil")
+	}
+	_ = cfg // Use variable to avoid unused variable warning
+}
+
+func TestLoadConfig_ErrorDetails_Validation(t *testing.T) {
+	// Test that validation errors are specific and helpful
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "beacon.yaml")
+
+	configContent := `pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+probes:
+  - type: tcp_ping
+    target: "8.8.8.8"
+    port: 70000  # Invalid port
+    interval: 300
+    count: 10
+    timeout: 5`
+	err := os.WriteFile(configPath, []byte(configContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	// Load config
+	_, err = config.LoadConfig(configPath)
+	if err == nil {
+		t.Error("Expected error for invalid port, got nil")
+	}
+
+	// Verify error message mentions the problem and expected range
+	errorMsg := err.Error()
+	// Should mention "port" and "1 and 65535"
+	portMentioned := strings.Contains(errorMsg, "port")
+	rangeMentioned := strings.Contains(errorMsg, "65535")
+	if !portMentioned {
+		t.Errorf("Expected error message to mention 'port', got: %s", errorMsg)
+	}
+	if !rangeMentioned {
+		t.Errorf("Expected error message to mention valid range, got: %s", errorMsg)
+	}
+}
+
+func TestLoadConfig_ProbeTarget_Invalid(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "beacon.yaml")
+
+	configContent := `
+pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+probes:
+  - type: tcp_ping
+    target: "invalid@host"
+    port: 80
+    interval: 300
+    count: 10
+    timeout: 5
+`
+	err := os.WriteFile(configPath, []byte(configContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err == nil {
+		t.Error("Expected error for invalid hostname with @ symbol, got nil")
+	}
+	_ = cfg // Use variable to avoid unused variable warning
+
+	errorMsg := err.Error()
+	if !strings.Contains(errorMsg, "invalid probe target") {
+		t.Errorf("Expected error message to mention invalid probe target, got: %s", errorMsg)
+	}
+}
+
+func TestLoadConfig_ICMPProbe_WithPort(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "beacon.yaml")
+
+	configContent := `
+pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+probes:
+  - type: icmp_ping
+    target: "10.0.0.1"
+    port: 80
+    interval: 60
+    count: 10
+    timeout_seconds: 1
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	_, err := config.LoadConfig(configPath)
+	if err == nil {
+		t.Fatal("Expected error for icmp_ping probe with a port, got nil")
+	}
+	if !strings.Contains(err.Error(), "port is not supported") {
+		t.Errorf("Expected port error, got: %v", err)
+	}
+}
+
+func TestLoadConfig_HTTPProbe_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		probe  string
+		errMsg string
+	}{
+		{
+			name:   "target without scheme",
+			probe:  `target: "api.example.com/health"`,
+			errMsg: "must be an http:// or https:// URL",
+		},
+		{
+			name:   "unsupported scheme",
+			probe:  `target: "ftp://api.example.com"`,
+			errMsg: "must be an http:// or https:// URL",
+		},
+		{
+			name: "port field",
+			probe: `target: "https://api.example.com"
+    port: 443`,
+			errMsg: "port is not supported",
+		},
+		{
+			name: "invalid expected status",
+			probe: `target: "https://api.example.com"
+    expected_status: 99`,
+			errMsg: "invalid expected status",
+		},
+		{
+			name: "invalid body regex",
+			probe: `target: "https://api.example.com"
+    body_regex: "("`,
+			errMsg: "invalid body regex",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "beacon.yaml")
+			configContent := `
+pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+probes:
+  - type: http_probe
+    ` + tt.probe + `
+    interval: 60
+    count: 10
+    timeout_seconds: 5
+`
+			if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+				t.Fatalf("Failed to create test config: %v", err)
+			}
+
+			_, err := config.LoadConfig(configPath)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got: %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_DNSProbe_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		probe  string
+		errMsg string
+	}{
+		{
+			name: "missing query name",
+			probe: `port: 53
+    record_type: "A"`,
+			errMsg: "invalid query name",
+		},
+		{
+			name: "unsupported record type",
+			probe: `port: 53
+    query_name: "example.com"
+    record_type: "SRV"`,
+			errMsg: "invalid record type",
+		},
+		{
+			name:   "missing resolver port",
+			probe:  `query_name: "example.com"`,
+			errMsg: "invalid port 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "beacon.yaml")
+			configContent := `
+pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+probes:
+  - type: dns_probe
+    target: "10.0.0.53"
+    ` + tt.probe + `
+    interval: 60
+    count: 10
+    timeout_seconds: 2
+`
+			if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+				t.Fatalf("Failed to create test config: %v", err)
+			}
+
+			_, err := config.LoadConfig(configPath)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got: %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_ServerNameOnNonTLSProbe(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "beacon.yaml")
+
+	configContent := `
+pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+probes:
+  - type: tcp_ping
+    target: "10.0.0.10"
+    port: 443
+    server_name: "api.example.com"
+    interval: 300
+    count: 10
+    timeout_seconds: 5
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	_, err := config.LoadConfig(configPath)
+	if err == nil {
+		t.Fatal("Expected error for server_name on tcp_ping, got nil")
+	}
+	if !strings.Contains(err.Error(), "server_name is not supported by tcp_ping") {
+		t.Errorf("Expected server_name error, got: %v", err)
+	}
+}
+
+func TestLoadConfig_PathProbe_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		probe   string
+		wantErr string
+	}{
+		{
+			name:    "unknown protocol",
+			probe:   "type: path_probe\n    target: \"8.8.8.8\"\n    protocol: tcp",
+			wantErr: "invalid protocol",
+		},
+		{
+			name:    "max_hops out of range",
+			probe:   "type: path_probe\n    target: \"8.8.8.8\"\n    max_hops: 65",
+			wantErr: "invalid max_hops",
+		},
+		{
+			name:    "port on path_probe",
+			probe:   "type: path_probe\n    target: \"8.8.8.8\"\n    port: 80",
+			wantErr: "port is not supported",
+		},
+		{
+			name:    "max_hops on another probe type",
+			probe:   "type: icmp_ping\n    target: \"8.8.8.8\"\n    max_hops: 10",
+			wantErr: "max_hops is not supported by icmp_ping",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "beacon.yaml")
+			configContent := `
+pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+probes:
+  - ` + tt.probe + `
+    interval: 300
+    count: 10
+    timeout_seconds: 2
+`
+			if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+				t.Fatalf("Failed to create test config: %v", err)
+			}
+
+			_, err := config.LoadConfig(configPath)
+			if err == nil {
+				t.Fatalf("Expected error containing %q, got nil", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_UDPResponder_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "one_way_delay without responder",
+			content: "probes:\n  - type: udp_ping\n    target: \"10.0.0.20\"\n    port: 7331\n    one_way_delay: true\n    interval: 300\n    count: 10\n    timeout_seconds: 2\n",
+			wantErr: "one_way_delay requires a responder",
+		},
+		{
+			name:    "responder on another probe type",
+			content: "probes:\n  - type: tcp_ping\n    target: \"10.0.0.20\"\n    port: 7331\n    responder: true\n    interval: 300\n    count: 10\n    timeout_seconds: 2\n",
+			wantErr: "responder is not supported by tcp_ping",
+		},
+		{
+			name:    "invalid listen address",
+			content: "responder:\n  enabled: true\n  listen: \"7331\"\n",
+			wantErr: "invalid responder.listen",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "beacon.yaml")
+			configContent := `
+pulse_server: "https://pulse.example.com"
+node_id: "us-east-01"
+node_name: "Test Node"
+` + tt.content
+			if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+				t.Fatalf("Failed to create test config: %v", err)
+			}
+
+			_, err := config.LoadConfig(configPath)
+			if err == nil {
+				t.Fatalf("Expected error containing %q, got nil", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
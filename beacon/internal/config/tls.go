@@ -0,0 +1,51 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TLSConfig represents TLS settings for connections to Pulse.
+// All fields are optional; without them the system trust store is used.
+type TLSConfig struct {
+	CAFile     string `mapstructure:"ca_file" yaml:"ca_file"`         // PEM CA bundle used to verify Pulse
+	CertFile   string `mapstructure:"cert_file" yaml:"cert_file"`     // PEM client certificate for mTLS
+	KeyFile    string `mapstructure:"key_file" yaml:"key_file"`       // PEM client private key for mTLS
+	ServerName string `mapstructure:"server_name" yaml:"server_name"` // Overrides the name verified in the Pulse certificate
+}
+
+// ClientTLSConfig builds the tls.Config used by the Pulse API clients
+func (t TLSConfig) ClientTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12, // Enforce TLS 1.2 or higher (NFR-SEC-001)
+		ServerName: t.ServerName,
+	}
+
+	if t.CAFile != "" {
+		caPEM, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tls.ca_file: %w", err)
+		}
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in tls.ca_file %s", t.CAFile)
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return nil, errors.New("tls.cert_file and tls.key_file must be set together")
+	}
+	if t.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -46,7 +49,14 @@ func NewFileWatcher(path string, initialConfig *Config, logger *logrus.Logger) (
 	return fw, nil
 }
 
-// Start begins monitoring the configuration file for changes
+// Start begins monitoring the configuration file for changes.
+//
+// Editors and config-management tools replace the file by rename (vim
+// saves, atomic mv, Kubernetes ConfigMap `..data` symlink swaps), which
+// drops a watch on the file itself. Start therefore watches the parent
+// directory, plus the directory of the symlink target when the path is a
+// symlink, and reloads when the file is written, recreated, or the path
+// resolves to a different file.
 func (fw *FileWatcher) Start(ctx context.Context) error {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -54,24 +64,31 @@ func (fw *FileWatcher) Start(ctx context.Context) error {
 	}
 	defer watcher.Close()
 
-	if err := watcher.Add(fw.path); err != nil {
-		return fmt.Errorf("failed to watch config file: %w", err)
+	configPath := filepath.Clean(fw.path)
+	configDir := filepath.Dir(configPath)
+	if err := watcher.Add(configDir); err != nil {
+		return fmt.Errorf("failed to watch config directory: %w", err)
 	}
 
+	// Follow the symlink target, if any
+	realPath := realConfigPath(configPath)
+	targetDir := fw.watchTarget(watcher, configDir, "", realPath)
+
 	fw.logger.WithFields(logrus.Fields{
-		"path":    fw.path,
-		"version": fw.version,
+		"path":      fw.path,
+		"real_path": realPath,
+		"version":   fw.version,
 	}).Info("Config watcher started")
 
-	var timer *time.Timer
-
 	for {
 		select {
 		case <-ctx.Done():
 			fw.logger.Info("Config watcher stopped")
-			if timer != nil {
-				timer.Stop()
+			fw.timerMu.Lock()
+			if fw.timer != nil {
+				fw.timer.Stop()
 			}
+			fw.timerMu.Unlock()
 			return nil
 
 		case event, ok := <-watcher.Events:
@@ -79,24 +96,33 @@ func (fw *FileWatcher) Start(ctx context.Context) error {
 				return nil
 			}
 
-			// Only handle Write and Create events
-			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+			// Other files in the watched directories are ignored unless
+			// they change what the config path resolves to
+			name := filepath.Clean(event.Name)
+			newRealPath := realConfigPath(configPath)
+			written := (name == configPath || name == realPath) && event.Op&(fsnotify.Write|fsnotify.Create) != 0
+			swapped := newRealPath != realPath
+			if !written && !swapped {
 				continue
 			}
 
-			fw.logger.WithField("event", event.Op.String()).Debug("File event detected")
+			fw.logger.WithFields(logrus.Fields{
+				"event":     event.Op.String(),
+				"name":      event.Name,
+				"real_path": newRealPath,
+			}).Debug("File event detected")
 
-			// Debounce: reset timer
-			fw.timerMu.Lock()
-			if fw.timer != nil {
-				fw.timer.Stop()
+			if swapped {
+				// Re-establish the target watch after Remove/Rename
+				targetDir = fw.watchTarget(watcher, configDir, targetDir, newRealPath)
+				realPath = newRealPath
 			}
-			fw.timer = time.AfterFunc(fw.debounce, func() {
-				if err := fw.reloadConfig(); err != nil {
-					fw.logger.WithError(err).Error("Failed to reload config")
-				}
-			})
-			fw.timerMu.Unlock()
+			if realPath == "" {
+				// Removed or mid-replacement: reload once the file is back
+				continue
+			}
+
+			fw.scheduleReload()
 
 		case err, ok := <-watcher.Errors:
 			if !ok {
@@ -107,6 +133,57 @@ func (fw *FileWatcher) Start(ctx context.Context) error {
 	}
 }
 
+// scheduleReload reloads the configuration after the debounce delay,
+// restarting the delay on every call
+func (fw *FileWatcher) scheduleReload() {
+	fw.timerMu.Lock()
+	defer fw.timerMu.Unlock()
+
+	if fw.timer != nil {
+		fw.timer.Stop()
+	}
+	fw.timer = time.AfterFunc(fw.debounce, func() {
+		if err := fw.reloadConfig(); err != nil {
+			fw.logger.WithError(err).Error("Failed to reload config")
+		}
+	})
+}
+
+// watchTarget watches the directory of the symlink target realPath when it
+// lies outside configDir, replacing the previous target directory watch.
+// It returns the watched target directory, or "" if none is needed.
+func (fw *FileWatcher) watchTarget(watcher *fsnotify.Watcher, configDir, oldDir, realPath string) string {
+	newDir := ""
+	if realPath != "" && filepath.Dir(realPath) != configDir {
+		newDir = filepath.Dir(realPath)
+	}
+	if newDir == oldDir {
+		return oldDir
+	}
+
+	// The old directory may be gone already (e.g. a ConfigMap revision)
+	if oldDir != "" {
+		_ = watcher.Remove(oldDir)
+	}
+	if newDir != "" {
+		if err := watcher.Add(newDir); err != nil {
+			fw.logger.WithError(err).WithField("dir", newDir).Warn("Failed to watch config symlink target directory")
+			return ""
+		}
+	}
+	return newDir
+}
+
+// realConfigPath returns the file path resolves to after following
+// symlinks, or "" if it does not exist
+func realConfigPath(path string) string {
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return ""
+	}
+	return realPath
+}
+
 // reloadConfig reloads the configuration from file
 func (fw *FileWatcher) reloadConfig() error {
 	fw.logger.WithFields(logrus.Fields{
@@ -167,11 +244,9 @@ func (fw *FileWatcher) reloadConfig() error {
 		fw.logger.WithField("changes", changes).Debug("Detailed changes")
 	}
 
-	// Check for restart-required fields and warn
-	for _, change := range changes {
-		if strings.Contains(change, "node_id:") || strings.Contains(change, "node_name:") {
-			fw.logger.Warn("Configuration change requires Beacon restart to take full effect")
-		}
+	// Report settings that are only applied at startup
+	if restartRequired := RestartRequiredChanges(oldConfig, newConfig); len(restartRequired) > 0 {
+		fw.logger.WithField("restart_required", restartRequired).Warn("Configuration change requires Beacon restart to take full effect")
 	}
 
 	// Store old config for potential rollback
@@ -230,6 +305,60 @@ func (fw *FileWatcher) diffConfig(old, new *Config) []string {
 		changes = append(changes, fmt.Sprintf("node_name: %s -> %s (WARNING: requires restart)", old.NodeName, new.NodeName))
 	}
 
+	// Check reconnect settings (applied to the reporter without restart)
+	if old.Reconnect != new.Reconnect {
+		changes = append(changes, fmt.Sprintf("reconnect: max_retries=%d retry_interval=%ds backoff=%s max_backoff=%ds -> max_retries=%d retry_interval=%ds backoff=%s max_backoff=%ds",
+			old.Reconnect.MaxRetries, old.Reconnect.RetryInterval, old.Reconnect.Backoff, old.Reconnect.MaxBackoff,
+			new.Reconnect.MaxRetries, new.Reconnect.RetryInterval, new.Reconnect.Backoff, new.Reconnect.MaxBackoff))
+	}
+
+	// Check selected statistics (applied to reporting and metrics without restart)
+	if strings.Join(old.Statistics, ",") != strings.Join(new.Statistics, ",") {
+		changes = append(changes, fmt.Sprintf("statistics: [%s] -> [%s]", strings.Join(old.Statistics, ", "), strings.Join(new.Statistics, ", ")))
+	}
+
+	// Check raw sample reporting (applied to reporting without restart)
+	if old.ReportSamples != new.ReportSamples {
+		changes = append(changes, fmt.Sprintf("report_samples: %v -> %v", old.ReportSamples, new.ReportSamples))
+	}
+
+	// Check responder (started once at startup, requires restart warning)
+	if old.Responder != new.Responder {
+		changes = append(changes, fmt.Sprintf("responder: enabled=%v listen=%s -> enabled=%v listen=%s (WARNING: requires restart)",
+			old.Responder.Enabled, old.Responder.Listen, new.Responder.Enabled, new.Responder.Listen))
+	}
+
+	// Check log level (applied to the logger without restart); debug_mode
+	// is folded into the effective level by LoadConfig
+	if old.LogLevel != new.LogLevel {
+		changes = append(changes, fmt.Sprintf("log_level: %s -> %s", old.LogLevel, new.LogLevel))
+	}
+	if old.DebugMode != new.DebugMode {
+		changes = append(changes, fmt.Sprintf("debug_mode: %v -> %v", old.DebugMode, new.DebugMode))
+	}
+
+	// Check metrics port (the metrics server moves without restart)
+	if old.MetricsPort != new.MetricsPort {
+		changes = append(changes, fmt.Sprintf("metrics_port: %d -> %d", old.MetricsPort, new.MetricsPort))
+	}
+
+	// Check resource monitor thresholds (applied to the monitor without restart)
+	if old.ResourceMonitor.Thresholds != new.ResourceMonitor.Thresholds ||
+		old.ResourceMonitor.Degradation != new.ResourceMonitor.Degradation ||
+		old.ResourceMonitor.Alerting != new.ResourceMonitor.Alerting {
+		changes = append(changes, "resource_monitor: thresholds, degradation or alerting changed")
+	}
+
+	// Remaining settings applied only at startup
+	for _, name := range RestartRequiredChanges(old, new) {
+		switch name {
+		case "pulse_server", "node_id", "node_name", "responder":
+			// Listed above with their values
+		default:
+			changes = append(changes, fmt.Sprintf("%s changed (WARNING: requires restart)", name))
+		}
+	}
+
 	// Check probes in detail (not just count)
 	oldLen := len(old.Probes)
 	newLen := len(new.Probes)
@@ -260,6 +389,9 @@ func (fw *FileWatcher) diffConfig(old, new *Config) []string {
 			oldProbe := old.Probes[i]
 			newProbe := new.Probes[i]
 
+			if oldProbe.ID != newProbe.ID {
+				changes = append(changes, fmt.Sprintf("probes[%d]: id %s -> %s", i, oldProbe.ID, newProbe.ID))
+			}
 			if oldProbe.Type != newProbe.Type {
 				changes = append(changes, fmt.Sprintf("probes[%d]: type %s -> %s", i, oldProbe.Type, newProbe.Type))
 			}
@@ -278,12 +410,81 @@ func (fw *FileWatcher) diffConfig(old, new *Config) []string {
 			if oldProbe.Count != newProbe.Count {
 				changes = append(changes, fmt.Sprintf("probes[%d]: count %d -> %d", i, oldProbe.Count, newProbe.Count))
 			}
+			changes = append(changes, diffProbeOptions(i, oldProbe.Options, newProbe.Options)...)
+		}
+	}
+
+	return changes
+}
+
+// diffProbeOptions describes the type-specific options changed between two
+// versions of probe i, in option name order
+func diffProbeOptions(i int, old, new map[string]interface{}) []string {
+	names := make([]string, 0, len(old)+len(new))
+	for name := range old {
+		names = append(names, name)
+	}
+	for name := range new {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
 		}
 	}
+	sort.Strings(names)
 
+	var changes []string
+	for _, name := range names {
+		oldValue, newValue := old[name], new[name]
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, fmt.Sprintf("probes[%d]: %s %v -> %v", i, name, oldValue, newValue))
+		}
+	}
 	return changes
 }
 
+// RestartRequiredChanges returns the settings changed between old and new
+// that are only applied at startup and take effect after a Beacon restart.
+// All other settings are applied live by the subsystem that owns them.
+func RestartRequiredChanges(old, new *Config) []string {
+	var fields []string
+	check := func(name string, changed bool) {
+		if changed {
+			fields = append(fields, name)
+		}
+	}
+
+	// Identity, registration and the Pulse connection
+	check("pulse_server", old.PulseServer != new.PulseServer)
+	check("node_id", old.NodeID != new.NodeID)
+	check("node_name", old.NodeName != new.NodeName)
+	check("region", old.Region != new.Region)
+	check("tags", strings.Join(old.Tags, ",") != strings.Join(new.Tags, ","))
+	check("auto_register", old.AutoRegister != new.AutoRegister)
+	check("state_file", old.StateFile != new.StateFile)
+	check("node_ip", old.NodeIP != new.NodeIP)
+	check("api_token", old.APIToken != new.APIToken)
+	check("enrollment_token", old.EnrollmentToken != new.EnrollmentToken)
+	check("tls", old.TLS != new.TLS)
+
+	// Subsystems started once
+	check("probe_sync", old.ProbeSync != new.ProbeSync)
+	check("outbox", old.Outbox != new.Outbox)
+	check("responder", old.Responder != new.Responder)
+	check("metrics_enabled", old.MetricsEnabled != new.MetricsEnabled)
+	check("metrics_update_seconds", old.MetricsUpdateSeconds != new.MetricsUpdateSeconds)
+	check("resource_monitor.enabled", old.ResourceMonitor.Enabled != new.ResourceMonitor.Enabled)
+	check("resource_monitor.check_interval_seconds", old.ResourceMonitor.CheckIntervalSeconds != new.ResourceMonitor.CheckIntervalSeconds)
+
+	// Log output and rotation
+	check("log_file", old.LogFile != new.LogFile)
+	check("log_max_size", old.LogMaxSize != new.LogMaxSize)
+	check("log_max_age", old.LogMaxAge != new.LogMaxAge)
+	check("log_max_backups", old.LogMaxBackups != new.LogMaxBackups)
+	check("log_compress", old.LogCompress != new.LogCompress)
+	check("log_to_console", old.LogToConsole != new.LogToConsole)
+
+	return fields
+}
+
 // GetConfig returns the current configuration (thread-safe)
 func (fw *FileWatcher) GetConfig() *Config {
 	return fw.config.Load().(*Config)
@@ -295,7 +496,11 @@ func (fw *FileWatcher) GetConfigPath() string {
 }
 
 // OnReload registers a callback to be invoked when config is reloaded
-// The callback receives the new config and a list of changes
+// The callback receives the new config and a list of changes.
+// Callbacks run in registration order. An error rolls back the config and
+// skips the remaining callbacks, but does not undo the callbacks that already
+// ran, so only the first callback should fail; later ones log and return nil.
+// Register all callbacks before calling Start.
 func (fw *FileWatcher) OnReload(callback func(*Config, []string) error) {
 	fw.mu.Lock()
 	defer fw.mu.Unlock()
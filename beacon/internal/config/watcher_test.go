@@ -540,3 +540,339 @@ probes:
 		t.Fatal("Start() did not return")
 	}
 }
+
+// TestFileWatcher_ReconnectReload tests that a reconnect-only change triggers
+// reload callbacks so retry settings can be tuned without restart
+func TestFileWatcher_ReconnectReload(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfgPath := filepath.Join(tmpDir, "beacon.yaml")
+
+	baseConfig := `pulse_server: http://localhost:8080
+node_id: test-node-1
+node_name: Test Node 1
+`
+	if err := os.WriteFile(cfgPath, []byte(baseConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(os.Stderr)
+
+	watcher, err := NewFileWatcher(cfgPath, cfg, logger)
+	if err != nil {
+		t.Fatalf("Failed to create file watcher: %v", err)
+	}
+
+	reloaded := make(chan []string, 1)
+	var lastConfig *Config
+	watcher.OnReload(func(newConfig *Config, changes []string) error {
+		lastConfig = newConfig
+		reloaded <- changes
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	go watcher.Start(ctx)
+	time.Sleep(500 * time.Millisecond)
+
+	// Change only the reconnect section
+	newConfig := baseConfig + `reconnect:
+  max_retries: 5
+  retry_interval: 2
+  backoff: exponential_jitter
+  max_backoff: 30
+`
+	if err := os.WriteFile(cfgPath, []byte(newConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case changes := <-reloaded:
+		if len(changes) != 1 || !contains(changes[0], "reconnect:") {
+			t.Errorf("Expected a single reconnect change, got %v", changes)
+		}
+		want := ReconnectConfig{MaxRetries: 5, RetryInterval: 2, Backoff: BackoffExponentialJitter, MaxBackoff: 30}
+		if lastConfig.Reconnect != want {
+			t.Errorf("Expected reconnect %+v, got %+v", want, lastConfig.Reconnect)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Reconnect change did not trigger a reload")
+	}
+}
+
+// TestRestartRequiredChanges tests that only settings applied at startup are
+// reported as requiring a restart
+func TestRestartRequiredChanges(t *testing.T) {
+	old := &Config{
+		NodeID:      "node-1",
+		LogLevel:    "INFO",
+		LogFile:     "/var/log/beacon.log",
+		MetricsPort: 9100,
+		Tags:        []string{"a"},
+	}
+	old.ResourceMonitor.Thresholds.CPUMicrocores = 100
+
+	// Live settings only
+	live := *old
+	live.LogLevel = "DEBUG"
+	live.DebugMode = true
+	live.MetricsPort = 9200
+	live.ResourceMonitor.Thresholds.CPUMicrocores = 200
+	if restart := RestartRequiredChanges(old, &live); len(restart) != 0 {
+		t.Errorf("Expected no restart-required changes, got %v", restart)
+	}
+
+	// Startup-only settings
+	startup := live
+	startup.NodeID = "node-2"
+	startup.LogFile = "/tmp/beacon.log"
+	startup.Tags = []string{"a", "b"}
+	startup.ResourceMonitor.CheckIntervalSeconds = 30
+	restart := RestartRequiredChanges(old, &startup)
+	want := []string{"node_id", "tags", "resource_monitor.check_interval_seconds", "log_file"}
+	if fmt.Sprint(restart) != fmt.Sprint(want) {
+		t.Errorf("Expected %v, got %v", want, restart)
+	}
+
+	changes := (&FileWatcher{}).diffConfig(old, &startup)
+	for _, expected := range []string{"log_level: INFO -> DEBUG", "metrics_port: 9100 -> 9200", "resource_monitor: thresholds", "log_file changed (WARNING: requires restart)"} {
+		found := false
+		for _, change := range changes {
+			if contains(change, expected) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected change %q in %v", expected, changes)
+		}
+	}
+}
+
+// TestDiffConfigProbeOptions tests that changed type-specific probe options
+// are reported by name
+func TestDiffConfigProbeOptions(t *testing.T) {
+	old := &Config{Probes: []ProbeConfig{{
+		Type:    "http_probe",
+		Target:  "https://api.example.com/health",
+		Options: map[string]interface{}{"expected_status": 200, "body_contains": "ok"},
+	}}}
+	new := &Config{Probes: []ProbeConfig{{
+		Type:    "http_probe",
+		Target:  "https://api.example.com/health",
+		Options: map[string]interface{}{"expected_status": 204, "body_regex": "^ok"},
+	}}}
+
+	changes := (&FileWatcher{}).diffConfig(old, new)
+	want := []string{
+		"probes[0]: body_contains ok -> <nil>",
+		"probes[0]: body_regex <nil> -> ^ok",
+		"probes[0]: expected_status 200 -> 204",
+	}
+	if fmt.Sprint(changes) != fmt.Sprint(want) {
+		t.Errorf("Expected %v, got %v", want, changes)
+	}
+
+	if changes := (&FileWatcher{}).diffConfig(old, old); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+}
+
+// startReplaceTestWatcher starts a watcher on cfgPath with a short debounce
+// and returns the node names of successfully reloaded configurations
+func startReplaceTestWatcher(t *testing.T, cfgPath string) <-chan string {
+	t.Helper()
+
+	cfg, err := LoadConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(os.Stderr)
+	logger.SetLevel(logrus.DebugLevel)
+
+	watcher, err := NewFileWatcher(cfgPath, cfg, logger)
+	if err != nil {
+		t.Fatalf("Failed to create file watcher: %v", err)
+	}
+	watcher.debounce = 100 * time.Millisecond
+
+	reloaded := make(chan string, 10)
+	watcher.OnReload(func(newConfig *Config, changes []string) error {
+		reloaded <- newConfig.NodeName
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go watcher.Start(ctx)
+	time.Sleep(200 * time.Millisecond)
+
+	return reloaded
+}
+
+// replaceTestConfig returns a minimal valid config with the given node name
+func replaceTestConfig(nodeName string) []byte {
+	return []byte(fmt.Sprintf("pulse_server: http://localhost:8080\nnode_id: test-node-1\nnode_name: %s\n", nodeName))
+}
+
+// expectReload waits for a reload that applied nodeName
+func expectReload(t *testing.T, reloaded <-chan string, nodeName string) {
+	t.Helper()
+
+	select {
+	case got := <-reloaded:
+		if got != nodeName {
+			t.Errorf("Expected node name %q after reload, got %q", nodeName, got)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("Replacing the config with node name %q did not trigger a reload", nodeName)
+	}
+}
+
+// TestFileWatcher_VimSave tests reloads after vim-style saves, which rename
+// the file to a backup, write a new file and delete the backup
+func TestFileWatcher_VimSave(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfgPath := filepath.Join(tmpDir, "beacon.yaml")
+	if err := os.WriteFile(cfgPath, replaceTestConfig("Node 0"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := startReplaceTestWatcher(t, cfgPath)
+
+	// Several saves: the watch must survive each replacement
+	for i := 1; i <= 3; i++ {
+		nodeName := fmt.Sprintf("Node %d", i)
+		backup := cfgPath + "~"
+		if err := os.Rename(cfgPath, backup); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(cfgPath, replaceTestConfig(nodeName), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Remove(backup); err != nil {
+			t.Fatal(err)
+		}
+
+		expectReload(t, reloaded, nodeName)
+	}
+}
+
+// TestFileWatcher_AtomicRename tests reloads after a new file is written
+// next to the config and moved over it
+func TestFileWatcher_AtomicRename(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfgPath := filepath.Join(tmpDir, "beacon.yaml")
+	if err := os.WriteFile(cfgPath, replaceTestConfig("Node 0"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := startReplaceTestWatcher(t, cfgPath)
+
+	for i := 1; i <= 3; i++ {
+		nodeName := fmt.Sprintf("Node %d", i)
+		tmpPath := filepath.Join(tmpDir, ".beacon.yaml.tmp")
+		if err := os.WriteFile(tmpPath, replaceTestConfig(nodeName), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(tmpPath, cfgPath); err != nil {
+			t.Fatal(err)
+		}
+
+		expectReload(t, reloaded, nodeName)
+	}
+}
+
+// TestFileWatcher_ConfigMapSwap tests reloads after Kubernetes ConfigMap
+// updates, which write a new timestamped directory and atomically swap the
+// `..data` symlink the config file points through
+func TestFileWatcher_ConfigMapSwap(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfgPath := filepath.Join(tmpDir, "beacon.yaml")
+
+	// Layout created by the kubelet:
+	// beacon.yaml -> ..data/beacon.yaml, ..data -> ..<revision>
+	writeRevision := func(revision, nodeName string) {
+		revisionDir := filepath.Join(tmpDir, revision)
+		if err := os.Mkdir(revisionDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(revisionDir, "beacon.yaml"), replaceTestConfig(nodeName), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeRevision("..rev_0", "Node 0")
+	if err := os.Symlink("..rev_0", filepath.Join(tmpDir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("..data", "beacon.yaml"), cfgPath); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := startReplaceTestWatcher(t, cfgPath)
+
+	for i := 1; i <= 3; i++ {
+		nodeName := fmt.Sprintf("Node %d", i)
+		revision := fmt.Sprintf("..rev_%d", i)
+		writeRevision(revision, nodeName)
+
+		// Swap ..data atomically, then drop the previous revision
+		tmpLink := filepath.Join(tmpDir, "..data_tmp")
+		if err := os.Symlink(revision, tmpLink); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(tmpLink, filepath.Join(tmpDir, "..data")); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.RemoveAll(filepath.Join(tmpDir, fmt.Sprintf("..rev_%d", i-1))); err != nil {
+			t.Fatal(err)
+		}
+
+		expectReload(t, reloaded, nodeName)
+	}
+}
+
+// TestFileWatcher_SymlinkTarget tests that writes to the target of a
+// symlinked config in another directory trigger a reload
+func TestFileWatcher_SymlinkTarget(t *testing.T) {
+	targetDir := t.TempDir()
+	targetPath := filepath.Join(targetDir, "beacon.yaml")
+	if err := os.WriteFile(targetPath, replaceTestConfig("Node 0"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfgPath := filepath.Join(t.TempDir(), "beacon.yaml")
+	if err := os.Symlink(targetPath, cfgPath); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := startReplaceTestWatcher(t, cfgPath)
+
+	// Edit the target in place
+	if err := os.WriteFile(targetPath, replaceTestConfig("Node 1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expectReload(t, reloaded, "Node 1")
+
+	// Repoint the symlink to a file in yet another directory
+	newTargetPath := filepath.Join(t.TempDir(), "beacon.yaml")
+	if err := os.WriteFile(newTargetPath, replaceTestConfig("Node 2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tmpLink := cfgPath + ".tmp"
+	if err := os.Symlink(newTargetPath, tmpLink); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmpLink, cfgPath); err != nil {
+		t.Fatal(err)
+	}
+	expectReload(t, reloaded, "Node 2")
+
+	// The new target is followed
+	if err := os.WriteFile(newTargetPath, replaceTestConfig("Node 3"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expectReload(t, reloaded, "Node 3")
+}
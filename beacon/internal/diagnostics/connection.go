@@ -2,6 +2,8 @@ package diagnostics
 
 import (
 	"time"
+
+	"beacon/internal/outbox"
 )
 
 // ConnectionStatus contains connection retry status information
@@ -17,21 +19,27 @@ type ConnectionStatus struct {
 	OldestQueuedItem *time.Time `json:"oldest_queued_item,omitempty"`
 }
 
-// collectConnectionStatus collects connection status information
-// NOTE: This requires integration with reporter.Reporter to get actual connection status.
-// For Story 3.10, this returns placeholder values indicating the feature is partially implemented.
-// Future work: Integrate with reporter.Reporter.GetConnectionStatus() method.
+// collectConnectionStatus collects heartbeat upload status from the reporter
+// connection state. Queue fields are read from the heartbeat outbox file
+// shared with the running daemon.
 func (c *collector) collectConnectionStatus() (*ConnectionStatus, error) {
+	state := c.connState.ConnectionState()
 	status := &ConnectionStatus{
-		Status:           "unknown",
-		LastSuccess:      nil,
-		LastFailure:      nil,
-		FailureReason:    "feature requires reporter integration",
-		RetryCount:       0,
-		BackoffSeconds:   0,
-		NextRetry:        nil,
-		QueueSize:        0,
-		OldestQueuedItem: nil,
+		Status:         state.Status,
+		LastSuccess:    state.LastSuccess,
+		LastFailure:    state.LastFailure,
+		FailureReason:  state.FailureReason,
+		RetryCount:     state.RetryCount,
+		BackoffSeconds: int(state.Backoff.Seconds()),
+		NextRetry:      state.NextRetry,
+	}
+
+	// An unreadable outbox (e.g. owned by the daemon user) leaves the queue fields empty
+	if c.cfg.Outbox.Enabled {
+		if stats, err := outbox.ReadStats(c.cfg.Outbox.Path); err == nil {
+			status.QueueSize = stats.Size
+			status.OldestQueuedItem = stats.Oldest
+		}
 	}
 
 	return status, nil
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"beacon/internal/config"
+	"beacon/internal/reporter"
 )
 
 // DiagnosticInfo contains all diagnostic information
@@ -40,13 +41,22 @@ type Collector interface {
 type collector struct {
 	cfg      *config.Config
 	startTime time.Time
+	connState reporter.ConnectionStateProvider
 }
 
-// NewCollector creates a new diagnostic information collector
+// NewCollector creates a new diagnostic information collector.
+// Connection status is read from the status file of the running beacon.
 func NewCollector(cfg *config.Config) Collector {
+	return NewCollectorWithConnectionState(cfg, reporter.ConnectionStatusFile(reporter.ConnectionStatusPath(cfg)))
+}
+
+// NewCollectorWithConnectionState creates a collector reading connection
+// status from provider (e.g. an in-process HeartbeatReporter)
+func NewCollectorWithConnectionState(cfg *config.Config, provider reporter.ConnectionStateProvider) Collector {
 	return &collector{
 		cfg:       cfg,
 		startTime: time.Now(),
+		connState: provider,
 	}
 }
 
@@ -148,6 +158,9 @@ func (c *collector) CollectPretty() (string, error) {
 		connStatus += fmt.Sprintf("Next Retry: %s\n", info.Diagnostics.ConnectionStatus.NextRetry.Format(time.RFC3339))
 	}
 	connStatus += fmt.Sprintf("Queue Size: %d\n", info.Diagnostics.ConnectionStatus.QueueSize)
+	if info.Diagnostics.ConnectionStatus.OldestQueuedItem != nil {
+		connStatus += fmt.Sprintf("Oldest Queued Item: %s\n", info.Diagnostics.ConnectionStatus.OldestQueuedItem.Format(time.RFC3339))
+	}
 
 	// Format probe tasks
 	probeInfo := fmt.Sprintf("Total Tasks: %d\n", info.Diagnostics.ProbeTasks.TotalTasks)
@@ -68,6 +68,31 @@ func InitLogger(cfg *config.Config) error {
 	return nil
 }
 
+// ApplyConfig applies the hot-reloadable logging settings of a reloaded
+// configuration: the log level, which debug_mode forces to DEBUG. Log file and
+// rotation settings are applied at startup only.
+func ApplyConfig(cfg *config.Config) error {
+	level, err := logrus.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		return fmt.Errorf("invalid log level %s: %w", cfg.LogLevel, err)
+	}
+
+	oldLevel := Logger.GetLevel()
+	if level == oldLevel {
+		return nil
+	}
+	Logger.SetLevel(level)
+
+	// Logged at the higher of both levels so the change is visible either way
+	entry := Logger.WithFields(logrus.Fields{"old_level": oldLevel.String(), "level": level.String()})
+	if level < oldLevel {
+		entry.Log(level, "Log level changed")
+	} else {
+		entry.Log(oldLevel, "Log level changed")
+	}
+	return nil
+}
+
 // WithFields creates a logger entry with structured fields
 func WithFields(fields map[string]interface{}) *logrus.Entry {
 	return Logger.WithFields(fields)
@@ -423,3 +423,52 @@ func TestLogToConsole(t *testing.T) {
 
 	Close()
 }
+
+// TestApplyConfig_LogLevel tests that a reloaded log level applies without
+// re-initializing the logger
+func TestApplyConfig_LogLevel(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "beacon.log")
+	cfg := &config.Config{
+		LogLevel:      "INFO",
+		LogFile:       logFile,
+		LogMaxSize:    10,
+		LogMaxAge:     7,
+		LogMaxBackups: 10,
+	}
+	if err := InitLogger(cfg); err != nil {
+		t.Fatalf("InitLogger failed: %v", err)
+	}
+	defer Close()
+	logger := Logger
+
+	// Act - debug_mode is folded into log_level by LoadConfig
+	reloaded := *cfg
+	reloaded.LogLevel = "DEBUG"
+	if err := ApplyConfig(&reloaded); err != nil {
+		t.Fatalf("ApplyConfig failed: %v", err)
+	}
+	WithField("component", "test").Debug("Debug after reload")
+
+	// Assert
+	if Logger != logger {
+		t.Error("Expected the logger instance to be kept")
+	}
+	if Logger.GetLevel().String() != "debug" {
+		t.Errorf("Expected debug level, got %s", Logger.GetLevel())
+	}
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(data), "Log level changed") || !strings.Contains(string(data), "Debug after reload") {
+		t.Errorf("Expected level change and debug entry in log, got %s", data)
+	}
+
+	reloaded.LogLevel = "LOUD"
+	if err := ApplyConfig(&reloaded); err == nil {
+		t.Error("Expected error for invalid log level")
+	}
+	if Logger.GetLevel().String() != "debug" {
+		t.Errorf("Expected level unchanged after invalid reload, got %s", Logger.GetLevel())
+	}
+}
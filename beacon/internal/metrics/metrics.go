@@ -3,6 +3,7 @@ package metrics
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"sync"
 	"time"
@@ -12,7 +13,9 @@ import (
 
 	"beacon/internal/config"
 	"beacon/internal/logger"
+	"beacon/internal/models"
 	"beacon/internal/probe"
+	"beacon/internal/reporter"
 )
 
 // Metrics handles Prometheus metrics exposure
@@ -26,8 +29,16 @@ type Metrics struct {
 	beaconPacketLoss *prometheus.GaugeVec
 	beaconJitterMs   *prometheus.GaugeVec
 
+	// Per-probe type-specific metrics (probe.ResultGauge), by gauge name
+	resultGauges map[string]*prometheus.GaugeVec
+
+	// Per-probe RTT statistics selected by the `statistics` config setting
+	beaconRTTStatisticMs *prometheus.GaugeVec
+	statistics           []string // Guarded by mu (hot-reloadable)
+
 	registry *prometheus.Registry
 	server   *http.Server
+	port     int // Guarded by mu (hot-reloadable)
 
 	mu           sync.RWMutex
 	running      bool
@@ -72,21 +83,47 @@ func NewMetrics(cfg *config.Config, scheduler *probe.ProbeScheduler) *Metrics {
 		[]string{"node_id", "node_name"},
 	)
 
+	beaconRTTStatisticMs := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "beacon_probe_rtt_statistic_ms",
+			Help: "Latest RTT statistic of a probe in milliseconds (min, max, stddev, p90, p95, p99, jitter_rfc3550)",
+		},
+		[]string{"node_id", "node_name", "probe_id", "target", "statistic"},
+	)
+
 	// Register metrics
 	registry.MustRegister(beaconUp)
 	registry.MustRegister(beaconRTTSeconds)
 	registry.MustRegister(beaconPacketLoss)
 	registry.MustRegister(beaconJitterMs)
+	registry.MustRegister(beaconRTTStatisticMs)
+
+	resultGauges := make(map[string]*prometheus.GaugeVec)
+	for _, gauge := range probe.ResultGauges() {
+		labels := []string{"node_id", "node_name", "probe_id", "target"}
+		if gauge.Label != "" {
+			labels = append(labels, gauge.Label)
+		}
+		resultGauges[gauge.Name] = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{Name: gauge.Name, Help: gauge.Help},
+			labels,
+		)
+		registry.MustRegister(resultGauges[gauge.Name])
+	}
 
 	return &Metrics{
-		config:           cfg,
-		scheduler:        scheduler,
-		beaconUp:         beaconUp,
-		beaconRTTSeconds: beaconRTTSeconds,
-		beaconPacketLoss: beaconPacketLoss,
-		beaconJitterMs:   beaconJitterMs,
-		registry:         registry,
-		stopChan:         make(chan struct{}),
+		config:               cfg,
+		scheduler:            scheduler,
+		beaconUp:             beaconUp,
+		beaconRTTSeconds:     beaconRTTSeconds,
+		beaconPacketLoss:     beaconPacketLoss,
+		beaconJitterMs:       beaconJitterMs,
+		resultGauges:         resultGauges,
+		beaconRTTStatisticMs: beaconRTTStatisticMs,
+		statistics:           cfg.Statistics,
+		registry:             registry,
+		port:                 cfg.MetricsPort,
+		stopChan:             make(chan struct{}),
 	}
 }
 
@@ -116,11 +153,12 @@ func (m *Metrics) Start() error {
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
 
-	addr := fmt.Sprintf(":%d", m.config.MetricsPort)
+	addr := fmt.Sprintf(":%d", m.port)
 	m.server = &http.Server{
 		Addr:    addr,
 		Handler: mux,
 	}
+	server := m.server
 
 	// Create new stopChan for this start cycle
 	m.stopChan = make(chan struct{})
@@ -129,7 +167,7 @@ func (m *Metrics) Start() error {
 	serverErrChan := make(chan error, 1)
 	go func() {
 		logger.WithFields(map[string]interface{}{"component": "metrics", "address": addr}).Info("Starting Prometheus metrics server")
-		if err := m.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			logger.WithFields(map[string]interface{}{"component": "metrics", "error": err.Error()}).Error("Metrics server error")
 			serverErrChan <- err
 		}
@@ -171,6 +209,7 @@ func (m *Metrics) Stop() error {
 
 	// Stop metrics collector by closing channel
 	close(m.stopChan)
+	server := m.server
 	
 	m.mu.Unlock()
 
@@ -178,11 +217,11 @@ func (m *Metrics) Stop() error {
 	m.collectorWg.Wait()
 
 	// Shutdown HTTP server gracefully if it exists
-	if m.server != nil {
+	if server != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		if err := m.server.Shutdown(ctx); err != nil {
+		if err := server.Shutdown(ctx); err != nil {
 			logger.WithFields(map[string]interface{}{"component": "metrics", "error": err.Error()}).Error("Metrics server shutdown error")
 			return err
 		}
@@ -214,10 +253,11 @@ func (m *Metrics) collectMetrics() {
 // updateMetrics updates Prometheus metrics from latest probe results
 func (m *Metrics) updateMetrics() {
 	// Get latest probe results from scheduler
-	tcpResults, udpResults := m.scheduler.GetLatestResults()
+	results := m.scheduler.GetLatestResults()
+	m.updateResultMetrics(results)
+	m.updateStatisticMetrics(results)
 
-	totalResults := len(tcpResults) + len(udpResults)
-	if totalResults == 0 {
+	if len(results) == 0 {
 		// No probe results, set metrics to indicate no data
 		m.beaconRTTSeconds.WithLabelValues(m.config.NodeID, m.config.NodeName).Set(0)
 		m.beaconPacketLoss.WithLabelValues(m.config.NodeID, m.config.NodeName).Set(1) // 100% loss
@@ -229,22 +269,11 @@ func (m *Metrics) updateMetrics() {
 	var totalRTT, totalPacketLoss, totalJitter float64
 	count := 0
 
-	// Process TCP probe results
-	for _, result := range tcpResults {
+	for _, result := range results {
 		if result != nil && result.Success {
-			totalRTT += result.RTTMs
-			totalPacketLoss += result.PacketLossRate
-			totalJitter += result.JitterMs
-			count++
-		}
-	}
-
-	// Process UDP probe results
-	for _, result := range udpResults {
-		if result != nil && result.Success {
-			totalRTT += result.RTTMs
-			totalPacketLoss += result.PacketLossRate
-			totalJitter += result.JitterMs
+			totalRTT += result.MetricFloat(models.MetricRTTMs)
+			totalPacketLoss += result.MetricFloat(models.MetricPacketLossRate)
+			totalJitter += result.MetricFloat(models.MetricJitterMs)
 			count++
 		}
 	}
@@ -267,6 +296,184 @@ func (m *Metrics) updateMetrics() {
 	}
 }
 
+// updateResultMetrics exposes the type-specific metrics of each probe that
+// its probe type describes with a gauge. Series of removed probes are dropped.
+func (m *Metrics) updateResultMetrics(results []*models.ProbeResult) {
+	for _, gauge := range m.resultGauges {
+		gauge.Reset()
+	}
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		labels := []string{m.config.NodeID, m.config.NodeName, result.ProbeID, result.Target}
+		for _, metric := range probe.ResultMetrics(result.Type) {
+			if metric.Gauge == nil || (metric.Gauge.SuccessOnly && !result.Success) {
+				continue
+			}
+			gauge, registered := m.resultGauges[metric.Gauge.Name]
+			if _, ok := metric.Value(result); !ok || !registered {
+				continue
+			}
+			if metric.Gauge.Label != "" {
+				gauge.WithLabelValues(append(labels, metric.Gauge.LabelValue)...).Set(result.MetricFloat(metric.Key))
+			} else {
+				gauge.WithLabelValues(labels...).Set(result.MetricFloat(metric.Key))
+			}
+		}
+	}
+}
+
+// SetStatistics selects the RTT statistics (models.StatisticNames) exposed
+// from the next update on
+func (m *Metrics) SetStatistics(statistics []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statistics = append([]string(nil), statistics...)
+}
+
+// updateStatisticMetrics exposes the selected RTT statistics of each probe
+// whose latest run succeeded. Series of removed probes or deselected
+// statistics are dropped.
+func (m *Metrics) updateStatisticMetrics(results []*models.ProbeResult) {
+	m.beaconRTTStatisticMs.Reset()
+
+	m.mu.RLock()
+	statistics := m.statistics
+	m.mu.RUnlock()
+	if len(statistics) == 0 {
+		return
+	}
+
+	for _, result := range results {
+		if result == nil || !result.Success {
+			continue
+		}
+		probeID := result.ProbeID
+		if probeID == "" {
+			probeID = models.ProbeKey(result.Type, result.Target, result.Port)
+		}
+		stats := result.Statistics()
+		for _, name := range statistics {
+			if value, ok := stats.Value(name); ok {
+				m.beaconRTTStatisticMs.WithLabelValues(m.config.NodeID, m.config.NodeName, probeID, result.Target, name).Set(value)
+			}
+		}
+	}
+}
+
+// ApplyConfig applies the hot-reloadable metrics settings of a reloaded
+// configuration: the exposed statistics and metrics_port. metrics_enabled and
+// metrics_update_seconds are applied at startup only.
+func (m *Metrics) ApplyConfig(cfg *config.Config) error {
+	m.SetStatistics(cfg.Statistics)
+	return m.setPort(cfg.MetricsPort)
+}
+
+// setPort moves a running metrics server to a new port. The new port is
+// bound before the old server is shut down, so if it cannot be bound the
+// server keeps serving on the current port and an error is returned.
+func (m *Metrics) setPort(port int) error {
+	m.mu.Lock()
+	if port == m.port {
+		m.mu.Unlock()
+		return nil
+	}
+	if !m.running {
+		// Applied by the next Start
+		m.port = port
+		m.mu.Unlock()
+		return nil
+	}
+
+	addr := fmt.Sprintf(":%d", port)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		m.mu.Unlock()
+		return fmt.Errorf("failed to bind metrics port %d: %w", port, err)
+	}
+
+	oldServer, oldPort := m.server, m.port
+	server := &http.Server{Addr: addr, Handler: oldServer.Handler}
+	m.server = server
+	m.port = port
+	m.mu.Unlock()
+
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.WithFields(map[string]interface{}{"component": "metrics", "error": err.Error()}).Error("Metrics server error")
+		}
+	}()
+
+	// Let scrapes in flight on the old port finish
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := oldServer.Shutdown(ctx); err != nil {
+		logger.WithFields(map[string]interface{}{"component": "metrics", "error": err.Error()}).Warn("Metrics server shutdown on old port failed")
+	}
+
+	logger.WithFields(map[string]interface{}{"component": "metrics", "address": addr, "old_port": oldPort}).Info("Prometheus metrics server moved to new port")
+	return nil
+}
+
+// SetConnectionStateProvider exposes heartbeat upload health read from provider.
+// Must be called at most once.
+func (m *Metrics) SetConnectionStateProvider(provider reporter.ConnectionStateProvider) {
+	labels := prometheus.Labels{"node_id": m.config.NodeID, "node_name": m.config.NodeName}
+
+	m.registry.MustRegister(
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "beacon_heartbeat_success_total",
+			Help:        "Total number of successful heartbeat uploads",
+			ConstLabels: labels,
+		}, func() float64 {
+			return float64(provider.ConnectionState().SuccessTotal)
+		}),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "beacon_heartbeat_failures_total",
+			Help:        "Total number of failed heartbeat upload attempts",
+			ConstLabels: labels,
+		}, func() float64 {
+			return float64(provider.ConnectionState().FailureTotal)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "beacon_heartbeat_connected",
+			Help:        "Whether the last heartbeat upload succeeded (1=connected, 0=otherwise)",
+			ConstLabels: labels,
+		}, func() float64 {
+			if provider.ConnectionState().Status == reporter.ConnectionConnected {
+				return 1
+			}
+			return 0
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "beacon_heartbeat_retry_count",
+			Help:        "Consecutive failed heartbeat upload attempts",
+			ConstLabels: labels,
+		}, func() float64 {
+			return float64(provider.ConnectionState().RetryCount)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "beacon_heartbeat_backoff_seconds",
+			Help:        "Current backoff before the next heartbeat upload attempt",
+			ConstLabels: labels,
+		}, func() float64 {
+			return provider.ConnectionState().Backoff.Seconds()
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "beacon_heartbeat_last_success_timestamp_seconds",
+			Help:        "Unix time of the last successful heartbeat upload (0 if none)",
+			ConstLabels: labels,
+		}, func() float64 {
+			if lastSuccess := provider.ConnectionState().LastSuccess; lastSuccess != nil {
+				return float64(lastSuccess.Unix())
+			}
+			return 0
+		}),
+	)
+}
+
 // IsRunning returns whether the metrics server is running
 func (m *Metrics) IsRunning() bool {
 	m.mu.RLock()
@@ -2,16 +2,21 @@ package metrics
 
 import (
 	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"beacon/internal/config"
 	"beacon/internal/logger"
+	"beacon/internal/models"
 	"beacon/internal/probe"
+	"beacon/internal/reporter"
 )
 
 // initTestLogger initializes the logger for tests
@@ -386,3 +391,260 @@ func TestMetricsWaitGroupBlocking(t *testing.T) {
 		t.Fatal("Stop() took too long - possible deadlock or WaitGroup issue")
 	}
 }
+
+// stubConnectionState is a fixed reporter.ConnectionStateProvider
+type stubConnectionState struct {
+	state reporter.ConnectionState
+}
+
+func (s *stubConnectionState) ConnectionState() reporter.ConnectionState {
+	return s.state
+}
+
+// TestConnectionStateMetrics tests heartbeat upload metrics read from the reporter
+func TestConnectionStateMetrics(t *testing.T) {
+	initTestLogger(t)
+	defer logger.Close()
+	cfg := &config.Config{
+		NodeID:               "test-node-id",
+		NodeName:             "test-node",
+		MetricsEnabled:       true,
+		MetricsPort:          19121,
+		MetricsUpdateSeconds: 10,
+	}
+
+	scheduler, err := probe.NewProbeScheduler([]config.ProbeConfig{})
+	require.NoError(t, err)
+
+	lastSuccess := time.Unix(1700000000, 0)
+	m := NewMetrics(cfg, scheduler)
+	m.SetConnectionStateProvider(&stubConnectionState{state: reporter.ConnectionState{
+		Status:       reporter.ConnectionConnecting,
+		LastSuccess:  &lastSuccess,
+		RetryCount:   2,
+		Backoff:      4 * time.Second,
+		SuccessTotal: 10,
+		FailureTotal: 3,
+	}})
+
+	err = m.Start()
+	require.NoError(t, err)
+	defer m.Stop()
+
+	resp, err := http.Get("http://localhost:19121/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	bodyStr := string(body)
+
+	labels := `{node_id="test-node-id",node_name="test-node"}`
+	assert.Contains(t, bodyStr, "beacon_heartbeat_success_total"+labels+" 10")
+	assert.Contains(t, bodyStr, "beacon_heartbeat_failures_total"+labels+" 3")
+	assert.Contains(t, bodyStr, "beacon_heartbeat_connected"+labels+" 0")
+	assert.Contains(t, bodyStr, "beacon_heartbeat_retry_count"+labels+" 2")
+	assert.Contains(t, bodyStr, "beacon_heartbeat_backoff_seconds"+labels+" 4")
+	assert.Contains(t, bodyStr, "beacon_heartbeat_last_success_timestamp_seconds"+labels+" 1.7e+09")
+}
+
+// TestHTTPMetrics tests per-probe phase timing and status code gauges from http_probe results
+func TestHTTPMetrics(t *testing.T) {
+	// Arrange
+	cfg := &config.Config{NodeID: "test-node-id", NodeName: "test-node"}
+	scheduler, err := probe.NewProbeScheduler([]config.ProbeConfig{})
+	require.NoError(t, err)
+	m := NewMetrics(cfg, scheduler)
+
+	scrape := func() string {
+		w := httptest.NewRecorder()
+		promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		return w.Body.String()
+	}
+
+	// Act
+	m.updateResultMetrics([]*models.ProbeResult{
+		{Type: "http_probe", ProbeID: "http_probe:https://api.example.com/", Target: "https://api.example.com/", Success: true, Metrics: map[string]interface{}{
+			models.MetricDNSMs:          4.5,
+			models.MetricConnectMs:      12,
+			models.MetricTLSHandshakeMs: 30.5,
+			models.MetricTTFBMs:         80,
+			models.MetricTotalMs:        85,
+			models.MetricStatusCode:     200,
+		}},
+		{Type: "http_probe", ProbeID: "http_probe:http://10.0.0.9/", Target: "http://10.0.0.9/", Success: false, Metrics: map[string]interface{}{
+			models.MetricStatusCode: 503,
+		}},
+		{Type: "icmp_ping", ProbeID: "icmp_ping:10.0.0.3", Target: "10.0.0.3", Success: true, Metrics: map[string]interface{}{}},
+	})
+	body := scrape()
+
+	// Assert
+	assert.Contains(t, body, `beacon_http_phase_ms{node_id="test-node-id",node_name="test-node",phase="tls_handshake",probe_id="http_probe:https://api.example.com/",target="https://api.example.com/"} 30.5`)
+	assert.Contains(t, body, `beacon_http_phase_ms{node_id="test-node-id",node_name="test-node",phase="ttfb",probe_id="http_probe:https://api.example.com/",target="https://api.example.com/"} 80`)
+	assert.Contains(t, body, `beacon_http_status_code{node_id="test-node-id",node_name="test-node",probe_id="http_probe:https://api.example.com/",target="https://api.example.com/"} 200`)
+	// Failed runs report the status code but no timing
+	assert.Contains(t, body, `beacon_http_status_code{node_id="test-node-id",node_name="test-node",probe_id="http_probe:http://10.0.0.9/",target="http://10.0.0.9/"} 503`)
+	assert.NotContains(t, body, `phase="total",probe_id="http_probe:http://10.0.0.9/"`)
+	assert.NotContains(t, body, "icmp_ping")
+
+	// Removed probes drop out on the next update
+	m.updateResultMetrics(nil)
+	assert.NotContains(t, scrape(), "beacon_http_phase_ms{")
+}
+
+// TestDNSMetrics tests per-probe response status gauges from dns_probe results
+func TestDNSMetrics(t *testing.T) {
+	// Arrange
+	cfg := &config.Config{NodeID: "test-node-id", NodeName: "test-node"}
+	scheduler, err := probe.NewProbeScheduler([]config.ProbeConfig{})
+	require.NoError(t, err)
+	m := NewMetrics(cfg, scheduler)
+
+	scrape := func() string {
+		w := httptest.NewRecorder()
+		promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		return w.Body.String()
+	}
+
+	// Act
+	m.updateResultMetrics([]*models.ProbeResult{
+		{Type: "dns_probe", ProbeID: "dns_probe:10.0.0.53:53/example.com/A", Target: "10.0.0.53", Metrics: map[string]interface{}{
+			models.MetricRcode:        3,
+			models.MetricAnswerCount:  0,
+			models.MetricAnswersMatch: false,
+		}},
+		{Type: "icmp_ping", ProbeID: "icmp_ping:10.0.0.3", Target: "10.0.0.3", Metrics: map[string]interface{}{}},
+	})
+	body := scrape()
+
+	// Assert
+	labels := `{node_id="test-node-id",node_name="test-node",probe_id="dns_probe:10.0.0.53:53/example.com/A",target="10.0.0.53"}`
+	assert.Contains(t, body, "beacon_dns_rcode"+labels+" 3")
+	assert.Contains(t, body, "beacon_dns_answer_count"+labels+" 0")
+	assert.Contains(t, body, "beacon_dns_answers_match"+labels+" 0")
+	assert.NotContains(t, body, "icmp_ping")
+
+	// Removed probes drop out on the next update
+	m.updateResultMetrics(nil)
+	assert.NotContains(t, scrape(), "beacon_dns_rcode{")
+}
+
+// TestCertificateMetrics tests per-probe certificate gauges from tls_probe results
+func TestCertificateMetrics(t *testing.T) {
+	// Arrange
+	cfg := &config.Config{NodeID: "test-node-id", NodeName: "test-node"}
+	scheduler, err := probe.NewProbeScheduler([]config.ProbeConfig{})
+	require.NoError(t, err)
+	m := NewMetrics(cfg, scheduler)
+
+	scrape := func() string {
+		w := httptest.NewRecorder()
+		promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		return w.Body.String()
+	}
+
+	// Act
+	m.updateResultMetrics([]*models.ProbeResult{
+		{Type: "tls_probe", ProbeID: "tls_probe:api.example.com:443", Target: "api.example.com", Metrics: map[string]interface{}{
+			models.MetricCertDaysRemaining: 12.5,
+			models.MetricChainValid:        true,
+		}},
+		{Type: "tls_probe", ProbeID: "tls_probe:10.0.0.9:443", Target: "10.0.0.9", Metrics: map[string]interface{}{}}, // No handshake yet
+		{Type: "icmp_ping", ProbeID: "icmp_ping:10.0.0.3", Target: "10.0.0.3", Metrics: map[string]interface{}{}},
+	})
+	body := scrape()
+
+	// Assert
+	labels := `{node_id="test-node-id",node_name="test-node",probe_id="tls_probe:api.example.com:443",target="api.example.com"}`
+	assert.Contains(t, body, "beacon_tls_cert_days_remaining"+labels+" 12.5")
+	assert.Contains(t, body, "beacon_tls_cert_chain_valid"+labels+" 1")
+	assert.NotContains(t, body, "10.0.0.9")
+	assert.NotContains(t, body, "icmp_ping")
+
+	// Removed probes drop out on the next update
+	m.updateResultMetrics(nil)
+	assert.NotContains(t, scrape(), "beacon_tls_cert_days_remaining{")
+}
+
+// TestStatisticMetrics tests per-probe RTT statistic gauges for selected statistics
+func TestStatisticMetrics(t *testing.T) {
+	// Arrange
+	cfg := &config.Config{NodeID: "test-node-id", NodeName: "test-node", Statistics: []string{models.StatisticP95}}
+	scheduler, err := probe.NewProbeScheduler([]config.ProbeConfig{})
+	require.NoError(t, err)
+	m := NewMetrics(cfg, scheduler)
+
+	scrape := func() string {
+		w := httptest.NewRecorder()
+		promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		return w.Body.String()
+	}
+
+	icmp := models.NewProbeResult("icmp_ping", "10.0.0.3", true, map[string]interface{}{}, "")
+	icmp.ProbeID = "icmp_ping:10.0.0.3"
+	models.RTTStatistics{RTTP95Ms: 8.5, RTTP99Ms: 9.75}.SetMetrics(icmp.Metrics)
+	tcp := (&models.TCPProbeResult{Success: true, Target: "8.8.8.8", Port: 80, RTTStatistics: models.RTTStatistics{RTTP95Ms: 24.7}}).ToGenericResult()
+	failed := (&models.TCPProbeResult{Success: false, Target: "10.0.0.9", Port: 80}).ToGenericResult()
+	results := []*models.ProbeResult{tcp, failed, icmp}
+
+	// Act
+	m.updateStatisticMetrics(results)
+	body := scrape()
+
+	// Assert
+	assert.Contains(t, body, `beacon_probe_rtt_statistic_ms{node_id="test-node-id",node_name="test-node",probe_id="tcp_ping:8.8.8.8:80",statistic="p95",target="8.8.8.8"} 24.7`)
+	assert.Contains(t, body, `beacon_probe_rtt_statistic_ms{node_id="test-node-id",node_name="test-node",probe_id="icmp_ping:10.0.0.3",statistic="p95",target="10.0.0.3"} 8.5`)
+	assert.NotContains(t, body, `statistic="p99"`)
+	assert.NotContains(t, body, "10.0.0.9")
+
+	// Deselected statistics drop out on the next update
+	m.SetStatistics(nil)
+	m.updateStatisticMetrics(results)
+	assert.NotContains(t, scrape(), "beacon_probe_rtt_statistic_ms{")
+}
+
+// TestMetricsApplyConfig_Port tests that a reloaded metrics_port moves the
+// running server and that an unavailable port keeps the current one
+func TestMetricsApplyConfig_Port(t *testing.T) {
+	initTestLogger(t)
+	defer logger.Close()
+
+	// Arrange
+	cfg := &config.Config{
+		NodeID:               "test-node-123",
+		NodeName:             "beacon-test",
+		MetricsEnabled:       true,
+		MetricsPort:          19122,
+		MetricsUpdateSeconds: 10,
+	}
+	scheduler, err := probe.NewProbeScheduler([]config.ProbeConfig{})
+	require.NoError(t, err)
+	m := NewMetrics(cfg, scheduler)
+	require.NoError(t, m.Start())
+	defer m.Stop()
+
+	// Act
+	reloaded := *cfg
+	reloaded.MetricsPort = 19123
+	require.NoError(t, m.ApplyConfig(&reloaded))
+
+	// Assert - served on the new port only
+	resp, err := http.Get("http://localhost:19123/metrics")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	_, err = http.Get("http://localhost:19122/metrics")
+	assert.Error(t, err, "Expected the old port to be closed")
+
+	// A port in use is rejected and the server stays on its port
+	blocker, err := net.Listen("tcp", ":19124")
+	require.NoError(t, err)
+	defer blocker.Close()
+	reloaded.MetricsPort = 19124
+	assert.Error(t, m.ApplyConfig(&reloaded))
+	resp, err = http.Get("http://localhost:19123/metrics")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"net"
+	"time"
+)
 
 // TCPProbeResult represents the result of a TCP probe operation.
 // Use this struct for TCP-specific probe results that include RTT measurements.
@@ -14,6 +18,14 @@ type TCPProbeResult struct {
 	SampleCount    int     `json:"sample_count"`    // Number of sample points
 	ErrorMessage   string  `json:"error_message"`   // Error message if failed
 	Timestamp      string  `json:"timestamp"`       // Probe timestamp (ISO 8601)
+	ProbeID        string  `json:"probe_id,omitempty"` // Probe identity (Pulse probe UUID or ProbeKey)
+	Target         string  `json:"target,omitempty"`   // Probe target host
+	Port           int     `json:"port,omitempty"`     // Probe target port
+
+	RTTStatistics // RTT distribution (min/max, stddev, percentiles, RFC 3550 jitter)
+
+	Samples []RawSample `json:"samples,omitempty"` // Individual attempts of the batch
+	Partial bool        `json:"partial,omitempty"` // Batch aborted before all attempts completed
 }
 
 // UDPProbeResult represents the result of a UDP probe operation.
@@ -30,18 +42,140 @@ type UDPProbeResult struct {
 	SampleCount     int     `json:"sample_count"`      // Number of sample points
 	ErrorMessage    string  `json:"error_message"`     // Error message if failed
 	Timestamp       string  `json:"timestamp"`         // Probe timestamp (ISO 8601)
+	ProbeID         string  `json:"probe_id,omitempty"` // Probe identity (Pulse probe UUID or ProbeKey)
+	Target          string  `json:"target,omitempty"`   // Probe target host
+	Port            int     `json:"port,omitempty"`     // Probe target port
+
+	RTTStatistics // RTT distribution (min/max, stddev, percentiles, RFC 3550 jitter)
+
+	Samples []RawSample `json:"samples,omitempty"` // Individual attempts of the batch
+	Partial bool        `json:"partial,omitempty"` // Batch aborted before all attempts completed
+
+	// Echo measurements, only available against a `beacon responder` target
+	ReorderedPackets int      `json:"reordered_packets,omitempty"`  // Replies arriving after a later sequence number
+	DuplicatePackets int      `json:"duplicate_packets,omitempty"`  // Extra replies for an already answered sequence number
+	OneWayForwardMs  *float64 `json:"one_way_forward_ms,omitempty"` // Mean prober→responder delay (requires synchronized clocks)
+	OneWayReverseMs  *float64 `json:"one_way_reverse_ms,omitempty"` // Mean responder→prober delay (requires synchronized clocks)
 }
 
-// ProbeResult represents a generic probe result for both TCP and UDP probes.
+// ProbeResult represents a generic probe result for any probe type.
 // Use this struct when you need a unified format for multiple probe types.
 // The Metrics field contains type-specific measurements (RTT, packet loss, etc.).
 type ProbeResult struct {
-	Type         string                 `json:"type"`          // "tcp_ping" or "udp_ping"
-	Target       string                 `json:"target"`        // Target IP:Port
-	Success      bool                   `json:"success"`       // Probe success status
-	Metrics      map[string]interface{} `json:"metrics"`       // RTT, packet loss, etc.
-	ErrorMessage string                 `json:"error_message"` // Error message if failed
-	Timestamp    string                 `json:"timestamp"`     // Probe timestamp (ISO 8601)
+	Type         string                 `json:"type"`               // Probe type, e.g. "icmp_ping"
+	Target       string                 `json:"target"`             // Target host
+	Success      bool                   `json:"success"`            // Probe success status
+	Metrics      map[string]interface{} `json:"metrics"`            // RTT, packet loss, etc.
+	ErrorMessage string                 `json:"error_message"`      // Error message if failed
+	Timestamp    string                 `json:"timestamp"`          // Probe timestamp (ISO 8601)
+	ProbeID      string                 `json:"probe_id,omitempty"` // Probe identity (Pulse probe UUID or ProbeKey)
+	Port         int                    `json:"port,omitempty"`     // Probe target port, if the probe type uses one
+	Samples      []RawSample            `json:"samples,omitempty"`  // Individual attempts of the batch, if recorded
+	Partial      bool                   `json:"partial,omitempty"`  // Batch aborted before all attempts completed
+}
+
+// Metric keys shared by probe types that report core metrics in ProbeResult.Metrics
+const (
+	MetricRTTMs           = "rtt_ms"
+	MetricRTTMedianMs     = "rtt_median_ms"
+	MetricJitterMs        = "jitter_ms"
+	MetricVarianceMs      = "variance_ms"
+	MetricPacketLossRate  = "packet_loss_rate"
+	MetricSampleCount     = "sample_count"
+	MetricSentPackets     = "sent_packets"
+	MetricReceivedPackets = "received_packets"
+)
+
+// Metric keys reported by udp_ping against a `beacon responder` target,
+// present only when observed; one-way delays require one_way_delay
+const (
+	MetricReorderedPackets = "reordered_packets"
+	MetricDuplicatePackets = "duplicate_packets"
+	MetricOneWayForwardMs  = "one_way_forward_ms"
+	MetricOneWayReverseMs  = "one_way_reverse_ms"
+)
+
+// Metric keys reported by http_probe: mean phase durations in milliseconds
+// over successful requests, and the last observed status code
+const (
+	MetricDNSMs          = "dns_ms"
+	MetricConnectMs      = "connect_ms"
+	MetricTLSHandshakeMs = "tls_handshake_ms"
+	MetricTTFBMs         = "ttfb_ms"
+	MetricTotalMs        = "total_ms"
+	MetricStatusCode     = "status_code"
+)
+
+// Metric keys reported by dns_probe: the rcode of the last response (-1 if
+// none), its number of answers of the queried type, and whether they matched
+// the expected set (true when no set is configured)
+const (
+	MetricRcode        = "rcode"
+	MetricAnswerCount  = "answer_count"
+	MetricAnswersMatch = "answers_match"
+)
+
+// Metric keys reported by tls_probe, describing the last successful
+// handshake; handshake latency is reported as the core RTT metrics
+const (
+	MetricTLSVersion        = "tls_version"         // e.g. "TLS 1.3"
+	MetricCipherSuite       = "cipher_suite"        // e.g. "TLS_AES_128_GCM_SHA256"
+	MetricChainValid        = "chain_valid"         // Chain verifies against the trust store and server name
+	MetricCertNotAfter      = "cert_not_after"      // Leaf expiry (RFC 3339)
+	MetricCertDaysRemaining = "cert_days_remaining" // Days until leaf expiry, negative once expired
+)
+
+// Metric keys reported by path_probe: the hop list ([]PathHop) and the
+// number of hops to the destination (0 when it was not reached); the
+// destination hop's latency and loss are reported as the core metrics
+const (
+	MetricHops     = "hops"
+	MetricHopCount = "hop_count"
+)
+
+// PathHop is one hop of a path_probe trace, as reported by MTR
+type PathHop struct {
+	TTL            int     `json:"ttl"`               // Hop number (IP TTL / hop limit)
+	Address        string  `json:"address,omitempty"` // Responding router, "" if no reply
+	Sent           int     `json:"sent"`              // Probes sent with this TTL
+	Received       int     `json:"received"`          // Replies received
+	PacketLossRate float64 `json:"packet_loss_rate"`  // Packet loss rate (0-100%)
+	RTTMs          float64 `json:"rtt_ms"`            // Mean RTT in milliseconds
+	BestMs         float64 `json:"best_ms"`           // Lowest RTT in milliseconds
+	WorstMs        float64 `json:"worst_ms"`          // Highest RTT in milliseconds
+}
+
+// MetricFloat returns a numeric metric as float64 (1 or 0 for booleans), or 0
+// if it is missing or not numeric
+func (r *ProbeResult) MetricFloat(key string) float64 {
+	switch v := r.Metrics[key].(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case bool:
+		if v {
+			return 1
+		}
+		return 0
+	default:
+		return 0
+	}
+}
+
+// ProbeKey returns the stable identity of a probe that has no explicit ID,
+// e.g. "tcp_ping:8.8.8.8:80". It is used wherever results must be attributed
+// to a single configured probe. Probe types without a port (port 0) use
+// "type:target", e.g. "icmp_ping:8.8.8.8".
+func ProbeKey(probeType, target string, port int) string {
+	if port == 0 {
+		return fmt.Sprintf("%s:%s", probeType, target)
+	}
+	return fmt.Sprintf("%s:%s", probeType, net.JoinHostPort(target, fmt.Sprintf("%d", port)))
 }
 
 // NewTCPProbeResult creates a new TCP probe result with current timestamp (backward compatible)
@@ -112,17 +246,69 @@ func NewProbeResult(probeType, target string, success bool, metrics map[string]i
 }
 
 // ToGenericResult converts a TCPProbeResult to a generic ProbeResult
-func (r *TCPProbeResult) ToGenericResult(target string) *ProbeResult {
+// carrying the core metrics and RTT statistics
+func (r *TCPProbeResult) ToGenericResult() *ProbeResult {
 	metrics := map[string]interface{}{
-		"rtt_ms": r.RTTMs,
+		MetricRTTMs:          r.RTTMs,
+		MetricRTTMedianMs:    r.RTTMedianMs,
+		MetricJitterMs:       r.JitterMs,
+		MetricVarianceMs:     r.VarianceMs,
+		MetricPacketLossRate: r.PacketLossRate,
+		MetricSampleCount:    r.SampleCount,
 	}
+	r.RTTStatistics.SetMetrics(metrics)
 
 	return &ProbeResult{
 		Type:         "tcp_ping",
-		Target:       target,
+		Target:       r.Target,
+		Success:      r.Success,
+		Metrics:      metrics,
+		ErrorMessage: r.ErrorMessage,
+		Timestamp:    r.Timestamp,
+		ProbeID:      r.ProbeID,
+		Port:         r.Port,
+		Samples:      r.Samples,
+		Partial:      r.Partial,
+	}
+}
+
+// ToGenericResult converts a UDPProbeResult to a generic ProbeResult
+// carrying the core metrics, RTT statistics and echo measurements
+func (r *UDPProbeResult) ToGenericResult() *ProbeResult {
+	metrics := map[string]interface{}{
+		MetricRTTMs:           r.RTTMs,
+		MetricRTTMedianMs:     r.RTTMedianMs,
+		MetricJitterMs:        r.JitterMs,
+		MetricVarianceMs:      r.VarianceMs,
+		MetricPacketLossRate:  r.PacketLossRate,
+		MetricSampleCount:     r.SampleCount,
+		MetricSentPackets:     r.SentPackets,
+		MetricReceivedPackets: r.ReceivedPackets,
+	}
+	r.RTTStatistics.SetMetrics(metrics)
+	if r.ReorderedPackets > 0 {
+		metrics[MetricReorderedPackets] = r.ReorderedPackets
+	}
+	if r.DuplicatePackets > 0 {
+		metrics[MetricDuplicatePackets] = r.DuplicatePackets
+	}
+	if r.OneWayForwardMs != nil {
+		metrics[MetricOneWayForwardMs] = *r.OneWayForwardMs
+	}
+	if r.OneWayReverseMs != nil {
+		metrics[MetricOneWayReverseMs] = *r.OneWayReverseMs
+	}
+
+	return &ProbeResult{
+		Type:         "udp_ping",
+		Target:       r.Target,
 		Success:      r.Success,
 		Metrics:      metrics,
 		ErrorMessage: r.ErrorMessage,
 		Timestamp:    r.Timestamp,
+		ProbeID:      r.ProbeID,
+		Port:         r.Port,
+		Samples:      r.Samples,
+		Partial:      r.Partial,
 	}
 }
@@ -0,0 +1,22 @@
+package models
+
+// Error classes of failed raw samples
+const (
+	SampleErrorTimeout     = "timeout"     // No answer within the probe timeout
+	SampleErrorRefused     = "refused"     // Connection refused / port unreachable
+	SampleErrorReset       = "reset"       // Connection reset by peer
+	SampleErrorUnreachable = "unreachable" // No route to host or network
+	SampleErrorDNS         = "dns"         // Target name did not resolve
+	SampleErrorTLS         = "tls"         // Handshake or certificate failure
+	SampleErrorResponse    = "response"    // Answered, but with an unexpected response
+	SampleErrorOther       = "other"       // Anything else, e.g. invalid configuration
+)
+
+// RawSample is a single probe attempt of a batch, reported to Pulse when
+// `report_samples` is enabled
+type RawSample struct {
+	Timestamp  string  `json:"timestamp"`             // Attempt time (ISO 8601)
+	Success    bool    `json:"success"`               // Attempt got a valid answer
+	RTTMs      float64 `json:"rtt_ms"`                // Round-trip time, 0 if failed
+	ErrorClass string  `json:"error_class,omitempty"` // SampleError* class if failed
+}
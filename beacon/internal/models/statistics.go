@@ -0,0 +1,118 @@
+package models
+
+// Selectable RTT statistics. Probes always compute all of them; the
+// `statistics` config setting selects which are reported to Pulse and
+// exposed to Prometheus.
+const (
+	StatisticMin           = "min"            // Lowest RTT
+	StatisticMax           = "max"            // Highest RTT
+	StatisticStdDev        = "stddev"         // RTT standard deviation
+	StatisticP90           = "p90"            // 90th percentile RTT
+	StatisticP95           = "p95"            // 95th percentile RTT
+	StatisticP99           = "p99"            // 99th percentile RTT
+	StatisticJitterRFC3550 = "jitter_rfc3550" // RFC 3550 interarrival jitter
+)
+
+// StatisticNames lists the selectable statistics in reporting order
+var StatisticNames = []string{
+	StatisticMin,
+	StatisticMax,
+	StatisticStdDev,
+	StatisticP90,
+	StatisticP95,
+	StatisticP99,
+	StatisticJitterRFC3550,
+}
+
+// Metric keys carrying RTTStatistics in ProbeResult.Metrics
+const (
+	MetricRTTMinMs        = "rtt_min_ms"
+	MetricRTTMaxMs        = "rtt_max_ms"
+	MetricRTTStdDevMs     = "rtt_stddev_ms"
+	MetricRTTP90Ms        = "rtt_p90_ms"
+	MetricRTTP95Ms        = "rtt_p95_ms"
+	MetricRTTP99Ms        = "rtt_p99_ms"
+	MetricJitterRFC3550Ms = "jitter_rfc3550_ms"
+)
+
+// RTTStatistics describes the RTT distribution of the successful samples of
+// a probe batch, in milliseconds
+type RTTStatistics struct {
+	RTTMinMs        float64 `json:"rtt_min_ms"`        // Lowest RTT
+	RTTMaxMs        float64 `json:"rtt_max_ms"`        // Highest RTT
+	RTTStdDevMs     float64 `json:"rtt_stddev_ms"`     // Population standard deviation
+	RTTP90Ms        float64 `json:"rtt_p90_ms"`        // 90th percentile
+	RTTP95Ms        float64 `json:"rtt_p95_ms"`        // 95th percentile
+	RTTP99Ms        float64 `json:"rtt_p99_ms"`        // 99th percentile
+	JitterRFC3550Ms float64 `json:"jitter_rfc3550_ms"` // RFC 3550 interarrival jitter estimate
+}
+
+// IsStatistic reports whether name is a selectable statistic
+func IsStatistic(name string) bool {
+	return statisticMetricKey(name) != ""
+}
+
+// Value returns the named statistic, or false if name is unknown
+func (s RTTStatistics) Value(name string) (float64, bool) {
+	switch name {
+	case StatisticMin:
+		return s.RTTMinMs, true
+	case StatisticMax:
+		return s.RTTMaxMs, true
+	case StatisticStdDev:
+		return s.RTTStdDevMs, true
+	case StatisticP90:
+		return s.RTTP90Ms, true
+	case StatisticP95:
+		return s.RTTP95Ms, true
+	case StatisticP99:
+		return s.RTTP99Ms, true
+	case StatisticJitterRFC3550:
+		return s.JitterRFC3550Ms, true
+	default:
+		return 0, false
+	}
+}
+
+// SetMetrics stores the statistics in a ProbeResult metrics map
+func (s RTTStatistics) SetMetrics(metrics map[string]interface{}) {
+	for _, name := range StatisticNames {
+		metrics[statisticMetricKey(name)], _ = s.Value(name)
+	}
+}
+
+// Statistics returns the RTT statistics carried in the result's metrics
+// (zero for probe types that do not report them)
+func (r *ProbeResult) Statistics() RTTStatistics {
+	return RTTStatistics{
+		RTTMinMs:        r.MetricFloat(MetricRTTMinMs),
+		RTTMaxMs:        r.MetricFloat(MetricRTTMaxMs),
+		RTTStdDevMs:     r.MetricFloat(MetricRTTStdDevMs),
+		RTTP90Ms:        r.MetricFloat(MetricRTTP90Ms),
+		RTTP95Ms:        r.MetricFloat(MetricRTTP95Ms),
+		RTTP99Ms:        r.MetricFloat(MetricRTTP99Ms),
+		JitterRFC3550Ms: r.MetricFloat(MetricJitterRFC3550Ms),
+	}
+}
+
+// statisticMetricKey maps a statistic name to its ProbeResult metric key
+func statisticMetricKey(name string) string {
+	switch name {
+	case StatisticMin:
+		return MetricRTTMinMs
+	case StatisticMax:
+		return MetricRTTMaxMs
+	case StatisticStdDev:
+		return MetricRTTStdDevMs
+	case StatisticP90:
+		return MetricRTTP90Ms
+	case StatisticP95:
+		return MetricRTTP95Ms
+	case StatisticP99:
+		return MetricRTTP99Ms
+	case StatisticJitterRFC3550:
+		return MetricJitterRFC3550Ms
+	default:
+		return ""
+	}
+}
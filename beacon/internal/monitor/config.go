@@ -77,11 +77,15 @@ type Monitor interface {
 
 	// IsRunning returns whether the monitor is running
 	IsRunning() bool
+
+	// UpdateConfig applies new thresholds, degradation levels and alerting
+	// settings (config hot reload)
+	UpdateConfig(cfg *ResourceMonitorConfig) error
 }
 
 // monitor implements the Monitor interface
 type monitor struct {
-	cfg      *ResourceMonitorConfig
+	cfg      *ResourceMonitorConfig // Guarded by mu, replaced on UpdateConfig
 	probeMgr ProbeManager
 	logger   Logger
 
@@ -1,6 +1,7 @@
 package monitor
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/shirou/gopsutil/v3/cpu"
@@ -68,11 +69,50 @@ func (m *monitor) IsRunning() bool {
 	return m.running
 }
 
+// UpdateConfig applies the thresholds, degradation levels, recovery and
+// alerting settings of cfg from the next check on. enabled and
+// check_interval_seconds are applied at startup only. While degraded, the
+// probe interval multiplier of the current level is re-applied.
+func (m *monitor) UpdateConfig(cfg *ResourceMonitorConfig) error {
+	if cfg == nil {
+		return ErrInvalidConfig
+	}
+
+	m.mu.Lock()
+	updated := *m.cfg
+	updated.Thresholds = cfg.Thresholds
+	updated.Degradation = cfg.Degradation
+	updated.Alerting = cfg.Alerting
+	m.cfg = &updated
+	level := m.level
+	m.mu.Unlock()
+
+	m.logger.Infof("Resource monitor config updated: thresholds cpu=%d memory=%dMB, degraded cpu=%d memory=%dMB x%d, critical cpu=%d memory=%dMB x%d",
+		updated.Thresholds.CPUMicrocores, updated.Thresholds.MemoryMB,
+		updated.Degradation.DegradedLevel.CPUMicrocores, updated.Degradation.DegradedLevel.MemoryMB, updated.Degradation.DegradedLevel.IntervalMultiplier,
+		updated.Degradation.CriticalLevel.CPUMicrocores, updated.Degradation.CriticalLevel.MemoryMB, updated.Degradation.CriticalLevel.IntervalMultiplier)
+
+	if level != DegradationLevelNormal {
+		multiplier := m.getIntervalMultiplier(level)
+		if err := m.probeMgr.UpdateProbeInterval(multiplier); err != nil {
+			return fmt.Errorf("failed to update probe interval (multiplier=%d): %w", multiplier, err)
+		}
+	}
+	return nil
+}
+
+// config returns the current configuration
+func (m *monitor) config() *ResourceMonitorConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cfg
+}
+
 // monitoringLoop is the main monitoring loop
 func (m *monitor) monitoringLoop() {
 	defer m.wg.Done()
 
-	ticker := time.NewTicker(time.Duration(m.cfg.CheckIntervalSeconds) * time.Second)
+	ticker := time.NewTicker(time.Duration(m.config().CheckIntervalSeconds) * time.Second)
 	defer ticker.Stop()
 
 	for {
@@ -133,26 +173,27 @@ func (m *monitor) collectResourceUsage() (*ResourceUsage, error) {
 // checkThresholds checks if resource usage exceeds thresholds and triggers alerts
 func (m *monitor) checkThresholds(usage *ResourceUsage) {
 	now := time.Now().Unix()
+	cfg := m.config()
 
 	// Check CPU threshold
-	if usage.CPUMicrocores > float64(m.cfg.Thresholds.CPUMicrocores) {
-		m.maybeTriggerAlert("cpu", usage.CPUMicrocores, float64(m.cfg.Thresholds.CPUMicrocores), now)
+	if usage.CPUMicrocores > float64(cfg.Thresholds.CPUMicrocores) {
+		m.maybeTriggerAlert(cfg, "cpu", usage.CPUMicrocores, float64(cfg.Thresholds.CPUMicrocores), now)
 	}
 
 	// Check memory threshold
-	if usage.MemoryMB > float64(m.cfg.Thresholds.MemoryMB) {
-		m.maybeTriggerAlert("memory", usage.MemoryMB, float64(m.cfg.Thresholds.MemoryMB), now)
+	if usage.MemoryMB > float64(cfg.Thresholds.MemoryMB) {
+		m.maybeTriggerAlert(cfg, "memory", usage.MemoryMB, float64(cfg.Thresholds.MemoryMB), now)
 	}
 }
 
 // maybeTriggerAlert triggers an alert if not within suppression window
-func (m *monitor) maybeTriggerAlert(resourceType string, currentValue, threshold float64, now int64) {
+func (m *monitor) maybeTriggerAlert(cfg *ResourceMonitorConfig, resourceType string, currentValue, threshold float64, now int64) {
 	// Check suppression window
 	m.mu.RLock()
 	lastAlert, exists := m.lastAlertTime[resourceType]
 	m.mu.RUnlock()
 
-	suppressionWindow := int64(m.cfg.Alerting.SuppressionWindowSeconds)
+	suppressionWindow := int64(cfg.Alerting.SuppressionWindowSeconds)
 	if exists && (now-lastAlert) < suppressionWindow {
 		return // Within suppression window
 	}
@@ -160,13 +201,13 @@ func (m *monitor) maybeTriggerAlert(resourceType string, currentValue, threshold
 	// Determine alert level
 	var level string
 	if resourceType == "cpu" {
-		if currentValue > float64(m.cfg.Degradation.CriticalLevel.CPUMicrocores) {
+		if currentValue > float64(cfg.Degradation.CriticalLevel.CPUMicrocores) {
 			level = "critical"
 		} else {
 			level = "degraded"
 		}
 	} else {
-		if currentValue > float64(m.cfg.Degradation.CriticalLevel.MemoryMB) {
+		if currentValue > float64(cfg.Degradation.CriticalLevel.MemoryMB) {
 			level = "critical"
 		} else {
 			level = "degraded"
@@ -195,13 +236,14 @@ func (m *monitor) maybeTriggerAlert(resourceType string, currentValue, threshold
 // evaluateDegradation evaluates and updates degradation level
 func (m *monitor) evaluateDegradation(usage *ResourceUsage) {
 	var newLevel DegradationLevel
+	cfg := m.config()
 
 	// Check critical level
-	if usage.CPUMicrocores > float64(m.cfg.Degradation.CriticalLevel.CPUMicrocores) ||
-		usage.MemoryMB > float64(m.cfg.Degradation.CriticalLevel.MemoryMB) {
+	if usage.CPUMicrocores > float64(cfg.Degradation.CriticalLevel.CPUMicrocores) ||
+		usage.MemoryMB > float64(cfg.Degradation.CriticalLevel.MemoryMB) {
 		newLevel = DegradationLevelCritical
-	} else if usage.CPUMicrocores > float64(m.cfg.Degradation.DegradedLevel.CPUMicrocores) ||
-		usage.MemoryMB > float64(m.cfg.Degradation.DegradedLevel.MemoryMB) {
+	} else if usage.CPUMicrocores > float64(cfg.Degradation.DegradedLevel.CPUMicrocores) ||
+		usage.MemoryMB > float64(cfg.Degradation.DegradedLevel.MemoryMB) {
 		newLevel = DegradationLevelDegraded
 	} else {
 		newLevel = DegradationLevelNormal
@@ -209,7 +251,7 @@ func (m *monitor) evaluateDegradation(usage *ResourceUsage) {
 
 	m.mu.RLock()
 	currentLevel := m.level
-	requiredChecks := m.cfg.Degradation.Recovery.ConsecutiveNormalChecks
+	requiredChecks := cfg.Degradation.Recovery.ConsecutiveNormalChecks
 	m.mu.RUnlock()
 
 	// Only transition to Normal if we've had consecutive normal checks
@@ -258,11 +300,12 @@ func (m *monitor) updateDegradationLevel(newLevel DegradationLevel) {
 
 // getIntervalMultiplier returns the interval multiplier for a degradation level
 func (m *monitor) getIntervalMultiplier(level DegradationLevel) int {
+	cfg := m.config()
 	switch level {
 	case DegradationLevelDegraded:
-		return m.cfg.Degradation.DegradedLevel.IntervalMultiplier
+		return cfg.Degradation.DegradedLevel.IntervalMultiplier
 	case DegradationLevelCritical:
-		return m.cfg.Degradation.CriticalLevel.IntervalMultiplier
+		return cfg.Degradation.CriticalLevel.IntervalMultiplier
 	default:
 		return 1
 	}
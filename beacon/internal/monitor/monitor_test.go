@@ -351,3 +351,61 @@ func TestDegradationLevel_String(t *testing.T) {
 		})
 	}
 }
+
+func TestMonitor_UpdateConfig(t *testing.T) {
+	cfg := &ResourceMonitorConfig{
+		Enabled:              true,
+		CheckIntervalSeconds: 1,
+		Thresholds:           ThresholdsConfig{CPUMicrocores: 100, MemoryMB: 100},
+		Degradation: DegradationConfig{
+			DegradedLevel: DegradationLevelConfig{CPUMicrocores: 200, MemoryMB: 150, IntervalMultiplier: 2},
+			CriticalLevel: DegradationLevelConfig{CPUMicrocores: 300, MemoryMB: 200, IntervalMultiplier: 3},
+			Recovery:      RecoveryConfig{ConsecutiveNormalChecks: 3},
+		},
+		Alerting: AlertingConfig{SuppressionWindowSeconds: 5},
+	}
+	probeMgr := &mockProbeManager{}
+	mon, err := NewMonitor(cfg, probeMgr, &mockLogger{})
+	if err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+	m := mon.(*monitor)
+
+	// Degraded under the initial thresholds
+	m.evaluateDegradation(&ResourceUsage{CPUMicrocores: 250, MemoryMB: 50})
+	if level := mon.GetDegradationLevel(); level != DegradationLevelDegraded {
+		t.Fatalf("Expected degraded level, got %s", level)
+	}
+
+	// Act - raise the degraded threshold and multiplier, change the check interval
+	updated := *cfg
+	updated.CheckIntervalSeconds = 30
+	updated.Degradation.DegradedLevel = DegradationLevelConfig{CPUMicrocores: 400, MemoryMB: 150, IntervalMultiplier: 4}
+	updated.Degradation.CriticalLevel = DegradationLevelConfig{CPUMicrocores: 500, MemoryMB: 200, IntervalMultiplier: 5}
+	if err := mon.UpdateConfig(&updated); err != nil {
+		t.Fatalf("UpdateConfig failed: %v", err)
+	}
+
+	// Assert - the current level's new multiplier applies immediately
+	probeMgr.mu.Lock()
+	lastMultiplier := probeMgr.lastMultiplier
+	probeMgr.mu.Unlock()
+	if lastMultiplier != 4 {
+		t.Errorf("Expected multiplier 4 re-applied, got %d", lastMultiplier)
+	}
+	if m.config().CheckIntervalSeconds != 1 {
+		t.Errorf("Expected check interval to stay at 1s until restart, got %d", m.config().CheckIntervalSeconds)
+	}
+
+	// The same usage is no longer degraded under the new thresholds
+	for i := 0; i < 3; i++ {
+		m.evaluateDegradation(&ResourceUsage{CPUMicrocores: 250, MemoryMB: 50})
+	}
+	if level := mon.GetDegradationLevel(); level != DegradationLevelNormal {
+		t.Errorf("Expected normal level under new thresholds, got %s", level)
+	}
+
+	if err := mon.UpdateConfig(nil); err != ErrInvalidConfig {
+		t.Errorf("Expected ErrInvalidConfig for nil config, got %v", err)
+	}
+}
@@ -0,0 +1,317 @@
+// Package outbox implements a bounded, disk-backed FIFO queue for reports
+// that could not be delivered to Pulse. Entries survive beacon restarts and
+// are replayed in order once Pulse is reachable again.
+//
+// The queue is stored as JSON lines: appends are written in place, while
+// acknowledgements and trimming rewrite the file atomically (write + rename).
+package outbox
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"beacon/internal/logger"
+)
+
+// maxLineSize bounds a single queued entry when reading the queue file
+const maxLineSize = 1024 * 1024
+
+// Entry is a single queued payload
+type Entry struct {
+	Seq      uint64          `json:"seq"`
+	QueuedAt time.Time       `json:"queued_at"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+// Stats summarizes the queue contents
+type Stats struct {
+	Size   int
+	Oldest *time.Time
+}
+
+// Outbox is a durable FIFO queue capped by entry count and entry age
+type Outbox struct {
+	path     string
+	maxItems int
+	maxAge   time.Duration
+
+	mu      sync.Mutex
+	entries []Entry
+	nextSeq uint64
+	now     func() time.Time
+}
+
+// Open loads the queue at path, creating its directory if needed.
+// Unreadable lines and entries beyond the caps are discarded.
+func Open(path string, maxItems int, maxAge time.Duration) (*Outbox, error) {
+	if maxItems <= 0 {
+		return nil, fmt.Errorf("invalid max items %d, must be positive", maxItems)
+	}
+	if maxAge <= 0 {
+		return nil, fmt.Errorf("invalid max age %s, must be positive", maxAge)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+
+	entries, skipped, err := readEntries(path)
+	if err != nil {
+		return nil, err
+	}
+	if skipped > 0 {
+		logger.WithFields(map[string]interface{}{"component": "outbox", "path": path, "skipped": skipped}).Warn("Skipped unreadable outbox entries")
+	}
+
+	o := &Outbox{
+		path:     path,
+		maxItems: maxItems,
+		maxAge:   maxAge,
+		entries:  entries,
+		now:      time.Now,
+	}
+	for _, entry := range entries {
+		if entry.Seq >= o.nextSeq {
+			o.nextSeq = entry.Seq + 1
+		}
+	}
+
+	// Rewrite after skipping lines so later appends don't follow a partial line
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.trimLocked() || skipped > 0 {
+		if err := o.rewriteLocked(); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// Enqueue appends payloads to the end of the queue. When the queue exceeds
+// its size cap the oldest entries are dropped.
+func (o *Outbox) Enqueue(payloads ...interface{}) error {
+	if len(payloads) == 0 {
+		return nil
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	queuedAt := o.now().UTC()
+	added := make([]Entry, 0, len(payloads))
+	for _, payload := range payloads {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal outbox payload: %w", err)
+		}
+		added = append(added, Entry{Seq: o.nextSeq, QueuedAt: queuedAt, Payload: data})
+		o.nextSeq++
+	}
+	o.entries = append(o.entries, added...)
+
+	if o.trimLocked() {
+		return o.rewriteLocked()
+	}
+	return o.appendLocked(added)
+}
+
+// Peek returns up to n of the oldest entries without removing them.
+// Expired entries are dropped first.
+func (o *Outbox) Peek(n int) ([]Entry, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.trimLocked() {
+		if err := o.rewriteLocked(); err != nil {
+			return nil, err
+		}
+	}
+	if n > len(o.entries) {
+		n = len(o.entries)
+	}
+	peeked := make([]Entry, n)
+	copy(peeked, o.entries[:n])
+	return peeked, nil
+}
+
+// Ack removes delivered entries from the queue by sequence number
+func (o *Outbox) Ack(seqs ...uint64) error {
+	if len(seqs) == 0 {
+		return nil
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	acked := make(map[uint64]bool, len(seqs))
+	for _, seq := range seqs {
+		acked[seq] = true
+	}
+	kept := o.entries[:0]
+	for _, entry := range o.entries {
+		if !acked[entry.Seq] {
+			kept = append(kept, entry)
+		}
+	}
+	o.entries = kept
+	return o.rewriteLocked()
+}
+
+// Stats returns the current queue size and the time of the oldest entry
+func (o *Outbox) Stats() Stats {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return statsOf(o.entries)
+}
+
+// Len returns the number of queued entries
+func (o *Outbox) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.entries)
+}
+
+// ReadStats reads queue statistics from the file at path without opening the
+// queue for writing (used by `beacon debug` while the daemon owns the queue).
+// A missing file is an empty queue.
+func ReadStats(path string) (Stats, error) {
+	entries, _, err := readEntries(path)
+	if err != nil {
+		return Stats{}, err
+	}
+	return statsOf(entries), nil
+}
+
+// trimLocked drops expired entries and the oldest entries beyond maxItems.
+// Returns true if any entry was dropped.
+func (o *Outbox) trimLocked() bool {
+	cutoff := o.now().Add(-o.maxAge)
+	expired := 0
+	for expired < len(o.entries) && o.entries[expired].QueuedAt.Before(cutoff) {
+		expired++
+	}
+	overflow := len(o.entries) - expired - o.maxItems
+	if overflow < 0 {
+		overflow = 0
+	}
+
+	dropped := expired + overflow
+	if dropped == 0 {
+		return false
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"component": "outbox",
+		"expired":   expired,
+		"overflow":  overflow,
+		"remaining": len(o.entries) - dropped,
+	}).Warn("Dropping queued reports beyond outbox limits")
+
+	o.entries = append([]Entry(nil), o.entries[dropped:]...)
+	return true
+}
+
+// appendLocked appends entries to the queue file
+func (o *Outbox) appendLocked(entries []Entry) error {
+	file, err := os.OpenFile(o.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open outbox file: %w", err)
+	}
+	defer file.Close()
+
+	if err := writeEntries(file, entries); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// rewriteLocked replaces the queue file with the in-memory entries
+func (o *Outbox) rewriteLocked() error {
+	tmpPath := o.path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create outbox file: %w", err)
+	}
+
+	err = writeEntries(file, o.entries)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, o.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace outbox file: %w", err)
+	}
+	return nil
+}
+
+// writeEntries writes entries as JSON lines
+func writeEntries(file *os.File, entries []Entry) error {
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("failed to write outbox entry: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write outbox file: %w", err)
+	}
+	return nil
+}
+
+// readEntries reads all valid entries from the queue file and returns the
+// number of unreadable lines skipped (e.g. a line truncated by a crash mid-append)
+func readEntries(path string) ([]Entry, int, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open outbox file: %w", err)
+	}
+	defer file.Close()
+
+	entries := make([]Entry, 0)
+	skipped := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil || len(entry.Payload) == 0 {
+			skipped++
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read outbox file: %w", err)
+	}
+	return entries, skipped, nil
+}
+
+// statsOf summarizes entries in queue order
+func statsOf(entries []Entry) Stats {
+	stats := Stats{Size: len(entries)}
+	if len(entries) > 0 {
+		oldest := entries[0].QueuedAt
+		stats.Oldest = &oldest
+	}
+	return stats
+}
@@ -0,0 +1,233 @@
+package outbox
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"beacon/internal/config"
+	"beacon/internal/logger"
+)
+
+type testPayload struct {
+	ProbeID string `json:"probe_id"`
+}
+
+// initTestLogger initializes the global logger used by outbox warnings
+func initTestLogger(t *testing.T) {
+	if err := logger.InitLogger(&config.Config{
+		LogLevel:      "INFO",
+		LogFile:       filepath.Join(t.TempDir(), "outbox.log"),
+		LogMaxSize:    10,
+		LogMaxAge:     7,
+		LogMaxBackups: 3,
+	}); err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+}
+
+// probeIDs decodes the probe IDs of entries in order
+func probeIDs(t *testing.T, entries []Entry) []string {
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		var payload testPayload
+		if err := json.Unmarshal(entry.Payload, &payload); err != nil {
+			t.Fatalf("Failed to decode payload: %v", err)
+		}
+		ids = append(ids, payload.ProbeID)
+	}
+	return ids
+}
+
+func assertProbeIDs(t *testing.T, entries []Entry, want ...string) {
+	t.Helper()
+	got := probeIDs(t, entries)
+	if len(got) != len(want) {
+		t.Fatalf("Expected entries %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected entries %v, got %v", want, got)
+			return
+		}
+	}
+}
+
+// TestOutboxFIFO tests that entries are returned oldest first and removed on ack
+func TestOutboxFIFO(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange
+	o, err := Open(filepath.Join(t.TempDir(), "outbox.jsonl"), 100, time.Hour)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if err := o.Enqueue(testPayload{"a"}, testPayload{"b"}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if err := o.Enqueue(testPayload{"c"}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	// Act
+	entries, err := o.Peek(2)
+	if err != nil {
+		t.Fatalf("Peek failed: %v", err)
+	}
+
+	// Assert
+	assertProbeIDs(t, entries, "a", "b")
+
+	// Act - ack only the second entry
+	if err := o.Ack(entries[1].Seq); err != nil {
+		t.Fatalf("Ack failed: %v", err)
+	}
+	entries, _ = o.Peek(10)
+
+	// Assert
+	assertProbeIDs(t, entries, "a", "c")
+}
+
+// TestOutboxPersistence tests that queued entries survive reopening the outbox
+func TestOutboxPersistence(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange
+	path := filepath.Join(t.TempDir(), "state", "outbox.jsonl")
+	o, err := Open(path, 100, time.Hour)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	o.Enqueue(testPayload{"a"}, testPayload{"b"}, testPayload{"c"})
+	entries, _ := o.Peek(1)
+	o.Ack(entries[0].Seq)
+
+	// Act
+	reopened, err := Open(path, 100, time.Hour)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	reopened.Enqueue(testPayload{"d"})
+	entries, _ = reopened.Peek(10)
+
+	// Assert - order kept and sequence numbers stay unique
+	assertProbeIDs(t, entries, "b", "c", "d")
+	if entries[2].Seq <= entries[1].Seq {
+		t.Errorf("Expected increasing sequence numbers, got %d after %d", entries[2].Seq, entries[1].Seq)
+	}
+}
+
+// TestOutboxMaxItems tests that the oldest entries are dropped beyond the size cap
+func TestOutboxMaxItems(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	o, err := Open(path, 2, time.Hour)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	// Act
+	o.Enqueue(testPayload{"a"}, testPayload{"b"})
+	o.Enqueue(testPayload{"c"})
+
+	// Assert - in memory and on disk
+	entries, _ := o.Peek(10)
+	assertProbeIDs(t, entries, "b", "c")
+
+	stats, err := ReadStats(path)
+	if err != nil {
+		t.Fatalf("ReadStats failed: %v", err)
+	}
+	if stats.Size != 2 {
+		t.Errorf("Expected 2 entries on disk, got %d", stats.Size)
+	}
+}
+
+// TestOutboxMaxAge tests that expired entries are dropped
+func TestOutboxMaxAge(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange
+	o, err := Open(filepath.Join(t.TempDir(), "outbox.jsonl"), 100, time.Hour)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	now := time.Now()
+	o.now = func() time.Time { return now.Add(-2 * time.Hour) }
+	o.Enqueue(testPayload{"old"})
+	o.now = func() time.Time { return now }
+	o.Enqueue(testPayload{"new"})
+
+	// Act
+	entries, err := o.Peek(10)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Peek failed: %v", err)
+	}
+	assertProbeIDs(t, entries, "new")
+	if stats := o.Stats(); stats.Size != 1 || stats.Oldest == nil || !stats.Oldest.Equal(now.UTC()) {
+		t.Errorf("Unexpected stats after expiry: %+v", stats)
+	}
+}
+
+// TestOutboxTruncatedLine tests recovery from a partially written entry
+func TestOutboxTruncatedLine(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange - valid entry followed by a line cut off mid-write
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	o, err := Open(path, 100, time.Hour)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	o.Enqueue(testPayload{"a"})
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatalf("Failed to open outbox file: %v", err)
+	}
+	file.WriteString(`{"seq":1,"queued_at":"2024-`)
+	file.Close()
+
+	// Act
+	reopened, err := Open(path, 100, time.Hour)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	reopened.Enqueue(testPayload{"b"})
+
+	// Assert - the partial line is discarded and later appends stay readable
+	stats, err := ReadStats(path)
+	if err != nil {
+		t.Fatalf("ReadStats failed: %v", err)
+	}
+	if stats.Size != 2 {
+		t.Errorf("Expected 2 entries on disk, got %d", stats.Size)
+	}
+}
+
+// TestReadStatsMissingFile tests that a missing outbox file is an empty queue
+func TestReadStatsMissingFile(t *testing.T) {
+	stats, err := ReadStats(filepath.Join(t.TempDir(), "missing.jsonl"))
+	if err != nil {
+		t.Fatalf("ReadStats failed: %v", err)
+	}
+	if stats.Size != 0 || stats.Oldest != nil {
+		t.Errorf("Expected empty stats, got %+v", stats)
+	}
+}
+
+// TestOpenInvalidLimits tests that non-positive caps are rejected
+func TestOpenInvalidLimits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	if _, err := Open(path, 0, time.Hour); err == nil {
+		t.Error("Expected error for zero max items")
+	}
+	if _, err := Open(path, 10, 0); err == nil {
+		t.Error("Expected error for zero max age")
+	}
+}
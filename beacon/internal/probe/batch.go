@@ -0,0 +1,48 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Probe batches take a context: when it is cancelled (scheduler stop or
+// reload) or its deadline passes (per-run deadline), the batch stops after
+// aborting the attempt in flight. The aborted attempt is not counted as lost;
+// the result covers the attempts completed so far and is marked Partial. A
+// batch aborted before its first attempt completed returns an error instead.
+
+// batchAbortedError is returned by a batch aborted before any attempt completed
+func batchAbortedError(ctx context.Context) error {
+	return fmt.Errorf("probe batch aborted: %w", ctx.Err())
+}
+
+// deadlineSetter is a connection whose pending I/O can be interrupted
+type deadlineSetter interface {
+	SetDeadline(t time.Time) error
+}
+
+// abortOnDone interrupts pending I/O on conn once ctx is done. The returned
+// function stops the interruption and must be called before conn is closed.
+func abortOnDone(ctx context.Context, conn deadlineSetter) (stop func() bool) {
+	return context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+}
+
+// sleepContext waits for d and reports whether the wait completed before ctx was done
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
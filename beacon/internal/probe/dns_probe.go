@@ -0,0 +1,565 @@
+package probe
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"beacon/internal/config"
+	"beacon/internal/models"
+)
+
+// DNS record types and response codes (RFC 1035, RFC 3596)
+const (
+	dnsTypeA     = 1
+	dnsTypeCNAME = 5
+	dnsTypeMX    = 15
+	dnsTypeTXT   = 16
+	dnsTypeAAAA  = 28
+
+	dnsClassIN = 1
+
+	dnsHeaderLen   = 12
+	dnsMaxUDPSize  = 4096
+	dnsMaxPointers = 32 // Bounds compression pointer chains
+)
+
+// dnsRecordTypes maps configured record type names to query types
+var dnsRecordTypes = map[string]uint16{
+	"A":     dnsTypeA,
+	"AAAA":  dnsTypeAAAA,
+	"CNAME": dnsTypeCNAME,
+	"MX":    dnsTypeMX,
+	"TXT":   dnsTypeTXT,
+}
+
+// dnsRcodeNames holds the names of common response codes for error messages
+var dnsRcodeNames = map[int]string{
+	0: "NOERROR",
+	1: "FORMERR",
+	2: "SERVFAIL",
+	3: "NXDOMAIN",
+	4: "NOTIMP",
+	5: "REFUSED",
+}
+
+// DNSProbeConfig represents DNS resolution probe configuration. Target and
+// Port address the resolver being monitored.
+type DNSProbeConfig struct {
+	ID              string   `yaml:"id"`
+	Type            string   `yaml:"type" validate:"required,eq=dns_probe"`
+	Target          string   `yaml:"target" validate:"required,ip|hostname"`
+	Port            int      `yaml:"port" validate:"required,min=1,max=65535"`
+	QueryName       string   `yaml:"query_name" validate:"required"`
+	RecordType      string   `yaml:"record_type"` // "" = A
+	ExpectedAnswers []string `yaml:"expected_answers"`
+	TimeoutSeconds  int      `yaml:"timeout_seconds" validate:"required,min=1,max=30"`
+	Interval        int      `yaml:"interval" validate:"required,min=60,max=300"`
+	Count           int      `yaml:"count" validate:"required,min=1,max=100"`
+}
+
+func init() {
+	RegisterProbeType("dns_probe", func(cfg config.ProbeConfig) (ProbeSpec, error) {
+		spec := &DNSProbeConfig{
+			ID:             cfg.ID,
+			Type:           cfg.Type,
+			Target:         cfg.Target,
+			Port:           cfg.Port,
+			TimeoutSeconds: cfg.TimeoutSeconds,
+			Interval:       cfg.Interval,
+			Count:          cfg.Count,
+		}
+		return spec, DecodeOptions(cfg, spec)
+	}, "port", "query_name", "record_type", "expected_answers")
+
+	RegisterResultMetrics("dns_probe",
+		ResultMetric{Key: models.MetricRcode, Gauge: &ResultGauge{
+			Name: "beacon_dns_rcode",
+			Help: "Response code of the last response to a dns_probe (-1 if none was received)",
+		}},
+		ResultMetric{Key: models.MetricAnswerCount, Gauge: &ResultGauge{
+			Name: "beacon_dns_answer_count",
+			Help: "Answers of the queried type in the last response to a dns_probe",
+		}},
+		ResultMetric{Key: models.MetricAnswersMatch, Gauge: &ResultGauge{
+			Name: "beacon_dns_answers_match",
+			Help: "Whether the last answers of a dns_probe matched expected_answers (1=match, 0=mismatch)",
+		}},
+	)
+}
+
+// NewProber creates the scheduler's probe engine for the configuration
+func (c *DNSProbeConfig) NewProber() Prober {
+	return NewDNSProber(*c)
+}
+
+// Validate validates the DNS probe configuration
+func (c *DNSProbeConfig) Validate() error {
+	if c.Type != "dns_probe" {
+		return fmt.Errorf("invalid probe type: %s (must be 'dns_probe')", c.Type)
+	}
+
+	if c.Target == "" {
+		return fmt.Errorf("probe target cannot be empty")
+	}
+
+	if net.ParseIP(c.Target) == nil {
+		if err := validateHostname(c.Target); err != nil {
+			return fmt.Errorf("invalid probe target '%s': %w", c.Target, err)
+		}
+	}
+
+	if c.Port < 1 || c.Port > 65535 {
+		return fmt.Errorf("invalid port %d, must be between 1 and 65535", c.Port)
+	}
+
+	if _, err := encodeDNSName(c.QueryName); err != nil {
+		return fmt.Errorf("invalid query name '%s': %w", c.QueryName, err)
+	}
+
+	if _, ok := dnsRecordTypes[c.recordType()]; !ok {
+		return fmt.Errorf("invalid record type '%s', must be one of: A, AAAA, CNAME, MX, TXT", c.RecordType)
+	}
+
+	if err := validateSchedule(c.Interval, c.Count, c.TimeoutSeconds); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// recordType returns the upper-cased record type, defaulting to A
+func (c *DNSProbeConfig) recordType() string {
+	if c.RecordType == "" {
+		return "A"
+	}
+	return strings.ToUpper(c.RecordType)
+}
+
+// DNSProber represents a DNS resolution probe engine
+type DNSProber struct {
+	config DNSProbeConfig
+}
+
+// NewDNSProber creates a new DNS prober with the given configuration
+func NewDNSProber(config DNSProbeConfig) *DNSProber {
+	return &DNSProber{config: config}
+}
+
+// dnsResponse is the part of a DNS response the probe reports on
+type dnsResponse struct {
+	rcode     int
+	answers   []string // Answers of the queried type in presentation format
+	truncated bool
+}
+
+// ExecuteBatch sends count queries to the resolver and calculates core
+// metrics on resolution latency. A query counts as lost when it times out,
+// the rcode is not NOERROR, or the answers do not match the expected set.
+// The batch stops early when ctx is done, see batch.go.
+func (p *DNSProber) ExecuteBatch(ctx context.Context, count int) (*models.ProbeResult, error) {
+	if count < 1 || count > 100 {
+		return nil, fmt.Errorf("invalid count %d, must be between 1 and 100", count)
+	}
+
+	if err := p.config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	qtype := dnsRecordTypes[p.config.recordType()]
+	samples := make([]SamplePoint, 0, count)
+	receivedPackets := 0
+	var last *dnsResponse
+	answersMatch := false
+	var lastErr error
+	aborted := false
+
+	for i := 0; i < count; i++ {
+		if ctx.Err() != nil {
+			aborted = true
+			break
+		}
+		start := time.Now()
+		resp, err := p.query(ctx, qtype)
+		rtt := time.Since(start)
+		if err != nil && ctx.Err() != nil {
+			aborted = true // Aborted in flight, not a lost query
+			break
+		}
+		if err == nil {
+			last = resp
+			answersMatch = p.answersMatch(resp.answers)
+			if resp.rcode != 0 {
+				err = fmt.Errorf("resolver returned %s", rcodeName(resp.rcode))
+			} else if !answersMatch {
+				err = fmt.Errorf("answers %v do not match expected %v", resp.answers, p.config.ExpectedAnswers)
+			}
+		}
+		if err != nil {
+			lastErr = err
+			errorClass := classifyError(err)
+			if resp != nil {
+				errorClass = models.SampleErrorResponse
+			}
+			samples = append(samples, SamplePoint{
+				RTTMs:      0,
+				Timestamp:  time.Now().Format(time.RFC3339),
+				Success:    false,
+				ErrorClass: errorClass,
+			})
+			continue
+		}
+
+		receivedPackets++
+		samples = append(samples, SamplePoint{
+			RTTMs:     durationMs(rtt),
+			Timestamp: time.Now().Format(time.RFC3339),
+			Success:   true,
+		})
+	}
+
+	if aborted && len(samples) == 0 {
+		return nil, batchAbortedError(ctx)
+	}
+	sent := len(samples)
+
+	metrics := NewCoreMetricsCollector().CalculateFromSamples(samples, sent, receivedPackets)
+
+	// rcode -1 means no response was received at all. The answer count only
+	// includes answers of the queried type, not e.g. CNAMEs leading to them.
+	rcode, answerCount := -1, 0
+	if last != nil {
+		rcode, answerCount = last.rcode, len(last.answers)
+	}
+
+	errorMessage := ""
+	if receivedPackets == 0 && lastErr != nil {
+		errorMessage = lastErr.Error()
+	}
+
+	result := models.NewProbeResult("dns_probe", p.config.Target, receivedPackets > 0, map[string]interface{}{
+		models.MetricRTTMs:           metrics.RTTMs,
+		models.MetricRTTMedianMs:     metrics.RTTMedianMs,
+		models.MetricJitterMs:        metrics.JitterMs,
+		models.MetricVarianceMs:      metrics.RTTVarianceMs,
+		models.MetricPacketLossRate:  metrics.PacketLossRate,
+		models.MetricSampleCount:     metrics.SampleCount,
+		models.MetricSentPackets:     sent,
+		models.MetricReceivedPackets: receivedPackets,
+		models.MetricRcode:           rcode,
+		models.MetricAnswerCount:     answerCount,
+		models.MetricAnswersMatch:    answersMatch,
+	}, errorMessage)
+	result.ProbeID = p.ProbeID()
+	metrics.SetMetrics(result.Metrics)
+	result.Samples = rawSamples(samples)
+	result.Port = p.config.Port
+	result.Partial = aborted
+
+	return result, nil
+}
+
+// query sends one query over UDP, retrying over TCP if the response is truncated
+func (p *DNSProber) query(ctx context.Context, qtype uint16) (*dnsResponse, error) {
+	id := uint16(rand.Intn(1 << 16))
+	msg, err := buildDNSQuery(id, p.config.QueryName, qtype)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.exchange(ctx, "udp", id, qtype, msg)
+	if err == nil && resp.truncated {
+		resp, err = p.exchange(ctx, "tcp", id, qtype, msg)
+	}
+	return resp, err
+}
+
+// exchange sends msg to the resolver and reads the response with the given ID
+func (p *DNSProber) exchange(ctx context.Context, network string, id, qtype uint16, msg []byte) (*dnsResponse, error) {
+	timeout := time.Duration(p.config.TimeoutSeconds) * time.Second
+	address := net.JoinHostPort(p.config.Target, strconv.Itoa(p.config.Port))
+
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, fmt.Errorf("connect to resolver failed: %w", err)
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, fmt.Errorf("set deadline failed: %w", err)
+	}
+	defer abortOnDone(ctx, conn)()
+
+	if network == "tcp" {
+		// DNS over TCP prefixes each message with its length (RFC 1035 4.2.2)
+		framed := make([]byte, 2+len(msg))
+		binary.BigEndian.PutUint16(framed, uint16(len(msg)))
+		copy(framed[2:], msg)
+		if _, err := conn.Write(framed); err != nil {
+			return nil, fmt.Errorf("send failed: %w", err)
+		}
+
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return nil, fmt.Errorf("no response: %w", err)
+		}
+		buffer := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, buffer); err != nil {
+			return nil, fmt.Errorf("no response: %w", err)
+		}
+		return parseDNSResponse(buffer, id, qtype)
+	}
+
+	if _, err := conn.Write(msg); err != nil {
+		return nil, fmt.Errorf("send failed: %w", err)
+	}
+
+	buffer := make([]byte, dnsMaxUDPSize)
+	for {
+		n, err := conn.Read(buffer)
+		if err != nil {
+			return nil, fmt.Errorf("no response: %w", err)
+		}
+		resp, err := parseDNSResponse(buffer[:n], id, qtype)
+		if errors.Is(err, errDNSIDMismatch) {
+			continue // Late reply to an earlier query
+		}
+		return resp, err
+	}
+}
+
+// answersMatch reports whether answers equal the expected set, ignoring order,
+// case and trailing dots. It is always true when no set is configured.
+func (p *DNSProber) answersMatch(answers []string) bool {
+	if len(p.config.ExpectedAnswers) == 0 {
+		return true
+	}
+
+	normalize := func(values []string) []string {
+		set := make(map[string]bool, len(values))
+		for _, v := range values {
+			set[strings.TrimSuffix(strings.ToLower(strings.TrimSpace(v)), ".")] = true
+		}
+		out := make([]string, 0, len(set))
+		for v := range set {
+			out = append(out, v)
+		}
+		sort.Strings(out)
+		return out
+	}
+
+	got, want := normalize(answers), normalize(p.config.ExpectedAnswers)
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ProbeID returns the configured probe ID, or a stable key derived from type,
+// resolver address, query name and record type, e.g.
+// "dns_probe:10.0.0.53:53/example.com/AAAA". The query is part of the key so
+// that several probes against one resolver are reported separately.
+func (p *DNSProber) ProbeID() string {
+	if p.config.ID != "" {
+		return p.config.ID
+	}
+	queryName := strings.TrimSuffix(strings.ToLower(p.config.QueryName), ".")
+	return fmt.Sprintf("%s/%s/%s", models.ProbeKey("dns_probe", p.config.Target, p.config.Port), queryName, p.config.recordType())
+}
+
+// rcodeName returns the mnemonic of a response code, e.g. "NXDOMAIN"
+func rcodeName(rcode int) string {
+	if name, ok := dnsRcodeNames[rcode]; ok {
+		return name
+	}
+	return fmt.Sprintf("rcode %d", rcode)
+}
+
+// buildDNSQuery builds a recursive query for one name and type
+func buildDNSQuery(id uint16, name string, qtype uint16) ([]byte, error) {
+	qname, err := encodeDNSName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := make([]byte, dnsHeaderLen, dnsHeaderLen+len(qname)+4)
+	binary.BigEndian.PutUint16(msg[0:], id)
+	binary.BigEndian.PutUint16(msg[2:], 0x0100) // RD
+	binary.BigEndian.PutUint16(msg[4:], 1)      // QDCOUNT
+	msg = append(msg, qname...)
+	msg = binary.BigEndian.AppendUint16(msg, qtype)
+	msg = binary.BigEndian.AppendUint16(msg, dnsClassIN)
+	return msg, nil
+}
+
+// encodeDNSName encodes a domain name as a sequence of length-prefixed labels
+func encodeDNSName(name string) ([]byte, error) {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" {
+		return nil, fmt.Errorf("name cannot be empty")
+	}
+	if len(name) > 253 {
+		return nil, fmt.Errorf("name too long (max 253 characters)")
+	}
+
+	encoded := make([]byte, 0, len(name)+2)
+	for _, label := range strings.Split(name, ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return nil, fmt.Errorf("invalid label length in %q", name)
+		}
+		if strings.ContainsAny(label, " \t") {
+			return nil, fmt.Errorf("label %q contains whitespace", label)
+		}
+		encoded = append(encoded, byte(len(label)))
+		encoded = append(encoded, label...)
+	}
+	return append(encoded, 0), nil
+}
+
+var (
+	// errDNSIDMismatch reports a response to a different query
+	errDNSIDMismatch = errors.New("DNS response ID mismatch")
+	// errDNSMalformed reports a response that cannot be parsed
+	errDNSMalformed = errors.New("malformed DNS response")
+)
+
+// parseDNSResponse decodes the header and answer section of a response,
+// keeping answers of the queried type
+func parseDNSResponse(msg []byte, id, qtype uint16) (*dnsResponse, error) {
+	if len(msg) < dnsHeaderLen {
+		return nil, errDNSMalformed
+	}
+	if binary.BigEndian.Uint16(msg[0:]) != id {
+		return nil, errDNSIDMismatch
+	}
+	flags := binary.BigEndian.Uint16(msg[2:])
+	if flags&0x8000 == 0 {
+		return nil, fmt.Errorf("%w: not a response", errDNSMalformed)
+	}
+
+	resp := &dnsResponse{
+		rcode:     int(flags & 0x000f),
+		truncated: flags&0x0200 != 0,
+	}
+	if resp.truncated {
+		return resp, nil
+	}
+
+	offset := dnsHeaderLen
+	for i := 0; i < int(binary.BigEndian.Uint16(msg[4:])); i++ {
+		_, next, err := readDNSName(msg, offset)
+		if err != nil || next+4 > len(msg) {
+			return nil, errDNSMalformed
+		}
+		offset = next + 4 // QTYPE, QCLASS
+	}
+
+	for i := 0; i < int(binary.BigEndian.Uint16(msg[6:])); i++ {
+		_, next, err := readDNSName(msg, offset)
+		if err != nil || next+10 > len(msg) {
+			return nil, errDNSMalformed
+		}
+		rrType := binary.BigEndian.Uint16(msg[next:])
+		rdLength := int(binary.BigEndian.Uint16(msg[next+8:]))
+		rdStart := next + 10
+		if rdStart+rdLength > len(msg) {
+			return nil, errDNSMalformed
+		}
+
+		if rrType == qtype {
+			answer, err := decodeRData(msg, rrType, rdStart, rdLength)
+			if err != nil {
+				return nil, err
+			}
+			resp.answers = append(resp.answers, answer)
+		}
+		offset = rdStart + rdLength
+	}
+
+	return resp, nil
+}
+
+// decodeRData formats record data: addresses for A/AAAA, the target name for
+// CNAME, "<preference> <exchange>" for MX and the joined strings for TXT
+func decodeRData(msg []byte, rrType uint16, start, length int) (string, error) {
+	rdata := msg[start : start+length]
+	switch rrType {
+	case dnsTypeA, dnsTypeAAAA:
+		if (rrType == dnsTypeA && length != net.IPv4len) || (rrType == dnsTypeAAAA && length != net.IPv6len) {
+			return "", errDNSMalformed
+		}
+		return net.IP(rdata).String(), nil
+	case dnsTypeCNAME:
+		name, _, err := readDNSName(msg, start)
+		return name, err
+	case dnsTypeMX:
+		if length < 3 {
+			return "", errDNSMalformed
+		}
+		name, _, err := readDNSName(msg, start+2)
+		return fmt.Sprintf("%d %s", binary.BigEndian.Uint16(rdata), name), err
+	case dnsTypeTXT:
+		var text strings.Builder
+		for i := 0; i < length; {
+			n := int(rdata[i])
+			if i+1+n > length {
+				return "", errDNSMalformed
+			}
+			text.Write(rdata[i+1 : i+1+n])
+			i += 1 + n
+		}
+		return text.String(), nil
+	}
+	return "", fmt.Errorf("unsupported record type %d", rrType)
+}
+
+// readDNSName reads a possibly compressed name at offset and returns it along
+// with the offset just past it in the original message
+func readDNSName(msg []byte, offset int) (string, int, error) {
+	var labels []string
+	next := -1
+	for pointers := 0; ; {
+		if offset >= len(msg) {
+			return "", 0, errDNSMalformed
+		}
+		length := int(msg[offset])
+		switch {
+		case length == 0:
+			if next < 0 {
+				next = offset + 1
+			}
+			return strings.Join(labels, "."), next, nil
+		case length&0xc0 == 0xc0:
+			if offset+1 >= len(msg) || pointers >= dnsMaxPointers {
+				return "", 0, errDNSMalformed
+			}
+			if next < 0 {
+				next = offset + 2
+			}
+			offset = int(binary.BigEndian.Uint16(msg[offset:]) & 0x3fff)
+			pointers++
+		case length&0xc0 != 0:
+			return "", 0, errDNSMalformed
+		default:
+			if offset+1+length > len(msg) {
+				return "", 0, errDNSMalformed
+			}
+			labels = append(labels, string(msg[offset+1:offset+1+length]))
+			offset += 1 + length
+		}
+	}
+}
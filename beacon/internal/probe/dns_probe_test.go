@@ -0,0 +1,394 @@
+package probe
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"beacon/internal/models"
+)
+
+// stubRecord is an answer served by dnsStub
+type stubRecord struct {
+	rrType uint16
+	rdata  []byte
+}
+
+// dnsStub is a minimal DNS server answering every query with fixed records
+type dnsStub struct {
+	conn      net.PacketConn
+	rcode     uint16
+	records   []stubRecord
+	truncate  bool // Set TC on UDP responses
+	drop      bool // Never answer
+	tcpServed chan struct{}
+}
+
+// startDNSStub starts a UDP DNS stub on localhost
+func startDNSStub(t *testing.T, stub *dnsStub) int {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start DNS stub: %v", err)
+	}
+	stub.conn = conn
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buffer := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			if stub.drop {
+				continue
+			}
+			conn.WriteTo(stub.respond(buffer[:n], stub.truncate), addr)
+		}
+	}()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+// serveTCP answers queries over TCP on the stub's port
+func (s *dnsStub) serveTCP(t *testing.T) {
+	t.Helper()
+	port := s.conn.LocalAddr().(*net.UDPAddr).Port
+	listener, err := net.ListenTCP("tcp", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port})
+	if err != nil {
+		t.Skipf("TCP port %d unavailable for DNS stub: %v", port, err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	s.tcpServed = make(chan struct{}, 1)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			var length [2]byte
+			if _, err := io.ReadFull(conn, length[:]); err == nil {
+				query := make([]byte, binary.BigEndian.Uint16(length[:]))
+				if _, err := io.ReadFull(conn, query); err == nil {
+					resp := s.respond(query, false)
+					conn.Write(binary.BigEndian.AppendUint16(nil, uint16(len(resp))))
+					conn.Write(resp)
+					s.tcpServed <- struct{}{}
+				}
+			}
+			conn.Close()
+		}
+	}()
+}
+
+// respond echoes the question and appends the stub records, naming each
+// with a compression pointer to the question
+func (s *dnsStub) respond(query []byte, truncate bool) []byte {
+	_, questionEnd, err := readDNSName(query, dnsHeaderLen)
+	if err != nil {
+		return nil
+	}
+	resp := append([]byte{}, query[:questionEnd+4]...)
+	flags := uint16(0x8180) | s.rcode // QR, RD, RA
+	if truncate {
+		flags |= 0x0200
+	}
+	binary.BigEndian.PutUint16(resp[2:], flags)
+	binary.BigEndian.PutUint16(resp[6:], uint16(len(s.records)))
+
+	for _, rr := range s.records {
+		resp = append(resp, 0xc0, dnsHeaderLen)
+		resp = binary.BigEndian.AppendUint16(resp, rr.rrType)
+		resp = binary.BigEndian.AppendUint16(resp, dnsClassIN)
+		resp = binary.BigEndian.AppendUint32(resp, 300)
+		resp = binary.BigEndian.AppendUint16(resp, uint16(len(rr.rdata)))
+		resp = append(resp, rr.rdata...)
+	}
+	return resp
+}
+
+func mustEncodeName(t *testing.T, name string) []byte {
+	t.Helper()
+	encoded, err := encodeDNSName(name)
+	if err != nil {
+		t.Fatalf("encodeDNSName(%q) failed: %v", name, err)
+	}
+	return encoded
+}
+
+func newTestDNSProber(port int, recordType string, expected ...string) *DNSProber {
+	return NewDNSProber(DNSProbeConfig{
+		Type:            "dns_probe",
+		Target:          "127.0.0.1",
+		Port:            port,
+		QueryName:       "svc.internal.example",
+		RecordType:      recordType,
+		ExpectedAnswers: expected,
+		TimeoutSeconds:  1,
+		Interval:        60,
+		Count:           10,
+	})
+}
+
+// TestDNSProbeConfigValidation tests DNS probe configuration validation
+func TestDNSProbeConfigValidation(t *testing.T) {
+	valid := DNSProbeConfig{Type: "dns_probe", Target: "10.0.0.53", Port: 53, QueryName: "example.com", TimeoutSeconds: 2, Interval: 60, Count: 10}
+
+	tests := []struct {
+		name    string
+		modify  func(c *DNSProbeConfig)
+		wantErr bool
+		errMsg  string
+	}{
+		{name: "valid config with default record type", modify: func(c *DNSProbeConfig) {}, wantErr: false},
+		{name: "valid lower-case record type", modify: func(c *DNSProbeConfig) { c.RecordType = "txt" }, wantErr: false},
+		{name: "valid underscore name", modify: func(c *DNSProbeConfig) { c.QueryName = "_dmarc.example.com."; c.RecordType = "TXT" }, wantErr: false},
+		{name: "invalid type", modify: func(c *DNSProbeConfig) { c.Type = "udp_ping" }, wantErr: true, errMsg: "invalid probe type"},
+		{name: "missing port", modify: func(c *DNSProbeConfig) { c.Port = 0 }, wantErr: true, errMsg: "invalid port"},
+		{name: "empty query name", modify: func(c *DNSProbeConfig) { c.QueryName = "" }, wantErr: true, errMsg: "invalid query name"},
+		{name: "empty label", modify: func(c *DNSProbeConfig) { c.QueryName = "a..example.com" }, wantErr: true, errMsg: "invalid query name"},
+		{name: "unsupported record type", modify: func(c *DNSProbeConfig) { c.RecordType = "SRV" }, wantErr: true, errMsg: "invalid record type"},
+		{name: "invalid count", modify: func(c *DNSProbeConfig) { c.Count = 0 }, wantErr: true, errMsg: "invalid count"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid
+			tt.modify(&config)
+			err := config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && !contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+// TestBuildDNSQuery tests query encoding
+func TestBuildDNSQuery(t *testing.T) {
+	msg, err := buildDNSQuery(0xbeef, "www.example.com.", dnsTypeAAAA)
+	if err != nil {
+		t.Fatalf("buildDNSQuery failed: %v", err)
+	}
+
+	if id := binary.BigEndian.Uint16(msg); id != 0xbeef {
+		t.Errorf("Expected ID 0xbeef, got %#x", id)
+	}
+	if qdcount := binary.BigEndian.Uint16(msg[4:]); qdcount != 1 {
+		t.Errorf("Expected 1 question, got %d", qdcount)
+	}
+	name, next, err := readDNSName(msg, dnsHeaderLen)
+	if err != nil || name != "www.example.com" {
+		t.Errorf("Expected question name www.example.com, got %q (%v)", name, err)
+	}
+	if qtype := binary.BigEndian.Uint16(msg[next:]); qtype != dnsTypeAAAA {
+		t.Errorf("Expected QTYPE AAAA, got %d", qtype)
+	}
+}
+
+// TestReadDNSName_PointerLoop tests that compression pointer loops are rejected
+func TestReadDNSName_PointerLoop(t *testing.T) {
+	msg := make([]byte, dnsHeaderLen+2)
+	msg[dnsHeaderLen], msg[dnsHeaderLen+1] = 0xc0, dnsHeaderLen // Points at itself
+
+	if _, _, err := readDNSName(msg, dnsHeaderLen); err == nil {
+		t.Error("Expected error for compression pointer loop")
+	}
+}
+
+// TestDNSProber_ARecordMatch tests latency, rcode and answer matching for A records
+func TestDNSProber_ARecordMatch(t *testing.T) {
+	// Arrange
+	port := startDNSStub(t, &dnsStub{records: []stubRecord{
+		{rrType: dnsTypeA, rdata: []byte{10, 0, 0, 2}},
+		{rrType: dnsTypeA, rdata: []byte{10, 0, 0, 1}},
+	}})
+	prober := newTestDNSProber(port, "A", "10.0.0.1", "10.0.0.2")
+
+	// Act
+	result, err := prober.ExecuteBatch(context.Background(), 5)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if !result.Success || result.Type != "dns_probe" || result.Port != port {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+	if loss := result.MetricFloat(models.MetricPacketLossRate); loss != 0 {
+		t.Errorf("Expected no failed queries, got %.2f%%", loss)
+	}
+	if rcode := result.MetricFloat(models.MetricRcode); rcode != 0 {
+		t.Errorf("Expected rcode 0, got %.0f", rcode)
+	}
+	if answers := result.MetricFloat(models.MetricAnswerCount); answers != 2 {
+		t.Errorf("Expected 2 answers, got %.0f", answers)
+	}
+	if match := result.MetricFloat(models.MetricAnswersMatch); match != 1 {
+		t.Error("Expected answers to match the expected set")
+	}
+	if rtt := result.MetricFloat(models.MetricRTTMs); rtt <= 0 {
+		t.Errorf("Expected positive resolution latency, got %.2f", rtt)
+	}
+}
+
+// TestDNSProber_AnswerMismatch tests that unexpected answers fail the query
+func TestDNSProber_AnswerMismatch(t *testing.T) {
+	port := startDNSStub(t, &dnsStub{records: []stubRecord{{rrType: dnsTypeA, rdata: []byte{192, 0, 2, 1}}}})
+
+	result, err := newTestDNSProber(port, "A", "10.0.0.1").ExecuteBatch(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if result.Success {
+		t.Error("Expected probe to fail on unexpected answers")
+	}
+	if match := result.MetricFloat(models.MetricAnswersMatch); match != 0 {
+		t.Error("Expected answers_match to be false")
+	}
+	if !contains(result.ErrorMessage, "do not match expected") {
+		t.Errorf("Unexpected error message: %q", result.ErrorMessage)
+	}
+}
+
+// TestDNSProber_NXDomain tests that a non-NOERROR rcode is reported and fails the query
+func TestDNSProber_NXDomain(t *testing.T) {
+	port := startDNSStub(t, &dnsStub{rcode: 3})
+
+	result, err := newTestDNSProber(port, "A").ExecuteBatch(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if result.Success {
+		t.Error("Expected probe to fail on NXDOMAIN")
+	}
+	if rcode := result.MetricFloat(models.MetricRcode); rcode != 3 {
+		t.Errorf("Expected rcode 3, got %.0f", rcode)
+	}
+	if !contains(result.ErrorMessage, "NXDOMAIN") {
+		t.Errorf("Expected NXDOMAIN in error message, got %q", result.ErrorMessage)
+	}
+}
+
+// TestDNSProber_RecordTypes tests answer decoding for each supported record type
+func TestDNSProber_RecordTypes(t *testing.T) {
+	tests := []struct {
+		recordType string
+		record     stubRecord
+		expected   string
+	}{
+		{recordType: "AAAA", record: stubRecord{rrType: dnsTypeAAAA, rdata: net.ParseIP("2001:db8::1")}, expected: "2001:db8::1"},
+		{recordType: "CNAME", record: stubRecord{rrType: dnsTypeCNAME, rdata: mustEncodeName(t, "lb.example.net")}, expected: "LB.example.net."},
+		{recordType: "MX", record: stubRecord{rrType: dnsTypeMX, rdata: append([]byte{0, 10}, mustEncodeName(t, "mail.example.com")...)}, expected: "10 mail.example.com"},
+		{recordType: "TXT", record: stubRecord{rrType: dnsTypeTXT, rdata: []byte("\x05v=spf\x0c1 -all tail.")}, expected: "v=spf1 -all tail."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.recordType, func(t *testing.T) {
+			port := startDNSStub(t, &dnsStub{records: []stubRecord{tt.record}})
+
+			result, err := newTestDNSProber(port, tt.recordType, tt.expected).ExecuteBatch(context.Background(), 1)
+			if err != nil {
+				t.Fatalf("ExecuteBatch failed: %v", err)
+			}
+			if !result.Success {
+				t.Errorf("Expected answer %q to match: %s", tt.expected, result.ErrorMessage)
+			}
+		})
+	}
+}
+
+// TestDNSProber_OnlyQueriedTypeMatched tests that CNAMEs leading to an A answer are neither counted nor matched
+func TestDNSProber_OnlyQueriedTypeMatched(t *testing.T) {
+	port := startDNSStub(t, &dnsStub{records: []stubRecord{
+		{rrType: dnsTypeCNAME, rdata: mustEncodeName(t, "lb.example.net")},
+		{rrType: dnsTypeA, rdata: []byte{10, 0, 0, 1}},
+	}})
+
+	result, err := newTestDNSProber(port, "A", "10.0.0.1").ExecuteBatch(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if !result.Success {
+		t.Errorf("Expected A answer to match: %s", result.ErrorMessage)
+	}
+	if answers := result.MetricFloat(models.MetricAnswerCount); answers != 1 {
+		t.Errorf("Expected answer count 1, got %.0f", answers)
+	}
+}
+
+// TestDNSProber_Timeout tests a resolver that never answers
+func TestDNSProber_Timeout(t *testing.T) {
+	port := startDNSStub(t, &dnsStub{drop: true})
+
+	result, err := newTestDNSProber(port, "A").ExecuteBatch(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if result.Success || !contains(result.ErrorMessage, "no response") {
+		t.Errorf("Expected timeout failure, got success=%v error=%q", result.Success, result.ErrorMessage)
+	}
+	if rcode := result.MetricFloat(models.MetricRcode); rcode != -1 {
+		t.Errorf("Expected rcode -1 without a response, got %.0f", rcode)
+	}
+}
+
+// TestDNSProber_TruncatedRetriesOverTCP tests the TCP fallback for truncated responses
+func TestDNSProber_TruncatedRetriesOverTCP(t *testing.T) {
+	stub := &dnsStub{truncate: true, records: []stubRecord{{rrType: dnsTypeA, rdata: []byte{10, 0, 0, 1}}}}
+	port := startDNSStub(t, stub)
+	stub.serveTCP(t)
+
+	result, err := newTestDNSProber(port, "A", "10.0.0.1").ExecuteBatch(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if !result.Success {
+		t.Errorf("Expected TCP retry to succeed: %s", result.ErrorMessage)
+	}
+	select {
+	case <-stub.tcpServed:
+	default:
+		t.Error("Expected the query to be retried over TCP")
+	}
+}
+
+// TestDNSProber_ProbeIDIncludesQuery tests that probes against one resolver get distinct IDs
+func TestDNSProber_ProbeIDIncludesQuery(t *testing.T) {
+	base := DNSProbeConfig{Type: "dns_probe", Target: "10.0.0.53", Port: 53, QueryName: "example.com", TimeoutSeconds: 2, Interval: 60, Count: 10}
+
+	other := base
+	other.QueryName = "example.org"
+	aaaa := base
+	aaaa.RecordType = "aaaa"
+	explicit := base
+	explicit.ID = "resolver-check"
+
+	ids := map[string]string{
+		NewDNSProber(base).ProbeID():  "example.com A",
+		NewDNSProber(other).ProbeID(): "example.org A",
+		NewDNSProber(aaaa).ProbeID():  "example.com AAAA",
+	}
+	if len(ids) != 3 {
+		t.Errorf("Expected 3 distinct probe IDs, got %v", ids)
+	}
+
+	if id := NewDNSProber(base).ProbeID(); id != "dns_probe:10.0.0.53:53/example.com/A" {
+		t.Errorf("Expected derived probe ID dns_probe:10.0.0.53:53/example.com/A, got %s", id)
+	}
+	trailingDot := base
+	trailingDot.QueryName = "Example.COM."
+	if NewDNSProber(trailingDot).ProbeID() != NewDNSProber(base).ProbeID() {
+		t.Error("Expected query name case and trailing dot not to change the probe ID")
+	}
+	if id := NewDNSProber(explicit).ProbeID(); id != "resolver-check" {
+		t.Errorf("Expected configured probe ID resolver-check, got %s", id)
+	}
+}
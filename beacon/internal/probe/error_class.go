@@ -0,0 +1,74 @@
+package probe
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+
+	"beacon/internal/models"
+)
+
+// classifyError maps the error of a failed probe attempt to a coarse
+// models.SampleError* class for raw sample reporting
+func classifyError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var netErr net.Error
+	if errors.Is(err, os.ErrDeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return models.SampleErrorTimeout
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return models.SampleErrorDNS
+	}
+
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return models.SampleErrorRefused
+	case errors.Is(err, syscall.ECONNRESET):
+		return models.SampleErrorReset
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return models.SampleErrorUnreachable
+	}
+
+	var (
+		recordErr    tls.RecordHeaderError
+		alertErr     tls.AlertError
+		authorityErr x509.UnknownAuthorityError
+		invalidErr   x509.CertificateInvalidError
+		hostnameErr  x509.HostnameError
+		verifyErr    *tls.CertificateVerificationError
+	)
+	if errors.As(err, &recordErr) || errors.As(err, &alertErr) || errors.As(err, &authorityErr) ||
+		errors.As(err, &invalidErr) || errors.As(err, &hostnameErr) || errors.As(err, &verifyErr) {
+		return models.SampleErrorTLS
+	}
+
+	// Probes that wait for replies themselves report timeouts as plain errors
+	message := err.Error()
+	if strings.Contains(message, "timeout") || strings.Contains(message, "no response") {
+		return models.SampleErrorTimeout
+	}
+	return models.SampleErrorOther
+}
+
+// rawSamples converts the sample points of a batch for raw sample reporting
+func rawSamples(samples []SamplePoint) []models.RawSample {
+	raw := make([]models.RawSample, len(samples))
+	for i, s := range samples {
+		raw[i] = models.RawSample{
+			Timestamp:  s.Timestamp,
+			Success:    s.Success,
+			RTTMs:      s.RTTMs,
+			ErrorClass: s.ErrorClass,
+		}
+	}
+	return raw
+}
@@ -0,0 +1,38 @@
+package probe
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+)
+
+// TestClassifyError tests mapping of probe errors to raw sample error classes
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "no error", err: nil, expected: ""},
+		{name: "deadline exceeded", err: fmt.Errorf("read failed: %w", os.ErrDeadlineExceeded), expected: "timeout"},
+		{name: "plain timeout", err: errors.New("timeout waiting for echo reply"), expected: "timeout"},
+		{name: "connection refused", err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, expected: "refused"},
+		{name: "connection reset", err: &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, expected: "reset"},
+		{name: "host unreachable", err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.EHOSTUNREACH)}, expected: "unreachable"},
+		{name: "name not found", err: &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "nx.example.com", IsNotFound: true}}, expected: "dns"},
+		{name: "unknown authority", err: fmt.Errorf("handshake failed: %w", x509.UnknownAuthorityError{}), expected: "tls"},
+		{name: "other", err: errors.New("invalid configuration"), expected: "other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := classifyError(tt.err); result != tt.expected {
+				t.Errorf("classifyError(%v) = %q, want %q", tt.err, result, tt.expected)
+			}
+		})
+	}
+}
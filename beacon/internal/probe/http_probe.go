@@ -0,0 +1,363 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"beacon/internal/config"
+	"beacon/internal/models"
+)
+
+const (
+	// defaultExpectedStatus is the status an http_probe expects when none is configured
+	defaultExpectedStatus = http.StatusOK
+	// maxHTTPBodyBytes bounds how much of a response body is read for body checks
+	maxHTTPBodyBytes = 1 << 20
+)
+
+// HTTPProbeConfig represents HTTP/HTTPS probe configuration
+type HTTPProbeConfig struct {
+	ID             string `yaml:"id"`
+	Type           string `yaml:"type" validate:"required,eq=http_probe"`
+	URL            string `yaml:"target" validate:"required,url"`
+	TimeoutSeconds int    `yaml:"timeout_seconds" validate:"required,min=1,max=30"`
+	Interval       int    `yaml:"interval" validate:"required,min=60,max=300"`
+	Count          int    `yaml:"count" validate:"required,min=1,max=100"`
+	ExpectedStatus int    `yaml:"expected_status"` // 0 = 200
+	BodyContains   string `yaml:"body_contains"`
+	BodyRegex      string `yaml:"body_regex"`
+}
+
+func init() {
+	RegisterProbeType("http_probe", func(cfg config.ProbeConfig) (ProbeSpec, error) {
+		spec := &HTTPProbeConfig{
+			ID:             cfg.ID,
+			Type:           cfg.Type,
+			URL:            cfg.Target,
+			TimeoutSeconds: cfg.TimeoutSeconds,
+			Interval:       cfg.Interval,
+			Count:          cfg.Count,
+		}
+		return spec, DecodeOptions(cfg, spec)
+	}, "expected_status", "body_contains", "body_regex")
+
+	phaseGauge := func(phase string) *ResultGauge {
+		return &ResultGauge{
+			Name:        "beacon_http_phase_ms",
+			Help:        "Mean duration of a request phase of an http_probe in milliseconds (dns, connect, tls_handshake, ttfb, total)",
+			Label:       "phase",
+			LabelValue:  phase,
+			SuccessOnly: true,
+		}
+	}
+	RegisterResultMetrics("http_probe",
+		ResultMetric{Key: models.MetricDNSMs, Gauge: phaseGauge("dns")},
+		ResultMetric{Key: models.MetricConnectMs, Gauge: phaseGauge("connect")},
+		ResultMetric{Key: models.MetricTLSHandshakeMs, Gauge: phaseGauge("tls_handshake")},
+		ResultMetric{Key: models.MetricTTFBMs, Gauge: phaseGauge("ttfb")},
+		ResultMetric{Key: models.MetricTotalMs, Gauge: phaseGauge("total")},
+		ResultMetric{Key: models.MetricStatusCode, OmitZero: true, Gauge: &ResultGauge{
+			Name: "beacon_http_status_code",
+			Help: "Last HTTP status code received by an http_probe",
+		}},
+	)
+}
+
+// NewProber creates the scheduler's probe engine for the configuration
+func (c *HTTPProbeConfig) NewProber() Prober {
+	return NewHTTPProber(*c)
+}
+
+// Validate validates the HTTP probe configuration
+func (c *HTTPProbeConfig) Validate() error {
+	if c.Type != "http_probe" {
+		return fmt.Errorf("invalid probe type: %s (must be 'http_probe')", c.Type)
+	}
+
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid probe target '%s', must be an http:// or https:// URL", c.URL)
+	}
+
+	if err := validateSchedule(c.Interval, c.Count, c.TimeoutSeconds); err != nil {
+		return err
+	}
+
+	if c.ExpectedStatus != 0 && (c.ExpectedStatus < 100 || c.ExpectedStatus > 599) {
+		return fmt.Errorf("invalid expected status %d, must be between 100 and 599", c.ExpectedStatus)
+	}
+
+	if c.BodyRegex != "" {
+		if _, err := regexp.Compile(c.BodyRegex); err != nil {
+			return fmt.Errorf("invalid body regex '%s': %w", c.BodyRegex, err)
+		}
+	}
+
+	return nil
+}
+
+// HTTPProber represents an HTTP/HTTPS probe engine
+type HTTPProber struct {
+	config HTTPProbeConfig
+	client *http.Client
+}
+
+// NewHTTPProber creates a new HTTP prober with the given configuration.
+// Every request uses a fresh connection so DNS, connect and TLS are measured
+// each time, and redirects are not followed so the status check sees the
+// target's own response.
+func NewHTTPProber(config HTTPProbeConfig) *HTTPProber {
+	transport := &http.Transport{
+		Proxy:             http.ProxyFromEnvironment,
+		DisableKeepAlives: true,
+		TLSClientConfig:   &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+
+	return &HTTPProber{
+		config: config,
+		client: &http.Client{
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// httpPhases holds the timing breakdown of one request
+type httpPhases struct {
+	DNS, Connect, TLSHandshake, TTFB, Total time.Duration
+}
+
+// phaseTrace records request phases from httptrace callbacks, which may run
+// on other goroutines (e.g. parallel dials)
+type phaseTrace struct {
+	mu                               sync.Mutex
+	dnsStart, connectStart, tlsStart time.Time
+	phases                           httpPhases
+}
+
+func (t *phaseTrace) clientTrace(start time.Time) *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			t.dnsStart = time.Now()
+			t.mu.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			t.phases.DNS = time.Since(t.dnsStart)
+			t.mu.Unlock()
+		},
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			if t.connectStart.IsZero() {
+				t.connectStart = time.Now()
+			}
+			t.mu.Unlock()
+		},
+		ConnectDone: func(_, _ string, err error) {
+			t.mu.Lock()
+			if err == nil && t.phases.Connect == 0 {
+				t.phases.Connect = time.Since(t.connectStart)
+			}
+			t.mu.Unlock()
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			t.tlsStart = time.Now()
+			t.mu.Unlock()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mu.Lock()
+			t.phases.TLSHandshake = time.Since(t.tlsStart)
+			t.mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			t.phases.TTFB = time.Since(start)
+			t.mu.Unlock()
+		},
+	}
+}
+
+// ExecuteBatch performs count requests and calculates core metrics on the
+// total request time, plus the mean duration of each phase. A request counts
+// as lost when it fails or its status or body check does not pass. The batch
+// stops early when ctx is done, see batch.go.
+func (p *HTTPProber) ExecuteBatch(ctx context.Context, count int) (*models.ProbeResult, error) {
+	if count < 1 || count > 100 {
+		return nil, fmt.Errorf("invalid count %d, must be between 1 and 100", count)
+	}
+
+	if err := p.config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	var bodyRegex *regexp.Regexp
+	if p.config.BodyRegex != "" {
+		bodyRegex = regexp.MustCompile(p.config.BodyRegex) // Checked by Validate
+	}
+
+	samples := make([]SamplePoint, 0, count)
+	receivedPackets := 0
+	statusCode := 0
+	var phaseSums httpPhases
+	var lastErr error
+	aborted := false
+
+	for i := 0; i < count; i++ {
+		if ctx.Err() != nil {
+			aborted = true
+			break
+		}
+		phases, status, err := p.executeOnce(ctx, bodyRegex)
+		if err != nil && ctx.Err() != nil {
+			aborted = true // Aborted in flight, not a lost request
+			break
+		}
+		if status != 0 {
+			statusCode = status
+		}
+		if err != nil {
+			lastErr = err
+			errorClass := classifyError(err)
+			if status != 0 {
+				errorClass = models.SampleErrorResponse
+			}
+			samples = append(samples, SamplePoint{
+				RTTMs:      0,
+				Timestamp:  time.Now().Format(time.RFC3339),
+				Success:    false,
+				ErrorClass: errorClass,
+			})
+			continue
+		}
+
+		receivedPackets++
+		phaseSums.DNS += phases.DNS
+		phaseSums.Connect += phases.Connect
+		phaseSums.TLSHandshake += phases.TLSHandshake
+		phaseSums.TTFB += phases.TTFB
+		phaseSums.Total += phases.Total
+		samples = append(samples, SamplePoint{
+			RTTMs:     durationMs(phases.Total),
+			Timestamp: time.Now().Format(time.RFC3339),
+			Success:   true,
+		})
+	}
+
+	if aborted && len(samples) == 0 {
+		return nil, batchAbortedError(ctx)
+	}
+	sent := len(samples)
+
+	metrics := NewCoreMetricsCollector().CalculateFromSamples(samples, sent, receivedPackets)
+
+	meanMs := func(sum time.Duration) float64 {
+		if receivedPackets == 0 {
+			return 0
+		}
+		return durationMs(sum / time.Duration(receivedPackets))
+	}
+
+	errorMessage := ""
+	if receivedPackets == 0 && lastErr != nil {
+		errorMessage = lastErr.Error()
+	}
+
+	result := models.NewProbeResult("http_probe", p.config.URL, receivedPackets > 0, map[string]interface{}{
+		models.MetricRTTMs:           metrics.RTTMs,
+		models.MetricRTTMedianMs:     metrics.RTTMedianMs,
+		models.MetricJitterMs:        metrics.JitterMs,
+		models.MetricVarianceMs:      metrics.RTTVarianceMs,
+		models.MetricPacketLossRate:  metrics.PacketLossRate,
+		models.MetricSampleCount:     metrics.SampleCount,
+		models.MetricSentPackets:     sent,
+		models.MetricReceivedPackets: receivedPackets,
+		models.MetricDNSMs:           meanMs(phaseSums.DNS),
+		models.MetricConnectMs:       meanMs(phaseSums.Connect),
+		models.MetricTLSHandshakeMs:  meanMs(phaseSums.TLSHandshake),
+		models.MetricTTFBMs:          meanMs(phaseSums.TTFB),
+		models.MetricTotalMs:         meanMs(phaseSums.Total),
+		models.MetricStatusCode:      statusCode,
+	}, errorMessage)
+	result.ProbeID = p.ProbeID()
+	metrics.SetMetrics(result.Metrics)
+	result.Samples = rawSamples(samples)
+	result.Partial = aborted
+
+	return result, nil
+}
+
+// executeOnce performs one request and checks the response. The status code
+// is returned whenever a response was received.
+func (p *HTTPProber) executeOnce(ctx context.Context, bodyRegex *regexp.Regexp) (httpPhases, int, error) {
+	timeout := time.Duration(p.config.TimeoutSeconds) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	trace := &phaseTrace{}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace.clientTrace(start)), http.MethodGet, p.config.URL, nil)
+	if err != nil {
+		return httpPhases{}, 0, fmt.Errorf("invalid request: %w", err)
+	}
+	req.Header.Set("User-Agent", "node-pulse-beacon")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return httpPhases{}, 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPBodyBytes))
+	total := time.Since(start)
+	if err != nil {
+		return httpPhases{}, resp.StatusCode, fmt.Errorf("read body failed: %w", err)
+	}
+
+	expected := p.config.ExpectedStatus
+	if expected == 0 {
+		expected = defaultExpectedStatus
+	}
+	if resp.StatusCode != expected {
+		return httpPhases{}, resp.StatusCode, fmt.Errorf("unexpected status %d (expected %d)", resp.StatusCode, expected)
+	}
+	if p.config.BodyContains != "" && !strings.Contains(string(body), p.config.BodyContains) {
+		return httpPhases{}, resp.StatusCode, fmt.Errorf("response body does not contain %q", p.config.BodyContains)
+	}
+	if bodyRegex != nil && !bodyRegex.Match(body) {
+		return httpPhases{}, resp.StatusCode, fmt.Errorf("response body does not match %q", p.config.BodyRegex)
+	}
+
+	trace.mu.Lock()
+	phases := trace.phases
+	trace.mu.Unlock()
+	phases.Total = total
+
+	return phases, resp.StatusCode, nil
+}
+
+// ProbeID returns the configured probe ID, or a stable key derived from type and URL
+func (p *HTTPProber) ProbeID() string {
+	if p.config.ID != "" {
+		return p.config.ID
+	}
+	return models.ProbeKey("http_probe", p.config.URL, 0)
+}
+
+// durationMs converts a duration to milliseconds rounded to 2 decimal places
+func durationMs(d time.Duration) float64 {
+	return math.Round(d.Seconds()*1000*rttPrecisionMultiplier) / rttPrecisionMultiplier
+}
@@ -0,0 +1,261 @@
+package probe
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"beacon/internal/models"
+)
+
+func newTestHTTPProber(url string) *HTTPProber {
+	return NewHTTPProber(HTTPProbeConfig{Type: "http_probe", URL: url, TimeoutSeconds: 2, Interval: 60, Count: 10})
+}
+
+// TestHTTPProbeConfigValidation tests HTTP probe configuration validation
+func TestHTTPProbeConfigValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  HTTPProbeConfig
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid HTTPS config",
+			config:  HTTPProbeConfig{Type: "http_probe", URL: "https://example.com/health", TimeoutSeconds: 5, Interval: 60, Count: 10},
+			wantErr: false,
+		},
+		{
+			name:    "valid config with checks",
+			config:  HTTPProbeConfig{Type: "http_probe", URL: "http://10.0.0.1:8080/", TimeoutSeconds: 5, Interval: 60, Count: 10, ExpectedStatus: 204, BodyRegex: `^ok$`},
+			wantErr: false,
+		},
+		{
+			name:    "invalid type",
+			config:  HTTPProbeConfig{Type: "tcp_ping", URL: "https://example.com", TimeoutSeconds: 5, Interval: 60, Count: 10},
+			wantErr: true,
+			errMsg:  "invalid probe type",
+		},
+		{
+			name:    "URL without scheme",
+			config:  HTTPProbeConfig{Type: "http_probe", URL: "example.com/health", TimeoutSeconds: 5, Interval: 60, Count: 10},
+			wantErr: true,
+			errMsg:  "invalid probe target",
+		},
+		{
+			name:    "unsupported scheme",
+			config:  HTTPProbeConfig{Type: "http_probe", URL: "ftp://example.com", TimeoutSeconds: 5, Interval: 60, Count: 10},
+			wantErr: true,
+			errMsg:  "invalid probe target",
+		},
+		{
+			name:    "invalid expected status",
+			config:  HTTPProbeConfig{Type: "http_probe", URL: "https://example.com", TimeoutSeconds: 5, Interval: 60, Count: 10, ExpectedStatus: 600},
+			wantErr: true,
+			errMsg:  "invalid expected status",
+		},
+		{
+			name:    "invalid body regex",
+			config:  HTTPProbeConfig{Type: "http_probe", URL: "https://example.com", TimeoutSeconds: 5, Interval: 60, Count: 10, BodyRegex: "("},
+			wantErr: true,
+			errMsg:  "invalid body regex",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && !contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+// TestHTTPProber_PhaseTiming tests a successful plain HTTP probe and its phase breakdown
+func TestHTTPProber_PhaseTiming(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	prober := newTestHTTPProber(server.URL)
+
+	// Act
+	result, err := prober.ExecuteBatch(context.Background(), 3)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if !result.Success || result.Type != "http_probe" || result.ProbeID != "http_probe:"+server.URL {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+	if status := result.MetricFloat(models.MetricStatusCode); status != 200 {
+		t.Errorf("Expected status 200, got %.0f", status)
+	}
+	if loss := result.MetricFloat(models.MetricPacketLossRate); loss != 0 {
+		t.Errorf("Expected no failed requests, got %.2f%%", loss)
+	}
+	total := result.MetricFloat(models.MetricTotalMs)
+	ttfb := result.MetricFloat(models.MetricTTFBMs)
+	if total <= 0 || ttfb <= 0 || ttfb > total {
+		t.Errorf("Expected 0 < ttfb <= total, got ttfb=%.2f total=%.2f", ttfb, total)
+	}
+	if connect := result.MetricFloat(models.MetricConnectMs); connect <= 0 || connect > total {
+		t.Errorf("Expected connect time within total, got %.2f", connect)
+	}
+	if tlsMs := result.MetricFloat(models.MetricTLSHandshakeMs); tlsMs != 0 {
+		t.Errorf("Expected no TLS handshake for plain HTTP, got %.2f", tlsMs)
+	}
+}
+
+// TestHTTPProber_TLSHandshake tests that HTTPS probes measure the TLS handshake
+func TestHTTPProber_TLSHandshake(t *testing.T) {
+	// Arrange - trust the test server certificate
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	prober := newTestHTTPProber(server.URL)
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	prober.client.Transport.(*http.Transport).TLSClientConfig.RootCAs = pool
+
+	// Act
+	result, err := prober.ExecuteBatch(context.Background(), 2)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Expected HTTPS probe to succeed: %s", result.ErrorMessage)
+	}
+	if tlsMs := result.MetricFloat(models.MetricTLSHandshakeMs); tlsMs <= 0 {
+		t.Errorf("Expected TLS handshake time, got %.2f", tlsMs)
+	}
+}
+
+// TestHTTPProber_UntrustedCertificate tests that certificate errors fail the probe
+func TestHTTPProber_UntrustedCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	result, err := newTestHTTPProber(server.URL).ExecuteBatch(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if result.Success || !contains(result.ErrorMessage, "certificate") {
+		t.Errorf("Expected certificate failure, got success=%v error=%q", result.Success, result.ErrorMessage)
+	}
+}
+
+// TestHTTPProber_UnexpectedStatus tests the status code check
+func TestHTTPProber_UnexpectedStatus(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// Act
+	result, err := newTestHTTPProber(server.URL).ExecuteBatch(context.Background(), 2)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if result.Success {
+		t.Error("Expected probe to fail on status 503")
+	}
+	if loss := result.MetricFloat(models.MetricPacketLossRate); loss != 100 {
+		t.Errorf("Expected 100%% failed requests, got %.2f", loss)
+	}
+	if status := result.MetricFloat(models.MetricStatusCode); status != 503 {
+		t.Errorf("Expected observed status 503, got %.0f", status)
+	}
+	if !contains(result.ErrorMessage, "unexpected status 503 (expected 200)") {
+		t.Errorf("Unexpected error message: %q", result.ErrorMessage)
+	}
+}
+
+// TestHTTPProber_RedirectNotFollowed tests that redirects are checked against expected_status
+func TestHTTPProber_RedirectNotFollowed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/elsewhere", http.StatusFound)
+	}))
+	defer server.Close()
+	prober := newTestHTTPProber(server.URL)
+	prober.config.ExpectedStatus = http.StatusFound
+
+	result, err := prober.ExecuteBatch(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if !result.Success {
+		t.Errorf("Expected redirect to satisfy expected_status 302: %s", result.ErrorMessage)
+	}
+}
+
+// TestHTTPProber_BodyChecks tests body substring and regex checks
+func TestHTTPProber_BodyChecks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"healthy","version":"1.4.2"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		bodyContains string
+		bodyRegex    string
+		wantSuccess  bool
+		errMsg       string
+	}{
+		{name: "substring match", bodyContains: `"healthy"`, wantSuccess: true},
+		{name: "substring mismatch", bodyContains: "degraded", wantSuccess: false, errMsg: "does not contain"},
+		{name: "regex match", bodyRegex: `"version":"1\.\d+\.\d+"`, wantSuccess: true},
+		{name: "regex mismatch", bodyRegex: `"version":"2\.`, wantSuccess: false, errMsg: "does not match"},
+		{name: "both checks", bodyContains: "healthy", bodyRegex: `1\.4`, wantSuccess: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prober := newTestHTTPProber(server.URL)
+			prober.config.BodyContains = tt.bodyContains
+			prober.config.BodyRegex = tt.bodyRegex
+
+			result, err := prober.ExecuteBatch(context.Background(), 1)
+			if err != nil {
+				t.Fatalf("ExecuteBatch failed: %v", err)
+			}
+			if result.Success != tt.wantSuccess {
+				t.Errorf("Expected success=%v, got %v (%s)", tt.wantSuccess, result.Success, result.ErrorMessage)
+			}
+			if !tt.wantSuccess && !contains(result.ErrorMessage, tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %q", tt.errMsg, result.ErrorMessage)
+			}
+		})
+	}
+}
+
+// TestHTTPProber_ConnectionRefused tests a target that is not listening
+func TestHTTPProber_ConnectionRefused(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	result, err := newTestHTTPProber(url).ExecuteBatch(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if result.Success || !contains(result.ErrorMessage, "request failed") {
+		t.Errorf("Expected request failure, got success=%v error=%q", result.Success, result.ErrorMessage)
+	}
+}
@@ -0,0 +1,366 @@
+package probe
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"beacon/internal/config"
+	"beacon/internal/logger"
+	"beacon/internal/models"
+)
+
+// ICMP echo message types (RFC 792, RFC 4443)
+const (
+	icmpv4EchoReply   = 0
+	icmpv4EchoRequest = 8
+	icmpv6EchoRequest = 128
+	icmpv6EchoReply   = 129
+
+	icmpHeaderLen = 8
+)
+
+// ICMPProbeConfig represents ICMP echo probe configuration
+type ICMPProbeConfig struct {
+	ID             string `yaml:"id"`
+	Type           string `yaml:"type" validate:"required,eq=icmp_ping"`
+	Target         string `yaml:"target" validate:"required,ip|hostname"`
+	TimeoutSeconds int    `yaml:"timeout_seconds" validate:"required,min=1,max=30"`
+	Interval       int    `yaml:"interval" validate:"required,min=60,max=300"`
+	Count          int    `yaml:"count" validate:"required,min=1,max=100"`
+}
+
+func init() {
+	RegisterProbeType("icmp_ping", func(cfg config.ProbeConfig) (ProbeSpec, error) {
+		return &icmpProbeSpec{ICMPProbeConfig{
+			ID:             cfg.ID,
+			Type:           cfg.Type,
+			Target:         cfg.Target,
+			TimeoutSeconds: cfg.TimeoutSeconds,
+			Interval:       cfg.Interval,
+			Count:          cfg.Count,
+		}}, nil
+	})
+}
+
+// icmpProbeSpec is a scheduled icmp_ping entry; its batches must hold enough
+// samples for the loss, jitter and percentile metrics
+type icmpProbeSpec struct {
+	ICMPProbeConfig
+}
+
+// Validate validates the configuration and the batch size of the entry
+func (s *icmpProbeSpec) Validate() error {
+	if err := s.ICMPProbeConfig.Validate(); err != nil {
+		return err
+	}
+	return validateCoreMetricsCount(s.Count)
+}
+
+// NewProber creates the scheduler's probe engine for the configuration
+func (c *ICMPProbeConfig) NewProber() Prober {
+	return NewICMPPinger(*c)
+}
+
+// Validate validates the ICMP probe configuration
+func (c *ICMPProbeConfig) Validate() error {
+	if c.Type != "icmp_ping" {
+		return fmt.Errorf("invalid probe type: %s (must be 'icmp_ping')", c.Type)
+	}
+
+	if c.Target == "" {
+		return fmt.Errorf("probe target cannot be empty")
+	}
+
+	// Validate target is IP or hostname
+	if net.ParseIP(c.Target) == nil {
+		// Not an IP, check if it's a valid hostname format
+		if err := validateHostname(c.Target); err != nil {
+			return fmt.Errorf("invalid probe target '%s': %w", c.Target, err)
+		}
+	}
+
+	if err := validateSchedule(c.Interval, c.Count, c.TimeoutSeconds); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ICMPPinger represents an ICMP echo probe engine
+type ICMPPinger struct {
+	config ICMPProbeConfig
+	id     uint16 // Echo identifier, used with raw sockets only
+	token  []byte // Echo payload, tells our replies apart from other pingers'
+
+	mu  sync.Mutex // Serializes batches so sequence numbers stay unique
+	seq uint16
+}
+
+// NewICMPPinger creates a new ICMP pinger with the given configuration
+func NewICMPPinger(config ICMPProbeConfig) *ICMPPinger {
+	token := make([]byte, 8)
+	binary.BigEndian.PutUint64(token, rand.Uint64())
+
+	return &ICMPPinger{
+		config: config,
+		id:     uint16(rand.Intn(math.MaxUint16 + 1)),
+		token:  token,
+	}
+}
+
+// ExecuteBatch sends count echo requests and calculates core metrics.
+// An error is returned only when no ICMP socket can be opened, or when ctx
+// is done before the first reply or timeout (see batch.go).
+func (p *ICMPPinger) ExecuteBatch(ctx context.Context, count int) (*models.ProbeResult, error) {
+	if count < 1 || count > 100 {
+		return nil, fmt.Errorf("invalid count %d, must be between 1 and 100", count)
+	}
+
+	if err := p.config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	dst, err := net.ResolveIPAddr("ip", p.config.Target)
+	if err != nil {
+		return p.newResult(nil, count, 0, fmt.Sprintf("resolve failed: %v", err)), nil
+	}
+	ipv6 := dst.IP.To4() == nil
+
+	conn, err := listenICMP(ipv6, p.id)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	defer abortOnDone(ctx, conn.conn)()
+
+	samples := make([]SamplePoint, 0, count)
+	receivedPackets := 0
+	var lastErr error
+	timeout := time.Duration(p.config.TimeoutSeconds) * time.Second
+	aborted := false
+
+	for i := 0; i < count; i++ {
+		p.seq++
+		rtt, err := conn.echo(ctx, dst, p.seq, p.token, timeout)
+		if err != nil && ctx.Err() != nil {
+			aborted = true // Aborted in flight, not a lost echo
+			break
+		}
+		if err != nil {
+			lastErr = err
+			samples = append(samples, SamplePoint{
+				RTTMs:      0,
+				Timestamp:  time.Now().Format(time.RFC3339),
+				Success:    false,
+				ErrorClass: classifyError(err),
+			})
+			continue
+		}
+
+		receivedPackets++
+		samples = append(samples, SamplePoint{
+			RTTMs:     math.Round(rtt.Seconds()*1000*rttPrecisionMultiplier) / rttPrecisionMultiplier,
+			Timestamp: time.Now().Format(time.RFC3339),
+			Success:   true,
+		})
+	}
+
+	if aborted && len(samples) == 0 {
+		return nil, batchAbortedError(ctx)
+	}
+
+	errorMessage := ""
+	if receivedPackets == 0 && lastErr != nil {
+		errorMessage = fmt.Sprintf("no echo reply: %v", lastErr)
+	}
+
+	result := p.newResult(samples, len(samples), receivedPackets, errorMessage)
+	result.Partial = aborted
+	return result, nil
+}
+
+// newResult builds a generic probe result carrying core metrics
+func (p *ICMPPinger) newResult(samples []SamplePoint, sent, received int, errorMessage string) *models.ProbeResult {
+	metrics := NewCoreMetricsCollector().CalculateFromSamples(samples, sent, received)
+
+	result := models.NewProbeResult("icmp_ping", p.config.Target, received > 0, map[string]interface{}{
+		models.MetricRTTMs:           metrics.RTTMs,
+		models.MetricRTTMedianMs:     metrics.RTTMedianMs,
+		models.MetricJitterMs:        metrics.JitterMs,
+		models.MetricVarianceMs:      metrics.RTTVarianceMs,
+		models.MetricPacketLossRate:  metrics.PacketLossRate,
+		models.MetricSampleCount:     metrics.SampleCount,
+		models.MetricSentPackets:     sent,
+		models.MetricReceivedPackets: received,
+	}, errorMessage)
+	result.ProbeID = p.ProbeID()
+	metrics.SetMetrics(result.Metrics)
+	result.Samples = rawSamples(samples)
+
+	return result
+}
+
+// ProbeID returns the configured probe ID, or a stable key derived from type and target
+func (p *ICMPPinger) ProbeID() string {
+	if p.config.ID != "" {
+		return p.config.ID
+	}
+	return models.ProbeKey("icmp_ping", p.config.Target, 0)
+}
+
+// icmpConn is an ICMP socket used for one probe batch
+type icmpConn struct {
+	conn     net.PacketConn
+	ipv6     bool
+	datagram bool   // Unprivileged datagram socket; the kernel owns the echo identifier
+	id       uint16 // Echo identifier for raw sockets
+}
+
+// listenICMP opens an unprivileged ICMP datagram socket where the kernel
+// allows it (net.ipv4.ping_group_range on Linux) and falls back to a raw
+// socket, which requires root or CAP_NET_RAW
+func listenICMP(ipv6 bool, id uint16) (*icmpConn, error) {
+	conn, dgramErr := listenICMPDatagram(ipv6)
+	if dgramErr == nil {
+		return &icmpConn{conn: conn, ipv6: ipv6, datagram: true}, nil
+	}
+
+	network, address := "ip4:icmp", "0.0.0.0"
+	if ipv6 {
+		network, address = "ip6:ipv6-icmp", "::"
+	}
+	conn, rawErr := net.ListenPacket(network, address)
+	if rawErr != nil {
+		return nil, fmt.Errorf("failed to open ICMP socket (datagram: %v; raw: %w)", dgramErr, rawErr)
+	}
+
+	logger.WithFields(map[string]interface{}{"component": "probe", "probe_type": "icmp_ping", "reason": dgramErr.Error()}).Debug("Unprivileged ICMP socket unavailable, using raw socket")
+	return &icmpConn{conn: conn, ipv6: ipv6, id: id}, nil
+}
+
+// Close closes the socket
+func (c *icmpConn) Close() error {
+	return c.conn.Close()
+}
+
+// echo sends one echo request and waits for the matching reply. Pending reads
+// are interrupted through the deadline once ctx is done (see abortOnDone).
+func (c *icmpConn) echo(ctx context.Context, dst *net.IPAddr, seq uint16, payload []byte, timeout time.Duration) (time.Duration, error) {
+	var addr net.Addr = dst
+	if c.datagram {
+		addr = &net.UDPAddr{IP: dst.IP, Zone: dst.Zone}
+	}
+
+	deadline := time.Now().Add(timeout)
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return 0, fmt.Errorf("set deadline failed: %w", err)
+	}
+	// The deadline above may have replaced the one set by an abort
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	if _, err := c.conn.WriteTo(marshalEchoRequest(c.ipv6, c.id, seq, payload), addr); err != nil {
+		return 0, fmt.Errorf("send failed: %w", err)
+	}
+
+	buffer := make([]byte, 1500)
+	for {
+		n, _, err := c.conn.ReadFrom(buffer)
+		if err != nil {
+			return 0, err
+		}
+		rtt := time.Since(start)
+
+		// Raw sockets see every ICMP message on the host; skip anything that is
+		// not the reply to this request
+		id, replySeq, replyPayload, ok := parseEchoReply(c.ipv6, buffer[:n])
+		if !ok || replySeq != seq || !bytes.Equal(replyPayload, payload) {
+			continue
+		}
+		if !c.datagram && id != c.id {
+			continue
+		}
+		return rtt, nil
+	}
+}
+
+// marshalEchoRequest builds an echo request message. The ICMPv6 checksum
+// covers a pseudo-header and is filled in by the kernel.
+func marshalEchoRequest(ipv6 bool, id, seq uint16, payload []byte) []byte {
+	msg := make([]byte, icmpHeaderLen+len(payload))
+	msg[0] = icmpv4EchoRequest
+	if ipv6 {
+		msg[0] = icmpv6EchoRequest
+	}
+	binary.BigEndian.PutUint16(msg[4:], id)
+	binary.BigEndian.PutUint16(msg[6:], seq)
+	copy(msg[icmpHeaderLen:], payload)
+
+	if !ipv6 {
+		binary.BigEndian.PutUint16(msg[2:], icmpChecksum(msg))
+	}
+	return msg
+}
+
+// errNotEchoReply reports a message that is not an echo reply
+var errNotEchoReply = errors.New("not an ICMP echo reply")
+
+// parseEchoReply extracts identifier, sequence and payload from an echo reply
+func parseEchoReply(ipv6 bool, msg []byte) (id, seq uint16, payload []byte, ok bool) {
+	msg, err := stripIPv4Header(ipv6, msg)
+	if err != nil || len(msg) < icmpHeaderLen {
+		return 0, 0, nil, false
+	}
+
+	replyType := byte(icmpv4EchoReply)
+	if ipv6 {
+		replyType = icmpv6EchoReply
+	}
+	if msg[0] != replyType || msg[1] != 0 {
+		return 0, 0, nil, false
+	}
+
+	return binary.BigEndian.Uint16(msg[4:]), binary.BigEndian.Uint16(msg[6:]), msg[icmpHeaderLen:], true
+}
+
+// stripIPv4Header removes an IPv4 header some platforms (e.g. macOS datagram
+// sockets) deliver in front of the ICMP message. An echo reply never starts
+// with 0x4_, so the version nibble identifies the header.
+func stripIPv4Header(ipv6 bool, msg []byte) ([]byte, error) {
+	if ipv6 || len(msg) == 0 || msg[0]>>4 != 4 {
+		return msg, nil
+	}
+	headerLen := int(msg[0]&0x0f) * 4
+	if headerLen < 20 || len(msg) < headerLen {
+		return nil, errNotEchoReply
+	}
+	return msg[headerLen:], nil
+}
+
+// icmpChecksum computes the Internet checksum (RFC 1071)
+func icmpChecksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(b[i])<<8 | uint32(b[i+1])
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+	return ^uint16(sum)
+}
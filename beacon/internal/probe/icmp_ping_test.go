@@ -0,0 +1,184 @@
+package probe
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"beacon/internal/models"
+)
+
+// TestICMPProbeConfigValidation tests ICMP probe configuration validation
+func TestICMPProbeConfigValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ICMPProbeConfig
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid ICMP config",
+			config:  ICMPProbeConfig{Type: "icmp_ping", Target: "8.8.8.8", TimeoutSeconds: 1, Interval: 60, Count: 10},
+			wantErr: false,
+		},
+		{
+			name:    "valid ICMP config with IPv6 target",
+			config:  ICMPProbeConfig{Type: "icmp_ping", Target: "2001:4860:4860::8888", TimeoutSeconds: 5, Interval: 300, Count: 100},
+			wantErr: false,
+		},
+		{
+			name:    "invalid type - tcp_ping",
+			config:  ICMPProbeConfig{Type: "tcp_ping", Target: "8.8.8.8", TimeoutSeconds: 1, Interval: 60, Count: 10},
+			wantErr: true,
+			errMsg:  "invalid probe type",
+		},
+		{
+			name:    "empty target",
+			config:  ICMPProbeConfig{Type: "icmp_ping", Target: "", TimeoutSeconds: 1, Interval: 60, Count: 10},
+			wantErr: true,
+			errMsg:  "probe target cannot be empty",
+		},
+		{
+			name:    "invalid timeout - 0",
+			config:  ICMPProbeConfig{Type: "icmp_ping", Target: "8.8.8.8", TimeoutSeconds: 0, Interval: 60, Count: 10},
+			wantErr: true,
+			errMsg:  "invalid timeout",
+		},
+		{
+			name:    "invalid interval - 301",
+			config:  ICMPProbeConfig{Type: "icmp_ping", Target: "8.8.8.8", TimeoutSeconds: 1, Interval: 301, Count: 10},
+			wantErr: true,
+			errMsg:  "invalid interval",
+		},
+		{
+			name:    "invalid count - 101",
+			config:  ICMPProbeConfig{Type: "icmp_ping", Target: "8.8.8.8", TimeoutSeconds: 1, Interval: 60, Count: 101},
+			wantErr: true,
+			errMsg:  "invalid count",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && !contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+// TestMarshalEchoRequest tests the ICMPv4 echo request encoding and checksum
+func TestMarshalEchoRequest(t *testing.T) {
+	// Act
+	msg := marshalEchoRequest(false, 0x1234, 7, []byte("token"))
+
+	// Assert
+	if msg[0] != icmpv4EchoRequest || msg[1] != 0 {
+		t.Errorf("Expected echo request type 8 code 0, got %d/%d", msg[0], msg[1])
+	}
+	if id := binary.BigEndian.Uint16(msg[4:]); id != 0x1234 {
+		t.Errorf("Expected identifier 0x1234, got %#x", id)
+	}
+	if seq := binary.BigEndian.Uint16(msg[6:]); seq != 7 {
+		t.Errorf("Expected sequence 7, got %d", seq)
+	}
+	// A message including its checksum sums to zero
+	if sum := icmpChecksum(msg); sum != 0 {
+		t.Errorf("Expected valid checksum, verification gave %#x", sum)
+	}
+
+	// ICMPv6 checksums are left to the kernel
+	msg6 := marshalEchoRequest(true, 1, 1, nil)
+	if msg6[0] != icmpv6EchoRequest || msg6[2] != 0 || msg6[3] != 0 {
+		t.Errorf("Unexpected ICMPv6 echo request header: %v", msg6[:4])
+	}
+}
+
+// TestParseEchoReply tests echo reply decoding and filtering
+func TestParseEchoReply(t *testing.T) {
+	reply := marshalEchoRequest(false, 42, 9, []byte("token"))
+	reply[0] = icmpv4EchoReply
+
+	// Plain ICMP message
+	id, seq, payload, ok := parseEchoReply(false, reply)
+	if !ok || id != 42 || seq != 9 || !bytes.Equal(payload, []byte("token")) {
+		t.Errorf("Unexpected parse result: ok=%v id=%d seq=%d payload=%q", ok, id, seq, payload)
+	}
+
+	// Message preceded by an IPv4 header (macOS datagram sockets)
+	withHeader := append([]byte{0x45, 0, 0, 0, 0, 0, 0, 0, 64, 1, 0, 0, 127, 0, 0, 1, 127, 0, 0, 1}, reply...)
+	if _, seq, _, ok := parseEchoReply(false, withHeader); !ok || seq != 9 {
+		t.Errorf("Expected reply behind IPv4 header to parse, ok=%v seq=%d", ok, seq)
+	}
+
+	// Our own echo request seen on a raw socket
+	if _, _, _, ok := parseEchoReply(false, marshalEchoRequest(false, 42, 9, nil)); ok {
+		t.Error("Expected echo request to be ignored")
+	}
+
+	// Truncated message
+	if _, _, _, ok := parseEchoReply(false, reply[:4]); ok {
+		t.Error("Expected truncated message to be ignored")
+	}
+
+	// ICMPv6 reply type
+	reply6 := marshalEchoRequest(true, 1, 2, nil)
+	reply6[0] = icmpv6EchoReply
+	if _, seq, _, ok := parseEchoReply(true, reply6); !ok || seq != 2 {
+		t.Errorf("Expected ICMPv6 reply to parse, ok=%v seq=%d", ok, seq)
+	}
+}
+
+// TestICMPPinger_ExecuteBatch_Loopback tests a real echo exchange with localhost
+func TestICMPPinger_ExecuteBatch_Loopback(t *testing.T) {
+	initSchedulerTestLogger(t)
+
+	// Arrange - needs ping_group_range or CAP_NET_RAW
+	conn, err := listenICMP(false, 1)
+	if err != nil {
+		t.Skipf("ICMP sockets unavailable in this environment: %v", err)
+	}
+	conn.Close()
+
+	pinger := NewICMPPinger(ICMPProbeConfig{Type: "icmp_ping", Target: "127.0.0.1", TimeoutSeconds: 1, Interval: 60, Count: 10})
+
+	// Act
+	result, err := pinger.ExecuteBatch(context.Background(), 10)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if !result.Success || result.Type != "icmp_ping" || result.ProbeID != "icmp_ping:127.0.0.1" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if loss := result.MetricFloat(models.MetricPacketLossRate); loss != 0 {
+		t.Errorf("Expected no packet loss to localhost, got %.2f%%", loss)
+	}
+	if samples := result.MetricFloat(models.MetricSampleCount); samples != 10 {
+		t.Errorf("Expected 10 samples, got %.0f", samples)
+	}
+	if received := result.MetricFloat(models.MetricReceivedPackets); received != 10 {
+		t.Errorf("Expected 10 replies, got %.0f", received)
+	}
+	if rtt := result.MetricFloat(models.MetricRTTMs); rtt < 0 || rtt > 1000 {
+		t.Errorf("Expected RTT within timeout, got %.2fms", rtt)
+	}
+}
+
+// TestICMPPinger_ExecuteBatch_InvalidCount tests count bounds
+func TestICMPPinger_ExecuteBatch_InvalidCount(t *testing.T) {
+	pinger := NewICMPPinger(ICMPProbeConfig{Type: "icmp_ping", Target: "127.0.0.1", TimeoutSeconds: 1, Interval: 60, Count: 10})
+	if _, err := pinger.ExecuteBatch(context.Background(), 0); err == nil {
+		t.Error("Expected error for count 0")
+	}
+	if _, err := pinger.ExecuteBatch(context.Background(), 101); err == nil {
+		t.Error("Expected error for count 101")
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !linux && !darwin
+
+package probe
+
+import (
+	"errors"
+	"net"
+)
+
+// listenICMPDatagram is not supported on this platform; raw sockets are used instead
+func listenICMPDatagram(ipv6 bool) (net.PacketConn, error) {
+	return nil, errors.New("unprivileged ICMP sockets are not supported on this platform")
+}
+
+// setTTL is not supported on this platform, so path_probe cannot run
+func setTTL(conn net.PacketConn, ipv6 bool, ttl int) error {
+	return errors.New("setting the IP TTL is not supported on this platform")
+}
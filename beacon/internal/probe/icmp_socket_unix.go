@@ -0,0 +1,59 @@
+//go:build linux || darwin
+
+package probe
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+)
+
+// listenICMPDatagram opens an unprivileged ICMP datagram ("ping") socket
+func listenICMPDatagram(ipv6 bool) (net.PacketConn, error) {
+	family, proto := syscall.AF_INET, syscall.IPPROTO_ICMP
+	var addr syscall.Sockaddr = &syscall.SockaddrInet4{}
+	if ipv6 {
+		family, proto = syscall.AF_INET6, syscall.IPPROTO_ICMPV6
+		addr = &syscall.SockaddrInet6{}
+	}
+
+	fd, err := syscall.Socket(family, syscall.SOCK_DGRAM, proto)
+	if err != nil {
+		return nil, os.NewSyscallError("socket", err)
+	}
+	if err := syscall.Bind(fd, addr); err != nil {
+		syscall.Close(fd)
+		return nil, os.NewSyscallError("bind", err)
+	}
+
+	// FilePacketConn duplicates the descriptor
+	file := os.NewFile(uintptr(fd), "icmp")
+	defer file.Close()
+	return net.FilePacketConn(file)
+}
+
+// setTTL sets the IP TTL (IPv6 hop limit) of unicast packets sent on conn
+func setTTL(conn net.PacketConn, ipv6 bool, ttl int) error {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return errors.New("socket does not expose a file descriptor")
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var sockErr error
+	err = raw.Control(func(fd uintptr) {
+		if ipv6 {
+			sockErr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_UNICAST_HOPS, ttl)
+		} else {
+			sockErr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_TTL, ttl)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return os.NewSyscallError("setsockopt", sockErr)
+}
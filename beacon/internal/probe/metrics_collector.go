@@ -3,6 +3,8 @@ package probe
 import (
 	"math"
 	"sort"
+
+	"beacon/internal/models"
 )
 
 // CoreMetrics represents calculated core network quality metrics
@@ -13,13 +15,16 @@ type CoreMetrics struct {
 	JitterMs       float64 `json:"jitter_ms"`        // Delay jitter (milliseconds)
 	PacketLossRate float64 `json:"packet_loss_rate"` // Packet loss rate (%)
 	SampleCount    int     `json:"sample_count"`     // Number of sample points
+
+	models.RTTStatistics // RTT distribution, reported when selected in config
 }
 
 // SamplePoint represents a single probe sample point
 type SamplePoint struct {
-	RTTMs     float64 `json:"rtt_ms"`
-	Timestamp string  `json:"timestamp"`
-	Success   bool    `json:"success"`
+	RTTMs      float64 `json:"rtt_ms"`
+	Timestamp  string  `json:"timestamp"`
+	Success    bool    `json:"success"`
+	ErrorClass string  `json:"error_class,omitempty"` // models.SampleError* class if failed
 }
 
 // CoreMetricsCollector calculates core metrics from sample points
@@ -92,6 +97,76 @@ func (c *CoreMetricsCollector) CalculateJitter(samples []float64) float64 {
 	return math.Round(jitter*100) / 100
 }
 
+// CalculateMin returns the lowest value of a sample set
+func (c *CoreMetricsCollector) CalculateMin(samples []float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	min := samples[0]
+	for _, v := range samples[1:] {
+		min = math.Min(min, v)
+	}
+	return math.Round(min*100) / 100
+}
+
+// CalculateMax returns the highest value of a sample set
+func (c *CoreMetricsCollector) CalculateMax(samples []float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	max := samples[0]
+	for _, v := range samples[1:] {
+		max = math.Max(max, v)
+	}
+	return math.Round(max*100) / 100
+}
+
+// CalculateStdDev calculates the population standard deviation of a sample set
+func (c *CoreMetricsCollector) CalculateStdDev(samples []float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range samples {
+		sum += v
+	}
+	mean := sum / float64(len(samples))
+	sum = 0.0
+	for _, v := range samples {
+		diff := v - mean
+		sum += diff * diff
+	}
+	return math.Round(math.Sqrt(sum/float64(len(samples)))*100) / 100
+}
+
+// CalculatePercentile calculates the p-th percentile (0-100) of a sample set,
+// interpolating linearly between the closest ranks
+func (c *CoreMetricsCollector) CalculatePercentile(samples []float64, p float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(samples))
+	copy(sorted, samples)
+	sort.Float64s(sorted)
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	value := sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+	return math.Round(value*100) / 100
+}
+
+// CalculateRFC3550Jitter calculates the interarrival jitter estimate of
+// RFC 3550 section 6.4.1 over consecutive samples: J += (|D| - J) / 16.
+// Unlike CalculateJitter it smooths out single outliers.
+func (c *CoreMetricsCollector) CalculateRFC3550Jitter(samples []float64) float64 {
+	jitter := 0.0
+	for i := 1; i < len(samples); i++ {
+		jitter += (math.Abs(samples[i]-samples[i-1]) - jitter) / 16
+	}
+	return math.Round(jitter*100) / 100
+}
+
 // CalculatePacketLossRate calculates the packet loss rate
 func (c *CoreMetricsCollector) CalculatePacketLossRate(sent, received int) float64 {
 	if sent == 0 {
@@ -125,5 +200,14 @@ func (c *CoreMetricsCollector) CalculateFromSamples(samples []SamplePoint, sent,
 		JitterMs:       jitterMs,
 		PacketLossRate: packetLossRate,
 		SampleCount:    len(samples),
+		RTTStatistics: models.RTTStatistics{
+			RTTMinMs:        c.CalculateMin(rttSamples),
+			RTTMaxMs:        c.CalculateMax(rttSamples),
+			RTTStdDevMs:     c.CalculateStdDev(rttSamples),
+			RTTP90Ms:        c.CalculatePercentile(rttSamples, 90),
+			RTTP95Ms:        c.CalculatePercentile(rttSamples, 95),
+			RTTP99Ms:        c.CalculatePercentile(rttSamples, 99),
+			JitterRFC3550Ms: c.CalculateRFC3550Jitter(rttSamples),
+		},
 	}
 }
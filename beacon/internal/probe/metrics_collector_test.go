@@ -377,6 +377,137 @@ func TestCalculateFromSamples(t *testing.T) {
 	}
 }
 
+// TestCalculatePercentile tests percentile calculation with linear interpolation
+func TestCalculatePercentile(t *testing.T) {
+	collector := NewCoreMetricsCollector()
+	decile := []float64{50, 10, 100, 30, 20, 70, 40, 90, 60, 80} // Unsorted on purpose
+
+	tests := []struct {
+		name       string
+		samples    []float64
+		percentile float64
+		expected   float64
+	}{
+		{name: "p50", samples: decile, percentile: 50, expected: 55},
+		{name: "p90", samples: decile, percentile: 90, expected: 91},
+		{name: "p95", samples: decile, percentile: 95, expected: 95.5},
+		{name: "p99", samples: decile, percentile: 99, expected: 99.1},
+		{name: "p100 is max", samples: decile, percentile: 100, expected: 100},
+		{name: "single value", samples: []float64{42.5}, percentile: 95, expected: 42.5},
+		{name: "empty samples", samples: []float64{}, percentile: 95, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := collector.CalculatePercentile(tt.samples, tt.percentile)
+			if result != tt.expected {
+				t.Errorf("CalculatePercentile(%v, %v) = %v, want %v", tt.samples, tt.percentile, result, tt.expected)
+			}
+		})
+	}
+
+	// Samples must not be reordered
+	if decile[0] != 50 {
+		t.Errorf("CalculatePercentile modified its input: %v", decile)
+	}
+}
+
+// TestCalculateMinMaxStdDev tests min, max and standard deviation calculation
+func TestCalculateMinMaxStdDev(t *testing.T) {
+	collector := NewCoreMetricsCollector()
+
+	samples := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+	if result := collector.CalculateMin(samples); result != 2 {
+		t.Errorf("CalculateMin(%v) = %v, want 2", samples, result)
+	}
+	if result := collector.CalculateMax(samples); result != 9 {
+		t.Errorf("CalculateMax(%v) = %v, want 9", samples, result)
+	}
+	if result := collector.CalculateStdDev(samples); result != 2 {
+		t.Errorf("CalculateStdDev(%v) = %v, want 2", samples, result)
+	}
+
+	empty := []float64{}
+	if collector.CalculateMin(empty) != 0 || collector.CalculateMax(empty) != 0 || collector.CalculateStdDev(empty) != 0 {
+		t.Error("Expected 0 for min, max and stddev of empty samples")
+	}
+}
+
+// TestCalculateRFC3550Jitter tests the RFC 3550 interarrival jitter estimate
+func TestCalculateRFC3550Jitter(t *testing.T) {
+	collector := NewCoreMetricsCollector()
+
+	tests := []struct {
+		name     string
+		samples  []float64
+		expected float64
+	}{
+		{
+			name:     "two transitions",
+			samples:  []float64{100, 102, 98}, // 2/16 = 0.125, then 0.125 + (4-0.125)/16
+			expected: 0.37,
+		},
+		{
+			name:     "constant RTT",
+			samples:  []float64{50, 50, 50, 50},
+			expected: 0,
+		},
+		{
+			name:     "single value",
+			samples:  []float64{42.5},
+			expected: 0,
+		},
+		{
+			name:     "empty samples",
+			samples:  []float64{},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := collector.CalculateRFC3550Jitter(tt.samples)
+			if result != tt.expected {
+				t.Errorf("CalculateRFC3550Jitter(%v) = %v, want %v", tt.samples, result, tt.expected)
+			}
+		})
+	}
+
+	// A single outlier moves the smoothed estimate far less than the mean difference
+	samples := []float64{10, 10, 10, 10, 90, 10, 10, 10, 10}
+	if rfc, simple := collector.CalculateRFC3550Jitter(samples), collector.CalculateJitter(samples); rfc >= simple {
+		t.Errorf("Expected RFC 3550 jitter (%v) below simple jitter (%v) for a single outlier", rfc, simple)
+	}
+}
+
+// TestCalculateFromSamples_Statistics tests that RTT statistics only cover successful samples
+func TestCalculateFromSamples_Statistics(t *testing.T) {
+	collector := NewCoreMetricsCollector()
+
+	samples := []SamplePoint{
+		{RTTMs: 100.0, Success: true},
+		{RTTMs: 0, Success: false},
+		{RTTMs: 102.5, Success: true},
+	}
+	result := collector.CalculateFromSamples(samples, 3, 2)
+
+	if result.RTTMinMs != 100 {
+		t.Errorf("RTTMinMs = %v, want 100", result.RTTMinMs)
+	}
+	if result.RTTMaxMs != 102.5 {
+		t.Errorf("RTTMaxMs = %v, want 102.5", result.RTTMaxMs)
+	}
+	if result.RTTStdDevMs != 1.25 {
+		t.Errorf("RTTStdDevMs = %v, want 1.25", result.RTTStdDevMs)
+	}
+	if result.RTTP95Ms != 102.38 {
+		t.Errorf("RTTP95Ms = %v, want 102.38", result.RTTP95Ms)
+	}
+	if result.JitterRFC3550Ms != 0.16 {
+		t.Errorf("JitterRFC3550Ms = %v, want 0.16", result.JitterRFC3550Ms)
+	}
+}
+
 // TestMeasurementPrecision tests that measurements meet precision requirements (≤1ms)
 func TestMeasurementPrecision(t *testing.T) {
 	collector := NewCoreMetricsCollector()
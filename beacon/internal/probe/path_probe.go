@@ -0,0 +1,565 @@
+package probe
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"beacon/internal/config"
+	"beacon/internal/models"
+)
+
+// ICMP error message types quoting the probe that triggered them (RFC 792, RFC 4443)
+const (
+	icmpv4DestUnreachable = 3
+	icmpv4TimeExceeded    = 11
+	icmpv6DestUnreachable = 1
+	icmpv6TimeExceeded    = 3
+
+	ipv6HeaderLen = 40
+
+	protocolICMP   = 1
+	protocolUDP    = 17
+	protocolICMPv6 = 58
+)
+
+const (
+	// DefaultPathMaxHops is the hop limit used when max_hops is not set
+	DefaultPathMaxHops = 30
+	// MaxPathMaxHops bounds max_hops
+	MaxPathMaxHops = 64
+
+	// pathUDPBasePort is the first destination port of UDP probes, as used by traceroute
+	pathUDPBasePort = 33434
+	// pathUDPPortRange is the number of destination ports UDP probes cycle through
+	pathUDPPortRange = 6400
+
+	// pathRoundInterval is the minimum time between trace rounds, which keeps
+	// routers from rate limiting their ICMP replies (MTR's default interval)
+	pathRoundInterval = time.Second
+
+	// pathHopGrace is how long a round keeps waiting for routers closer than
+	// the destination once the destination replied. Their replies arrive
+	// before the destination's unless they do not answer at all.
+	pathHopGrace = 500 * time.Millisecond
+)
+
+// PathProbeConfig represents traceroute-style path probe configuration
+type PathProbeConfig struct {
+	ID             string `yaml:"id"`
+	Type           string `yaml:"type" validate:"required,eq=path_probe"`
+	Target         string `yaml:"target" validate:"required,ip|hostname"`
+	Protocol       string `yaml:"protocol"` // "icmp" (default) or "udp"
+	MaxHops        int    `yaml:"max_hops"` // 0 = DefaultPathMaxHops
+	TimeoutSeconds int    `yaml:"timeout_seconds" validate:"required,min=1,max=30"`
+	Interval       int    `yaml:"interval" validate:"required,min=60,max=300"`
+	Count          int    `yaml:"count" validate:"required,min=1,max=100"`
+}
+
+func init() {
+	RegisterProbeType("path_probe", func(cfg config.ProbeConfig) (ProbeSpec, error) {
+		spec := &PathProbeConfig{
+			ID:             cfg.ID,
+			Type:           cfg.Type,
+			Target:         cfg.Target,
+			TimeoutSeconds: cfg.TimeoutSeconds,
+			Interval:       cfg.Interval,
+			Count:          cfg.Count,
+		}
+		return spec, DecodeOptions(cfg, spec)
+	}, "protocol", "max_hops")
+
+	RegisterResultMetrics("path_probe", ResultMetric{Key: models.MetricHops, OmitZero: true})
+}
+
+// NewProber creates the scheduler's probe engine for the configuration
+func (c *PathProbeConfig) NewProber() Prober {
+	return NewPathProber(*c)
+}
+
+// Validate validates the path probe configuration
+func (c *PathProbeConfig) Validate() error {
+	if c.Type != "path_probe" {
+		return fmt.Errorf("invalid probe type: %s (must be 'path_probe')", c.Type)
+	}
+
+	if c.Target == "" {
+		return fmt.Errorf("probe target cannot be empty")
+	}
+
+	if net.ParseIP(c.Target) == nil {
+		if err := validateHostname(c.Target); err != nil {
+			return fmt.Errorf("invalid probe target '%s': %w", c.Target, err)
+		}
+	}
+
+	if c.Protocol != "" && c.Protocol != "icmp" && c.Protocol != "udp" {
+		return fmt.Errorf("invalid protocol '%s', must be 'icmp' or 'udp'", c.Protocol)
+	}
+
+	if c.MaxHops < 0 || c.MaxHops > MaxPathMaxHops {
+		return fmt.Errorf("invalid max_hops %d, must be between 1 and %d", c.MaxHops, MaxPathMaxHops)
+	}
+
+	if err := validateSchedule(c.Interval, c.Count, c.TimeoutSeconds); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// maxHops returns the configured hop limit or the default
+func (c *PathProbeConfig) maxHops() int {
+	if c.MaxHops == 0 {
+		return DefaultPathMaxHops
+	}
+	return c.MaxHops
+}
+
+// PathProber traces the route to a target like MTR: each round sends one
+// probe per TTL and collects the ICMP Time Exceeded replies of the routers
+// on the way and the reply of the destination itself. Replies are read from
+// a raw ICMP socket, which requires root or CAP_NET_RAW.
+type PathProber struct {
+	config PathProbeConfig
+	id     uint16 // Echo identifier of ICMP probes
+	token  []byte // Probe payload
+
+	mu  sync.Mutex // Serializes batches so sequence numbers stay unique
+	seq uint16
+}
+
+// NewPathProber creates a new path prober with the given configuration
+func NewPathProber(config PathProbeConfig) *PathProber {
+	token := make([]byte, 8)
+	binary.BigEndian.PutUint64(token, rand.Uint64())
+
+	return &PathProber{
+		config: config,
+		id:     uint16(rand.Intn(math.MaxUint16 + 1)),
+		token:  token,
+	}
+}
+
+// pendingHopProbe is a probe awaiting its reply
+type pendingHopProbe struct {
+	ttl    int
+	sentAt time.Time
+}
+
+// ExecuteBatch runs count trace rounds and reports per-hop address, RTT and
+// loss. The core metrics describe the destination hop; the probe succeeds
+// when the destination replied. An error is returned only when the sockets
+// cannot be opened. The batch stops early when ctx is done, see batch.go;
+// probes still pending in the aborted round are not counted as lost.
+func (p *PathProber) ExecuteBatch(ctx context.Context, count int) (*models.ProbeResult, error) {
+	if count < 1 || count > 100 {
+		return nil, fmt.Errorf("invalid count %d, must be between 1 and 100", count)
+	}
+
+	if err := p.config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	dst, err := net.ResolveIPAddr("ip", p.config.Target)
+	if err != nil {
+		return p.newResult(nil, 0, count, fmt.Sprintf("resolve failed: %v", err)), nil
+	}
+	ipv6 := dst.IP.To4() == nil
+	udp := p.config.Protocol == "udp"
+
+	listener, err := listenICMPRaw(ipv6)
+	if err != nil {
+		return nil, err
+	}
+	defer listener.Close()
+	defer abortOnDone(ctx, listener)()
+
+	sender := listener
+	localPort := 0
+	if udp {
+		network := "udp4"
+		if ipv6 {
+			network = "udp6"
+		}
+		udpConn, err := net.ListenUDP(network, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open UDP socket: %w", err)
+		}
+		defer udpConn.Close()
+		sender = udpConn
+		localPort = udpConn.LocalAddr().(*net.UDPAddr).Port
+	}
+
+	maxHops := p.config.maxHops()
+	hops := make([]*hopStats, maxHops)
+	for i := range hops {
+		hops[i] = &hopStats{ttl: i + 1, addresses: make(map[string]int)}
+	}
+
+	timeout := time.Duration(p.config.TimeoutSeconds) * time.Second
+	destTTL := 0
+	var lastErr error
+	buffer := make([]byte, 1500)
+	rounds := 0
+	aborted := false
+
+	for round := 0; round < count; round++ {
+		if ctx.Err() != nil {
+			aborted = true
+			break
+		}
+		roundStart := time.Now()
+		limit := maxHops
+		if destTTL > 0 {
+			limit = destTTL
+		}
+
+		pending := make(map[uint16]*pendingHopProbe, limit)
+		for ttl := 1; ttl <= limit; ttl++ {
+			key, err := p.send(sender, dst, ipv6, udp, ttl)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			pending[key] = &pendingHopProbe{ttl: ttl, sentAt: time.Now()}
+			hops[ttl-1].sent++
+		}
+
+		deadline := roundStart.Add(timeout)
+		if err := listener.SetReadDeadline(deadline); err != nil {
+			return nil, fmt.Errorf("set deadline failed: %w", err)
+		}
+		match := func(msg []byte) (uint16, bool) {
+			return parseHopReply(ipv6, udp, msg, dst.IP, p.id, localPort)
+		}
+		destReplied, err := awaitHopReplies(ctx, listener, match, dst.IP, pending, hops, &destTTL, deadline, buffer)
+		if err != nil {
+			lastErr = err
+		}
+
+		if ctx.Err() != nil {
+			// Aborted mid-round: unanswered probes are not lost, and the round
+			// counts only if the destination already replied
+			for _, probe := range pending {
+				hops[probe.ttl-1].sent--
+			}
+			if destReplied {
+				rounds++
+			}
+			aborted = true
+			break
+		}
+		rounds++
+
+		if round < count-1 && !sleepContext(ctx, time.Until(roundStart.Add(pathRoundInterval))) {
+			aborted = true
+			break
+		}
+	}
+
+	if aborted && rounds == 0 {
+		return nil, batchAbortedError(ctx)
+	}
+
+	// Report up to the destination, or up to the last router that replied
+	last := destTTL
+	if last == 0 {
+		for i := len(hops) - 1; i >= 0; i-- {
+			if hops[i].received > 0 {
+				last = i + 1
+				break
+			}
+		}
+	}
+	path := make([]models.PathHop, 0, last)
+	for _, hop := range hops[:last] {
+		path = append(path, hop.summary())
+	}
+
+	errorMessage := ""
+	if destTTL == 0 {
+		if lastErr != nil {
+			errorMessage = fmt.Sprintf("destination not reached within %d hops: %v", maxHops, lastErr)
+		} else {
+			errorMessage = fmt.Sprintf("destination not reached within %d hops", maxHops)
+		}
+	}
+
+	var destination *hopStats
+	if destTTL > 0 {
+		destination = hops[destTTL-1]
+	}
+	result := p.newResult(destination, destTTL, rounds, errorMessage)
+	result.Metrics[models.MetricHops] = path
+	result.Partial = aborted
+	return result, nil
+}
+
+// awaitHopReplies records the replies to the pending probes of one round in
+// hops until all arrived or the round deadline passes, and lowers destTTL to
+// the closest TTL the destination answered. Once the destination replied,
+// probes beyond it are dropped and closer routers are waited for at most
+// pathHopGrace, so a silent router does not stretch every round to the full
+// timeout. It reports whether the destination replied and the last read error.
+func awaitHopReplies(ctx context.Context, conn net.PacketConn, match func(msg []byte) (uint16, bool), dstIP net.IP, pending map[uint16]*pendingHopProbe, hops []*hopStats, destTTL *int, deadline time.Time, buffer []byte) (bool, error) {
+	destReplied := false
+	for len(pending) > 0 {
+		if ctx.Err() != nil {
+			break // The round deadline may have overridden the abort
+		}
+		n, from, err := conn.ReadFrom(buffer)
+		if err != nil {
+			var netErr net.Error
+			if !errors.As(err, &netErr) || !netErr.Timeout() {
+				return destReplied, err
+			}
+			break
+		}
+		fromIP := addrIP(from)
+		key, ok := match(buffer[:n])
+		if !ok {
+			continue
+		}
+		probe, ok := pending[key]
+		if !ok {
+			continue
+		}
+		delete(pending, key)
+
+		hops[probe.ttl-1].record(fromIP.String(), durationMs(time.Since(probe.sentAt)))
+		if !fromIP.Equal(dstIP) {
+			continue
+		}
+		if *destTTL == 0 || probe.ttl < *destTTL {
+			*destTTL = probe.ttl
+		}
+		if !destReplied {
+			destReplied = true
+			if grace := time.Now().Add(pathHopGrace); grace.Before(deadline) {
+				if err := conn.SetReadDeadline(grace); err != nil {
+					return destReplied, fmt.Errorf("set deadline failed: %w", err)
+				}
+			}
+		}
+		for pendingKey, pendingProbe := range pending {
+			if pendingProbe.ttl > *destTTL {
+				hops[pendingProbe.ttl-1].sent--
+				delete(pending, pendingKey)
+			}
+		}
+	}
+	return destReplied, nil
+}
+
+// send sends one probe with the given TTL and returns the key its reply is
+// matched by: the echo sequence number for ICMP, the destination port for UDP
+func (p *PathProber) send(conn net.PacketConn, dst *net.IPAddr, ipv6, udp bool, ttl int) (uint16, error) {
+	if err := setTTL(conn, ipv6, ttl); err != nil {
+		return 0, fmt.Errorf("set TTL failed: %w", err)
+	}
+
+	p.seq++
+	if udp {
+		port := uint16(pathUDPBasePort + int(p.seq)%pathUDPPortRange)
+		if _, err := conn.WriteTo(p.token, &net.UDPAddr{IP: dst.IP, Port: int(port), Zone: dst.Zone}); err != nil {
+			return 0, fmt.Errorf("send failed: %w", err)
+		}
+		return port, nil
+	}
+
+	if _, err := conn.WriteTo(marshalEchoRequest(ipv6, p.id, p.seq, p.token), dst); err != nil {
+		return 0, fmt.Errorf("send failed: %w", err)
+	}
+	return p.seq, nil
+}
+
+// newResult builds a path probe result whose core metrics describe the
+// destination hop (nil when the destination was not reached)
+func (p *PathProber) newResult(destination *hopStats, hopCount, sent int, errorMessage string) *models.ProbeResult {
+	received := 0
+	var samples []SamplePoint
+	if destination != nil {
+		received = destination.received
+		for _, rtt := range destination.rtts {
+			samples = append(samples, SamplePoint{RTTMs: rtt, Timestamp: time.Now().Format(time.RFC3339), Success: true})
+		}
+	}
+	metrics := NewCoreMetricsCollector().CalculateFromSamples(samples, sent, received)
+
+	result := models.NewProbeResult("path_probe", p.config.Target, destination != nil, map[string]interface{}{
+		models.MetricRTTMs:           metrics.RTTMs,
+		models.MetricRTTMedianMs:     metrics.RTTMedianMs,
+		models.MetricJitterMs:        metrics.JitterMs,
+		models.MetricVarianceMs:      metrics.RTTVarianceMs,
+		models.MetricPacketLossRate:  metrics.PacketLossRate,
+		models.MetricSampleCount:     metrics.SampleCount,
+		models.MetricSentPackets:     sent,
+		models.MetricReceivedPackets: received,
+		models.MetricHopCount:        hopCount,
+		models.MetricHops:            []models.PathHop{},
+	}, errorMessage)
+	result.ProbeID = p.ProbeID()
+	metrics.SetMetrics(result.Metrics)
+
+	return result
+}
+
+// ProbeID returns the configured probe ID, or a stable key derived from type and target
+func (p *PathProber) ProbeID() string {
+	if p.config.ID != "" {
+		return p.config.ID
+	}
+	return models.ProbeKey("path_probe", p.config.Target, 0)
+}
+
+// hopStats accumulates the replies for one TTL across rounds
+type hopStats struct {
+	ttl       int
+	sent      int
+	received  int
+	rtts      []float64
+	addresses map[string]int // Reply count per responding address
+	order     []string       // Addresses in order of first reply
+}
+
+// record adds a reply from address
+func (h *hopStats) record(address string, rttMs float64) {
+	h.received++
+	h.rtts = append(h.rtts, rttMs)
+	if h.addresses[address] == 0 {
+		h.order = append(h.order, address)
+	}
+	h.addresses[address]++
+}
+
+// summary returns the hop as reported. With load-balanced paths several
+// routers answer for one TTL; the one that answered most often is reported.
+func (h *hopStats) summary() models.PathHop {
+	hop := models.PathHop{TTL: h.ttl, Sent: h.sent, Received: h.received}
+	if h.sent > 0 {
+		hop.PacketLossRate = math.Round(float64(h.sent-h.received)/float64(h.sent)*100*100) / 100
+	}
+
+	for _, address := range h.order {
+		if h.addresses[address] > h.addresses[hop.Address] {
+			hop.Address = address
+		}
+	}
+
+	if len(h.rtts) > 0 {
+		sum := 0.0
+		hop.BestMs, hop.WorstMs = h.rtts[0], h.rtts[0]
+		for _, rtt := range h.rtts {
+			sum += rtt
+			hop.BestMs = math.Min(hop.BestMs, rtt)
+			hop.WorstMs = math.Max(hop.WorstMs, rtt)
+		}
+		hop.RTTMs = math.Round(sum/float64(len(h.rtts))*rttPrecisionMultiplier) / rttPrecisionMultiplier
+	}
+	return hop
+}
+
+// listenICMPRaw opens a raw ICMP socket, which receives the ICMP errors that
+// routers send for expired probes. Requires root or CAP_NET_RAW.
+func listenICMPRaw(ipv6 bool) (net.PacketConn, error) {
+	network, address := "ip4:icmp", "0.0.0.0"
+	if ipv6 {
+		network, address = "ip6:ipv6-icmp", "::"
+	}
+	conn, err := net.ListenPacket(network, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open raw ICMP socket (requires root or CAP_NET_RAW): %w", err)
+	}
+	return conn, nil
+}
+
+// addrIP returns the IP of a packet source address
+func addrIP(addr net.Addr) net.IP {
+	switch a := addr.(type) {
+	case *net.IPAddr:
+		return a.IP
+	case *net.UDPAddr:
+		return a.IP
+	default:
+		return nil
+	}
+}
+
+// parseHopReply matches an ICMP message to one of our probes and returns its
+// key. Echo replies (ICMP mode) carry the key directly; Time Exceeded and
+// Destination Unreachable messages quote the IP header and first 8 bytes of
+// the probe, which identify it.
+func parseHopReply(ipv6, udp bool, msg []byte, dst net.IP, id uint16, localPort int) (uint16, bool) {
+	msg, err := stripIPv4Header(ipv6, msg)
+	if err != nil || len(msg) < icmpHeaderLen {
+		return 0, false
+	}
+
+	echoReply, timeExceeded, unreachable := byte(icmpv4EchoReply), byte(icmpv4TimeExceeded), byte(icmpv4DestUnreachable)
+	if ipv6 {
+		echoReply, timeExceeded, unreachable = icmpv6EchoReply, icmpv6TimeExceeded, icmpv6DestUnreachable
+	}
+
+	switch msg[0] {
+	case echoReply:
+		if udp || msg[1] != 0 || binary.BigEndian.Uint16(msg[4:]) != id {
+			return 0, false
+		}
+		return binary.BigEndian.Uint16(msg[6:]), true
+	case timeExceeded, unreachable:
+	default:
+		return 0, false
+	}
+
+	protocol, quotedDst, payload, ok := parseQuotedHeader(ipv6, msg[icmpHeaderLen:])
+	if !ok || !quotedDst.Equal(dst) || len(payload) < 8 {
+		return 0, false
+	}
+
+	if udp {
+		if protocol != protocolUDP || int(binary.BigEndian.Uint16(payload[0:])) != localPort {
+			return 0, false
+		}
+		return binary.BigEndian.Uint16(payload[2:]), true
+	}
+
+	echoRequest, icmpProtocol := byte(icmpv4EchoRequest), protocolICMP
+	if ipv6 {
+		echoRequest, icmpProtocol = icmpv6EchoRequest, protocolICMPv6
+	}
+	if protocol != icmpProtocol || payload[0] != echoRequest || binary.BigEndian.Uint16(payload[4:]) != id {
+		return 0, false
+	}
+	return binary.BigEndian.Uint16(payload[6:]), true
+}
+
+// parseQuotedHeader parses the IP header quoted in an ICMP error message
+// and returns the protocol, destination and quoted payload. IPv6 extension
+// headers are not followed.
+func parseQuotedHeader(ipv6 bool, quoted []byte) (protocol int, dst net.IP, payload []byte, ok bool) {
+	if ipv6 {
+		if len(quoted) < ipv6HeaderLen || quoted[0]>>4 != 6 {
+			return 0, nil, nil, false
+		}
+		return int(quoted[6]), net.IP(quoted[24:40]), quoted[ipv6HeaderLen:], true
+	}
+
+	if len(quoted) < 20 || quoted[0]>>4 != 4 {
+		return 0, nil, nil, false
+	}
+	headerLen := int(quoted[0]&0x0f) * 4
+	if headerLen < 20 || len(quoted) < headerLen {
+		return 0, nil, nil, false
+	}
+	return int(quoted[9]), net.IP(quoted[16:20]), quoted[headerLen:], true
+}
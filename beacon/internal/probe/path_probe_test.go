@@ -0,0 +1,262 @@
+package probe
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"beacon/internal/models"
+)
+
+// TestPathProbeConfigValidation tests path probe configuration validation
+func TestPathProbeConfigValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  PathProbeConfig
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid icmp config",
+			config:  PathProbeConfig{Type: "path_probe", Target: "example.com", TimeoutSeconds: 2, Interval: 60, Count: 10},
+			wantErr: false,
+		},
+		{
+			name:    "valid udp config",
+			config:  PathProbeConfig{Type: "path_probe", Target: "8.8.8.8", Protocol: "udp", MaxHops: 20, TimeoutSeconds: 2, Interval: 60, Count: 10},
+			wantErr: false,
+		},
+		{
+			name:    "invalid protocol",
+			config:  PathProbeConfig{Type: "path_probe", Target: "8.8.8.8", Protocol: "tcp", TimeoutSeconds: 2, Interval: 60, Count: 10},
+			wantErr: true,
+			errMsg:  "invalid protocol",
+		},
+		{
+			name:    "max hops too high",
+			config:  PathProbeConfig{Type: "path_probe", Target: "8.8.8.8", MaxHops: 65, TimeoutSeconds: 2, Interval: 60, Count: 10},
+			wantErr: true,
+			errMsg:  "invalid max_hops",
+		},
+		{
+			name:    "invalid type",
+			config:  PathProbeConfig{Type: "icmp_ping", Target: "8.8.8.8", TimeoutSeconds: 2, Interval: 60, Count: 10},
+			wantErr: true,
+			errMsg:  "invalid probe type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && !contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+// quotedICMPError builds an IPv4 ICMP error message of the given type
+// quoting a probe sent to dst with the given protocol and first 8 bytes
+func quotedICMPError(icmpType byte, protocol byte, dst net.IP, probe []byte) []byte {
+	quoted := make([]byte, 20, 28)
+	quoted[0] = 0x45
+	quoted[9] = protocol
+	copy(quoted[16:20], dst.To4())
+	quoted = append(quoted, probe[:8]...)
+
+	msg := make([]byte, icmpHeaderLen, icmpHeaderLen+len(quoted))
+	msg[0] = icmpType
+	return append(msg, quoted...)
+}
+
+// TestParseHopReply tests matching ICMP replies to ICMP and UDP probes
+func TestParseHopReply(t *testing.T) {
+	dst := net.ParseIP("192.0.2.10")
+
+	// Time Exceeded for an ICMP echo probe
+	echo := marshalEchoRequest(false, 0x1234, 42, nil)
+	if key, ok := parseHopReply(false, false, quotedICMPError(icmpv4TimeExceeded, protocolICMP, dst, echo), dst, 0x1234, 0); !ok || key != 42 {
+		t.Errorf("Expected ICMP time exceeded to match seq 42, got key=%d ok=%v", key, ok)
+	}
+
+	// Other pingers' probes and other destinations are ignored
+	if _, ok := parseHopReply(false, false, quotedICMPError(icmpv4TimeExceeded, protocolICMP, dst, echo), dst, 0x9999, 0); ok {
+		t.Error("Expected probe with another echo identifier to be ignored")
+	}
+	if _, ok := parseHopReply(false, false, quotedICMPError(icmpv4TimeExceeded, protocolICMP, net.ParseIP("192.0.2.99"), echo), dst, 0x1234, 0); ok {
+		t.Error("Expected probe to another destination to be ignored")
+	}
+
+	// Echo reply from the destination
+	reply := marshalEchoRequest(false, 0x1234, 43, nil)
+	reply[0] = icmpv4EchoReply
+	if key, ok := parseHopReply(false, false, reply, dst, 0x1234, 0); !ok || key != 43 {
+		t.Errorf("Expected echo reply to match seq 43, got key=%d ok=%v", key, ok)
+	}
+
+	// Time Exceeded and Port Unreachable for UDP probes, keyed by destination port
+	udpHeader := make([]byte, 8)
+	binary.BigEndian.PutUint16(udpHeader[0:], 50000)
+	binary.BigEndian.PutUint16(udpHeader[2:], 33440)
+	for _, icmpType := range []byte{icmpv4TimeExceeded, icmpv4DestUnreachable} {
+		if key, ok := parseHopReply(false, true, quotedICMPError(icmpType, protocolUDP, dst, udpHeader), dst, 0, 50000); !ok || key != 33440 {
+			t.Errorf("Expected ICMP type %d to match port 33440, got key=%d ok=%v", icmpType, key, ok)
+		}
+	}
+	if _, ok := parseHopReply(false, true, quotedICMPError(icmpv4TimeExceeded, protocolUDP, dst, udpHeader), dst, 0, 50001); ok {
+		t.Error("Expected UDP probe from another socket to be ignored")
+	}
+	if _, ok := parseHopReply(false, true, reply, dst, 0x1234, 50000); ok {
+		t.Error("Expected echo reply to be ignored by UDP probes")
+	}
+}
+
+// TestHopStatsSummary tests per-hop loss, RTT and address selection
+func TestHopStatsSummary(t *testing.T) {
+	hop := &hopStats{ttl: 3, sent: 4, addresses: make(map[string]int)}
+	hop.record("10.0.0.1", 12)
+	hop.record("10.0.0.2", 8)
+	hop.record("10.0.0.2", 10)
+
+	summary := hop.summary()
+
+	if summary.TTL != 3 || summary.Sent != 4 || summary.Received != 3 {
+		t.Errorf("Unexpected counts: %+v", summary)
+	}
+	if summary.Address != "10.0.0.2" {
+		t.Errorf("Expected the most frequent responder, got %q", summary.Address)
+	}
+	if summary.PacketLossRate != 25 {
+		t.Errorf("Expected 25%% loss, got %.2f", summary.PacketLossRate)
+	}
+	if summary.RTTMs != 10 || summary.BestMs != 8 || summary.WorstMs != 12 {
+		t.Errorf("Unexpected RTTs: mean=%.2f best=%.2f worst=%.2f", summary.RTTMs, summary.BestMs, summary.WorstMs)
+	}
+
+	silent := (&hopStats{ttl: 4, sent: 2, addresses: make(map[string]int)}).summary()
+	if silent.Address != "" || silent.PacketLossRate != 100 {
+		t.Errorf("Expected silent hop with full loss, got %+v", silent)
+	}
+}
+
+// scriptedReplyConn returns scripted replies, then blocks until its read
+// deadline like a socket nobody else answers on
+type scriptedReplyConn struct {
+	net.PacketConn // Only ReadFrom and SetReadDeadline are used
+	replies        []scriptedReply
+	deadline       time.Time
+}
+
+type scriptedReply struct {
+	key  byte // Probe key the reply matches
+	from string
+}
+
+func (c *scriptedReplyConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	if len(c.replies) > 0 {
+		reply := c.replies[0]
+		c.replies = c.replies[1:]
+		b[0] = reply.key
+		return 1, &net.IPAddr{IP: net.ParseIP(reply.from)}, nil
+	}
+	time.Sleep(time.Until(c.deadline))
+	return 0, nil, os.ErrDeadlineExceeded
+}
+
+func (c *scriptedReplyConn) SetReadDeadline(t time.Time) error {
+	c.deadline = t
+	return nil
+}
+
+// TestAwaitHopReplies_SilentHop tests that a router that never answers does
+// not hold a round until the timeout once the destination replied, so rounds
+// stay short and the batch finishes within its interval
+func TestAwaitHopReplies_SilentHop(t *testing.T) {
+	// Arrange - TTL 2 never answers, the destination is at TTL 3 and also
+	// answers the TTL 4 probe
+	hops := make([]*hopStats, 5)
+	pending := make(map[uint16]*pendingHopProbe)
+	for ttl := 1; ttl <= 5; ttl++ {
+		hops[ttl-1] = &hopStats{ttl: ttl, sent: 1, addresses: make(map[string]int)}
+		pending[uint16(ttl)] = &pendingHopProbe{ttl: ttl, sentAt: time.Now()}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	conn := &scriptedReplyConn{
+		replies:  []scriptedReply{{key: 1, from: "10.0.0.1"}, {key: 4, from: "192.0.2.9"}, {key: 3, from: "192.0.2.9"}},
+		deadline: deadline,
+	}
+	match := func(msg []byte) (uint16, bool) { return uint16(msg[0]), true }
+	destTTL := 0
+
+	// Act
+	start := time.Now()
+	destReplied, err := awaitHopReplies(context.Background(), conn, match, net.ParseIP("192.0.2.9"), pending, hops, &destTTL, deadline, make([]byte, 64))
+	elapsed := time.Since(start)
+
+	// Assert
+	if err != nil || !destReplied || destTTL != 3 {
+		t.Fatalf("Expected the destination at TTL 3, got replied=%v destTTL=%d err=%v", destReplied, destTTL, err)
+	}
+	if elapsed > 2*pathHopGrace {
+		t.Errorf("Expected the round to end %v after the destination replied, took %v", pathHopGrace, elapsed)
+	}
+	if len(pending) != 1 || pending[2] == nil {
+		t.Errorf("Expected only the silent hop to stay unanswered, got %v", pending)
+	}
+	if silent := hops[1].summary(); silent.Sent != 1 || silent.PacketLossRate != 100 {
+		t.Errorf("Expected the silent hop counted as lost, got %+v", silent)
+	}
+	if beyond := hops[4]; beyond.sent != 0 {
+		t.Errorf("Expected the probe beyond the destination not to count, got sent=%d", beyond.sent)
+	}
+}
+
+// TestPathProber_ExecuteBatch_Loopback traces localhost, which is one hop away
+func TestPathProber_ExecuteBatch_Loopback(t *testing.T) {
+	initSchedulerTestLogger(t)
+
+	// Arrange - needs CAP_NET_RAW
+	conn, err := listenICMPRaw(false)
+	if err != nil {
+		t.Skipf("Raw ICMP sockets unavailable in this environment: %v", err)
+	}
+	conn.Close()
+
+	for _, protocol := range []string{"icmp", "udp"} {
+		t.Run(protocol, func(t *testing.T) {
+			prober := NewPathProber(PathProbeConfig{Type: "path_probe", Target: "127.0.0.1", Protocol: protocol, MaxHops: 5, TimeoutSeconds: 1, Interval: 60, Count: 2})
+
+			// Act
+			result, err := prober.ExecuteBatch(context.Background(), 2)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("ExecuteBatch failed: %v", err)
+			}
+			if !result.Success || result.Type != "path_probe" || result.ProbeID != "path_probe:127.0.0.1" {
+				t.Fatalf("Unexpected result: %+v", result)
+			}
+			if hopCount := result.MetricFloat(models.MetricHopCount); hopCount != 1 {
+				t.Errorf("Expected 1 hop, got %.0f", hopCount)
+			}
+			hops, ok := result.Metrics[models.MetricHops].([]models.PathHop)
+			if !ok || len(hops) != 1 {
+				t.Fatalf("Expected one hop, got %v", result.Metrics[models.MetricHops])
+			}
+			if hops[0].Address != "127.0.0.1" || hops[0].Received != 2 || hops[0].PacketLossRate != 0 {
+				t.Errorf("Unexpected hop: %+v", hops[0])
+			}
+			if loss := result.MetricFloat(models.MetricPacketLossRate); loss != 0 {
+				t.Errorf("Expected no loss to localhost, got %.2f%%", loss)
+			}
+		})
+	}
+}
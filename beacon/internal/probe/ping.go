@@ -1,18 +0,0 @@
-package probe
-
-// Ping represents a network probe implementation
-// This package will be implemented in future stories
-type Ping struct {
-	// Probe configuration and implementation will go here
-}
-
-// NewPing creates a new Ping probe
-func NewPing() *Ping {
-	return &Ping{}
-}
-
-// Execute performs the ping operation
-func (p *Ping) Execute() error {
-	// Placeholder - will be implemented in future stories
-	return nil
-}
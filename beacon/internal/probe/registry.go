@@ -0,0 +1,324 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-viper/mapstructure/v2"
+
+	"beacon/internal/config"
+	"beacon/internal/models"
+)
+
+// Prober is a configured probe engine. The scheduler executes one batch per
+// interval; every probe type reports the unified models.ProbeResult.
+type Prober interface {
+	ExecuteBatch(ctx context.Context, count int) (*models.ProbeResult, error)
+	ProbeID() string
+}
+
+// ProbeSpec is a probe configuration decoded into its type-specific form
+type ProbeSpec interface {
+	Validate() error   // Checks the type-specific configuration
+	NewProber() Prober // Builds the probe engine; called only after Validate succeeds
+}
+
+// ProbeDecoder converts a generic probe configuration entry into the
+// ProbeSpec of its type, parsing its type-specific options (see
+// DecodeOptions)
+type ProbeDecoder func(cfg config.ProbeConfig) (ProbeSpec, error)
+
+// ErrUnknownProbeType is returned for probe types without a registered decoder
+var ErrUnknownProbeType = errors.New("unknown probe type")
+
+// ResultMetric describes a type-specific entry of ProbeResult.Metrics. The
+// reporter sends it to Pulse as heartbeat field Field (Key if empty) whenever
+// a result carries it; Gauge, if set, exposes it to Prometheus.
+type ResultMetric struct {
+	Key      string
+	Field    string
+	OmitZero bool         // Not reported while the value is 0, false or empty
+	Gauge    *ResultGauge // Prometheus gauge, nil if not exposed
+}
+
+// ResultGauge is the Prometheus gauge of a ResultMetric, labelled node_id,
+// node_name, probe_id and target. Metrics sharing a gauge tell their series
+// apart by an extra label Label=LabelValue.
+type ResultGauge struct {
+	Name        string
+	Help        string
+	Label       string
+	LabelValue  string
+	SuccessOnly bool // Exposed only while the latest run succeeded
+}
+
+// HeartbeatField returns the heartbeat field name of the metric
+func (m ResultMetric) HeartbeatField() string {
+	if m.Field == "" {
+		return m.Key
+	}
+	return m.Field
+}
+
+// Value returns the metric of a result and whether it is reported
+func (m ResultMetric) Value(result *models.ProbeResult) (interface{}, bool) {
+	value, ok := result.Metrics[m.Key]
+	if !ok || value == nil {
+		return nil, false
+	}
+	if m.OmitZero {
+		v := reflect.ValueOf(value)
+		switch v.Kind() {
+		case reflect.Slice, reflect.Map, reflect.String:
+			return value, v.Len() > 0
+		default:
+			return value, !v.IsZero()
+		}
+	}
+	return value, true
+}
+
+// registeredType is the decoder of a probe type, the type-specific options
+// (see config.ProbeConfig.Options) its entries may set, plus port if the type
+// uses one, and the type-specific metrics its results report
+type registeredType struct {
+	decode  ProbeDecoder
+	options map[string]bool
+	metrics []ResultMetric
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]registeredType)
+)
+
+func init() {
+	// Probe entries of the config file are validated against the registry
+	config.SetProbeValidator(ValidateConfig)
+}
+
+// RegisterProbeType makes a probe type available to config validation and
+// the scheduler. options lists the type-specific settings the type accepts,
+// including port; entries setting any other option are rejected. Type-specific result
+// metrics are described with RegisterResultMetrics. Probe types register
+// themselves from an init function in their own file; config validation, the
+// scheduler, reporter and metrics server need no changes for a new type.
+// Pulse only accepts heartbeats of the types it knows. Registering the same
+// type twice panics.
+func RegisterProbeType(probeType string, decode ProbeDecoder, options ...string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if decode == nil {
+		panic("probe: nil decoder for probe type " + probeType)
+	}
+	if _, exists := registry[probeType]; exists {
+		panic("probe: probe type registered twice: " + probeType)
+	}
+
+	accepted := make(map[string]bool, len(options))
+	for _, option := range options {
+		accepted[option] = true
+	}
+	registry[probeType] = registeredType{decode: decode, options: accepted}
+}
+
+// RegisterResultMetrics describes the type-specific metrics a registered
+// probe type reports, for heartbeats and Prometheus. Metrics not described
+// here stay local to the beacon. Registering metrics of an unknown type
+// panics.
+func RegisterResultMetrics(probeType string, metrics ...ResultMetric) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registered, exists := registry[probeType]
+	if !exists {
+		panic("probe: result metrics for unregistered probe type " + probeType)
+	}
+	registered.metrics = append(registered.metrics, metrics...)
+	registry[probeType] = registered
+}
+
+// ResultMetrics returns the type-specific metrics reported by a probe type,
+// nil for unregistered types
+func ResultMetrics(probeType string) []ResultMetric {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registry[probeType].metrics
+}
+
+// ResultGauges returns the Prometheus gauges of all registered probe types,
+// one per gauge name, sorted by name
+func ResultGauges() []ResultGauge {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	byName := make(map[string]ResultGauge)
+	for _, registered := range registry {
+		for _, metric := range registered.metrics {
+			if metric.Gauge != nil {
+				if _, seen := byName[metric.Gauge.Name]; !seen {
+					byName[metric.Gauge.Name] = *metric.Gauge
+				}
+			}
+		}
+	}
+
+	gauges := make([]ResultGauge, 0, len(byName))
+	for _, gauge := range byName {
+		gauges = append(gauges, gauge)
+	}
+	sort.Slice(gauges, func(i, j int) bool { return gauges[i].Name < gauges[j].Name })
+	return gauges
+}
+
+// RegisteredProbeTypes returns the registered probe types, sorted by name
+func RegisteredProbeTypes() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	types := make([]string, 0, len(registry))
+	for probeType := range registry {
+		types = append(types, probeType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// ValidateConfig checks a probe configuration entry against the rules of its
+// registered type. Unregistered types return ErrUnknownProbeType.
+func ValidateConfig(cfg config.ProbeConfig) error {
+	_, err := decodeProbe(cfg)
+	return err
+}
+
+// NewProber decodes and validates a probe configuration entry and builds its
+// probe engine. Unregistered types return ErrUnknownProbeType.
+func NewProber(cfg config.ProbeConfig) (Prober, error) {
+	spec, err := decodeProbe(cfg)
+	if errors.Is(err, ErrUnknownProbeType) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid probe config for %s: %w", probeLabel(cfg), err)
+	}
+
+	return spec.NewProber(), nil
+}
+
+// decodeProbe decodes a probe configuration entry into the validated
+// ProbeSpec of its type
+func decodeProbe(cfg config.ProbeConfig) (ProbeSpec, error) {
+	registryMu.RLock()
+	probeType, ok := registry[cfg.Type]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w '%s', must be one of: %s", ErrUnknownProbeType, cfg.Type, strings.Join(RegisteredProbeTypes(), ", "))
+	}
+
+	// Settings no probe type knows are ignored, like unknown config keys
+	options := make([]string, 0, len(cfg.Options)+1)
+	if cfg.Port != 0 {
+		options = append(options, "port")
+	}
+	for option := range cfg.Options {
+		if isRegisteredOption(option) {
+			options = append(options, option)
+		}
+	}
+	sort.Strings(options)
+	for _, option := range options {
+		if !probeType.options[option] {
+			return nil, fmt.Errorf("%s is not supported by %s probes (suggestion: remove %s from the probe)", option, cfg.Type, option)
+		}
+	}
+
+	spec, err := probeType.decode(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// isRegisteredOption reports whether any registered probe type accepts the
+// option
+func isRegisteredOption(option string) bool {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, registered := range registry {
+		if registered.options[option] {
+			return true
+		}
+	}
+	return false
+}
+
+// DecodeOptions decodes the type-specific options of a probe entry into the
+// fields of target (a struct pointer) whose yaml tag names the option, with
+// the same type conversions as the config file
+func DecodeOptions(cfg config.ProbeConfig, target interface{}) error {
+	if len(cfg.Options) == 0 {
+		return nil
+	}
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		TagName:          "yaml",
+		WeaklyTypedInput: true,
+		Result:           target,
+	})
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(cfg.Options); err != nil {
+		return fmt.Errorf("invalid %s options: %w", cfg.Type, err)
+	}
+	return nil
+}
+
+// validateSchedule checks the interval, count and timeout ranges shared by
+// all probe types
+func validateSchedule(interval, count, timeoutSeconds int) error {
+	if timeoutSeconds < 1 || timeoutSeconds > 30 {
+		return fmt.Errorf("invalid timeout %d, must be between 1 and 30 seconds", timeoutSeconds)
+	}
+
+	if interval < 60 || interval > 300 {
+		return fmt.Errorf("invalid interval %d, must be between 60 and 300 seconds", interval)
+	}
+
+	if count < 1 || count > 100 {
+		return fmt.Errorf("invalid count %d, must be between 1 and 100", count)
+	}
+
+	return nil
+}
+
+// minCoreMetricsCount is the smallest batch the ping probe types calculate
+// their loss, jitter and percentile metrics from
+const minCoreMetricsCount = 10
+
+// validateCoreMetricsCount checks the batch size of a ping probe type
+func validateCoreMetricsCount(count int) error {
+	if count < minCoreMetricsCount {
+		return fmt.Errorf("probe count must be ≥ %d to calculate core metrics (current: %d)", minCoreMetricsCount, count)
+	}
+	return nil
+}
+
+// probeLabel returns the target of a probe entry for error messages, with
+// the port for probe types that use one
+func probeLabel(cfg config.ProbeConfig) string {
+	if cfg.Port == 0 {
+		return cfg.Target
+	}
+	return fmt.Sprintf("%s:%d", cfg.Target, cfg.Port)
+}
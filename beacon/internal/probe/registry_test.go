@@ -0,0 +1,320 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"beacon/internal/config"
+	"beacon/internal/models"
+)
+
+// stubProbeConfig is a probe type registered by the tests only
+type stubProbeConfig struct {
+	target string
+}
+
+func (c *stubProbeConfig) Validate() error {
+	if c.target == "" {
+		return fmt.Errorf("probe target cannot be empty")
+	}
+	return nil
+}
+
+func (c *stubProbeConfig) NewProber() Prober {
+	return &stubProber{target: c.target}
+}
+
+type stubProber struct {
+	target string
+}
+
+func (p *stubProber) ExecuteBatch(ctx context.Context, count int) (*models.ProbeResult, error) {
+	result := models.NewProbeResult("stub_probe", p.target, true, map[string]interface{}{
+		models.MetricRTTMs:       1.5,
+		models.MetricSampleCount: count,
+	}, "")
+	result.ProbeID = p.ProbeID()
+	return result, nil
+}
+
+func (p *stubProber) ProbeID() string {
+	return models.ProbeKey("stub_probe", p.target, 0)
+}
+
+func init() {
+	RegisterProbeType("stub_probe", func(cfg config.ProbeConfig) (ProbeSpec, error) {
+		return &stubProbeConfig{target: cfg.Target}, nil
+	})
+}
+
+// TestValidateConfig_Registry tests that config validation applies the
+// rules of the registered probe types
+func TestValidateConfig_Registry(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.ProbeConfig
+		wantErr string
+	}{
+		{name: "type registered by tests", cfg: config.ProbeConfig{Type: "stub_probe", Target: "10.0.0.1"}},
+		{name: "unknown type", cfg: config.ProbeConfig{Type: "carrier_pigeon", Target: "10.0.0.1"}, wantErr: "unknown probe type 'carrier_pigeon', must be one of:"},
+		{name: "option of another type", cfg: config.ProbeConfig{Type: "stub_probe", Target: "10.0.0.1", Options: map[string]interface{}{"server_name": "example.com"}}, wantErr: "server_name is not supported by stub_probe"},
+		{name: "type rules", cfg: config.ProbeConfig{Type: "stub_probe"}, wantErr: "probe target cannot be empty"},
+		{name: "setting no type knows", cfg: config.ProbeConfig{Type: "stub_probe", Target: "10.0.0.1", Options: map[string]interface{}{"timeout": 5}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := config.ValidateProbeConfig(tt.cfg)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected config to be accepted, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestRegisterProbeType_Duplicate tests that a probe type cannot be registered twice
+func TestRegisterProbeType_Duplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for duplicate registration")
+		}
+	}()
+
+	RegisterProbeType("tcp_ping", func(cfg config.ProbeConfig) (ProbeSpec, error) { return nil, nil })
+}
+
+// TestResultMetrics tests the type-specific metrics described by the probe types
+func TestResultMetrics(t *testing.T) {
+	// Arrange
+	statusCode := ResultMetric{Key: models.MetricStatusCode, OmitZero: true}
+	hops := ResultMetric{Key: models.MetricHops, OmitZero: true}
+	rcode := ResultMetric{Key: models.MetricRcode}
+	result := &models.ProbeResult{Metrics: map[string]interface{}{
+		models.MetricStatusCode: 0,
+		models.MetricHops:       []models.PathHop{},
+		models.MetricRcode:      0,
+	}}
+
+	// Act & Assert
+	if _, ok := statusCode.Value(result); ok {
+		t.Error("Expected a zero status code to be omitted")
+	}
+	if _, ok := hops.Value(result); ok {
+		t.Error("Expected an empty hop list to be omitted")
+	}
+	if value, ok := rcode.Value(result); !ok || value != 0 {
+		t.Errorf("Expected rcode 0 to be reported, got %v (%v)", value, ok)
+	}
+	if _, ok := (ResultMetric{Key: models.MetricTLSVersion}).Value(result); ok {
+		t.Error("Expected a missing metric to be omitted")
+	}
+
+	cipher := ResultMetric{Key: models.MetricCipherSuite, Field: "tls_cipher"}
+	if cipher.HeartbeatField() != "tls_cipher" || rcode.HeartbeatField() != "rcode" {
+		t.Errorf("Unexpected heartbeat fields %q, %q", cipher.HeartbeatField(), rcode.HeartbeatField())
+	}
+	if len(ResultMetrics("stub_probe")) != 0 || len(ResultMetrics("dns_probe")) != 3 {
+		t.Errorf("Unexpected result metrics: stub_probe %v, dns_probe %v", ResultMetrics("stub_probe"), ResultMetrics("dns_probe"))
+	}
+
+	phases := 0
+	for _, gauge := range ResultGauges() {
+		if gauge.Name == "beacon_http_phase_ms" {
+			phases++
+		}
+	}
+	if phases != 1 {
+		t.Errorf("Expected one beacon_http_phase_ms gauge shared by the phases, got %d", phases)
+	}
+}
+
+// TestRegisterResultMetrics_UnknownType tests that metrics of an unregistered type are rejected
+func TestRegisterResultMetrics_UnknownType(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for an unregistered probe type")
+		}
+	}()
+
+	RegisterResultMetrics("unknown_probe", ResultMetric{Key: "value"})
+}
+
+// TestNewProber tests decoding, validation and engine construction through the registry
+func TestNewProber(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.ProbeConfig
+		wantErr string
+	}{
+		{name: "tcp_ping", cfg: config.ProbeConfig{Type: "tcp_ping", Target: "127.0.0.1", Port: 80, TimeoutSeconds: 1, Interval: 60, Count: 10}},
+		{name: "udp_ping", cfg: config.ProbeConfig{Type: "udp_ping", Target: "127.0.0.1", Port: 53, TimeoutSeconds: 1, Interval: 60, Count: 10}},
+		{name: "unknown type", cfg: config.ProbeConfig{Type: "carrier_pigeon", Target: "127.0.0.1", Count: 10}, wantErr: "unknown probe type"},
+		{name: "invalid config", cfg: config.ProbeConfig{Type: "tcp_ping", Target: "127.0.0.1", Port: 0, Interval: 60, Count: 10}, wantErr: "invalid probe config for 127.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prober, err := NewProber(tt.cfg)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewProber failed: %v", err)
+			}
+			if want := models.ProbeKey(tt.cfg.Type, tt.cfg.Target, tt.cfg.Port); prober.ProbeID() != want {
+				t.Errorf("Expected probe ID %s, got %s", want, prober.ProbeID())
+			}
+		})
+	}
+
+	if _, err := NewProber(config.ProbeConfig{Type: "carrier_pigeon"}); !errors.Is(err, ErrUnknownProbeType) {
+		t.Errorf("Expected ErrUnknownProbeType, got %v", err)
+	}
+}
+
+// TestDecodeOptions tests that type-specific options are decoded into the
+// spec of the registered type
+func TestDecodeOptions(t *testing.T) {
+	// Arrange - values as loaded from YAML
+	cfg := config.ProbeConfig{
+		Type:           "dns_probe",
+		Target:         "10.0.0.53",
+		Port:           53,
+		TimeoutSeconds: 2,
+		Interval:       60,
+		Count:          10,
+		Options: map[string]interface{}{
+			"query_name":       "api.internal.example.com",
+			"record_type":      "AAAA",
+			"expected_answers": []interface{}{"::1", "::2"},
+		},
+	}
+
+	// Act
+	spec, err := decodeProbe(cfg)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected dns_probe to decode, got %v", err)
+	}
+	dns := spec.(*DNSProbeConfig)
+	if dns.QueryName != "api.internal.example.com" || dns.RecordType != "AAAA" || strings.Join(dns.ExpectedAnswers, ",") != "::1,::2" {
+		t.Errorf("Unexpected spec: %+v", dns)
+	}
+
+	// Act - option of the wrong type
+	cfg = config.ProbeConfig{Type: "http_probe", Target: "https://example.com", TimeoutSeconds: 5, Interval: 60, Count: 1, Options: map[string]interface{}{"expected_status": "ok"}}
+	if _, err := decodeProbe(cfg); err == nil || !strings.Contains(err.Error(), "invalid http_probe options") {
+		t.Errorf("Expected option decoding error, got %v", err)
+	}
+}
+
+// TestNewProber_CoreMetricsCount tests that only the ping probe types
+// require batches large enough for the core metrics
+func TestNewProber_CoreMetricsCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.ProbeConfig
+		wantErr bool
+	}{
+		{name: "tcp_ping count 5", cfg: config.ProbeConfig{Type: "tcp_ping", Target: "127.0.0.1", Port: 80, TimeoutSeconds: 1, Interval: 60, Count: 5}, wantErr: true},
+		{name: "udp_ping count 5", cfg: config.ProbeConfig{Type: "udp_ping", Target: "127.0.0.1", Port: 53, TimeoutSeconds: 1, Interval: 60, Count: 5}, wantErr: true},
+		{name: "icmp_ping count 5", cfg: config.ProbeConfig{Type: "icmp_ping", Target: "127.0.0.1", TimeoutSeconds: 1, Interval: 60, Count: 5}, wantErr: true},
+		{name: "http_probe count 1", cfg: config.ProbeConfig{Type: "http_probe", Target: "https://example.com/health", TimeoutSeconds: 5, Interval: 60, Count: 1}},
+		{name: "tls_probe count 1", cfg: config.ProbeConfig{Type: "tls_probe", Target: "example.com", Port: 443, TimeoutSeconds: 5, Interval: 60, Count: 1}},
+		{name: "stub_probe count 1", cfg: config.ProbeConfig{Type: "stub_probe", Target: "127.0.0.1", Count: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewProber(tt.cfg)
+
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "must be ≥ 10") {
+					t.Errorf("Expected core metrics count error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected config to be accepted, got %v", err)
+			}
+		})
+	}
+}
+
+// TestProbeScheduler_RegisteredType tests that a registered probe type is
+// scheduled and reported without scheduler changes
+func TestProbeScheduler_RegisteredType(t *testing.T) {
+	initSchedulerTestLogger(t)
+
+	// Arrange
+	scheduler, err := NewProbeScheduler([]config.ProbeConfig{
+		{Type: "stub_probe", Target: "10.0.0.1", Interval: 60, Count: 10},
+		{Type: "carrier_pigeon", Target: "10.0.0.2", Interval: 60, Count: 10},
+	})
+	if err != nil {
+		t.Fatalf("NewProbeScheduler failed: %v", err)
+	}
+
+	// Act
+	scheduler.executeProbe(context.Background(), scheduler.probes[0])
+	results := scheduler.GetLatestResults()
+
+	// Assert - unsupported types are skipped
+	if scheduler.GetProbeCount() != 1 {
+		t.Errorf("Expected 1 probe, got %d", scheduler.GetProbeCount())
+	}
+	if len(results) != 1 || results[0].Type != "stub_probe" || results[0].ProbeID != "stub_probe:10.0.0.1" {
+		t.Fatalf("Unexpected results: %+v", results)
+	}
+	if count := results[0].MetricFloat(models.MetricSampleCount); count != 10 {
+		t.Errorf("Expected sample count 10, got %.0f", count)
+	}
+}
+
+// TestTCPProber_GenericResult tests that tcp_ping results reach the
+// scheduler as generic results with core metrics and statistics
+func TestTCPProber_GenericResult(t *testing.T) {
+	// Arrange - nothing listens on the port
+	prober, err := NewProber(config.ProbeConfig{Type: "tcp_ping", Target: "127.0.0.1", Port: 19997, TimeoutSeconds: 1, Interval: 60, Count: 10})
+	if err != nil {
+		t.Fatalf("NewProber failed: %v", err)
+	}
+
+	// Act
+	result, err := prober.ExecuteBatch(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+
+	// Assert
+	if result.Type != "tcp_ping" || result.Target != "127.0.0.1" || result.Port != 19997 {
+		t.Errorf("Unexpected identity: %+v", result)
+	}
+	if result.Success {
+		t.Error("Expected failure against a closed port")
+	}
+	if loss := result.MetricFloat(models.MetricPacketLossRate); loss != 100 {
+		t.Errorf("Expected 100%% packet loss, got %.1f", loss)
+	}
+	if _, ok := result.Metrics[models.MetricRTTP95Ms]; !ok {
+		t.Error("Expected RTT statistics in metrics")
+	}
+	if len(result.Samples) != 10 {
+		t.Errorf("Expected 10 samples, got %d", len(result.Samples))
+	}
+}
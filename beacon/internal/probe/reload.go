@@ -0,0 +1,36 @@
+package probe
+
+// ReloadReport lists the probes affected by a configuration reload. Probes
+// are identified by their probe ID: the explicit `id`, or type, target and
+// port (see models.ProbeKey).
+type ReloadReport struct {
+	Added     []string `json:"added,omitempty"`   // Probes started
+	Removed   []string `json:"removed,omitempty"` // Probes stopped, their results dropped
+	Changed   []string `json:"changed,omitempty"` // Probes restarted with new settings
+	Unchanged int      `json:"unchanged"`         // Probes kept running with their results
+}
+
+// HasChanges reports whether the reload started, stopped or restarted any probe
+func (r ReloadReport) HasChanges() bool {
+	return len(r.Added) > 0 || len(r.Removed) > 0 || len(r.Changed) > 0
+}
+
+// Fields returns the report as log fields
+func (r ReloadReport) Fields() map[string]interface{} {
+	fields := map[string]interface{}{
+		"added_count":     len(r.Added),
+		"removed_count":   len(r.Removed),
+		"changed_count":   len(r.Changed),
+		"unchanged_count": r.Unchanged,
+	}
+	if len(r.Added) > 0 {
+		fields["added"] = r.Added
+	}
+	if len(r.Removed) > 0 {
+		fields["removed"] = r.Removed
+	}
+	if len(r.Changed) > 0 {
+		fields["changed"] = r.Changed
+	}
+	return fields
+}
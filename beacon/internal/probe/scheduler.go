@@ -1,8 +1,11 @@
 package probe
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"net"
+	"math/rand"
+	"reflect"
 	"sync"
 	"time"
 
@@ -11,239 +14,241 @@ import (
 	"beacon/internal/models"
 )
 
-// ProbeScheduler manages and executes multiple probes
+// maxStartJitter bounds the random delay before a probe's first execution,
+// spreading probes so a beacon does not burst them all at the same instant
+const maxStartJitter = 10 * time.Second
+
+// ProbeScheduler manages and executes multiple probes.
+// Each probe runs on its own timer at its configured interval.
 type ProbeScheduler struct {
-	tcpPingers    []*TCPPinger
-	udpPingers    []*UDPPinger
-	interval      time.Duration
-	baseInterval  time.Duration // Store original interval for degradation recovery
-	stopChan      chan struct{}
-	wg            sync.WaitGroup
-	running       bool
-	mu            sync.RWMutex
-	// Cache latest results for heartbeat reporting
-	latestTCPResults []*models.TCPProbeResult
-	latestUDPResults []*models.UDPProbeResult
-	resultsMu        sync.RWMutex
+	probes     []*scheduledProbe // Configured probes, in configuration order
+	multiplier int               // Resource degradation multiplier applied to each probe's interval
+	// intervalChanged is closed and replaced whenever the multiplier changes,
+	// waking every probe loop to reschedule its pending timer
+	intervalChanged chan struct{}
+	stopChan        chan struct{} // Closed by Stop
+	// probeCtx is the parent of every probe loop's context; it is cancelled
+	// by Stop to end all loops and abort their in-flight batches
+	probeCtx    context.Context
+	probeCancel context.CancelFunc
+	wg          sync.WaitGroup
+	running     bool
+	mu          sync.RWMutex
+	// startOffset returns the delay before a probe's first execution
+	startOffset func(interval time.Duration) time.Duration
+	// Cache latest result per probe for heartbeat reporting
+	latestResults map[*scheduledProbe]*models.ProbeResult
+	resultsMu     sync.RWMutex
 }
 
 // NewProbeScheduler creates a new probe scheduler from configuration
 func NewProbeScheduler(probeConfigs []config.ProbeConfig) (*ProbeScheduler, error) {
-	scheduler := &ProbeScheduler{
-		tcpPingers: make([]*TCPPinger, 0),
-		udpPingers: make([]*UDPPinger, 0),
-		stopChan:   make(chan struct{}),
-		running:    false,
+	probes, err := newProbes(probeConfigs)
+	if err != nil {
+		return nil, err
 	}
 
-	// Initialize TCP and UDP pingers from config
-	for _, cfg := range probeConfigs {
-		if cfg.Type == "tcp_ping" {
-			tcpConfig := TCPProbeConfig{
-				Type:           cfg.Type,
-				Target:         cfg.Target,
-				Port:           cfg.Port,
-				TimeoutSeconds: cfg.TimeoutSeconds,
-				Interval:       cfg.Interval,
-				Count:          cfg.Count,
-			}
-
-			// Validate configuration (includes count ≥ 10 check)
-			if err := tcpConfig.Validate(); err != nil {
-				return nil, fmt.Errorf("invalid probe config for %s:%d: %w", cfg.Target, cfg.Port, err)
-			}
-
-			// Additional count ≥ 10 validation for core metrics
-			if cfg.Count < 10 {
-				return nil, fmt.Errorf("probe count for %s must be ≥ 10 to calculate core metrics (current: %d)", cfg.Target, cfg.Count)
-			}
+	return &ProbeScheduler{
+		probes:          probes,
+		multiplier:      1,
+		intervalChanged: make(chan struct{}),
+		stopChan:        make(chan struct{}),
+		running:         false,
+		startOffset:     randomStartOffset,
+		latestResults:   make(map[*scheduledProbe]*models.ProbeResult),
+	}, nil
+}
 
-			pinger := NewTCPPinger(tcpConfig)
-			scheduler.tcpPingers = append(scheduler.tcpPingers, pinger)
-		} else if cfg.Type == "udp_ping" {
-			udpConfig := UDPProbeConfig{
-				Type:           cfg.Type,
-				Target:         cfg.Target,
-				Port:           cfg.Port,
-				TimeoutSeconds: cfg.TimeoutSeconds,
-				Interval:       cfg.Interval,
-				Count:          cfg.Count,
-			}
+// scheduledProbe is a configured probe and its engine
+type scheduledProbe struct {
+	config config.ProbeConfig
+	prober Prober
 
-			// Validate configuration (includes count ≥ 10 check)
-			if err := udpConfig.Validate(); err != nil {
-				return nil, fmt.Errorf("invalid probe config for %s:%d: %w", cfg.Target, cfg.Port, err)
-			}
+	// Set while the probe loop runs; cancel stops the loop and aborts its
+	// in-flight batch, done is closed once cancelled
+	cancel context.CancelFunc
+	done   <-chan struct{}
+}
 
-			// Additional count ≥ 10 validation for core metrics
-			if cfg.Count < 10 {
-				return nil, fmt.Errorf("probe count for %s must be ≥ 10 to calculate core metrics (current: %d)", cfg.Target, cfg.Count)
-			}
+// newProbes builds the probe engines of the configuration through the probe
+// type registry. Entries of unregistered types are skipped.
+func newProbes(probeConfigs []config.ProbeConfig) ([]*scheduledProbe, error) {
+	probes := make([]*scheduledProbe, 0, len(probeConfigs))
 
-			pinger := NewUDPPinger(udpConfig)
-			scheduler.udpPingers = append(scheduler.udpPingers, pinger)
+	for _, cfg := range probeConfigs {
+		prober, err := NewProber(cfg)
+		if errors.Is(err, ErrUnknownProbeType) {
+			logger.WithFields(map[string]interface{}{"component": "probe", "probe_type": cfg.Type, "target": cfg.Target}).Warn("Skipping probe of unsupported type")
+			continue
 		}
+		if err != nil {
+			return nil, err
+		}
+		probes = append(probes, &scheduledProbe{config: cfg, prober: prober})
 	}
 
-	return scheduler, nil
+	return probes, nil
+}
+
+// randomStartOffset returns a random delay within min(interval, maxStartJitter)
+func randomStartOffset(interval time.Duration) time.Duration {
+	window := interval
+	if window > maxStartJitter {
+		window = maxStartJitter
+	}
+	if window <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(window)))
 }
 
-// Start begins the probe scheduling loop
+// Start begins scheduling each configured probe on its own interval
 func (s *ProbeScheduler) Start() error {
 	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if s.running {
-		s.mu.Unlock()
 		return fmt.Errorf("scheduler is already running")
 	}
 	s.running = true
-	s.mu.Unlock()
+	s.probeCtx, s.probeCancel = context.WithCancel(context.Background())
 
-	// Determine interval from first probe config (all probes share the same interval in MVP)
-	totalProbes := len(s.tcpPingers) + len(s.udpPingers)
-	if totalProbes == 0 {
+	if len(s.probes) == 0 {
 		logger.Info("No probes configured, scheduler started but will not execute any probes")
 		return nil
 	}
 
-	interval := time.Duration(60) * time.Second // Default interval
-	if len(s.tcpPingers) > 0 {
-		interval = time.Duration(s.tcpPingers[0].config.Interval) * time.Second
-	} else if len(s.udpPingers) > 0 {
-		interval = time.Duration(s.udpPingers[0].config.Interval) * time.Second
-	}
-	s.interval = interval
-	s.baseInterval = interval // Store base interval
+	s.startProbeLoopsLocked()
 
 	logger.WithFields(map[string]interface{}{
-		"component":  "probe",
-		"interval":   interval.String(),
-		"tcp_count":  len(s.tcpPingers),
-		"udp_count":  len(s.udpPingers),
+		"component":   "probe",
+		"probe_count": len(s.probes),
 	}).Info("Probe scheduler started")
 
-	// Start scheduling loop in background
+	return nil
+}
+
+// startProbeLoopsLocked starts one scheduling loop per probe. Caller holds s.mu.
+func (s *ProbeScheduler) startProbeLoopsLocked() {
+	for _, probe := range s.probes {
+		s.startProbeLoopLocked(probe)
+	}
+}
+
+// startProbeLoopLocked starts the scheduling loop of one probe. Caller holds s.mu.
+func (s *ProbeScheduler) startProbeLoopLocked(p *scheduledProbe) {
+	ctx, cancel := context.WithCancel(s.probeCtx)
+	p.cancel = cancel
+	p.done = ctx.Done()
+
 	s.wg.Add(1)
-	go s.run()
+	go s.runProbeLoop(probeInterval(p.config.Interval), func() { s.executeProbe(ctx, p) }, ctx.Done())
+}
 
-	return nil
+// stopProbeLoop stops the loop of a probe, if running, and aborts its in-flight batch
+func (p *scheduledProbe) stopProbeLoop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
 }
 
-// run executes the scheduling loop
-func (s *ProbeScheduler) run() {
-	defer s.wg.Done()
+// probeInterval converts a configured interval in seconds, defaulting to 60s
+func probeInterval(seconds int) time.Duration {
+	if seconds <= 0 {
+		return 60 * time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
 
-	ticker := time.NewTicker(s.interval)
-	defer ticker.Stop()
+// runProbeLoop executes a probe after a jittered start offset and then every
+// baseInterval scaled by the current degradation multiplier. A multiplier
+// change reschedules the pending execution relative to the last run.
+func (s *ProbeScheduler) runProbeLoop(baseInterval time.Duration, execute func(), stop <-chan struct{}) {
+	defer s.wg.Done()
 
-	// Execute probes immediately on start
-	s.executeProbes()
+	changed := s.intervalChangedChan()
+	timer := time.NewTimer(s.startOffset(baseInterval))
+	defer timer.Stop()
 
+	var lastRun time.Time
 	for {
 		select {
-		case <-ticker.C:
-			s.executeProbes()
+		case <-timer.C:
+			// Schedule from the start of the execution so the cadence does not drift
+			lastRun = time.Now()
+			execute()
+			timer.Reset(time.Until(lastRun.Add(s.scaledInterval(baseInterval))))
+		case <-changed:
+			changed = s.intervalChangedChan()
+			// Before the first run the jittered start offset still applies
+			if !lastRun.IsZero() {
+				timer.Reset(time.Until(lastRun.Add(s.scaledInterval(baseInterval))))
+			}
+		case <-stop:
+			return
 		case <-s.stopChan:
-			logger.WithField("component", "probe").Info("Probe scheduler stopping...")
+			logger.WithField("component", "probe").Debug("Probe loop stopping...")
 			return
 		}
 	}
 }
 
-// executeProbes runs all probes concurrently
-func (s *ProbeScheduler) executeProbes() {
-	logger.WithFields(map[string]interface{}{"component": "probe", "tcp_count": len(s.tcpPingers), "udp_count": len(s.udpPingers)}).Info("Executing probes...")
-
-	var wg sync.WaitGroup
-
-	// Temporary storage for results
-	tcpResults := make([]*models.TCPProbeResult, len(s.tcpPingers))
-	udpResults := make([]*models.UDPProbeResult, len(s.udpPingers))
-
-	// Execute TCP probes
-	for i, pinger := range s.tcpPingers {
-		wg.Add(1)
-		go func(index int, p *TCPPinger) {
-			defer wg.Done()
-
-			// Build target address with IPv6 support
-			target := net.JoinHostPort(p.config.Target, fmt.Sprintf("%d", p.config.Port))
-			logger.WithFields(map[string]interface{}{"component": "probe", "probe_type": "tcp_ping", "target": target, "count": p.config.Count}).Debug("Starting TCP probe")
+// intervalChangedChan returns the channel closed on the next multiplier change
+func (s *ProbeScheduler) intervalChangedChan() <-chan struct{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.intervalChanged
+}
 
-			// Execute batch probes with core metrics calculation
-			result, err := p.ExecuteBatch(p.config.Count)
-			if err != nil {
-				logger.WithFields(map[string]interface{}{"component": "probe", "probe_type": "tcp_ping", "target": target, "error": err}).Error("TCP probe failed")
-				return
-			}
+// scaledInterval applies the degradation multiplier to a probe's base interval
+func (s *ProbeScheduler) scaledInterval(baseInterval time.Duration) time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return baseInterval * time.Duration(s.multiplier)
+}
 
-			// Store result for heartbeat reporting
-			tcpResults[index] = result
-
-			// Log core metrics
-			logger.WithFields(map[string]interface{}{
-				"component":      "probe",
-				"probe_type":     "tcp_ping",
-				"target":         target,
-				"success":        result.Success,
-				"sample_count":   result.SampleCount,
-				"rtt_ms":         result.RTTMs,
-				"rtt_median_ms":  result.RTTMedianMs,
-				"jitter_ms":      result.JitterMs,
-				"variance_ms":    result.VarianceMs,
-				"packet_loss":    result.PacketLossRate,
-				"timestamp":      result.Timestamp,
-			}).Info("TCP probe completed")
-		}(i, pinger)
-	}
+// executeProbe runs one batch of a probe and caches the result. The batch is
+// aborted when ctx is cancelled or when it outlasts the probe's current
+// interval, so a slow batch never delays the next run.
+func (s *ProbeScheduler) executeProbe(ctx context.Context, p *scheduledProbe) {
+	target := p.config.Target
+	logger.WithFields(map[string]interface{}{"component": "probe", "probe_type": p.config.Type, "target": target, "count": p.config.Count}).Debug("Starting probe")
 
-	// Execute UDP probes
-	for i, pinger := range s.udpPingers {
-		wg.Add(1)
-		go func(index int, p *UDPPinger) {
-			defer wg.Done()
-
-			// Build target address with IPv6 support
-			target := net.JoinHostPort(p.config.Target, fmt.Sprintf("%d", p.config.Port))
-			logger.WithFields(map[string]interface{}{"component": "probe", "probe_type": "udp_ping", "target": target, "count": p.config.Count}).Debug("Starting UDP probe")
-
-			// Execute batch probes with core metrics calculation
-			result, err := p.ExecuteBatch(p.config.Count)
-			if err != nil {
-				logger.WithFields(map[string]interface{}{"component": "probe", "probe_type": "udp_ping", "target": target, "error": err}).Error("UDP probe failed")
-				return
-			}
+	runCtx, cancel := context.WithTimeout(ctx, s.scaledInterval(probeInterval(p.config.Interval)))
+	defer cancel()
 
-			// Store result for heartbeat reporting
-			udpResults[index] = result
-
-			// Log core metrics
-			logger.WithFields(map[string]interface{}{
-				"component":       "probe",
-				"probe_type":      "udp_ping",
-				"target":          target,
-				"success":         result.Success,
-				"sample_count":    result.SampleCount,
-				"sent":            result.SentPackets,
-				"received":        result.ReceivedPackets,
-				"rtt_ms":          result.RTTMs,
-				"rtt_median_ms":   result.RTTMedianMs,
-				"jitter_ms":       result.JitterMs,
-				"variance_ms":     result.VarianceMs,
-				"packet_loss":     result.PacketLossRate,
-				"timestamp":       result.Timestamp,
-			}).Info("UDP probe completed")
-		}(i, pinger)
+	result, err := p.prober.ExecuteBatch(runCtx, p.config.Count)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{"component": "probe", "probe_type": p.config.Type, "target": target, "error": err}).Error("Probe failed")
+		return
 	}
 
-	wg.Wait()
-
-	// Update cached results
+	// Store result for heartbeat reporting, unless the probe was removed or
+	// the scheduler stopped meanwhile
 	s.resultsMu.Lock()
-	s.latestTCPResults = tcpResults
-	s.latestUDPResults = udpResults
+	if ctx.Err() == nil {
+		s.latestResults[p] = result
+	}
 	s.resultsMu.Unlock()
 
-	logger.WithField("component", "probe").Info("All probes completed")
+	// Log core metrics along with type-specific ones
+	fields := map[string]interface{}{
+		"component":  "probe",
+		"probe_type": p.config.Type,
+		"target":     target,
+		"success":    result.Success,
+		"timestamp":  result.Timestamp,
+	}
+	if result.Partial {
+		fields["partial"] = true
+	}
+	for key, value := range result.Metrics {
+		fields[key] = value
+	}
+	if result.ErrorMessage != "" {
+		fields["error"] = result.ErrorMessage
+	}
+	logger.WithFields(fields).Info("Probe completed")
 }
 
 // Stop gracefully stops the scheduler
@@ -254,6 +259,7 @@ func (s *ProbeScheduler) Stop() {
 		return
 	}
 	s.running = false
+	s.probeCancel()
 	s.mu.Unlock()
 
 	logger.WithField("component", "probe").Info("Stopping probe scheduler...")
@@ -273,39 +279,47 @@ func (s *ProbeScheduler) IsRunning() bool {
 func (s *ProbeScheduler) GetProbeCount() int {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return len(s.tcpPingers) + len(s.udpPingers)
+	return len(s.probes)
 }
 
-// ExecuteProbeNow executes a specific probe immediately (for testing or manual trigger)
-func (s *ProbeScheduler) ExecuteProbeNow(index int) (*models.TCPProbeResult, error) {
+// ExecuteProbeNow executes one batch of a specific probe immediately (for
+// testing or manual trigger), without caching the result. The batch runs
+// outside the scheduler lock, so reloads are not blocked while it runs.
+func (s *ProbeScheduler) ExecuteProbeNow(ctx context.Context, index int) (*models.ProbeResult, error) {
 	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	if index < 0 || index >= len(s.tcpPingers) {
-		return nil, fmt.Errorf("probe index %d out of range [0, %d]", index, len(s.tcpPingers)-1)
+	if index < 0 || index >= len(s.probes) {
+		count := len(s.probes)
+		s.mu.RUnlock()
+		return nil, fmt.Errorf("probe index %d out of range [0, %d]", index, count-1)
 	}
+	prober, count := s.probes[index].prober, s.probes[index].config.Count
+	s.mu.RUnlock()
 
-	pinger := s.tcpPingers[index]
-	return pinger.Execute()
+	return prober.ExecuteBatch(ctx, count)
 }
 
-// GetLatestResults returns the most recent probe results for heartbeat reporting
-func (s *ProbeScheduler) GetLatestResults() ([]*models.TCPProbeResult, []*models.UDPProbeResult) {
+// GetLatestResults returns the most recent result of each configured probe
+// for heartbeat reporting, in configuration order. Probes that have not
+// completed a run yet are omitted.
+func (s *ProbeScheduler) GetLatestResults() []*models.ProbeResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	s.resultsMu.RLock()
 	defer s.resultsMu.RUnlock()
 
-	// Return copies to avoid concurrent access issues
-	tcpCopy := make([]*models.TCPProbeResult, len(s.latestTCPResults))
-	udpCopy := make([]*models.UDPProbeResult, len(s.latestUDPResults))
-
-	copy(tcpCopy, s.latestTCPResults)
-	copy(udpCopy, s.latestUDPResults)
+	results := make([]*models.ProbeResult, 0, len(s.probes))
+	for _, probe := range s.probes {
+		if result, ok := s.latestResults[probe]; ok {
+			results = append(results, result)
+		}
+	}
 
-	return tcpCopy, udpCopy
+	return results
 }
 
-// UpdateProbeInterval dynamically updates the probe interval (for Story 3.11 resource monitoring)
-// multiplier: interval multiplier (1=normal, 2=degraded, 3=critical)
+// UpdateProbeInterval dynamically scales probe intervals (for Story 3.11 resource monitoring)
+// multiplier: interval multiplier (1=normal, 2=degraded, 3=critical), applied to each
+// probe's own configured interval. Running probe loops reschedule immediately.
 func (s *ProbeScheduler) UpdateProbeInterval(multiplier int) error {
 	if multiplier < 1 || multiplier > 10 {
 		return fmt.Errorf("invalid multiplier %d, must be between 1 and 10", multiplier)
@@ -314,106 +328,112 @@ func (s *ProbeScheduler) UpdateProbeInterval(multiplier int) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	newInterval := s.baseInterval * time.Duration(multiplier)
-	oldInterval := s.interval
-	s.interval = newInterval
+	oldMultiplier := s.multiplier
+	if multiplier == oldMultiplier {
+		return nil
+	}
+	s.multiplier = multiplier
+
+	// Wake the probe loops so pending timers use the new interval
+	close(s.intervalChanged)
+	s.intervalChanged = make(chan struct{})
 
 	logger.WithFields(map[string]interface{}{
-		"component":       "probe",
-		"multiplier":      multiplier,
-		"old_interval":    oldInterval.String(),
-		"new_interval":    newInterval.String(),
+		"component":      "probe",
+		"multiplier":     multiplier,
+		"old_multiplier": oldMultiplier,
 	}).Info("Probe interval updated due to resource degradation")
 
-	// Restart the ticker with new interval by stopping and starting goroutine
-	// This is handled by recreating the ticker in the next cycle
-
 	return nil
 }
 
-// GetInterval returns the current probe interval (for testing)
-func (s *ProbeScheduler) GetInterval() time.Duration {
+// GetIntervalMultiplier returns the current degradation multiplier (for testing)
+func (s *ProbeScheduler) GetIntervalMultiplier() int {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return s.interval
+	return s.multiplier
 }
 
-// ReloadConfig reloads probe configuration (for Story 3.13 config hot reload)
-// This recreates the probe scheduler with new configuration without stopping
-func (s *ProbeScheduler) ReloadConfig(probeConfigs []config.ProbeConfig) error {
+// ReloadConfig applies a new probe configuration (for Story 3.13 config hot
+// reload) without stopping the scheduler. Old and new probes are matched by
+// probe ID: unchanged probes keep running with their cached results, changed
+// probes are restarted, new probes are started and removed probes stopped,
+// aborting their in-flight batches. An invalid configuration leaves the
+// running probes untouched.
+func (s *ProbeScheduler) ReloadConfig(probeConfigs []config.ProbeConfig) (ReloadReport, error) {
+	// Create new probes from the updated config
+	probes, err := newProbes(probeConfigs)
+	if err != nil {
+		return ReloadReport{}, err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Create new pingers from the updated config
-	newTCPingers := make([]*TCPPinger, 0)
-	newUDPPingers := make([]*UDPPinger, 0)
-
-	for _, cfg := range probeConfigs {
-		if cfg.Type == "tcp_ping" {
-			tcpConfig := TCPProbeConfig{
-				Type:           cfg.Type,
-				Target:         cfg.Target,
-				Port:           cfg.Port,
-				TimeoutSeconds: cfg.TimeoutSeconds,
-				Interval:       cfg.Interval,
-				Count:          cfg.Count,
-			}
-
-			if err := tcpConfig.Validate(); err != nil {
-				return fmt.Errorf("invalid probe config for %s:%d: %w", cfg.Target, cfg.Port, err)
-			}
-
-			if cfg.Count < 10 {
-				return fmt.Errorf("probe count for %s must be ≥ 10 to calculate core metrics (current: %d)", cfg.Target, cfg.Count)
-			}
-
-			pinger := NewTCPPinger(tcpConfig)
-			newTCPingers = append(newTCPingers, pinger)
-		} else if cfg.Type == "udp_ping" {
-			udpConfig := UDPProbeConfig{
-				Type:           cfg.Type,
-				Target:         cfg.Target,
-				Port:           cfg.Port,
-				TimeoutSeconds: cfg.TimeoutSeconds,
-				Interval:       cfg.Interval,
-				Count:          cfg.Count,
-			}
-
-			if err := udpConfig.Validate(); err != nil {
-				return fmt.Errorf("invalid probe config for %s:%d: %w", cfg.Target, cfg.Port, err)
-			}
+	// Old probes by ID, in configuration order; a configuration may hold the
+	// same ID more than once, those are matched in order
+	current := make(map[string][]*scheduledProbe, len(s.probes))
+	for _, p := range s.probes {
+		id := p.prober.ProbeID()
+		current[id] = append(current[id], p)
+	}
 
-			if cfg.Count < 10 {
-				return fmt.Errorf("probe count for %s must be ≥ 10 to calculate core metrics (current: %d)", cfg.Target, cfg.Count)
-			}
+	var report ReloadReport
+	matched := make(map[*scheduledProbe]bool, len(s.probes))
+	kept := make(map[*scheduledProbe]bool, len(s.probes))
+	for i, p := range probes {
+		id := p.prober.ProbeID()
+		candidates := current[id]
+		if len(candidates) == 0 {
+			report.Added = append(report.Added, id)
+			continue
+		}
+		old := candidates[0]
+		current[id] = candidates[1:]
+		matched[old] = true
+
+		if reflect.DeepEqual(old.config, p.config) {
+			probes[i] = old
+			kept[old] = true
+			report.Unchanged++
+			continue
+		}
+		report.Changed = append(report.Changed, id)
+	}
 
-			pinger := NewUDPPinger(udpConfig)
-			newUDPPingers = append(newUDPPingers, pinger)
+	// Stop removed and changed probes and drop their cached results
+	s.resultsMu.Lock()
+	for _, old := range s.probes {
+		if kept[old] {
+			continue
+		}
+		old.stopProbeLoop()
+		delete(s.latestResults, old)
+		if !matched[old] {
+			report.Removed = append(report.Removed, old.prober.ProbeID())
 		}
 	}
+	s.resultsMu.Unlock()
+
+	s.probes = probes
 
-	// Atomically replace the pingers
-	s.tcpPingers = newTCPingers
-	s.udpPingers = newUDPPingers
-
-	// Update base interval if probes exist
-	totalProbes := len(s.tcpPingers) + len(s.udpPingers)
-	if totalProbes > 0 {
-		if len(s.tcpPingers) > 0 {
-			s.baseInterval = time.Duration(s.tcpPingers[0].config.Interval) * time.Second
-		} else if len(s.udpPingers) > 0 {
-			s.baseInterval = time.Duration(s.udpPingers[0].config.Interval) * time.Second
+	// Also covers a scheduler started without probes (e.g. all probes come from Pulse sync)
+	if s.running {
+		for _, p := range probes {
+			if !kept[p] {
+				s.startProbeLoopLocked(p)
+			}
 		}
-		// Reset current interval to base (will be adjusted by resource monitor if needed)
-		s.interval = s.baseInterval
 	}
 
 	logger.WithFields(map[string]interface{}{
-		"component":  "probe",
-		"tcp_count":  len(s.tcpPingers),
-		"udp_count":  len(s.udpPingers),
-		"interval":   s.interval.String(),
+		"component":     "probe",
+		"probe_count":   len(s.probes),
+		"multiplier":    s.multiplier,
+		"added_count":   len(report.Added),
+		"removed_count": len(report.Removed),
+		"changed_count": len(report.Changed),
 	}).Info("Probe configuration reloaded")
 
-	return nil
+	return report, nil
 }
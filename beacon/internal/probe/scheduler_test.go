@@ -0,0 +1,661 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"beacon/internal/config"
+	"beacon/internal/logger"
+	"beacon/internal/models"
+	"beacon/internal/monitor"
+)
+
+// initSchedulerTestLogger initializes the global logger used by the scheduler
+func initSchedulerTestLogger(t *testing.T) {
+	if err := logger.InitLogger(&config.Config{
+		LogLevel:      "INFO",
+		LogFile:       filepath.Join(t.TempDir(), "probe.log"),
+		LogMaxSize:    10,
+		LogMaxAge:     7,
+		LogMaxBackups: 3,
+	}); err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+}
+
+func testProbeConfigs() []config.ProbeConfig {
+	return []config.ProbeConfig{
+		{Type: "tcp_ping", Target: "127.0.0.1", Port: 18901, TimeoutSeconds: 1, Interval: 60, Count: 10},
+		{Type: "tcp_ping", Target: "127.0.0.1", Port: 18902, TimeoutSeconds: 1, Interval: 120, Count: 10},
+		{Type: "udp_ping", Target: "127.0.0.1", Port: 18903, TimeoutSeconds: 1, Interval: 300, Count: 10},
+	}
+}
+
+// TestProbeSchedulerStart_PerProbeIntervals tests that each probe loop is
+// scheduled with its own configured interval
+func TestProbeSchedulerStart_PerProbeIntervals(t *testing.T) {
+	initSchedulerTestLogger(t)
+
+	// Arrange - record intervals and keep probes from running
+	scheduler, err := NewProbeScheduler(testProbeConfigs())
+	if err != nil {
+		t.Fatalf("NewProbeScheduler failed: %v", err)
+	}
+	var mu sync.Mutex
+	var intervals []time.Duration
+	scheduler.startOffset = func(interval time.Duration) time.Duration {
+		mu.Lock()
+		defer mu.Unlock()
+		intervals = append(intervals, interval)
+		return time.Hour
+	}
+
+	// Act
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	scheduler.Stop()
+
+	// Assert
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i] < intervals[j] })
+	want := []time.Duration{60 * time.Second, 120 * time.Second, 300 * time.Second}
+	if len(intervals) != len(want) {
+		t.Fatalf("Expected %d probe loops, got %d", len(want), len(intervals))
+	}
+	for i := range want {
+		if intervals[i] != want[i] {
+			t.Errorf("Expected intervals %v, got %v", want, intervals)
+			break
+		}
+	}
+}
+
+// TestRunProbeLoop_IndependentCadence tests that probes with different
+// intervals execute at their own cadence
+func TestRunProbeLoop_IndependentCadence(t *testing.T) {
+	initSchedulerTestLogger(t)
+
+	// Arrange
+	scheduler, _ := NewProbeScheduler(nil)
+	scheduler.startOffset = func(time.Duration) time.Duration { return 0 }
+	stop := make(chan struct{})
+	var fast, slow int32
+
+	// Act
+	scheduler.wg.Add(2)
+	go scheduler.runProbeLoop(20*time.Millisecond, func() { atomic.AddInt32(&fast, 1) }, stop)
+	go scheduler.runProbeLoop(100*time.Millisecond, func() { atomic.AddInt32(&slow, 1) }, stop)
+	time.Sleep(450 * time.Millisecond)
+	close(stop)
+	scheduler.wg.Wait()
+
+	// Assert - about 23 vs 5 executions
+	fastCount, slowCount := atomic.LoadInt32(&fast), atomic.LoadInt32(&slow)
+	if slowCount < 3 || slowCount > 6 {
+		t.Errorf("Expected about 5 executions of the 100ms probe, got %d", slowCount)
+	}
+	if fastCount < 3*slowCount {
+		t.Errorf("Expected the 20ms probe to run much more often: fast=%d slow=%d", fastCount, slowCount)
+	}
+}
+
+// TestScaledInterval tests that the degradation multiplier scales each probe's own interval
+func TestScaledInterval(t *testing.T) {
+	initSchedulerTestLogger(t)
+	scheduler, _ := NewProbeScheduler(nil)
+
+	if err := scheduler.UpdateProbeInterval(3); err != nil {
+		t.Fatalf("UpdateProbeInterval failed: %v", err)
+	}
+
+	if got := scheduler.scaledInterval(60 * time.Second); got != 180*time.Second {
+		t.Errorf("Expected 180s for a 60s probe, got %s", got)
+	}
+	if got := scheduler.scaledInterval(120 * time.Second); got != 360*time.Second {
+		t.Errorf("Expected 360s for a 120s probe, got %s", got)
+	}
+	if err := scheduler.UpdateProbeInterval(11); err == nil {
+		t.Error("Expected error for multiplier out of range")
+	}
+}
+
+// executionRecorder records probe execution times
+type executionRecorder struct {
+	mu    sync.Mutex
+	times []time.Time
+}
+
+func (r *executionRecorder) execute() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.times = append(r.times, time.Now())
+}
+
+func (r *executionRecorder) snapshot() []time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]time.Time(nil), r.times...)
+}
+
+// TestRunProbeLoop_MultiplierReschedulesPendingRun tests that a multiplier
+// change applies to the already scheduled execution, not only the one after it
+func TestRunProbeLoop_MultiplierReschedulesPendingRun(t *testing.T) {
+	initSchedulerTestLogger(t)
+
+	// Arrange - first run immediately, next one due after 100ms
+	scheduler, _ := NewProbeScheduler(nil)
+	scheduler.startOffset = func(time.Duration) time.Duration { return 0 }
+	stop := make(chan struct{})
+	defer func() {
+		close(stop)
+		scheduler.wg.Wait()
+	}()
+	recorder := &executionRecorder{}
+	scheduler.wg.Add(1)
+	go scheduler.runProbeLoop(100*time.Millisecond, recorder.execute, stop)
+	time.Sleep(20 * time.Millisecond)
+
+	// Act - slow down before the pending run fires
+	if err := scheduler.UpdateProbeInterval(3); err != nil {
+		t.Fatalf("UpdateProbeInterval failed: %v", err)
+	}
+	time.Sleep(180 * time.Millisecond)
+
+	// Assert - the run due at 100ms was pushed to 300ms
+	if got := len(recorder.snapshot()); got != 1 {
+		t.Fatalf("Expected 1 execution 200ms after slowing down, got %d", got)
+	}
+
+	// Act - recover; the next run is already overdue and fires right away
+	if err := scheduler.UpdateProbeInterval(1); err != nil {
+		t.Fatalf("UpdateProbeInterval failed: %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	// Assert
+	if got := len(recorder.snapshot()); got != 2 {
+		t.Errorf("Expected an immediate execution after recovery, got %d executions", got)
+	}
+}
+
+// TestProbeCadenceSlowsUnderCriticalDegradation tests that the resource
+// monitor entering DegradationLevelCritical slows a running probe loop
+func TestProbeCadenceSlowsUnderCriticalDegradation(t *testing.T) {
+	initSchedulerTestLogger(t)
+
+	// Arrange - memory thresholds low enough that the first check is critical
+	scheduler, _ := NewProbeScheduler(nil)
+	scheduler.startOffset = func(time.Duration) time.Duration { return 0 }
+	monitorCfg := &monitor.ResourceMonitorConfig{
+		Enabled:              true,
+		CheckIntervalSeconds: 1,
+		Thresholds:           monitor.ThresholdsConfig{CPUMicrocores: 100000, MemoryMB: 1},
+		Degradation: monitor.DegradationConfig{
+			DegradedLevel: monitor.DegradationLevelConfig{CPUMicrocores: 100000, MemoryMB: 2, IntervalMultiplier: 2},
+			CriticalLevel: monitor.DegradationLevelConfig{CPUMicrocores: 100000, MemoryMB: 3, IntervalMultiplier: 3},
+			Recovery:      monitor.RecoveryConfig{ConsecutiveNormalChecks: 3},
+		},
+		Alerting: monitor.AlertingConfig{SuppressionWindowSeconds: 60},
+	}
+	resourceMonitor, err := monitor.NewMonitor(monitorCfg, scheduler, &monitor.LogrusLogger{})
+	if err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+
+	stop := make(chan struct{})
+	recorder := &executionRecorder{}
+	scheduler.wg.Add(1)
+	go scheduler.runProbeLoop(100*time.Millisecond, recorder.execute, stop)
+
+	// Act
+	if err := resourceMonitor.Start(); err != nil {
+		t.Fatalf("Failed to start monitor: %v", err)
+	}
+	deadline := time.Now().Add(3 * time.Second)
+	for resourceMonitor.GetDegradationLevel() != monitor.DegradationLevelCritical {
+		if time.Now().After(deadline) {
+			t.Fatalf("Monitor did not reach critical level, got %s", resourceMonitor.GetDegradationLevel())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	criticalAt := time.Now()
+	time.Sleep(1200 * time.Millisecond)
+	resourceMonitor.Stop()
+	close(stop)
+	scheduler.wg.Wait()
+
+	// Assert - runs about every 100ms before, every 300ms after going critical
+	if got := scheduler.GetIntervalMultiplier(); got != 3 {
+		t.Errorf("Expected multiplier 3 under critical degradation, got %d", got)
+	}
+	var before, after []time.Duration
+	times := recorder.snapshot()
+	for i := 1; i < len(times); i++ {
+		gap := times[i].Sub(times[i-1])
+		if times[i-1].Before(criticalAt) {
+			before = append(before, gap)
+		} else {
+			after = append(after, gap)
+		}
+	}
+	if len(before) < 3 {
+		t.Fatalf("Expected several executions before degradation, got %d", len(before))
+	}
+	if len(after) < 2 || len(after) > 4 {
+		t.Errorf("Expected 2-4 executions in 1.2s under critical degradation, got %d", len(after))
+	}
+	for _, gap := range after {
+		if gap < 300*time.Millisecond {
+			t.Errorf("Expected at least 300ms between executions under critical degradation, got %s", gap)
+		}
+	}
+}
+
+// TestRandomStartOffset tests that start offsets stay within the jitter window
+func TestRandomStartOffset(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		if offset := randomStartOffset(5 * time.Second); offset < 0 || offset >= 5*time.Second {
+			t.Fatalf("Expected offset in [0, 5s), got %s", offset)
+		}
+		if offset := randomStartOffset(time.Hour); offset >= maxStartJitter {
+			t.Fatalf("Expected offset below %s, got %s", maxStartJitter, offset)
+		}
+	}
+	if offset := randomStartOffset(0); offset != 0 {
+		t.Errorf("Expected zero offset for zero interval, got %s", offset)
+	}
+}
+
+// TestGetLatestResults_ConfigOrder tests that results follow configuration
+// order and probes without a completed run are omitted
+func TestGetLatestResults_ConfigOrder(t *testing.T) {
+	// Arrange
+	scheduler, err := NewProbeScheduler(testProbeConfigs())
+	if err != nil {
+		t.Fatalf("NewProbeScheduler failed: %v", err)
+	}
+	scheduler.latestResults[scheduler.probes[1]] = &models.ProbeResult{Target: "second"}
+	scheduler.latestResults[scheduler.probes[0]] = &models.ProbeResult{Target: "first"}
+
+	// Act
+	results := scheduler.GetLatestResults()
+
+	// Assert - the UDP probe has not completed a run yet
+	if len(results) != 2 || results[0].Target != "first" || results[1].Target != "second" {
+		t.Errorf("Expected results in config order, got %+v", results)
+	}
+}
+
+// TestReloadConfig_Incremental tests that reload keeps unchanged probes with
+// their results, restarts changed ones, starts new ones and stops removed ones
+func TestReloadConfig_Incremental(t *testing.T) {
+	initSchedulerTestLogger(t)
+
+	// Arrange
+	scheduler, err := NewProbeScheduler(testProbeConfigs())
+	if err != nil {
+		t.Fatalf("NewProbeScheduler failed: %v", err)
+	}
+	var loops int32
+	scheduler.startOffset = func(time.Duration) time.Duration {
+		atomic.AddInt32(&loops, 1)
+		return time.Hour
+	}
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer scheduler.Stop()
+	unchanged, changed, removed := scheduler.probes[0], scheduler.probes[1], scheduler.probes[2]
+	scheduler.latestResults[unchanged] = &models.ProbeResult{Target: "kept"}
+	scheduler.latestResults[changed] = &models.ProbeResult{Target: "stale"}
+
+	configs := testProbeConfigs()[:2]
+	configs[1].Interval = 180
+	configs = append(configs, config.ProbeConfig{Type: "icmp_ping", Target: "127.0.0.1", TimeoutSeconds: 1, Interval: 60, Count: 10})
+
+	// Act
+	report, err := scheduler.ReloadConfig(configs)
+	if err != nil {
+		t.Fatalf("ReloadConfig failed: %v", err)
+	}
+
+	// Assert - the report lists probes by identity
+	if len(report.Added) != 1 || report.Added[0] != "icmp_ping:127.0.0.1" {
+		t.Errorf("Unexpected added probes: %v", report.Added)
+	}
+	if len(report.Changed) != 1 || report.Changed[0] != "tcp_ping:127.0.0.1:18902" {
+		t.Errorf("Unexpected changed probes: %v", report.Changed)
+	}
+	if len(report.Removed) != 1 || report.Removed[0] != "udp_ping:127.0.0.1:18903" {
+		t.Errorf("Unexpected removed probes: %v", report.Removed)
+	}
+	if report.Unchanged != 1 || !report.HasChanges() {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+
+	// Unchanged probe keeps its loop and result; the others are stopped
+	if scheduler.probes[0] != unchanged {
+		t.Error("Expected the unchanged probe to be kept")
+	}
+	select {
+	case <-unchanged.done:
+		t.Error("Expected the unchanged probe loop to keep running")
+	default:
+	}
+	for name, p := range map[string]*scheduledProbe{"changed": changed, "removed": removed} {
+		select {
+		case <-p.done:
+		default:
+			t.Errorf("Expected the %s probe loop to be stopped", name)
+		}
+	}
+	if results := scheduler.GetLatestResults(); len(results) != 1 || results[0].Target != "kept" {
+		t.Errorf("Expected only the unchanged probe's result, got %+v", results)
+	}
+	if scheduler.probes[1].config.Interval != 180 {
+		t.Errorf("Expected the changed probe to use the new interval, got %d", scheduler.probes[1].config.Interval)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := atomic.LoadInt32(&loops); got != 5 {
+		t.Errorf("Expected 3 initial + 2 restarted/started probe loops, got %d", got)
+	}
+}
+
+// TestReloadConfig_NoProbeChanges tests that reloading the same probes
+// restarts nothing and reports no changes
+func TestReloadConfig_NoProbeChanges(t *testing.T) {
+	initSchedulerTestLogger(t)
+
+	// Arrange
+	scheduler, err := NewProbeScheduler(testProbeConfigs())
+	if err != nil {
+		t.Fatalf("NewProbeScheduler failed: %v", err)
+	}
+	scheduler.startOffset = func(time.Duration) time.Duration { return time.Hour }
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer scheduler.Stop()
+	scheduler.latestResults[scheduler.probes[2]] = &models.ProbeResult{Target: "kept"}
+
+	// Act
+	report, err := scheduler.ReloadConfig(testProbeConfigs())
+	if err != nil {
+		t.Fatalf("ReloadConfig failed: %v", err)
+	}
+
+	// Assert
+	if report.HasChanges() || report.Unchanged != 3 {
+		t.Errorf("Expected no changes, got %+v", report)
+	}
+	if results := scheduler.GetLatestResults(); len(results) != 1 {
+		t.Errorf("Expected cached results kept, got %d", len(results))
+	}
+}
+
+// TestReloadConfig_ExplicitID tests that a probe with an explicit id keeps
+// its identity when its target changes
+func TestReloadConfig_ExplicitID(t *testing.T) {
+	initSchedulerTestLogger(t)
+
+	// Arrange
+	configs := []config.ProbeConfig{{ID: "edge-1", Type: "tcp_ping", Target: "127.0.0.1", Port: 18901, TimeoutSeconds: 1, Interval: 60, Count: 10}}
+	scheduler, err := NewProbeScheduler(configs)
+	if err != nil {
+		t.Fatalf("NewProbeScheduler failed: %v", err)
+	}
+	configs[0].Target = "127.0.0.2"
+
+	// Act
+	report, err := scheduler.ReloadConfig(configs)
+	if err != nil {
+		t.Fatalf("ReloadConfig failed: %v", err)
+	}
+
+	// Assert
+	if len(report.Changed) != 1 || report.Changed[0] != "edge-1" || len(report.Added) != 0 || len(report.Removed) != 0 {
+		t.Errorf("Expected edge-1 reported as changed, got %+v", report)
+	}
+}
+
+// TestReloadConfig_InvalidKeepsProbes tests that a rejected configuration
+// leaves the running probes untouched
+func TestReloadConfig_InvalidKeepsProbes(t *testing.T) {
+	initSchedulerTestLogger(t)
+
+	scheduler, err := NewProbeScheduler(testProbeConfigs())
+	if err != nil {
+		t.Fatalf("NewProbeScheduler failed: %v", err)
+	}
+	probes := scheduler.probes
+
+	invalid := testProbeConfigs()
+	invalid[0].Count = 5
+	if _, err := scheduler.ReloadConfig(invalid); err == nil {
+		t.Fatal("Expected error for invalid configuration")
+	}
+
+	if len(scheduler.probes) != 3 || scheduler.probes[0] != probes[0] {
+		t.Error("Expected probes unchanged after a rejected reload")
+	}
+}
+
+// blockingProber runs a batch until its context is cancelled
+type blockingProber struct {
+	started chan struct{}
+}
+
+func (p *blockingProber) ExecuteBatch(ctx context.Context, count int) (*models.ProbeResult, error) {
+	close(p.started)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (p *blockingProber) ProbeID() string {
+	return "blocking"
+}
+
+// TestExecuteProbeNow_OutsideLock tests that a manual batch runs with the
+// caller's context and does not block reloads while it runs
+func TestExecuteProbeNow_OutsideLock(t *testing.T) {
+	initSchedulerTestLogger(t)
+
+	// Arrange
+	scheduler, err := NewProbeScheduler(testProbeConfigs())
+	if err != nil {
+		t.Fatalf("NewProbeScheduler failed: %v", err)
+	}
+	prober := &blockingProber{started: make(chan struct{})}
+	scheduler.probes[0].prober = prober
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errs := make(chan error, 1)
+	go func() {
+		_, err := scheduler.ExecuteProbeNow(ctx, 0)
+		errs <- err
+	}()
+	<-prober.started
+
+	// Act - reload while the batch runs
+	reloaded := make(chan error, 1)
+	go func() {
+		_, err := scheduler.ReloadConfig(testProbeConfigs()[1:])
+		reloaded <- err
+	}()
+
+	// Assert
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Fatalf("ReloadConfig failed: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected reload not to wait for the manual batch")
+	}
+
+	cancel()
+	select {
+	case err := <-errs:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the manual batch to stop with its context")
+	}
+}
+
+// TestGetLatestResults_ICMPProbe tests that ICMP probes report generic results
+func TestGetLatestResults_ICMPProbe(t *testing.T) {
+	// Arrange
+	scheduler, err := NewProbeScheduler([]config.ProbeConfig{
+		{Type: "icmp_ping", Target: "127.0.0.1", TimeoutSeconds: 1, Interval: 60, Count: 10},
+		{Type: "icmp_ping", Target: "127.0.0.2", TimeoutSeconds: 1, Interval: 60, Count: 10},
+	})
+	if err != nil {
+		t.Fatalf("NewProbeScheduler failed: %v", err)
+	}
+	scheduler.latestResults[scheduler.probes[1]] = &models.ProbeResult{Type: "icmp_ping", Target: "127.0.0.2"}
+
+	// Act
+	results := scheduler.GetLatestResults()
+
+	// Assert
+	if scheduler.GetProbeCount() != 2 {
+		t.Errorf("Expected 2 probes, got %d", scheduler.GetProbeCount())
+	}
+	if len(results) != 1 || results[0].Target != "127.0.0.2" {
+		t.Errorf("Expected only the ICMP probe with a result, got %+v", results)
+	}
+}
+
+// TestExecuteProbe_HTTPProbe tests that http_probe results reach GetLatestResults
+func TestExecuteProbe_HTTPProbe(t *testing.T) {
+	initSchedulerTestLogger(t)
+
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	scheduler, err := NewProbeScheduler([]config.ProbeConfig{
+		{Type: "http_probe", Target: server.URL, TimeoutSeconds: 1, Interval: 60, Count: 10, Options: map[string]interface{}{"body_contains": "ok"}},
+	})
+	if err != nil {
+		t.Fatalf("NewProbeScheduler failed: %v", err)
+	}
+
+	// Act
+	scheduler.executeProbe(context.Background(), scheduler.probes[0])
+	results := scheduler.GetLatestResults()
+
+	// Assert
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if !results[0].Success || results[0].Type != "http_probe" {
+		t.Errorf("Unexpected result: %+v", results[0])
+	}
+	if status := results[0].MetricFloat(models.MetricStatusCode); status != 200 {
+		t.Errorf("Expected status 200, got %.0f", status)
+	}
+}
+
+// TestNewProbeScheduler_PathProbe tests that path_probe configs are wired to a PathProber
+func TestNewProbeScheduler_PathProbe(t *testing.T) {
+	initSchedulerTestLogger(t)
+
+	scheduler, err := NewProbeScheduler([]config.ProbeConfig{
+		{Type: "path_probe", Target: "127.0.0.1", TimeoutSeconds: 1, Interval: 60, Count: 10, Options: map[string]interface{}{"protocol": "udp", "max_hops": 10}},
+	})
+	if err != nil {
+		t.Fatalf("NewProbeScheduler failed: %v", err)
+	}
+
+	if len(scheduler.probes) != 1 {
+		t.Fatalf("Expected 1 probe, got %d", len(scheduler.probes))
+	}
+	prober, ok := scheduler.probes[0].prober.(*PathProber)
+	if !ok {
+		t.Fatalf("Expected *PathProber, got %T", scheduler.probes[0].prober)
+	}
+	if prober.config.Protocol != "udp" || prober.config.maxHops() != 10 {
+		t.Errorf("Unexpected prober config: %+v", prober.config)
+	}
+}
+
+// TestProbeSchedulerStop_AbortsInFlightBatch tests that Stop does not wait
+// out count x timeout of a running batch
+func TestProbeSchedulerStop_AbortsInFlightBatch(t *testing.T) {
+	initSchedulerTestLogger(t)
+
+	// Arrange - a udp_ping batch that would block 10 x 5s on a silent peer
+	conn := startSilentUDPServer(t, 0)
+	scheduler, err := NewProbeScheduler([]config.ProbeConfig{
+		{Type: "udp_ping", Target: "127.0.0.1", Port: conn.LocalAddr().(*net.UDPAddr).Port, TimeoutSeconds: 5, Interval: 60, Count: 10},
+	})
+	if err != nil {
+		t.Fatalf("NewProbeScheduler failed: %v", err)
+	}
+	scheduler.startOffset = func(time.Duration) time.Duration { return 0 }
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	// Act
+	start := time.Now()
+	scheduler.Stop()
+	elapsed := time.Since(start)
+
+	// Assert
+	if elapsed > 2*time.Second {
+		t.Errorf("Expected Stop to abort the in-flight batch, took %v", elapsed)
+	}
+	if results := scheduler.GetLatestResults(); len(results) != 0 {
+		t.Errorf("Expected no result from the aborted batch, got %+v", results)
+	}
+}
+
+// TestExecuteProbe_RunDeadline tests that a batch outlasting the probe
+// interval is cut off and its completed attempts cached as partial
+func TestExecuteProbe_RunDeadline(t *testing.T) {
+	initSchedulerTestLogger(t)
+
+	// Arrange - 2 replies, then 5s timeouts against a 1s interval
+	conn := startSilentUDPServer(t, 2)
+	scheduler, err := NewProbeScheduler([]config.ProbeConfig{
+		{Type: "udp_ping", Target: "127.0.0.1", Port: conn.LocalAddr().(*net.UDPAddr).Port, TimeoutSeconds: 5, Interval: 60, Count: 10},
+	})
+	if err != nil {
+		t.Fatalf("NewProbeScheduler failed: %v", err)
+	}
+	scheduler.probes[0].config.Interval = 1 // Below the configurable minimum to keep the test short
+
+	// Act
+	start := time.Now()
+	scheduler.executeProbe(context.Background(), scheduler.probes[0])
+	elapsed := time.Since(start)
+
+	// Assert
+	if elapsed > 2*time.Second {
+		t.Errorf("Expected the run to end at the 1s interval, took %v", elapsed)
+	}
+	results := scheduler.GetLatestResults()
+	if len(results) != 1 || !results[0].Partial {
+		t.Fatalf("Expected a cached partial result, got %+v", results)
+	}
+	if sent := results[0].MetricFloat(models.MetricSentPackets); sent != 2 {
+		t.Errorf("Expected 2 completed attempts, got %.0f", sent)
+	}
+}
@@ -1,11 +1,14 @@
 package probe
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"net"
+	"strings"
 	"time"
 
+	"beacon/internal/config"
 	"beacon/internal/models"
 )
 
@@ -16,6 +19,7 @@ const (
 
 // TCPProbeConfig represents TCP probe configuration
 type TCPProbeConfig struct {
+	ID             string `yaml:"id"`
 	Type           string `yaml:"type" validate:"required,eq=tcp_ping"`
 	Target         string `yaml:"target" validate:"required,ip|hostname"`
 	Port           int    `yaml:"port" validate:"required,min=1,max=65535"`
@@ -24,6 +28,39 @@ type TCPProbeConfig struct {
 	Count          int    `yaml:"count" validate:"required,min=1,max=100"`
 }
 
+func init() {
+	RegisterProbeType("tcp_ping", func(cfg config.ProbeConfig) (ProbeSpec, error) {
+		return &tcpProbeSpec{TCPProbeConfig{
+			ID:             cfg.ID,
+			Type:           cfg.Type,
+			Target:         cfg.Target,
+			Port:           cfg.Port,
+			TimeoutSeconds: cfg.TimeoutSeconds,
+			Interval:       cfg.Interval,
+			Count:          cfg.Count,
+		}}, nil
+	}, "port")
+}
+
+// tcpProbeSpec is a scheduled tcp_ping entry; its batches must hold enough
+// samples for the loss, jitter and percentile metrics
+type tcpProbeSpec struct {
+	TCPProbeConfig
+}
+
+// Validate validates the configuration and the batch size of the entry
+func (s *tcpProbeSpec) Validate() error {
+	if err := s.TCPProbeConfig.Validate(); err != nil {
+		return err
+	}
+	return validateCoreMetricsCount(s.Count)
+}
+
+// NewProber creates the scheduler's probe engine for the configuration
+func (c *TCPProbeConfig) NewProber() Prober {
+	return &tcpProber{NewTCPPinger(*c)}
+}
+
 // Validate validates the TCP probe configuration
 func (c *TCPProbeConfig) Validate() error {
 	if c.Type != "tcp_ping" {
@@ -62,14 +99,39 @@ func (c *TCPProbeConfig) Validate() error {
 	return nil
 }
 
-// validateHostname validates hostname format (basic validation)
+// validateHostname validates hostname format
 func validateHostname(hostname string) error {
-	if len(hostname) == 0 || len(hostname) > 253 {
-		return fmt.Errorf("invalid hostname length")
+	if len(hostname) == 0 {
+		return fmt.Errorf("hostname cannot be empty")
 	}
+	if len(hostname) > 253 {
+		return fmt.Errorf("hostname too long (max 253 characters)")
+	}
+
+	// Check for invalid characters
+	for _, char := range hostname {
+		if !isValidHostnameChar(char) {
+			return fmt.Errorf("hostname contains invalid character '%c' (suggestion: use only letters, numbers, hyphens, underscores, and dots)", char)
+		}
+	}
+
+	// Check that hostname doesn't start or end with hyphen/dot/underscore
+	if strings.HasPrefix(hostname, "-") || strings.HasPrefix(hostname, ".") || strings.HasPrefix(hostname, "_") ||
+		strings.HasSuffix(hostname, "-") || strings.HasSuffix(hostname, ".") || strings.HasSuffix(hostname, "_") {
+		return fmt.Errorf("hostname cannot start or end with hyphen, dot, or underscore")
+	}
+
 	return nil
 }
 
+// isValidHostnameChar checks if a character is valid in hostname
+func isValidHostnameChar(r rune) bool {
+	return (r >= 'a' && r <= 'z') ||
+		(r >= 'A' && r <= 'Z') ||
+		(r >= '0' && r <= '9') ||
+		r == '-' || r == '.' || r == '_'
+}
+
 // TCPPinger represents a TCP ping probe engine
 type TCPPinger struct {
 	config TCPProbeConfig
@@ -119,8 +181,9 @@ func (p *TCPPinger) Execute() (*models.TCPProbeResult, error) {
 	return models.NewTCPProbeResult(true, rttMs, ""), nil
 }
 
-// ExecuteBatch performs multiple TCP probes and calculates core metrics
-func (p *TCPPinger) ExecuteBatch(count int) (*models.TCPProbeResult, error) {
+// ExecuteBatch performs multiple TCP probes and calculates core metrics.
+// The batch stops early when ctx is done, see batch.go.
+func (p *TCPPinger) ExecuteBatch(ctx context.Context, count int) (*models.TCPProbeResult, error) {
 	if count < 1 || count > 100 {
 		return nil, fmt.Errorf("invalid count %d, must be between 1 and 100", count)
 	}
@@ -129,10 +192,15 @@ func (p *TCPPinger) ExecuteBatch(count int) (*models.TCPProbeResult, error) {
 	sentPackets := 0
 	receivedPackets := 0
 	var errors []string
+	aborted := false
 
 	collector := NewCoreMetricsCollector()
 
 	for i := 0; i < count; i++ {
+		if ctx.Err() != nil {
+			aborted = true
+			break
+		}
 		sentPackets++
 		startTime := time.Now()
 
@@ -140,9 +208,10 @@ func (p *TCPPinger) ExecuteBatch(count int) (*models.TCPProbeResult, error) {
 		if err := p.config.Validate(); err != nil {
 			errors = append(errors, err.Error())
 			samples = append(samples, SamplePoint{
-				RTTMs:     0,
-				Timestamp: time.Now().Format(time.RFC3339),
-				Success:   false,
+				RTTMs:      0,
+				Timestamp:  time.Now().Format(time.RFC3339),
+				Success:    false,
+				ErrorClass: classifyError(err),
 			})
 			continue
 		}
@@ -155,18 +224,26 @@ func (p *TCPPinger) ExecuteBatch(count int) (*models.TCPProbeResult, error) {
 		if timeout == 0 {
 			timeout = 5
 		}
-		conn, err := net.DialTimeout("tcp", targetAddr, time.Duration(timeout)*time.Second)
+		dialer := net.Dialer{Timeout: time.Duration(timeout) * time.Second}
+		conn, err := dialer.DialContext(ctx, "tcp", targetAddr)
 
 		elapsed := time.Since(startTime)
 		rttMs := math.Round(elapsed.Seconds()*1000*rttPrecisionMultiplier) / rttPrecisionMultiplier
 
+		if err != nil && ctx.Err() != nil {
+			// Aborted in flight, not a lost probe
+			sentPackets--
+			aborted = true
+			break
+		}
 		if err != nil {
 			// Connection failed
 			errors = append(errors, err.Error())
 			samples = append(samples, SamplePoint{
-				RTTMs:     0,
-				Timestamp: time.Now().Format(time.RFC3339),
-				Success:   false,
+				RTTMs:      0,
+				Timestamp:  time.Now().Format(time.RFC3339),
+				Success:    false,
+				ErrorClass: classifyError(err),
 			})
 			continue
 		}
@@ -182,6 +259,10 @@ func (p *TCPPinger) ExecuteBatch(count int) (*models.TCPProbeResult, error) {
 		})
 	}
 
+	if aborted && len(samples) == 0 {
+		return nil, batchAbortedError(ctx)
+	}
+
 	// Calculate core metrics
 	metrics := collector.CalculateFromSamples(samples, sentPackets, receivedPackets)
 
@@ -191,7 +272,7 @@ func (p *TCPPinger) ExecuteBatch(count int) (*models.TCPProbeResult, error) {
 		errorMessage = fmt.Sprintf("probing failed: %d errors", len(errors))
 	}
 
-	return models.NewTCPProbeResultWithMetrics(
+	result := models.NewTCPProbeResultWithMetrics(
 		success,
 		metrics.RTTMs,
 		metrics.RTTMedianMs,
@@ -200,5 +281,37 @@ func (p *TCPPinger) ExecuteBatch(count int) (*models.TCPProbeResult, error) {
 		metrics.PacketLossRate,
 		metrics.SampleCount,
 		errorMessage,
-	), nil
+	)
+	result.RTTStatistics = metrics.RTTStatistics
+	result.Samples = rawSamples(samples)
+	result.Partial = aborted
+
+	// Attach probe identity so results can be reported per probe
+	result.ProbeID = p.ProbeID()
+	result.Target = p.config.Target
+	result.Port = p.config.Port
+
+	return result, nil
+}
+
+// ProbeID returns the configured probe ID, or a stable key derived from type, target and port
+func (p *TCPPinger) ProbeID() string {
+	if p.config.ID != "" {
+		return p.config.ID
+	}
+	return models.ProbeKey("tcp_ping", p.config.Target, p.config.Port)
+}
+
+// tcpProber adapts TCPPinger to the Prober interface
+type tcpProber struct {
+	*TCPPinger
+}
+
+// ExecuteBatch performs a TCP probe batch and reports it as a generic result
+func (p *tcpProber) ExecuteBatch(ctx context.Context, count int) (*models.ProbeResult, error) {
+	result, err := p.TCPPinger.ExecuteBatch(ctx, count)
+	if err != nil {
+		return nil, err
+	}
+	return result.ToGenericResult(), nil
 }
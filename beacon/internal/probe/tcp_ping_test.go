@@ -1,6 +1,7 @@
 package probe
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"strings"
@@ -215,7 +216,7 @@ func TestTCPPinger_ExecuteBatch(t *testing.T) {
 
 	pinger := NewTCPPinger(config)
 	count := 10
-	result, err := pinger.ExecuteBatch(count)
+	result, err := pinger.ExecuteBatch(context.Background(), count)
 
 	if err != nil {
 		t.Fatalf("ExecuteBatch() failed: %v", err)
@@ -239,6 +240,86 @@ func TestTCPPinger_ExecuteBatch(t *testing.T) {
 	}
 }
 
+// TestTCPPinger_ExecuteBatchProbeIdentity tests that batch results carry the probe identity
+func TestTCPPinger_ExecuteBatchProbeIdentity(t *testing.T) {
+	server := startTestTCPServer(t, "localhost:18891")
+	defer server.Close()
+
+	config := TCPProbeConfig{
+		Type:           "tcp_ping",
+		Target:         "localhost",
+		Port:           18891,
+		TimeoutSeconds: 5,
+		Interval:       60,
+		Count:          1,
+	}
+
+	// Without an explicit ID the identity is derived from type, target and port
+	result, err := NewTCPPinger(config).ExecuteBatch(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("ExecuteBatch() failed: %v", err)
+	}
+	if result.ProbeID != "tcp_ping:localhost:18891" {
+		t.Errorf("Expected ProbeID 'tcp_ping:localhost:18891', got %s", result.ProbeID)
+	}
+	if result.Target != "localhost" || result.Port != 18891 {
+		t.Errorf("Expected target localhost:18891, got %s:%d", result.Target, result.Port)
+	}
+
+	// An explicit ID takes precedence
+	config.ID = "3b8f6c2a-1111-4222-8333-444455556666"
+	result, err = NewTCPPinger(config).ExecuteBatch(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("ExecuteBatch() failed: %v", err)
+	}
+	if result.ProbeID != config.ID {
+		t.Errorf("Expected ProbeID %s, got %s", config.ID, result.ProbeID)
+	}
+}
+
+// TestTCPPinger_ExecuteBatchSamples tests that batch results carry each attempt with its error class
+func TestTCPPinger_ExecuteBatchSamples(t *testing.T) {
+	server := startTestTCPServer(t, "localhost:18892")
+	defer server.Close()
+
+	config := TCPProbeConfig{
+		Type:           "tcp_ping",
+		Target:         "localhost",
+		Port:           18892,
+		TimeoutSeconds: 5,
+		Interval:       60,
+		Count:          1,
+	}
+
+	result, err := NewTCPPinger(config).ExecuteBatch(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("ExecuteBatch() failed: %v", err)
+	}
+	if len(result.Samples) != 3 {
+		t.Fatalf("Expected 3 samples, got %d", len(result.Samples))
+	}
+	for i, sample := range result.Samples {
+		if !sample.Success || sample.RTTMs <= 0 || sample.ErrorClass != "" || sample.Timestamp == "" {
+			t.Errorf("Sample %d: expected successful sample with RTT, got %+v", i, sample)
+		}
+	}
+
+	// Refused connections are classified per attempt
+	config.Port = 19998 // Port not in use
+	result, err = NewTCPPinger(config).ExecuteBatch(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("ExecuteBatch() failed: %v", err)
+	}
+	if len(result.Samples) != 2 {
+		t.Fatalf("Expected 2 samples, got %d", len(result.Samples))
+	}
+	for i, sample := range result.Samples {
+		if sample.Success || sample.ErrorClass != "refused" {
+			t.Errorf("Sample %d: expected refused sample, got %+v", i, sample)
+		}
+	}
+}
+
 // TestTCPProbeConfig_Validate tests configuration validation
 func TestTCPProbeConfig_Validate(t *testing.T) {
 	tests := []struct {
@@ -354,6 +435,59 @@ func TestTCPProbeConfig_Validate(t *testing.T) {
 	}
 }
 
+// TestValidateHostname_Valid tests hostname validation of probe targets
+func TestValidateHostname_Valid(t *testing.T) {
+	testCases := []struct {
+		hostname string
+		valid    bool
+	}{
+		{"localhost", true},
+		{"example.com", true},
+		{"subdomain.example.com", true},
+		{"my-host-01", true},
+		{"host_name", true},
+		{"a", true}, // Single character
+	}
+
+	for _, tc := range testCases {
+		err := validateHostname(tc.hostname)
+		if tc.valid && err != nil {
+			t.Errorf("Expected hostname '%s' to be valid, got error: %v", tc.hostname, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("Expected hostname '%s' to be invalid, got nil", tc.hostname)
+		}
+	}
+}
+
+// TestValidateHostname_Invalid tests rejected probe target hostnames
+func TestValidateHostname_Invalid(t *testing.T) {
+	testCases := []struct {
+		hostname string
+		errContains string
+	}{
+		{"-invalid", "cannot start"},
+		{".invalid", "cannot start"},
+		{"_invalid", "cannot start"},
+		{"invalid-", "end"},
+		{"invalid.", "end"},
+		{"invalid_", "end"},
+		{"invalid host", "invalid character"},
+		{"invalid@host", "invalid character"},
+		{strings.Repeat("a", 300), "too long"},
+	}
+
+	for _, tc := range testCases {
+		err := validateHostname(tc.hostname)
+		if err == nil {
+			t.Errorf("Expected hostname '%s' to be invalid, got nil", tc.hostname)
+		}
+		if err != nil && !contains(err.Error(), tc.errContains) {
+			t.Errorf("Expected error for '%s' to contain '%s', got: %v", tc.hostname, tc.errContains, err)
+		}
+	}
+}
+
 // Helper functions
 
 // startTestTCPServer starts a simple TCP server for testing
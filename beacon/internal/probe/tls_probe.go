@@ -0,0 +1,273 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"time"
+
+	"beacon/internal/config"
+	"beacon/internal/models"
+)
+
+// TLSProbeConfig represents TLS handshake probe configuration
+type TLSProbeConfig struct {
+	ID             string `yaml:"id"`
+	Type           string `yaml:"type" validate:"required,eq=tls_probe"`
+	Target         string `yaml:"target" validate:"required,ip|hostname"`
+	Port           int    `yaml:"port" validate:"required,min=1,max=65535"`
+	ServerName     string `yaml:"server_name"` // "" = target
+	TimeoutSeconds int    `yaml:"timeout_seconds" validate:"required,min=1,max=30"`
+	Interval       int    `yaml:"interval" validate:"required,min=60,max=300"`
+	Count          int    `yaml:"count" validate:"required,min=1,max=100"`
+}
+
+func init() {
+	RegisterProbeType("tls_probe", func(cfg config.ProbeConfig) (ProbeSpec, error) {
+		spec := &TLSProbeConfig{
+			ID:             cfg.ID,
+			Type:           cfg.Type,
+			Target:         cfg.Target,
+			Port:           cfg.Port,
+			TimeoutSeconds: cfg.TimeoutSeconds,
+			Interval:       cfg.Interval,
+			Count:          cfg.Count,
+		}
+		return spec, DecodeOptions(cfg, spec)
+	}, "port", "server_name")
+
+	RegisterResultMetrics("tls_probe",
+		ResultMetric{Key: models.MetricTLSVersion},
+		ResultMetric{Key: models.MetricCipherSuite, Field: "tls_cipher"},
+		ResultMetric{Key: models.MetricChainValid, Field: "cert_chain_valid", Gauge: &ResultGauge{
+			Name: "beacon_tls_cert_chain_valid",
+			Help: "Whether the certificate chain seen by a tls_probe verifies (1=valid, 0=invalid)",
+		}},
+		ResultMetric{Key: models.MetricCertNotAfter},
+		ResultMetric{Key: models.MetricCertDaysRemaining, Gauge: &ResultGauge{
+			Name: "beacon_tls_cert_days_remaining",
+			Help: "Days until the leaf certificate seen by a tls_probe expires (negative once expired)",
+		}},
+	)
+}
+
+// NewProber creates the scheduler's probe engine for the configuration
+func (c *TLSProbeConfig) NewProber() Prober {
+	return NewTLSProber(*c)
+}
+
+// Validate validates the TLS probe configuration
+func (c *TLSProbeConfig) Validate() error {
+	if c.Type != "tls_probe" {
+		return fmt.Errorf("invalid probe type: %s (must be 'tls_probe')", c.Type)
+	}
+
+	if c.Target == "" {
+		return fmt.Errorf("probe target cannot be empty")
+	}
+
+	if net.ParseIP(c.Target) == nil {
+		if err := validateHostname(c.Target); err != nil {
+			return fmt.Errorf("invalid probe target '%s': %w", c.Target, err)
+		}
+	}
+
+	if c.Port < 1 || c.Port > 65535 {
+		return fmt.Errorf("invalid port %d, must be between 1 and 65535", c.Port)
+	}
+
+	if c.ServerName != "" {
+		if err := validateHostname(c.ServerName); err != nil {
+			return fmt.Errorf("invalid server name '%s': %w", c.ServerName, err)
+		}
+	}
+
+	if err := validateSchedule(c.Interval, c.Count, c.TimeoutSeconds); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// serverName returns the name sent as SNI and verified against the
+// certificate. IP targets are verified against IP SANs and send no SNI.
+func (c *TLSProbeConfig) serverName() string {
+	if c.ServerName != "" {
+		return c.ServerName
+	}
+	return c.Target
+}
+
+// TLSProber represents a TLS handshake probe engine
+type TLSProber struct {
+	config  TLSProbeConfig
+	rootCAs *x509.CertPool // nil = system trust store
+}
+
+// NewTLSProber creates a new TLS prober with the given configuration
+func NewTLSProber(config TLSProbeConfig) *TLSProber {
+	return &TLSProber{config: config}
+}
+
+// ExecuteBatch performs count handshakes and calculates core metrics on the
+// handshake duration (excluding the TCP connect). Version, cipher and
+// certificate details come from the last successful handshake. The chain is
+// verified separately from the handshake, so an invalid or expired
+// certificate is still measured; the result is then marked failed with the
+// verification error. The batch stops early when ctx is done, see batch.go.
+func (p *TLSProber) ExecuteBatch(ctx context.Context, count int) (*models.ProbeResult, error) {
+	if count < 1 || count > 100 {
+		return nil, fmt.Errorf("invalid count %d, must be between 1 and 100", count)
+	}
+
+	if err := p.config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	samples := make([]SamplePoint, 0, count)
+	receivedPackets := 0
+	var state *tls.ConnectionState
+	var lastErr error
+	aborted := false
+
+	for i := 0; i < count; i++ {
+		if ctx.Err() != nil {
+			aborted = true
+			break
+		}
+		handshake, connState, err := p.handshake(ctx)
+		if err != nil && ctx.Err() != nil {
+			aborted = true // Aborted in flight, not a failed handshake
+			break
+		}
+		if err != nil {
+			lastErr = err
+			samples = append(samples, SamplePoint{
+				RTTMs:      0,
+				Timestamp:  time.Now().Format(time.RFC3339),
+				Success:    false,
+				ErrorClass: classifyError(err),
+			})
+			continue
+		}
+
+		receivedPackets++
+		state = connState
+		samples = append(samples, SamplePoint{
+			RTTMs:     durationMs(handshake),
+			Timestamp: time.Now().Format(time.RFC3339),
+			Success:   true,
+		})
+	}
+
+	if aborted && len(samples) == 0 {
+		return nil, batchAbortedError(ctx)
+	}
+	sent := len(samples)
+
+	metrics := NewCoreMetricsCollector().CalculateFromSamples(samples, sent, receivedPackets)
+	values := map[string]interface{}{
+		models.MetricRTTMs:           metrics.RTTMs,
+		models.MetricRTTMedianMs:     metrics.RTTMedianMs,
+		models.MetricJitterMs:        metrics.JitterMs,
+		models.MetricVarianceMs:      metrics.RTTVarianceMs,
+		models.MetricPacketLossRate:  metrics.PacketLossRate,
+		models.MetricSampleCount:     metrics.SampleCount,
+		models.MetricSentPackets:     sent,
+		models.MetricReceivedPackets: receivedPackets,
+	}
+
+	success := receivedPackets > 0
+	errorMessage := ""
+	if state == nil {
+		if lastErr != nil {
+			errorMessage = lastErr.Error()
+		}
+	} else {
+		leaf := state.PeerCertificates[0]
+		verifyErr := p.verifyChain(state.PeerCertificates)
+
+		values[models.MetricTLSVersion] = tls.VersionName(state.Version)
+		values[models.MetricCipherSuite] = tls.CipherSuiteName(state.CipherSuite)
+		values[models.MetricChainValid] = verifyErr == nil
+		values[models.MetricCertNotAfter] = leaf.NotAfter.UTC().Format(time.RFC3339)
+		values[models.MetricCertDaysRemaining] = math.Round(time.Until(leaf.NotAfter).Hours()/24*100) / 100
+
+		if verifyErr != nil {
+			success = false
+			errorMessage = fmt.Sprintf("certificate verification failed: %v", verifyErr)
+		}
+	}
+
+	result := models.NewProbeResult("tls_probe", p.config.Target, success, values, errorMessage)
+	result.ProbeID = p.ProbeID()
+	metrics.SetMetrics(result.Metrics)
+	result.Samples = rawSamples(samples)
+	result.Port = p.config.Port
+	result.Partial = aborted
+
+	return result, nil
+}
+
+// handshake connects and performs one TLS handshake without verification,
+// returning the handshake duration and the connection state
+func (p *TLSProber) handshake(ctx context.Context) (time.Duration, *tls.ConnectionState, error) {
+	timeout := time.Duration(p.config.TimeoutSeconds) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	address := net.JoinHostPort(p.config.Target, strconv.Itoa(p.config.Port))
+	dialer := &net.Dialer{}
+	rawConn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return 0, nil, fmt.Errorf("connect failed: %w", err)
+	}
+	defer rawConn.Close()
+
+	// Verification happens in verifyChain so an invalid certificate can still
+	// be inspected and reported
+	conn := tls.Client(rawConn, &tls.Config{
+		ServerName:         p.config.serverName(),
+		InsecureSkipVerify: true,
+	})
+
+	start := time.Now()
+	if err := conn.HandshakeContext(ctx); err != nil {
+		return 0, nil, fmt.Errorf("handshake failed: %w", err)
+	}
+	elapsed := time.Since(start)
+
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return 0, nil, fmt.Errorf("handshake failed: server sent no certificate")
+	}
+	return elapsed, &state, nil
+}
+
+// verifyChain verifies the presented chain against the trust store and the
+// server name, as a regular TLS client would
+func (p *TLSProber) verifyChain(certs []*x509.Certificate) error {
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         p.rootCAs,
+		Intermediates: intermediates,
+		DNSName:       p.config.serverName(),
+	})
+	return err
+}
+
+// ProbeID returns the configured probe ID, or a stable key derived from type, target and port
+func (p *TLSProber) ProbeID() string {
+	if p.config.ID != "" {
+		return p.config.ID
+	}
+	return models.ProbeKey("tls_probe", p.config.Target, p.config.Port)
+}
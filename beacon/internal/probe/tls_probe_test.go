@@ -0,0 +1,246 @@
+package probe
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"beacon/internal/models"
+)
+
+// startTLSServer serves TLS handshakes on localhost with a self-signed
+// certificate valid for 127.0.0.1 and service.test between notBefore and
+// notAfter. It returns the port and a pool trusting the certificate.
+func startTLSServer(t *testing.T, notBefore, notAfter time.Time) (int, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "service.test"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"service.test"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to start TLS server: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return listener.Addr().(*net.TCPAddr).Port, pool
+}
+
+func newTestTLSProber(port int, roots *x509.CertPool) *TLSProber {
+	prober := NewTLSProber(TLSProbeConfig{Type: "tls_probe", Target: "127.0.0.1", Port: port, TimeoutSeconds: 2, Interval: 60, Count: 10})
+	prober.rootCAs = roots
+	return prober
+}
+
+// TestTLSProbeConfigValidation tests TLS probe configuration validation
+func TestTLSProbeConfigValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  TLSProbeConfig
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid config",
+			config:  TLSProbeConfig{Type: "tls_probe", Target: "example.com", Port: 443, TimeoutSeconds: 5, Interval: 60, Count: 10},
+			wantErr: false,
+		},
+		{
+			name:    "valid config with SNI",
+			config:  TLSProbeConfig{Type: "tls_probe", Target: "10.0.0.10", Port: 8443, ServerName: "api.example.com", TimeoutSeconds: 5, Interval: 60, Count: 10},
+			wantErr: false,
+		},
+		{
+			name:    "invalid type",
+			config:  TLSProbeConfig{Type: "tcp_ping", Target: "example.com", Port: 443, TimeoutSeconds: 5, Interval: 60, Count: 10},
+			wantErr: true,
+			errMsg:  "invalid probe type",
+		},
+		{
+			name:    "missing port",
+			config:  TLSProbeConfig{Type: "tls_probe", Target: "example.com", TimeoutSeconds: 5, Interval: 60, Count: 10},
+			wantErr: true,
+			errMsg:  "invalid port",
+		},
+		{
+			name:    "invalid timeout",
+			config:  TLSProbeConfig{Type: "tls_probe", Target: "example.com", Port: 443, TimeoutSeconds: 0, Interval: 60, Count: 10},
+			wantErr: true,
+			errMsg:  "invalid timeout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && !contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+// TestTLSProber_ValidCertificate tests handshake metrics and certificate details
+func TestTLSProber_ValidCertificate(t *testing.T) {
+	// Arrange
+	port, roots := startTLSServer(t, time.Now().Add(-time.Hour), time.Now().Add(30*24*time.Hour))
+	prober := newTestTLSProber(port, roots)
+
+	// Act
+	result, err := prober.ExecuteBatch(context.Background(), 3)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if !result.Success || result.Type != "tls_probe" || result.Port != port {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+	if rtt := result.MetricFloat(models.MetricRTTMs); rtt <= 0 {
+		t.Errorf("Expected positive handshake latency, got %.2f", rtt)
+	}
+	if version := result.Metrics[models.MetricTLSVersion]; version != "TLS 1.3" {
+		t.Errorf("Expected TLS 1.3, got %v", version)
+	}
+	if cipher, _ := result.Metrics[models.MetricCipherSuite].(string); cipher == "" {
+		t.Error("Expected negotiated cipher suite")
+	}
+	if valid := result.MetricFloat(models.MetricChainValid); valid != 1 {
+		t.Error("Expected chain to be valid")
+	}
+	if days := result.MetricFloat(models.MetricCertDaysRemaining); days < 29.9 || days > 30 {
+		t.Errorf("Expected about 30 days until expiry, got %.2f", days)
+	}
+	if _, err := time.Parse(time.RFC3339, result.Metrics[models.MetricCertNotAfter].(string)); err != nil {
+		t.Errorf("Expected RFC 3339 expiry, got %v", result.Metrics[models.MetricCertNotAfter])
+	}
+}
+
+// TestTLSProber_ExpiredCertificate tests that an expired leaf is measured but fails verification
+func TestTLSProber_ExpiredCertificate(t *testing.T) {
+	port, roots := startTLSServer(t, time.Now().Add(-48*time.Hour), time.Now().Add(-24*time.Hour))
+
+	result, err := newTestTLSProber(port, roots).ExecuteBatch(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if result.Success {
+		t.Error("Expected expired certificate to fail the probe")
+	}
+	if loss := result.MetricFloat(models.MetricPacketLossRate); loss != 0 {
+		t.Errorf("Expected the handshake itself to succeed, got %.2f%% loss", loss)
+	}
+	if days := result.MetricFloat(models.MetricCertDaysRemaining); days > -0.9 {
+		t.Errorf("Expected negative days remaining, got %.2f", days)
+	}
+	if valid := result.MetricFloat(models.MetricChainValid); valid != 0 {
+		t.Error("Expected chain to be invalid")
+	}
+	if !contains(result.ErrorMessage, "certificate verification failed") {
+		t.Errorf("Unexpected error message: %q", result.ErrorMessage)
+	}
+}
+
+// TestTLSProber_UntrustedAndMismatchedName tests chain verification failures
+func TestTLSProber_UntrustedAndMismatchedName(t *testing.T) {
+	port, roots := startTLSServer(t, time.Now().Add(-time.Hour), time.Now().Add(24*time.Hour))
+
+	tests := []struct {
+		name       string
+		roots      *x509.CertPool
+		serverName string
+		wantValid  bool
+	}{
+		{name: "untrusted root", roots: x509.NewCertPool(), wantValid: false},
+		{name: "matching SNI", roots: roots, serverName: "service.test", wantValid: true},
+		{name: "mismatched SNI", roots: roots, serverName: "other.test", wantValid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prober := newTestTLSProber(port, tt.roots)
+			prober.config.ServerName = tt.serverName
+
+			result, err := prober.ExecuteBatch(context.Background(), 1)
+			if err != nil {
+				t.Fatalf("ExecuteBatch failed: %v", err)
+			}
+			if valid := result.MetricFloat(models.MetricChainValid) == 1; valid != tt.wantValid {
+				t.Errorf("Expected chain_valid=%v, got %v (%s)", tt.wantValid, valid, result.ErrorMessage)
+			}
+			if result.Success != tt.wantValid {
+				t.Errorf("Expected success=%v, got %v", tt.wantValid, result.Success)
+			}
+		})
+	}
+}
+
+// TestTLSProber_ConnectionRefused tests a port that is not listening
+func TestTLSProber_ConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to reserve port: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	result, err := newTestTLSProber(port, nil).ExecuteBatch(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if result.Success || !contains(result.ErrorMessage, "connect failed") {
+		t.Errorf("Expected connect failure, got success=%v error=%q", result.Success, result.ErrorMessage)
+	}
+	if _, ok := result.Metrics[models.MetricCertDaysRemaining]; ok {
+		t.Error("Expected no certificate details without a handshake")
+	}
+}
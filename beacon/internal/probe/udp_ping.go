@@ -1,25 +1,74 @@
 package probe
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math"
+	"math/rand"
 	"net"
 	"strings"
 	"time"
 
+	"beacon/internal/config"
 	"beacon/internal/models"
+	"beacon/internal/responder"
 )
 
 // UDPProbeConfig represents UDP probe configuration
 type UDPProbeConfig struct {
+	ID             string `yaml:"id"`
 	Type           string `yaml:"type" validate:"required,eq=udp_ping"`
 	Target         string `yaml:"target" validate:"required,ip|hostname"`
 	Port           int    `yaml:"port" validate:"required,min=1,max=65535"`
+	Responder      bool   `yaml:"responder"`     // Target runs `beacon responder`
+	OneWayDelay    bool   `yaml:"one_way_delay"` // Report one-way delay; clocks must be synchronized
 	TimeoutSeconds int    `yaml:"timeout_seconds" validate:"required,min=1,max=30"`
 	Interval       int    `yaml:"interval" validate:"required,min=60,max=300"`
 	Count          int    `yaml:"count" validate:"required,min=1,max=100"`
 }
 
+func init() {
+	RegisterProbeType("udp_ping", func(cfg config.ProbeConfig) (ProbeSpec, error) {
+		spec := &udpProbeSpec{UDPProbeConfig{
+			ID:             cfg.ID,
+			Type:           cfg.Type,
+			Target:         cfg.Target,
+			Port:           cfg.Port,
+			TimeoutSeconds: cfg.TimeoutSeconds,
+			Interval:       cfg.Interval,
+			Count:          cfg.Count,
+		}}
+		return spec, DecodeOptions(cfg, &spec.UDPProbeConfig)
+	}, "port", "responder", "one_way_delay")
+
+	RegisterResultMetrics("udp_ping",
+		ResultMetric{Key: models.MetricReorderedPackets},
+		ResultMetric{Key: models.MetricDuplicatePackets},
+		ResultMetric{Key: models.MetricOneWayForwardMs},
+		ResultMetric{Key: models.MetricOneWayReverseMs},
+	)
+}
+
+// udpProbeSpec is a scheduled udp_ping entry; its batches must hold enough
+// samples for the loss, jitter and percentile metrics
+type udpProbeSpec struct {
+	UDPProbeConfig
+}
+
+// Validate validates the configuration and the batch size of the entry
+func (s *udpProbeSpec) Validate() error {
+	if err := s.UDPProbeConfig.Validate(); err != nil {
+		return err
+	}
+	return validateCoreMetricsCount(s.Count)
+}
+
+// NewProber creates the scheduler's probe engine for the configuration
+func (c *UDPProbeConfig) NewProber() Prober {
+	return &udpProber{NewUDPPinger(*c)}
+}
+
 // Validate validates the UDP probe configuration
 func (c *UDPProbeConfig) Validate() error {
 	if c.Type != "udp_ping" {
@@ -42,12 +91,12 @@ func (c *UDPProbeConfig) Validate() error {
 		return fmt.Errorf("invalid port %d, must be between 1 and 65535", c.Port)
 	}
 
-	if c.TimeoutSeconds < 1 || c.TimeoutSeconds > 30 {
-		return fmt.Errorf("invalid timeout %d, must be between 1 and 30 seconds", c.TimeoutSeconds)
+	if c.OneWayDelay && !c.Responder {
+		return fmt.Errorf("one_way_delay requires a responder target (set responder: true)")
 	}
 
-	if c.Interval < 60 || c.Interval > 300 {
-		return fmt.Errorf("invalid interval %d, must be between 60 and 300 seconds", c.Interval)
+	if err := validateSchedule(c.Interval, c.Count, c.TimeoutSeconds); err != nil {
+		return err
 	}
 
 	// Validate that interval is sufficient for timeout and count
@@ -57,10 +106,6 @@ func (c *UDPProbeConfig) Validate() error {
 			c.Interval, c.Count, c.TimeoutSeconds, minInterval)
 	}
 
-	if c.Count < 1 || c.Count > 100 {
-		return fmt.Errorf("invalid count %d, must be between 1 and 100", c.Count)
-	}
-
 	return nil
 }
 
@@ -135,29 +180,42 @@ func (p *UDPPinger) Execute() (*models.UDPProbeResult, error) {
 	return models.NewUDPProbeResult(true, 0.0, rttMs, 1, 1, ""), nil
 }
 
-// ExecuteBatch performs multiple UDP probes and calculates core metrics
-func (p *UDPPinger) ExecuteBatch(count int) (*models.UDPProbeResult, error) {
+// ExecuteBatch performs multiple UDP probes and calculates core metrics.
+// Against a responder target the probes are sequence-numbered echo requests,
+// see executeResponderBatch. The batch stops early when ctx is done, see
+// batch.go.
+func (p *UDPPinger) ExecuteBatch(ctx context.Context, count int) (*models.UDPProbeResult, error) {
 	if count < 1 || count > 100 {
 		return nil, fmt.Errorf("invalid count %d, must be between 1 and 100", count)
 	}
 
+	if p.config.Responder {
+		return p.executeResponderBatch(ctx, count)
+	}
+
 	samples := make([]SamplePoint, 0, count)
 	sentPackets := 0
 	receivedPackets := 0
 	var errors []string
+	aborted := false
 
 	collector := NewCoreMetricsCollector()
 
 	for i := 0; i < count; i++ {
+		if ctx.Err() != nil {
+			aborted = true
+			break
+		}
 		sentPackets++
 
 		// Validate configuration before executing
 		if err := p.config.Validate(); err != nil {
 			errors = append(errors, err.Error())
 			samples = append(samples, SamplePoint{
-				RTTMs:     0,
-				Timestamp: time.Now().Format(time.RFC3339),
-				Success:   false,
+				RTTMs:      0,
+				Timestamp:  time.Now().Format(time.RFC3339),
+				Success:    false,
+				ErrorClass: classifyError(err),
 			})
 			continue
 		}
@@ -168,15 +226,23 @@ func (p *UDPPinger) ExecuteBatch(count int) (*models.UDPProbeResult, error) {
 		targetAddr := net.JoinHostPort(p.config.Target, fmt.Sprintf("%d", p.config.Port))
 
 		// Create UDP connection with timeout
-		conn, err := net.DialTimeout("udp", targetAddr, time.Duration(p.config.TimeoutSeconds)*time.Second)
-
+		dialer := net.Dialer{Timeout: time.Duration(p.config.TimeoutSeconds) * time.Second}
+		conn, err := dialer.DialContext(ctx, "udp", targetAddr)
+
+		if err != nil && ctx.Err() != nil {
+			// Aborted in flight, not a lost probe
+			sentPackets--
+			aborted = true
+			break
+		}
 		if err != nil {
 			// Connection failed
 			errors = append(errors, err.Error())
 			samples = append(samples, SamplePoint{
-				RTTMs:     0,
-				Timestamp: time.Now().Format(time.RFC3339),
-				Success:   false,
+				RTTMs:      0,
+				Timestamp:  time.Now().Format(time.RFC3339),
+				Success:    false,
+				ErrorClass: classifyError(err),
 			})
 			continue
 		}
@@ -188,9 +254,10 @@ func (p *UDPPinger) ExecuteBatch(count int) (*models.UDPProbeResult, error) {
 			conn.Close()
 			errors = append(errors, err.Error())
 			samples = append(samples, SamplePoint{
-				RTTMs:     0,
-				Timestamp: time.Now().Format(time.RFC3339),
-				Success:   false,
+				RTTMs:      0,
+				Timestamp:  time.Now().Format(time.RFC3339),
+				Success:    false,
+				ErrorClass: classifyError(err),
 			})
 			continue
 		}
@@ -202,9 +269,10 @@ func (p *UDPPinger) ExecuteBatch(count int) (*models.UDPProbeResult, error) {
 			conn.Close()
 			errors = append(errors, err.Error())
 			samples = append(samples, SamplePoint{
-				RTTMs:     0,
-				Timestamp: time.Now().Format(time.RFC3339),
-				Success:   false,
+				RTTMs:      0,
+				Timestamp:  time.Now().Format(time.RFC3339),
+				Success:    false,
+				ErrorClass: classifyError(err),
 			})
 			continue
 		}
@@ -216,27 +284,36 @@ func (p *UDPPinger) ExecuteBatch(count int) (*models.UDPProbeResult, error) {
 			conn.Close()
 			errors = append(errors, err.Error())
 			samples = append(samples, SamplePoint{
-				RTTMs:     0,
-				Timestamp: time.Now().Format(time.RFC3339),
-				Success:   false,
+				RTTMs:      0,
+				Timestamp:  time.Now().Format(time.RFC3339),
+				Success:    false,
+				ErrorClass: classifyError(err),
 			})
 			continue
 		}
 
-		// Wait for response
+		// Wait for response, unless the batch is aborted meanwhile
+		stopAbort := abortOnDone(ctx, conn)
 		buffer := make([]byte, 1024)
 		_, err = conn.Read(buffer)
+		stopAbort()
 		conn.Close()
 
 		totalElapsed := time.Since(startTime)
 
+		if err != nil && ctx.Err() != nil {
+			sentPackets--
+			aborted = true
+			break
+		}
 		if err != nil {
 			// Timeout or read failure - treat as packet loss
 			errors = append(errors, err.Error())
 			samples = append(samples, SamplePoint{
-				RTTMs:     0,
-				Timestamp: time.Now().Format(time.RFC3339),
-				Success:   false,
+				RTTMs:      0,
+				Timestamp:  time.Now().Format(time.RFC3339),
+				Success:    false,
+				ErrorClass: classifyError(err),
 			})
 			continue
 		}
@@ -252,6 +329,10 @@ func (p *UDPPinger) ExecuteBatch(count int) (*models.UDPProbeResult, error) {
 		})
 	}
 
+	if aborted && len(samples) == 0 {
+		return nil, batchAbortedError(ctx)
+	}
+
 	// Calculate core metrics using CoreMetricsCollector
 	metrics := collector.CalculateFromSamples(samples, sentPackets, receivedPackets)
 
@@ -284,7 +365,7 @@ func (p *UDPPinger) ExecuteBatch(count int) (*models.UDPProbeResult, error) {
 		errorMessage = fmt.Sprintf("丢包原因统计: %s", strings.Join(summaryParts, ", "))
 	}
 
-	return models.NewUDPProbeResultWithMetrics(
+	result := models.NewUDPProbeResultWithMetrics(
 		success,
 		metrics.PacketLossRate,
 		metrics.RTTMs,
@@ -295,5 +376,220 @@ func (p *UDPPinger) ExecuteBatch(count int) (*models.UDPProbeResult, error) {
 		receivedPackets,
 		metrics.SampleCount,
 		errorMessage,
-	), nil
+	)
+	result.RTTStatistics = metrics.RTTStatistics
+	result.Samples = rawSamples(samples)
+	result.Partial = aborted
+
+	// Attach probe identity so results can be reported per probe
+	result.ProbeID = p.ProbeID()
+	result.Target = p.config.Target
+	result.Port = p.config.Port
+
+	return result, nil
+}
+
+// ProbeID returns the configured probe ID, or a stable key derived from type, target and port
+func (p *UDPPinger) ProbeID() string {
+	if p.config.ID != "" {
+		return p.config.ID
+	}
+	return models.ProbeKey("udp_ping", p.config.Target, p.config.Port)
+}
+
+// responderPacketGap spaces echo requests so that several are in flight at
+// once, which exposes reordering on the path
+const responderPacketGap = 20 * time.Millisecond
+
+// executeResponderBatch sends count sequence-numbered echo requests to a
+// `beacon responder` on one socket and collects the replies until timeout
+// after the last request. Every request is answered unless lost, so loss is
+// exact; replies for a sequence number already seen count as duplicates and
+// replies arriving after a later sequence number as reordered. RTT excludes
+// the responder's processing time. One-way delay compares the two beacons'
+// clocks and is only reported when one_way_delay is set. When ctx is done,
+// requests not sent or still within their timeout are left out of the result.
+func (p *UDPPinger) executeResponderBatch(ctx context.Context, count int) (*models.UDPProbeResult, error) {
+	if err := p.config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	timeout := time.Duration(p.config.TimeoutSeconds) * time.Second
+	targetAddr := net.JoinHostPort(p.config.Target, fmt.Sprintf("%d", p.config.Port))
+
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "udp", targetAddr)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, batchAbortedError(ctx)
+		}
+		return p.newResponderResult(nil, count, 0, fmt.Sprintf("connect failed: %v", err)), nil
+	}
+	defer conn.Close()
+	defer abortOnDone(ctx, conn)()
+
+	if err := conn.SetReadDeadline(time.Now().Add(time.Duration(count)*responderPacketGap + timeout)); err != nil {
+		return p.newResponderResult(nil, count, 0, fmt.Sprintf("set read deadline failed: %v", err)), nil
+	}
+
+	session := rand.Uint64()
+	sentAt := make([]time.Time, count) // Zero for requests not sent; read after sendErrs
+	sendErrs := make(chan error, 1)
+	go func() {
+		var lastErr error
+		for seq := 0; seq < count; seq++ {
+			if seq > 0 && !sleepContext(ctx, responderPacketGap) {
+				break
+			}
+			packet := &responder.Packet{Session: session, Seq: uint32(seq), Sent: time.Now()}
+			sentAt[seq] = packet.Sent
+			if _, err := conn.Write(packet.Marshal()); err != nil {
+				lastErr = fmt.Errorf("send failed: %w", err)
+			}
+		}
+		sendErrs <- lastErr
+	}()
+
+	rtts := make([]float64, count)
+	seen := make([]bool, count)
+	received, reordered, duplicates := 0, 0, 0
+	highestSeq := -1
+	var forward, reverse []float64
+	var lastErr error
+
+	buffer := make([]byte, 1500)
+	for received < count {
+		n, err := conn.Read(buffer)
+		arrived := time.Now()
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			// ICMP port unreachable surfaces as a read error on connected
+			// sockets; later requests may still be answered
+			lastErr = err
+			if errors.Is(err, net.ErrClosed) {
+				break
+			}
+			continue
+		}
+
+		packet, err := responder.ParsePacket(buffer[:n])
+		if err != nil || !packet.Reply || packet.Session != session || int(packet.Seq) >= count {
+			continue
+		}
+		seq := int(packet.Seq)
+		if seen[seq] {
+			duplicates++
+			continue
+		}
+		seen[seq] = true
+		received++
+		if seq < highestSeq {
+			reordered++
+		} else {
+			highestSeq = seq
+		}
+
+		rtt := arrived.Sub(packet.Sent)
+		if processing := packet.Replied.Sub(packet.Received); processing > 0 && processing < rtt {
+			rtt -= processing
+		}
+		rtts[seq] = math.Round(rtt.Seconds()*1000*rttPrecisionMultiplier) / rttPrecisionMultiplier
+		forward = append(forward, packet.Received.Sub(packet.Sent).Seconds()*1000)
+		reverse = append(reverse, arrived.Sub(packet.Replied).Seconds()*1000)
+	}
+
+	if err := <-sendErrs; err != nil && lastErr == nil {
+		lastErr = err
+	}
+
+	// Samples in sequence order, so jitter reflects consecutive packets
+	abortedAt := time.Now()
+	cancelled := ctx.Err() != nil
+	samples := make([]SamplePoint, 0, count)
+	for seq := 0; seq < count; seq++ {
+		if cancelled && !seen[seq] && (sentAt[seq].IsZero() || abortedAt.Sub(sentAt[seq]) < timeout) {
+			continue // Not sent, or still awaiting its reply when the batch was aborted
+		}
+		sample := SamplePoint{
+			RTTMs:     rtts[seq],
+			Timestamp: time.Now().Format(time.RFC3339),
+			Success:   seen[seq],
+		}
+		if !sample.Success {
+			sample.ErrorClass = models.SampleErrorTimeout
+		}
+		samples = append(samples, sample)
+	}
+
+	errorMessage := ""
+	if received == 0 {
+		errorMessage = "no echo reply from responder"
+		if lastErr != nil {
+			errorMessage = fmt.Sprintf("no echo reply from responder: %v", lastErr)
+		}
+	}
+
+	aborted := len(samples) < count
+	if aborted && len(samples) == 0 {
+		return nil, batchAbortedError(ctx)
+	}
+
+	result := p.newResponderResult(samples, len(samples), received, errorMessage)
+	result.Partial = aborted
+	result.ReorderedPackets = reordered
+	result.DuplicatePackets = duplicates
+	if p.config.OneWayDelay && received > 0 {
+		result.OneWayForwardMs = meanDelayMs(forward)
+		result.OneWayReverseMs = meanDelayMs(reverse)
+	}
+
+	return result, nil
+}
+
+// newResponderResult builds a UDP result from echo samples
+func (p *UDPPinger) newResponderResult(samples []SamplePoint, sent, received int, errorMessage string) *models.UDPProbeResult {
+	metrics := NewCoreMetricsCollector().CalculateFromSamples(samples, sent, received)
+
+	result := models.NewUDPProbeResultWithMetrics(
+		received > 0,
+		metrics.PacketLossRate,
+		metrics.RTTMs,
+		metrics.RTTMedianMs,
+		metrics.JitterMs,
+		metrics.RTTVarianceMs,
+		sent,
+		received,
+		metrics.SampleCount,
+		errorMessage,
+	)
+	result.RTTStatistics = metrics.RTTStatistics
+	result.Samples = rawSamples(samples)
+	result.ProbeID = p.ProbeID()
+	result.Target = p.config.Target
+	result.Port = p.config.Port
+	return result
+}
+
+// meanDelayMs returns the mean of one-way delays, rounded like RTTs
+func meanDelayMs(delays []float64) *float64 {
+	mean := NewCoreMetricsCollector().CalculateMean(delays)
+	mean = math.Round(mean*rttPrecisionMultiplier) / rttPrecisionMultiplier
+	return &mean
+}
+
+// udpProber adapts UDPPinger to the Prober interface
+type udpProber struct {
+	*UDPPinger
+}
+
+// ExecuteBatch performs a UDP probe batch and reports it as a generic result
+func (p *udpProber) ExecuteBatch(ctx context.Context, count int) (*models.ProbeResult, error) {
+	result, err := p.UDPPinger.ExecuteBatch(ctx, count)
+	if err != nil {
+		return nil, err
+	}
+	return result.ToGenericResult(), nil
 }
@@ -1,11 +1,15 @@
 package probe
 
 import (
+	"context"
+	"errors"
 	"math"
+	"net"
 	"testing"
 	"time"
 
 	"beacon/internal/models"
+	"beacon/internal/responder"
 )
 
 // TestUDPProbeConfigValidation tests UDP probe configuration validation
@@ -40,6 +44,20 @@ func TestUDPProbeConfigValidation(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "one-way delay without responder",
+			config: UDPProbeConfig{
+				Type:           "udp_ping",
+				Target:         "10.0.0.20",
+				Port:           7331,
+				OneWayDelay:    true,
+				TimeoutSeconds: 2,
+				Interval:       60,
+				Count:          10,
+			},
+			wantErr: true,
+			errMsg:  "one_way_delay requires a responder",
+		},
 		{
 			name: "invalid type - tcp_ping",
 			config: UDPProbeConfig{
@@ -404,7 +422,7 @@ func TestExecuteBatch(t *testing.T) {
 
 		pinger := NewUDPPinger(config)
 
-		result, err := pinger.ExecuteBatch(5)
+		result, err := pinger.ExecuteBatch(context.Background(), 5)
 
 		if err != nil {
 			t.Errorf("ExecuteBatch() error = %v", err)
@@ -435,7 +453,7 @@ func TestExecuteBatch(t *testing.T) {
 
 		pinger := NewUDPPinger(config)
 
-		_, err := pinger.ExecuteBatch(0)
+		_, err := pinger.ExecuteBatch(context.Background(), 0)
 		if err == nil {
 			t.Error("ExecuteBatch(0) should return error")
 		}
@@ -453,7 +471,7 @@ func TestExecuteBatch(t *testing.T) {
 
 		pinger := NewUDPPinger(config)
 
-		_, err := pinger.ExecuteBatch(101)
+		_, err := pinger.ExecuteBatch(context.Background(), 101)
 		if err == nil {
 			t.Error("ExecuteBatch(101) should return error")
 		}
@@ -482,3 +500,200 @@ func almostEqual(a, b float64) bool {
 	epsilon := 0.0001
 	return math.Abs(a-b) < epsilon
 }
+
+// TestUDPPinger_ExecuteBatch_Responder probes a beacon responder on loopback
+func TestUDPPinger_ExecuteBatch_Responder(t *testing.T) {
+	initSchedulerTestLogger(t)
+
+	// Arrange
+	server := responder.NewServer("127.0.0.1:0")
+	if err := server.Start(); err != nil {
+		t.Fatalf("Failed to start responder: %v", err)
+	}
+	defer server.Stop()
+	port := server.Addr().(*net.UDPAddr).Port
+
+	pinger := NewUDPPinger(UDPProbeConfig{Type: "udp_ping", Target: "127.0.0.1", Port: port, Responder: true, OneWayDelay: true, TimeoutSeconds: 1, Interval: 60, Count: 5})
+
+	// Act
+	result, err := pinger.ExecuteBatch(context.Background(), 5)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if !result.Success || result.SentPackets != 5 || result.ReceivedPackets != 5 || result.PacketLossRate != 0 {
+		t.Fatalf("Expected all echoes answered, got %+v", result)
+	}
+	if result.RTTMs < 0 || result.RTTMs > 100 {
+		t.Errorf("Expected loopback RTT, got %.3fms", result.RTTMs)
+	}
+	if result.ReorderedPackets != 0 || result.DuplicatePackets != 0 {
+		t.Errorf("Expected no reordering or duplicates, got %d/%d", result.ReorderedPackets, result.DuplicatePackets)
+	}
+	if result.OneWayForwardMs == nil || result.OneWayReverseMs == nil {
+		t.Error("Expected one-way delays with one_way_delay enabled")
+	}
+	if server.Replies() != 5 {
+		t.Errorf("Expected 5 replies, got %d", server.Replies())
+	}
+}
+
+// TestUDPPinger_ExecuteBatch_ResponderReorderAndDuplicate uses a fake
+// responder that answers in reverse order and repeats the first reply
+func TestUDPPinger_ExecuteBatch_ResponderReorderAndDuplicate(t *testing.T) {
+	initSchedulerTestLogger(t)
+
+	// Arrange
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer conn.Close()
+
+	const count = 4
+	go func() {
+		var requests []*responder.Packet
+		var peer net.Addr
+		buffer := make([]byte, 1500)
+		for len(requests) < count {
+			n, addr, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			packet, err := responder.ParsePacket(buffer[:n])
+			if err != nil {
+				continue
+			}
+			requests = append(requests, packet)
+			peer = addr
+		}
+		for i := count - 1; i >= 0; i-- {
+			reply := requests[i]
+			reply.Reply = true
+			reply.Received = time.Now()
+			reply.Replied = time.Now()
+			conn.WriteTo(reply.Marshal(), peer)
+			if i == count-1 {
+				conn.WriteTo(reply.Marshal(), peer)
+			}
+		}
+	}()
+
+	pinger := NewUDPPinger(UDPProbeConfig{Type: "udp_ping", Target: "127.0.0.1", Port: conn.LocalAddr().(*net.UDPAddr).Port, Responder: true, TimeoutSeconds: 1, Interval: 60, Count: count})
+
+	// Act
+	result, err := pinger.ExecuteBatch(context.Background(), count)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if result.ReceivedPackets != count {
+		t.Fatalf("Expected %d replies, got %+v", count, result)
+	}
+	if result.ReorderedPackets != count-1 {
+		t.Errorf("Expected %d reordered replies, got %d", count-1, result.ReorderedPackets)
+	}
+	if result.DuplicatePackets != 1 {
+		t.Errorf("Expected 1 duplicate reply, got %d", result.DuplicatePackets)
+	}
+	if result.OneWayForwardMs != nil || result.OneWayReverseMs != nil {
+		t.Error("Expected no one-way delays without one_way_delay")
+	}
+}
+
+// TestUDPPinger_ExecuteBatch_ResponderUnreachable expects full loss without a responder
+func TestUDPPinger_ExecuteBatch_ResponderUnreachable(t *testing.T) {
+	initSchedulerTestLogger(t)
+
+	pinger := NewUDPPinger(UDPProbeConfig{Type: "udp_ping", Target: "127.0.0.1", Port: 12347, Responder: true, TimeoutSeconds: 1, Interval: 60, Count: 3})
+
+	result, err := pinger.ExecuteBatch(context.Background(), 3)
+
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if result.Success || result.PacketLossRate != 100 {
+		t.Errorf("Expected full loss, got %+v", result)
+	}
+	if !contains(result.ErrorMessage, "no echo reply from responder") {
+		t.Errorf("Unexpected error message: %q", result.ErrorMessage)
+	}
+}
+
+// startSilentUDPServer answers the first `replies` datagrams and ignores the rest
+func startSilentUDPServer(t *testing.T, replies int) *net.UDPConn {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buffer := make([]byte, 1024)
+		for answered := 0; ; answered++ {
+			n, addr, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			if answered < replies {
+				conn.WriteTo(buffer[:n], addr)
+			}
+		}
+	}()
+	return conn
+}
+
+// TestUDPPinger_ExecuteBatch_Cancelled tests that cancelling the context
+// aborts the pending read and reports the completed attempts as partial
+func TestUDPPinger_ExecuteBatch_Cancelled(t *testing.T) {
+	// Arrange - two replies, then silence until the 5s timeout
+	conn := startSilentUDPServer(t, 2)
+	pinger := NewUDPPinger(UDPProbeConfig{Type: "udp_ping", Target: "127.0.0.1", Port: conn.LocalAddr().(*net.UDPAddr).Port, TimeoutSeconds: 5, Interval: 60, Count: 10})
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	// Act
+	start := time.Now()
+	result, err := pinger.ExecuteBatch(ctx, 10)
+	elapsed := time.Since(start)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("Expected prompt abort, took %v", elapsed)
+	}
+	if !result.Partial {
+		t.Error("Expected result marked partial")
+	}
+	if result.SentPackets != 2 || result.ReceivedPackets != 2 || len(result.Samples) != 2 {
+		t.Errorf("Expected 2 completed attempts, got sent=%d received=%d samples=%d", result.SentPackets, result.ReceivedPackets, len(result.Samples))
+	}
+	if result.PacketLossRate != 0 {
+		t.Errorf("Expected the aborted attempt not counted as lost, got %.1f%% loss", result.PacketLossRate)
+	}
+}
+
+// TestUDPPinger_ExecuteBatch_CancelledBeforeFirstReply tests that a batch
+// aborted before any attempt completed returns the context error
+func TestUDPPinger_ExecuteBatch_CancelledBeforeFirstReply(t *testing.T) {
+	conn := startSilentUDPServer(t, 0)
+	for _, responderMode := range []bool{false, true} {
+		pinger := NewUDPPinger(UDPProbeConfig{Type: "udp_ping", Target: "127.0.0.1", Port: conn.LocalAddr().(*net.UDPAddr).Port, Responder: responderMode, TimeoutSeconds: 5, Interval: 60, Count: 10})
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+
+		start := time.Now()
+		result, err := pinger.ExecuteBatch(ctx, 10)
+		cancel()
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("responder=%v: expected deadline error, got result=%+v err=%v", responderMode, result, err)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("responder=%v: expected prompt abort, took %v", responderMode, elapsed)
+		}
+	}
+}
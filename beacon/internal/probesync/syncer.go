@@ -0,0 +1,180 @@
+// Package probesync keeps the probe scheduler in sync with probes managed
+// centrally in Pulse. Synced probes are merged with the local config file.
+package probesync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"beacon/internal/api"
+	"beacon/internal/config"
+	"beacon/internal/logger"
+	"beacon/internal/models"
+	"beacon/internal/probe"
+)
+
+// ProbeFetcher fetches the probe configuration assigned to a node
+type ProbeFetcher interface {
+	FetchProbeConfig(ctx context.Context, nodeID string, version string) (*api.ProbeConfigData, error)
+}
+
+// ProbeReloader applies a new probe configuration (implemented by ProbeScheduler)
+type ProbeReloader interface {
+	ReloadConfig(probeConfigs []config.ProbeConfig) (probe.ReloadReport, error)
+}
+
+// Syncer polls Pulse for probe configuration and reloads the scheduler on change
+type Syncer struct {
+	fetcher  ProbeFetcher
+	reloader ProbeReloader
+	interval time.Duration
+
+	mu           sync.Mutex
+	nodeID       string
+	localProbes  []config.ProbeConfig
+	remoteProbes []config.ProbeConfig
+	version      string
+}
+
+// NewSyncer creates a new probe config syncer
+func NewSyncer(fetcher ProbeFetcher, reloader ProbeReloader, nodeID string, interval time.Duration, localProbes []config.ProbeConfig) *Syncer {
+	return &Syncer{
+		fetcher:     fetcher,
+		reloader:    reloader,
+		nodeID:      nodeID,
+		interval:    interval,
+		localProbes: localProbes,
+	}
+}
+
+// Run polls Pulse until ctx is cancelled, syncing immediately on start
+func (s *Syncer) Run(ctx context.Context) {
+	logger.WithFields(map[string]interface{}{"component": "probesync", "interval": s.interval.String()}).Info("Starting probe config sync")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.SyncOnce(ctx); err != nil && ctx.Err() == nil {
+			logger.WithFields(map[string]interface{}{"component": "probesync", "error": err.Error()}).Warn("Probe config sync failed")
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			logger.WithField("component", "probesync").Info("Probe config sync stopped")
+			return
+		}
+	}
+}
+
+// SyncOnce fetches the probe configuration once and reloads the scheduler if
+// the version changed. Returns true when a new configuration was applied.
+func (s *Syncer) SyncOnce(ctx context.Context) (bool, error) {
+	s.mu.Lock()
+	nodeID, version := s.nodeID, s.version
+	s.mu.Unlock()
+
+	data, err := s.fetcher.FetchProbeConfig(ctx, nodeID, version)
+	if errors.Is(err, api.ErrProbeConfigNotModified) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	remote := make([]config.ProbeConfig, 0, len(data.Probes))
+	for _, item := range data.Probes {
+		probe := config.ProbeConfig{
+			ID:             item.ID,
+			Type:           item.Type,
+			Target:         item.Target,
+			Port:           item.Port,
+			TimeoutSeconds: item.TimeoutSeconds,
+			Interval:       item.Interval,
+			Count:          item.Count,
+		}
+		if item.Responder {
+			probe.Options = map[string]interface{}{"responder": true}
+		}
+		if err := config.ValidateProbeConfig(probe); err != nil {
+			logger.WithFields(map[string]interface{}{"component": "probesync", "probe_id": item.ID, "error": err.Error()}).Warn("Skipping invalid probe from Pulse")
+			continue
+		}
+		remote = append(remote, probe)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Version is only recorded after a successful reload, so a rejected
+	// configuration is fetched and retried on the next poll
+	report, err := s.reloader.ReloadConfig(MergeProbes(s.localProbes, remote))
+	if err != nil {
+		return false, err
+	}
+	s.remoteProbes = remote
+	s.version = data.Version
+
+	fields := report.Fields()
+	fields["component"] = "probesync"
+	fields["version"] = data.Version
+	fields["remote_count"] = len(remote)
+	fields["local_count"] = len(s.localProbes)
+	logger.WithFields(fields).Info("Probe configuration synced from Pulse")
+	return true, nil
+}
+
+// SetNodeID switches syncing to another node ID (e.g. after re-registration).
+// The next poll fetches the full configuration of that node.
+func (s *Syncer) SetNodeID(nodeID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nodeID = nodeID
+	s.version = ""
+}
+
+// SetLocalProbes replaces the probes from the local config file (e.g. on hot
+// reload) and reloads the scheduler with the merged configuration, returning
+// the scheduler's reload report
+func (s *Syncer) SetLocalProbes(localProbes []config.ProbeConfig) (probe.ReloadReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report, err := s.reloader.ReloadConfig(MergeProbes(localProbes, s.remoteProbes))
+	if err != nil {
+		return probe.ReloadReport{}, err
+	}
+	s.localProbes = localProbes
+	return report, nil
+}
+
+// Version returns the last applied Pulse configuration version
+func (s *Syncer) Version() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.version
+}
+
+// MergeProbes combines local and remote probes. A remote probe replaces a local
+// probe with the same ID or the same type/target/port.
+func MergeProbes(local, remote []config.ProbeConfig) []config.ProbeConfig {
+	remoteKeys := make(map[string]bool, len(remote)*2)
+	for _, probe := range remote {
+		remoteKeys[models.ProbeKey(probe.Type, probe.Target, probe.Port)] = true
+		if probe.ID != "" {
+			remoteKeys[probe.ID] = true
+		}
+	}
+
+	merged := make([]config.ProbeConfig, 0, len(local)+len(remote))
+	for _, probe := range local {
+		if remoteKeys[models.ProbeKey(probe.Type, probe.Target, probe.Port)] || (probe.ID != "" && remoteKeys[probe.ID]) {
+			continue
+		}
+		merged = append(merged, probe)
+	}
+	return append(merged, remote...)
+}
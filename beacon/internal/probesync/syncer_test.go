@@ -0,0 +1,238 @@
+package probesync
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"beacon/internal/api"
+	"beacon/internal/config"
+	"beacon/internal/logger"
+	"beacon/internal/probe"
+)
+
+// initTestLogger initializes the logger for tests
+func initTestLogger(t *testing.T) {
+	if err := logger.InitLogger(&config.Config{
+		LogLevel:      "INFO",
+		LogFile:       "/tmp/test-probesync.log",
+		LogMaxSize:    10,
+		LogMaxAge:     7,
+		LogMaxBackups: 3,
+	}); err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+}
+
+// fakeReloader records the probe configurations applied by the syncer
+type fakeReloader struct {
+	mu      sync.Mutex
+	reloads [][]config.ProbeConfig
+}
+
+func (f *fakeReloader) ReloadConfig(probeConfigs []config.ProbeConfig) (probe.ReloadReport, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reloads = append(f.reloads, probeConfigs)
+	return probe.ReloadReport{Unchanged: len(probeConfigs)}, nil
+}
+
+func (f *fakeReloader) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.reloads)
+}
+
+func (f *fakeReloader) last() []config.ProbeConfig {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.reloads[len(f.reloads)-1]
+}
+
+// newPulseServer serves probe config for one node with ETag support
+func newPulseServer(t *testing.T, version *string, probes *[]api.ProbeConfigItem) *httptest.Server {
+	initTestLogger(t)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/beacon/nodes/node-1/probes" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		etag := `"` + *version + `"`
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": api.ProbeConfigData{NodeID: "node-1", Version: *version, Probes: *probes},
+		})
+	}))
+}
+
+// TestSyncOnce tests that the scheduler is reloaded only when the version changes
+func TestSyncOnce(t *testing.T) {
+	// Arrange
+	version := "v1"
+	probes := []api.ProbeConfigItem{
+		{ID: "11111111-1111-1111-1111-111111111111", Type: "tcp_ping", Target: "8.8.8.8", Port: 80, Interval: 60, Count: 10, TimeoutSeconds: 5},
+	}
+	server := newPulseServer(t, &version, &probes)
+	defer server.Close()
+
+	reloader := &fakeReloader{}
+	local := []config.ProbeConfig{
+		{Type: "udp_ping", Target: "1.1.1.1", Port: 53, Interval: 60, Count: 10, TimeoutSeconds: 5},
+	}
+	syncer := NewSyncer(api.NewPulseClient(server.URL, "", nil), reloader, "node-1", time.Minute, local)
+	ctx := context.Background()
+
+	// Act - first sync applies the configuration
+	changed, err := syncer.SyncOnce(ctx)
+
+	// Assert
+	if err != nil || !changed {
+		t.Fatalf("Expected first sync to apply config, changed=%v err=%v", changed, err)
+	}
+	if got := len(reloader.last()); got != 2 {
+		t.Errorf("Expected 2 merged probes (1 local + 1 remote), got %d", got)
+	}
+	if syncer.Version() != "v1" {
+		t.Errorf("Expected version v1, got %s", syncer.Version())
+	}
+
+	// Act - unchanged version does not reload
+	changed, err = syncer.SyncOnce(ctx)
+	if err != nil || changed {
+		t.Errorf("Expected no change, changed=%v err=%v", changed, err)
+	}
+	if reloader.count() != 1 {
+		t.Errorf("Expected 1 reload, got %d", reloader.count())
+	}
+
+	// Act - new version reloads
+	version = "v2"
+	probes = append(probes, api.ProbeConfigItem{ID: "22222222-2222-2222-2222-222222222222", Type: "udp_ping", Target: "9.9.9.9", Port: 53, Interval: 60, Count: 10, TimeoutSeconds: 5})
+	changed, err = syncer.SyncOnce(ctx)
+
+	// Assert
+	if err != nil || !changed {
+		t.Fatalf("Expected new version to apply, changed=%v err=%v", changed, err)
+	}
+	if got := len(reloader.last()); got != 3 {
+		t.Errorf("Expected 3 merged probes, got %d", got)
+	}
+}
+
+// TestSyncOnceSkipsInvalidProbes tests that invalid probes from Pulse are skipped
+func TestSyncOnceSkipsInvalidProbes(t *testing.T) {
+	// Arrange
+	version := "v1"
+	probes := []api.ProbeConfigItem{
+		{ID: "a", Type: "tcp_ping", Target: "8.8.8.8", Port: 80, Interval: 60, Count: 10, TimeoutSeconds: 5},
+		{ID: "b", Type: "icmp", Target: "8.8.8.8", Port: 0, Interval: 60, Count: 10, TimeoutSeconds: 5},
+	}
+	server := newPulseServer(t, &version, &probes)
+	defer server.Close()
+
+	reloader := &fakeReloader{}
+	syncer := NewSyncer(api.NewPulseClient(server.URL, "", nil), reloader, "node-1", time.Minute, nil)
+
+	// Act
+	if _, err := syncer.SyncOnce(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Assert
+	if got := reloader.last(); len(got) != 1 || got[0].ID != "a" {
+		t.Errorf("Expected only valid probe 'a', got %+v", got)
+	}
+}
+
+// TestSyncOnceMeshProbes tests that mesh probes keep their responder flag
+func TestSyncOnceMeshProbes(t *testing.T) {
+	// Arrange
+	version := "v1"
+	probes := []api.ProbeConfigItem{
+		{ID: "mesh:33333333-3333-3333-3333-333333333333", Type: "udp_ping", Target: "10.0.0.2", Port: 7331, Interval: 60, Count: 10, TimeoutSeconds: 2, Responder: true},
+	}
+	server := newPulseServer(t, &version, &probes)
+	defer server.Close()
+
+	reloader := &fakeReloader{}
+	syncer := NewSyncer(api.NewPulseClient(server.URL, "", nil), reloader, "node-1", time.Minute, nil)
+
+	// Act
+	if _, err := syncer.SyncOnce(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Assert
+	if got := reloader.last(); len(got) != 1 || got[0].Options["responder"] != true || got[0].ID != probes[0].ID {
+		t.Errorf("Expected mesh probe with responder enabled, got %+v", got)
+	}
+}
+
+// TestMergeProbes tests that remote probes replace local probes with the same identity
+func TestMergeProbes(t *testing.T) {
+	// Arrange
+	local := []config.ProbeConfig{
+		{Type: "tcp_ping", Target: "8.8.8.8", Port: 80, Interval: 300},
+		{Type: "udp_ping", Target: "8.8.8.8", Port: 53, Interval: 300},
+		{ID: "remote-2", Type: "tcp_ping", Target: "10.0.0.1", Port: 22, Interval: 300},
+	}
+	remote := []config.ProbeConfig{
+		{ID: "remote-1", Type: "tcp_ping", Target: "8.8.8.8", Port: 80, Interval: 60},
+		{ID: "remote-2", Type: "tcp_ping", Target: "10.0.0.2", Port: 22, Interval: 60},
+	}
+
+	// Act
+	merged := MergeProbes(local, remote)
+
+	// Assert
+	if len(merged) != 3 {
+		t.Fatalf("Expected 3 probes, got %d: %+v", len(merged), merged)
+	}
+	if merged[0].Type != "udp_ping" {
+		t.Errorf("Expected local udp probe to be kept, got %+v", merged[0])
+	}
+	if merged[1].ID != "remote-1" || merged[1].Interval != 60 {
+		t.Errorf("Expected remote probe to replace local tcp probe, got %+v", merged[1])
+	}
+}
+
+// TestSetLocalProbes tests that local config reloads keep synced probes
+func TestSetLocalProbes(t *testing.T) {
+	// Arrange
+	version := "v1"
+	probes := []api.ProbeConfigItem{
+		{ID: "a", Type: "tcp_ping", Target: "8.8.8.8", Port: 80, Interval: 60, Count: 10, TimeoutSeconds: 5},
+	}
+	server := newPulseServer(t, &version, &probes)
+	defer server.Close()
+
+	reloader := &fakeReloader{}
+	syncer := NewSyncer(api.NewPulseClient(server.URL, "", nil), reloader, "node-1", time.Minute, nil)
+	if _, err := syncer.SyncOnce(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Act
+	report, err := syncer.SetLocalProbes([]config.ProbeConfig{
+		{Type: "udp_ping", Target: "1.1.1.1", Port: 53, Interval: 60, Count: 10, TimeoutSeconds: 5},
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := len(reloader.last()); got != 2 {
+		t.Errorf("Expected local + synced probes (2), got %d", got)
+	}
+	if report.Unchanged != 2 {
+		t.Errorf("Expected the scheduler's reload report, got %+v", report)
+	}
+}
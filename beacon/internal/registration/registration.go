@@ -0,0 +1,213 @@
+// Package registration implements Beacon self-registration with Pulse.
+// The node ID and API token assigned by Pulse are persisted to a local state
+// file so the beacon keeps the same identity across restarts.
+package registration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"beacon/internal/api"
+	"beacon/internal/config"
+	"beacon/internal/logger"
+)
+
+// uuidPattern matches the node IDs assigned by Pulse
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// State represents the persisted registration state
+type State struct {
+	NodeID       string `json:"node_id"`
+	APIToken     string `json:"api_token,omitempty"`
+	PulseServer  string `json:"pulse_server"`
+	RegisteredAt string `json:"registered_at"`
+}
+
+// Registrar registers the node with Pulse (implemented by api.PulseClient)
+type Registrar interface {
+	SetAuthToken(authToken string)
+	RegisterNode(ctx context.Context, req *api.RegisterNodeRequest) (*api.RegisterNodeResponse, error)
+}
+
+// Register ensures this beacon is registered with Pulse and returns its node ID
+// and API token. The known node ID (config, then state file) is sent along so
+// Pulse returns the same node; Pulse falls back to name+IP lookup, so
+// re-registration is idempotent. The known token authenticates re-registration.
+// If Pulse is unreachable but a node ID is already known, that identity is used.
+func Register(ctx context.Context, registrar Registrar, cfg *config.Config) (*State, error) {
+	known := knownState(cfg)
+	state, err := register(ctx, registrar, cfg, known)
+	if err != nil {
+		return fallbackState(known, err)
+	}
+	return state, nil
+}
+
+// Reregister registers the beacon again after Pulse reported its node as
+// unknown, e.g. because the node was deleted. Unlike Register it returns the
+// error instead of falling back to the known identity.
+func Reregister(ctx context.Context, registrar Registrar, cfg *config.Config) (*State, error) {
+	return register(ctx, registrar, cfg, knownState(cfg))
+}
+
+// knownState returns the identity known locally: the configured node ID and
+// token, completed from the state file of the same node
+func knownState(cfg *config.Config) *State {
+	known := &State{NodeID: cfg.NodeID, APIToken: cfg.APIToken}
+	state, err := LoadState(cfg.StateFile)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{"component": "registration", "state_file": cfg.StateFile, "error": err.Error()}).Warn("Failed to read registration state, registering as new node")
+	} else if state != nil && (known.NodeID == "" || known.NodeID == state.NodeID) {
+		known.NodeID = state.NodeID
+		if known.APIToken == "" {
+			known.APIToken = state.APIToken
+		}
+	}
+	return known
+}
+
+// register sends the registration request and persists the assigned identity
+func register(ctx context.Context, registrar Registrar, cfg *config.Config, known *State) (*State, error) {
+	registrar.SetAuthToken(known.APIToken)
+
+	ip := cfg.NodeIP
+	if ip == "" {
+		detected, err := DetectIP(cfg.PulseServer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect node IP: %w", err)
+		}
+		ip = detected
+	}
+
+	resp, err := registrar.RegisterNode(ctx, &api.RegisterNodeRequest{
+		NodeID:   known.NodeID,
+		NodeName: cfg.NodeName,
+		IP:       ip,
+		Region:   cfg.Region,
+		Tags:     cfg.Tags,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("registration failed: %w", err)
+	}
+
+	nodeID := resp.Data.ID
+	if !uuidPattern.MatchString(nodeID) {
+		return nil, fmt.Errorf("pulse returned invalid node_id %q", nodeID)
+	}
+
+	if nodeID != known.NodeID {
+		logger.WithFields(map[string]interface{}{"component": "registration", "node_id": nodeID, "previous_node_id": known.NodeID}).Info("Node registered with Pulse")
+	}
+
+	// Pulse only returns a token when it issues a new one
+	apiToken := resp.Data.APIToken
+	if apiToken == "" {
+		apiToken = known.APIToken
+	}
+
+	// Persist the assigned identity; a write failure is not fatal for this run
+	state := &State{NodeID: nodeID, APIToken: apiToken, PulseServer: cfg.PulseServer, RegisteredAt: time.Now().Format(time.RFC3339)}
+	if err := SaveState(cfg.StateFile, state); err != nil {
+		logger.WithFields(map[string]interface{}{"component": "registration", "state_file": cfg.StateFile, "error": err.Error()}).Warn("Failed to persist node_id, beacon will re-register on next start")
+	}
+
+	return state, nil
+}
+
+// StoredAPIToken returns the API token from config, or the token persisted in
+// the state file for the configured node. Returns "" if none is known.
+func StoredAPIToken(cfg *config.Config) string {
+	if cfg.APIToken != "" {
+		return cfg.APIToken
+	}
+	state, err := LoadState(cfg.StateFile)
+	if err != nil || state == nil || state.NodeID != cfg.NodeID {
+		return ""
+	}
+	return state.APIToken
+}
+
+// fallbackState returns the known identity when registration fails, or the error
+// if the beacon has never been registered
+func fallbackState(known *State, err error) (*State, error) {
+	if known.NodeID == "" {
+		return nil, err
+	}
+	logger.WithFields(map[string]interface{}{"component": "registration", "node_id": known.NodeID, "error": err.Error()}).Warn("Registration failed, continuing with known node_id")
+	return known, nil
+}
+
+// LoadState reads the registration state file. Returns nil if it does not exist.
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %w", path, err)
+	}
+	if state.NodeID == "" {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// SaveState writes the registration state file atomically (write + rename)
+func SaveState(path string, state *State) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+	return nil
+}
+
+// DetectIP returns the local IP address used to reach the Pulse server.
+// No packets are sent: a UDP "connection" only selects the outbound route.
+func DetectIP(pulseServer string) (string, error) {
+	u, err := url.Parse(pulseServer)
+	if err != nil {
+		return "", fmt.Errorf("invalid pulse_server URL: %w", err)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+
+	conn, err := net.Dial("udp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	return conn.LocalAddr().(*net.UDPAddr).IP.String(), nil
+}
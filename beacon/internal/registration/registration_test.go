@@ -0,0 +1,202 @@
+package registration
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"beacon/internal/api"
+	"beacon/internal/config"
+	"beacon/internal/logger"
+)
+
+const testNodeID = "550e8400-e29b-41d4-a716-446655440000"
+
+// initTestLogger initializes the logger for tests
+func initTestLogger(t *testing.T) {
+	if err := logger.InitLogger(&config.Config{
+		LogLevel:      "INFO",
+		LogFile:       "/tmp/test-registration.log",
+		LogMaxSize:    10,
+		LogMaxAge:     7,
+		LogMaxBackups: 3,
+	}); err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+}
+
+// mockRegistrar records registration requests and returns a fixed result
+type mockRegistrar struct {
+	requests  []*api.RegisterNodeRequest
+	tokens    []string // Auth token in effect for each request
+	authToken string
+	nodeID    string
+	apiToken  string // Token issued on first registration
+	err       error
+}
+
+func (m *mockRegistrar) SetAuthToken(authToken string) {
+	m.authToken = authToken
+}
+
+func (m *mockRegistrar) RegisterNode(ctx context.Context, req *api.RegisterNodeRequest) (*api.RegisterNodeResponse, error) {
+	m.requests = append(m.requests, req)
+	m.tokens = append(m.tokens, m.authToken)
+	if m.err != nil {
+		return nil, m.err
+	}
+	data := api.RegisterNodeData{ID: m.nodeID, Name: req.NodeName, IP: req.IP}
+	if len(m.requests) == 1 {
+		data.APIToken = m.apiToken
+	}
+	return &api.RegisterNodeResponse{Data: data}, nil
+}
+
+func newTestConfig(t *testing.T) *config.Config {
+	return &config.Config{
+		PulseServer:  "http://127.0.0.1:8080",
+		NodeName:     "beacon-01",
+		NodeIP:       "10.0.0.1",
+		Region:       "us-east",
+		Tags:         []string{"edge"},
+		AutoRegister: true,
+		StateFile:    filepath.Join(t.TempDir(), "state", "state.json"),
+	}
+}
+
+// TestRegister_PersistsAndReusesNodeID tests first registration and restart
+func TestRegister_PersistsAndReusesNodeID(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange
+	cfg := newTestConfig(t)
+	registrar := &mockRegistrar{nodeID: testNodeID, apiToken: "npb_secret"}
+
+	// Act - first start without node_id
+	result, err := Register(context.Background(), registrar, cfg)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.NodeID != testNodeID || result.APIToken != "npb_secret" {
+		t.Errorf("Expected node_id %s with issued token, got %+v", testNodeID, result)
+	}
+	if registrar.requests[0].NodeID != "" || registrar.requests[0].IP != "10.0.0.1" || registrar.tokens[0] != "" {
+		t.Errorf("Unexpected first request: %+v (token %q)", registrar.requests[0], registrar.tokens[0])
+	}
+
+	state, err := LoadState(cfg.StateFile)
+	if err != nil || state == nil || state.NodeID != testNodeID || state.APIToken != "npb_secret" {
+		t.Fatalf("Expected state file with node_id and token, got %+v (err %v)", state, err)
+	}
+
+	// Act - restart sends persisted node_id and token so Pulse returns the same node
+	result, err = Register(context.Background(), registrar, cfg)
+
+	// Assert - token kept although Pulse does not return it again
+	if err != nil || result.NodeID != testNodeID || result.APIToken != "npb_secret" {
+		t.Errorf("Expected same identity on restart, got %+v (err %v)", result, err)
+	}
+	if registrar.requests[1].NodeID != testNodeID || registrar.tokens[1] != "npb_secret" {
+		t.Errorf("Expected persisted identity in request, got %q (token %q)", registrar.requests[1].NodeID, registrar.tokens[1])
+	}
+	if token := StoredAPIToken(&config.Config{NodeID: testNodeID, StateFile: cfg.StateFile}); token != "npb_secret" {
+		t.Errorf("Expected stored token for configured node, got %q", token)
+	}
+}
+
+// TestRegister_PulseUnavailable tests fallback to a known node_id
+func TestRegister_PulseUnavailable(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange
+	cfg := newTestConfig(t)
+	registrar := &mockRegistrar{err: errors.New("connection refused")}
+
+	// Act - never registered: error
+	_, err := Register(context.Background(), registrar, cfg)
+
+	// Assert
+	if err == nil {
+		t.Error("Expected error when never registered and Pulse unavailable")
+	}
+
+	// Arrange - previously registered
+	if err := SaveState(cfg.StateFile, &State{NodeID: testNodeID, APIToken: "npb_secret"}); err != nil {
+		t.Fatalf("Failed to save state: %v", err)
+	}
+
+	// Act
+	result, err := Register(context.Background(), registrar, cfg)
+
+	// Assert
+	if err != nil || result.NodeID != testNodeID || result.APIToken != "npb_secret" {
+		t.Errorf("Expected fallback to persisted identity, got %+v (err %v)", result, err)
+	}
+}
+
+// TestRegister_InvalidNodeIDFromPulse tests that a malformed ID is rejected
+func TestRegister_InvalidNodeIDFromPulse(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange
+	cfg := newTestConfig(t)
+	registrar := &mockRegistrar{nodeID: "not-a-uuid"}
+
+	// Act
+	_, err := Register(context.Background(), registrar, cfg)
+
+	// Assert
+	if err == nil {
+		t.Error("Expected error for invalid node_id from Pulse")
+	}
+}
+
+// TestReregister_UnknownNode tests re-registration of a node Pulse no longer knows
+func TestReregister_UnknownNode(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange - configured node_id that Pulse deleted
+	const newNodeID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	cfg := newTestConfig(t)
+	cfg.AutoRegister = false
+	cfg.NodeID = testNodeID
+	cfg.APIToken = "npb_old"
+	registrar := &mockRegistrar{nodeID: newNodeID, apiToken: "npb_new"}
+
+	// Act
+	result, err := Reregister(context.Background(), registrar, cfg)
+
+	// Assert - new identity returned and persisted
+	if err != nil || result.NodeID != newNodeID || result.APIToken != "npb_new" {
+		t.Fatalf("Expected new identity, got %+v (err %v)", result, err)
+	}
+	if registrar.requests[0].NodeID != testNodeID || registrar.tokens[0] != "npb_old" {
+		t.Errorf("Expected known identity in request, got %q (token %q)", registrar.requests[0].NodeID, registrar.tokens[0])
+	}
+	if state, err := LoadState(cfg.StateFile); err != nil || state == nil || state.NodeID != newNodeID {
+		t.Errorf("Expected new node_id persisted, got %+v (err %v)", state, err)
+	}
+
+	// Act - Pulse rejects registration: no fallback to the known identity
+	registrar.err = errors.New("registration disabled")
+	if _, err := Reregister(context.Background(), registrar, cfg); err == nil {
+		t.Error("Expected error when re-registration fails")
+	}
+}
+
+// TestDetectIP tests outbound IP detection against a loopback server
+func TestDetectIP(t *testing.T) {
+	// Act
+	ip, err := DetectIP("http://127.0.0.1:8080")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ip != "127.0.0.1" {
+		t.Errorf("Expected 127.0.0.1, got %s", ip)
+	}
+}
@@ -0,0 +1,79 @@
+package reporter
+
+import (
+	"math/rand"
+	"time"
+
+	"beacon/internal/config"
+)
+
+// maxBackoffShift bounds the exponential factor to avoid overflow
+const maxBackoffShift = 30
+
+// BackoffPolicy controls retries of a failed heartbeat report
+type BackoffPolicy struct {
+	MaxRetries int           // Send attempts per report
+	Interval   time.Duration // Base delay between attempts
+	MaxBackoff time.Duration // Upper bound for a single delay
+	Strategy   string        // config.Backoff* strategy
+}
+
+// DefaultBackoffPolicy returns the policy used without reconnect configuration:
+// 3 attempts with 1s, 2s exponential backoff
+func DefaultBackoffPolicy() BackoffPolicy {
+	return BackoffPolicy{
+		MaxRetries: MaxRetries,
+		Interval:   time.Second,
+		MaxBackoff: time.Minute,
+		Strategy:   config.BackoffExponential,
+	}
+}
+
+// BackoffPolicyFromConfig builds a policy from reconnect configuration.
+// Unset fields fall back to DefaultBackoffPolicy.
+func BackoffPolicyFromConfig(reconnect config.ReconnectConfig) BackoffPolicy {
+	policy := DefaultBackoffPolicy()
+	if reconnect.MaxRetries > 0 {
+		policy.MaxRetries = reconnect.MaxRetries
+	}
+	if reconnect.RetryInterval > 0 {
+		policy.Interval = time.Duration(reconnect.RetryInterval) * time.Second
+	}
+	if reconnect.MaxBackoff > 0 {
+		policy.MaxBackoff = time.Duration(reconnect.MaxBackoff) * time.Second
+	}
+	if reconnect.Backoff != "" {
+		policy.Strategy = reconnect.Backoff
+	}
+	return policy
+}
+
+// Delay returns the wait before retry number attempt (0 = first retry)
+func (p BackoffPolicy) Delay(attempt int) time.Duration {
+	var delay time.Duration
+	switch p.Strategy {
+	case config.BackoffConstant:
+		delay = p.Interval
+	case config.BackoffLinear:
+		delay = p.Interval * time.Duration(attempt+1)
+	default: // exponential, exponential_jitter
+		shift := attempt
+		if shift > maxBackoffShift {
+			shift = maxBackoffShift
+		}
+		delay = p.Interval << uint(shift)
+	}
+
+	if p.MaxBackoff > 0 && (delay > p.MaxBackoff || delay <= 0) {
+		delay = p.MaxBackoff
+	}
+
+	// Equal jitter: keep half the delay and randomize the rest, so beacons
+	// that lost Pulse at the same time do not retry in lockstep
+	if p.Strategy == config.BackoffExponentialJitter && delay > 1 {
+		half := delay / 2
+		delay = half + time.Duration(rand.Int63n(int64(delay-half)+1))
+	}
+
+	return delay
+}
@@ -0,0 +1,121 @@
+package reporter
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"beacon/internal/config"
+	"beacon/internal/models"
+)
+
+// TestBackoffPolicyDelay tests delays for each strategy, including the cap
+func TestBackoffPolicyDelay(t *testing.T) {
+	tests := []struct {
+		strategy string
+		want     []time.Duration
+	}{
+		{config.BackoffConstant, []time.Duration{2 * time.Second, 2 * time.Second, 2 * time.Second, 2 * time.Second}},
+		{config.BackoffLinear, []time.Duration{2 * time.Second, 4 * time.Second, 6 * time.Second, 8 * time.Second}},
+		{config.BackoffExponential, []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second}},
+	}
+
+	for _, tt := range tests {
+		policy := BackoffPolicy{MaxRetries: 5, Interval: 2 * time.Second, MaxBackoff: 10 * time.Second, Strategy: tt.strategy}
+		for attempt, want := range tt.want {
+			if got := policy.Delay(attempt); got != want {
+				t.Errorf("%s attempt %d: expected %s, got %s", tt.strategy, attempt, want, got)
+			}
+		}
+	}
+}
+
+// TestBackoffPolicyDelayJitter tests that jittered delays stay within [d/2, d] and the cap
+func TestBackoffPolicyDelayJitter(t *testing.T) {
+	policy := BackoffPolicy{MaxRetries: 5, Interval: time.Second, MaxBackoff: 5 * time.Second, Strategy: config.BackoffExponentialJitter}
+
+	for i := 0; i < 100; i++ {
+		if got := policy.Delay(1); got < time.Second || got > 2*time.Second {
+			t.Fatalf("attempt 1: expected delay in [1s, 2s], got %s", got)
+		}
+		if got := policy.Delay(10); got < 2500*time.Millisecond || got > 5*time.Second {
+			t.Fatalf("attempt 10: expected capped delay in [2.5s, 5s], got %s", got)
+		}
+	}
+}
+
+// TestBackoffPolicyDelayOverflow tests that large attempt numbers do not overflow past the cap
+func TestBackoffPolicyDelayOverflow(t *testing.T) {
+	policy := BackoffPolicy{MaxRetries: 100, Interval: 600 * time.Second, MaxBackoff: time.Hour, Strategy: config.BackoffExponential}
+
+	if got := policy.Delay(99); got != time.Hour {
+		t.Errorf("Expected delay capped at 1h, got %s", got)
+	}
+}
+
+// TestBackoffPolicyFromConfig tests mapping reconnect configuration to a policy
+func TestBackoffPolicyFromConfig(t *testing.T) {
+	// Zero config keeps the defaults
+	if got := BackoffPolicyFromConfig(config.ReconnectConfig{}); got != DefaultBackoffPolicy() {
+		t.Errorf("Expected default policy, got %+v", got)
+	}
+
+	got := BackoffPolicyFromConfig(config.ReconnectConfig{MaxRetries: 5, RetryInterval: 2, Backoff: "linear", MaxBackoff: 30})
+	want := BackoffPolicy{MaxRetries: 5, Interval: 2 * time.Second, MaxBackoff: 30 * time.Second, Strategy: "linear"}
+	if got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+// TestReportWithRetryUsesBackoffPolicy tests that the configured attempts are used
+func TestReportWithRetryUsesBackoffPolicy(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange - failing Pulse, 5 attempts with a short constant backoff
+	mockServer := NewMockPulseServer()
+	mockServer.SetResponseStatusCode(http.StatusInternalServerError)
+	defer mockServer.Close()
+
+	mockScheduler := &mockProbeScheduler{
+		tcpResults: []*models.TCPProbeResult{{Success: true, RTTMs: 100.0}},
+	}
+	reporter := NewHeartbeatReporter(NewPulseAPIClient(mockServer.GetURL(), 5*time.Second), "test-node-uuid", mockScheduler)
+	reporter.SetBackoffPolicy(BackoffPolicy{MaxRetries: 5, Interval: 10 * time.Millisecond, MaxBackoff: time.Second, Strategy: config.BackoffConstant})
+
+	// Act
+	start := time.Now()
+	reporter.reportWithRetry()
+
+	// Assert
+	if mockServer.GetRequestCount() != 5 {
+		t.Errorf("Expected 5 attempts, got %d", mockServer.GetRequestCount())
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected short constant backoff, took %s", elapsed)
+	}
+}
+
+// TestWaitBackoffInterruptedByStop tests that stopping the reporter interrupts a backoff wait
+func TestWaitBackoffInterruptedByStop(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange
+	stopped := make(chan struct{})
+	reporter := NewHeartbeatReporter(NewPulseAPIClient("https://pulse.example.com", 5*time.Second), "test-node-uuid", &mockProbeScheduler{})
+	reporter.stopped = stopped
+
+	// Act
+	done := make(chan bool, 1)
+	go func() { done <- reporter.waitBackoff(time.Minute) }()
+	close(stopped)
+
+	// Assert
+	select {
+	case completed := <-done:
+		if completed {
+			t.Error("Expected waitBackoff to report interruption")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waitBackoff was not interrupted by stop")
+	}
+}
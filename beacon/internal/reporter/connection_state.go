@@ -0,0 +1,153 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"beacon/internal/config"
+	"beacon/internal/logger"
+)
+
+// ConnectionStatusFileName is the reporter status file, kept next to state_file
+// so `beacon debug` can read the running daemon's upload health
+const ConnectionStatusFileName = "connection_status.json"
+
+// Connection statuses reported in ConnectionState.Status
+const (
+	ConnectionUnknown      = "unknown"
+	ConnectionConnected    = "connected"
+	ConnectionConnecting   = "connecting" // Retrying within a report cycle
+	ConnectionDisconnected = "disconnected"
+)
+
+// ConnectionState is a snapshot of heartbeat upload health
+type ConnectionState struct {
+	Status        string        `json:"status"`
+	LastSuccess   *time.Time    `json:"last_success,omitempty"`
+	LastFailure   *time.Time    `json:"last_failure,omitempty"`
+	FailureReason string        `json:"failure_reason,omitempty"`
+	RetryCount    int           `json:"retry_count"` // Consecutive failed attempts
+	Backoff       time.Duration `json:"backoff"`     // Current wait before the next attempt
+	NextRetry     *time.Time    `json:"next_retry,omitempty"`
+	SuccessTotal  uint64        `json:"success_total"` // Successful uploads since start
+	FailureTotal  uint64        `json:"failure_total"` // Failed upload attempts since start
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// ConnectionStateProvider exposes reporter connection state to diagnostics and metrics
+type ConnectionStateProvider interface {
+	ConnectionState() ConnectionState
+}
+
+// ConnectionStatusPath returns the status file path for cfg
+func ConnectionStatusPath(cfg *config.Config) string {
+	return filepath.Join(filepath.Dir(cfg.StateFile), ConnectionStatusFileName)
+}
+
+// ConnectionStatusFile reads the state persisted by a running reporter
+type ConnectionStatusFile string
+
+// ConnectionState implements ConnectionStateProvider. A missing or unreadable
+// file (beacon not running, or no report yet) yields ConnectionUnknown.
+func (f ConnectionStatusFile) ConnectionState() ConnectionState {
+	data, err := os.ReadFile(string(f))
+	if err != nil {
+		return ConnectionState{Status: ConnectionUnknown, FailureReason: "no connection status recorded (is beacon running?)"}
+	}
+
+	var state ConnectionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return ConnectionState{Status: ConnectionUnknown, FailureReason: fmt.Sprintf("invalid connection status file: %v", err)}
+	}
+	return state
+}
+
+// SetStatusFile persists connection state to path after every change
+func (r *HeartbeatReporter) SetStatusFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create status directory: %w", err)
+	}
+
+	r.stateMu.Lock()
+	defer r.stateMu.Unlock()
+	r.statusFile = path
+	return nil
+}
+
+// ConnectionState implements ConnectionStateProvider
+func (r *HeartbeatReporter) ConnectionState() ConnectionState {
+	r.stateMu.RLock()
+	defer r.stateMu.RUnlock()
+	return r.state
+}
+
+// recordSuccess marks a successful upload
+func (r *HeartbeatReporter) recordSuccess() {
+	r.updateState(func(state *ConnectionState, now time.Time) {
+		state.Status = ConnectionConnected
+		state.LastSuccess = &now
+		state.RetryCount = 0
+		state.Backoff = 0
+		state.NextRetry = nil
+		state.SuccessTotal++
+	})
+}
+
+// recordFailure marks a failed upload attempt. A positive backoff means the
+// attempt is retried after it; otherwise the next try is the next report.
+func (r *HeartbeatReporter) recordFailure(err error, backoff time.Duration) {
+	r.updateState(func(state *ConnectionState, now time.Time) {
+		state.LastFailure = &now
+		state.FailureReason = err.Error()
+		state.RetryCount++
+		state.FailureTotal++
+
+		state.Status = ConnectionConnecting
+		state.Backoff = backoff
+		if backoff <= 0 {
+			state.Status = ConnectionDisconnected
+			backoff = ReportInterval
+		}
+		nextRetry := now.Add(backoff)
+		state.NextRetry = &nextRetry
+	})
+}
+
+// updateState applies update and persists the result to the status file
+func (r *HeartbeatReporter) updateState(update func(state *ConnectionState, now time.Time)) {
+	r.stateMu.Lock()
+	now := time.Now()
+	update(&r.state, now)
+	r.state.UpdatedAt = now
+	state := r.state
+	statusFile := r.statusFile
+	r.stateMu.Unlock()
+
+	if statusFile == "" {
+		return
+	}
+	if err := writeConnectionStatus(statusFile, state); err != nil {
+		logger.WithFields(map[string]interface{}{"component": "reporter", "path": statusFile, "error": err.Error()}).Debug("Failed to write connection status file")
+	}
+}
+
+// writeConnectionStatus writes the status file atomically (write + rename)
+func writeConnectionStatus(path string, state ConnectionState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package reporter
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"beacon/internal/config"
+	"beacon/internal/models"
+)
+
+// TestConnectionStateTransitions tests state tracking for successes and failures
+func TestConnectionStateTransitions(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange
+	reporter := NewHeartbeatReporter(NewPulseAPIClient("https://pulse.example.com", 5*time.Second), "test-node-uuid", &mockProbeScheduler{})
+	if got := reporter.ConnectionState().Status; got != ConnectionUnknown {
+		t.Errorf("Expected initial status %s, got %s", ConnectionUnknown, got)
+	}
+
+	// Act - a retried failure
+	reporter.recordFailure(errors.New("connection refused"), 2*time.Second)
+
+	// Assert
+	state := reporter.ConnectionState()
+	if state.Status != ConnectionConnecting || state.RetryCount != 1 || state.Backoff != 2*time.Second {
+		t.Errorf("Unexpected state after retried failure: %+v", state)
+	}
+	if state.FailureReason != "connection refused" || state.LastFailure == nil || state.NextRetry == nil {
+		t.Errorf("Expected failure details, got %+v", state)
+	}
+
+	// Act - retries exhausted
+	reporter.recordFailure(errors.New("connection refused"), 0)
+
+	// Assert
+	state = reporter.ConnectionState()
+	if state.Status != ConnectionDisconnected || state.RetryCount != 2 || state.FailureTotal != 2 {
+		t.Errorf("Unexpected state after exhausted retries: %+v", state)
+	}
+
+	// Act - recovery
+	reporter.recordSuccess()
+
+	// Assert - consecutive failures reset, totals kept
+	state = reporter.ConnectionState()
+	if state.Status != ConnectionConnected || state.RetryCount != 0 || state.Backoff != 0 || state.NextRetry != nil {
+		t.Errorf("Unexpected state after success: %+v", state)
+	}
+	if state.SuccessTotal != 1 || state.FailureTotal != 2 || state.LastSuccess == nil {
+		t.Errorf("Expected totals 1/2 with last success, got %+v", state)
+	}
+}
+
+// TestConnectionStateFromReports tests that reportWithRetry records each attempt
+func TestConnectionStateFromReports(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange - failing Pulse with 2 quick attempts
+	mockServer := NewMockPulseServer()
+	mockServer.SetResponseStatusCode(http.StatusInternalServerError)
+	defer mockServer.Close()
+
+	mockScheduler := &mockProbeScheduler{
+		tcpResults: []*models.TCPProbeResult{{Success: true, RTTMs: 100.0}},
+	}
+	reporter := NewHeartbeatReporter(NewPulseAPIClient(mockServer.GetURL(), 5*time.Second), "test-node-uuid", mockScheduler)
+	reporter.SetBackoffPolicy(BackoffPolicy{MaxRetries: 2, Interval: 10 * time.Millisecond, MaxBackoff: time.Second, Strategy: config.BackoffConstant})
+
+	// Act
+	reporter.reportWithRetry()
+
+	// Assert
+	state := reporter.ConnectionState()
+	if state.Status != ConnectionDisconnected || state.FailureTotal != 2 || state.RetryCount != 2 {
+		t.Errorf("Expected disconnected after 2 failed attempts, got %+v", state)
+	}
+
+	// Act - Pulse recovers
+	mockServer.SetResponseStatusCode(http.StatusOK)
+	reporter.reportWithRetry()
+
+	// Assert
+	state = reporter.ConnectionState()
+	if state.Status != ConnectionConnected || state.SuccessTotal != 1 || state.RetryCount != 0 {
+		t.Errorf("Expected connected after recovery, got %+v", state)
+	}
+}
+
+// TestConnectionStatusFile tests persisting state for `beacon debug`
+func TestConnectionStatusFile(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange
+	path := filepath.Join(t.TempDir(), "state", ConnectionStatusFileName)
+	reporter := NewHeartbeatReporter(NewPulseAPIClient("https://pulse.example.com", 5*time.Second), "test-node-uuid", &mockProbeScheduler{})
+	if err := reporter.SetStatusFile(path); err != nil {
+		t.Fatalf("SetStatusFile failed: %v", err)
+	}
+
+	// Missing file before the first report
+	if got := ConnectionStatusFile(path).ConnectionState().Status; got != ConnectionUnknown {
+		t.Errorf("Expected %s before first report, got %s", ConnectionUnknown, got)
+	}
+
+	// Act
+	reporter.recordFailure(errors.New("timeout"), time.Second)
+
+	// Assert
+	state := ConnectionStatusFile(path).ConnectionState()
+	if state.Status != ConnectionConnecting || state.FailureReason != "timeout" || state.FailureTotal != 1 {
+		t.Errorf("Unexpected persisted state: %+v", state)
+	}
+}
@@ -7,62 +7,249 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
+	"strings"
 	"sync"
 	"time"
 
 	"beacon/internal/logger"
 	"beacon/internal/models"
+	"beacon/internal/outbox"
+	"beacon/internal/probe"
 )
 
 const (
 	// ReportInterval is the interval between heartbeat reports (60 seconds)
 	ReportInterval = 60 * time.Second
-	// MaxRetries is the maximum number of retry attempts for failed reports
+	// MaxRetries is the default number of attempts for failed reports (see BackoffPolicy)
 	MaxRetries = 3
 	// MaxUploadLatency is the maximum acceptable upload latency (NFR-PERF-001)
 	MaxUploadLatency = 5 * time.Second
+	// OutboxReplayBatchSize is the number of queued heartbeats sent per replay request
+	OutboxReplayBatchSize = 100
+	// OutboxReplayMaxBatches is the number of replay requests sent per report
+	OutboxReplayMaxBatches = 10
+	// OutboxReplayBudget bounds the time a report spends replaying, including
+	// backoff waits, so the next report is not delayed
+	OutboxReplayBudget = ReportInterval / 2
+	// ReportRetryBudget bounds the time a report spends retrying, including
+	// backoff waits; what is left goes to the outbox
+	ReportRetryBudget = ReportInterval
 )
 
-// HeartbeatData represents the heartbeat data structure for reporting to Pulse
+// HeartbeatData represents the heartbeat data structure for reporting to Pulse.
+// Each record describes a single configured probe; the probe identity fields
+// are empty only for node-level summaries built by AggregateMetrics.
 type HeartbeatData struct {
-	NodeID         string  `json:"node_id"`           // UUID from Pulse registration
-	LatencyMs      float64 `json:"latency_ms"`        // RTT mean in milliseconds
-	PacketLossRate float64 `json:"packet_loss_rate"`  // Packet loss rate (0-100%)
-	JitterMs       float64 `json:"jitter_ms"`         // Delay jitter in milliseconds
-	Timestamp      string  `json:"timestamp"`         // ISO 8601 timestamp
+	NodeID          string  `json:"node_id"`              // UUID from Pulse registration
+	ProbeID         string  `json:"probe_id,omitempty"`   // Pulse probe UUID or local probe key
+	ProbeType       string  `json:"probe_type,omitempty"` // tcp_ping, udp_ping, icmp_ping, http_probe, dns_probe, tls_probe or path_probe
+	Target          string  `json:"target,omitempty"`     // Probe target host
+	Port            int     `json:"port,omitempty"`       // Probe target port
+	Success         bool    `json:"success"`              // At least one sample succeeded
+	LatencyMs       float64 `json:"latency_ms"`           // RTT mean in milliseconds
+	LatencyMedianMs float64 `json:"latency_median_ms"`    // RTT median in milliseconds
+	VarianceMs      float64 `json:"variance_ms"`          // RTT variance in milliseconds²
+	PacketLossRate  float64 `json:"packet_loss_rate"`     // Packet loss rate (0-100%)
+	JitterMs        float64 `json:"jitter_ms"`            // Delay jitter in milliseconds
+	SampleCount     int     `json:"sample_count"`         // Number of sample points
+	Timestamp       string  `json:"timestamp"`            // ISO 8601 timestamp
+	Partial         bool    `json:"partial,omitempty"`    // Batch aborted early, metrics cover the completed attempts
+
+	// RTT statistics selected by the `statistics` config setting, omitted otherwise
+	LatencyMinMs    *float64 `json:"latency_min_ms,omitempty"`    // Lowest RTT
+	LatencyMaxMs    *float64 `json:"latency_max_ms,omitempty"`    // Highest RTT
+	LatencyStdDevMs *float64 `json:"latency_stddev_ms,omitempty"` // RTT standard deviation
+	LatencyP90Ms    *float64 `json:"latency_p90_ms,omitempty"`    // 90th percentile RTT
+	LatencyP95Ms    *float64 `json:"latency_p95_ms,omitempty"`    // 95th percentile RTT
+	LatencyP99Ms    *float64 `json:"latency_p99_ms,omitempty"`    // 99th percentile RTT
+	JitterRFC3550Ms *float64 `json:"jitter_rfc3550_ms,omitempty"` // RFC 3550 interarrival jitter
+
+	// Individual attempts of the probe batch, included when `report_samples` is enabled
+	Samples []models.RawSample `json:"samples,omitempty"`
+
+	// Type-specific metrics described by the probe type (probe.ResultMetric),
+	// keyed by heartbeat field and sent alongside the fields above
+	Metrics map[string]interface{} `json:"-"`
+}
+
+// heartbeatFields are the JSON names of the fixed HeartbeatData fields
+var heartbeatFields = func() map[string]bool {
+	fields := make(map[string]bool)
+	t := reflect.TypeOf(HeartbeatData{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}()
+
+// MarshalJSON encodes the record as a single object holding the fixed fields
+// and the type-specific metrics
+func (h HeartbeatData) MarshalJSON() ([]byte, error) {
+	type heartbeat HeartbeatData
+	data, err := json.Marshal(heartbeat(h))
+	if err != nil || len(h.Metrics) == 0 {
+		return data, err
+	}
+
+	metrics, err := json.Marshal(h.Metrics)
+	if err != nil {
+		return nil, err
+	}
+	// Both are non-empty objects: splice "{fixed}" and "{metrics}" into one
+	return append(append(data[:len(data)-1], ','), metrics[1:]...), nil
+}
+
+// UnmarshalJSON decodes a record encoded by MarshalJSON; fields other than
+// the fixed ones are kept as type-specific metrics
+func (h *HeartbeatData) UnmarshalJSON(data []byte) error {
+	type heartbeat HeartbeatData
+	if err := json.Unmarshal(data, (*heartbeat)(h)); err != nil {
+		return err
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	h.Metrics = nil
+	for name, value := range fields {
+		if heartbeatFields[name] {
+			continue
+		}
+		if h.Metrics == nil {
+			h.Metrics = make(map[string]interface{})
+		}
+		h.Metrics[name] = value
+	}
+	return nil
+}
+
+// HeartbeatBatch is the request body for the batched heartbeat endpoint
+type HeartbeatBatch struct {
+	NodeID     string           `json:"node_id"`
+	Heartbeats []*HeartbeatData `json:"heartbeats"`
+}
+
+// HeartbeatItemResult is the per-item status returned by the batched endpoint
+type HeartbeatItemResult struct {
+	Index    int    `json:"index"`
+	ProbeID  string `json:"probe_id"`
+	Accepted bool   `json:"accepted"`
+	Error    *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// ErrBatchNotSupported is returned when Pulse does not expose the batched endpoint
+var ErrBatchNotSupported = errors.New("pulse API does not support batched heartbeats")
+
+// APIError is returned when Pulse answers a heartbeat request with a non-200 status
+type APIError struct {
+	StatusCode int
+	Code       string // Pulse error code from the response body, if any
+	Body       string
+}
+
+// newAPIError builds an APIError from a Pulse error response
+func newAPIError(statusCode int, body []byte) *APIError {
+	var errResp struct {
+		Code string `json:"code"`
+	}
+	_ = json.Unmarshal(body, &errResp)
+	return &APIError{StatusCode: statusCode, Code: errResp.Code, Body: string(body)}
 }
 
+func (e *APIError) Error() string {
+	return fmt.Sprintf("pulse API returned error %d: %s", e.StatusCode, e.Body)
+}
+
+// isPermanentRejection reports whether Pulse refused the request itself, so
+// resending the same data cannot succeed. Authentication failures, unknown
+// nodes (fixed by re-registration), timeouts and rate limiting are transient
+// and retried.
+func isPermanentRejection(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode < 400 || apiErr.StatusCode >= 500 {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return true
+}
+
+// isUnknownNode reports whether Pulse rejected the request because it does not
+// know this node: the node was deleted (ERR_NODE_NOT_FOUND) or its API token
+// no longer resolves to a node (ERR_INVALID_NODE_TOKEN)
+func isUnknownNode(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return (apiErr.StatusCode == http.StatusNotFound && apiErr.Code == "ERR_NODE_NOT_FOUND") ||
+		(apiErr.StatusCode == http.StatusUnauthorized && apiErr.Code == "ERR_INVALID_NODE_TOKEN")
+}
+
+// ReregisterFunc registers the beacon again after Pulse reported its node as
+// unknown and returns the assigned node ID and API token
+type ReregisterFunc func(ctx context.Context) (nodeID, apiToken string, err error)
+
+// ReregisterInterval is the minimum time between re-registration attempts
+const ReregisterInterval = 5 * time.Minute
+
 // PulseAPIClient handles HTTP/HTTPS communication with Pulse server
 type PulseAPIClient struct {
 	serverURL  string
+	authToken  string // Per-node API token, sent as Bearer token
 	httpClient *http.Client
 	timeout    time.Duration
 }
 
 // ProbeScheduler interface for accessing probe results
 type ProbeScheduler interface {
-	GetLatestResults() ([]*models.TCPProbeResult, []*models.UDPProbeResult)
+	GetLatestResults() []*models.ProbeResult
 }
 
 // HeartbeatReporter manages scheduled heartbeat reporting to Pulse
 type HeartbeatReporter struct {
 	apiClient *PulseAPIClient
-	nodeID    string
+	nodeID    string // Guarded by mu, replaced by re-registration
 	scheduler ProbeScheduler
+	outbox    *outbox.Outbox    // Optional durable queue for undelivered heartbeats
+	backoff   BackoffPolicy     // Retry policy, guarded by mu (hot-reloadable)
+	stats     []string          // Statistics reported per probe, guarded by mu (hot-reloadable)
+	samples   bool              // Whether raw samples are reported, guarded by mu (hot-reloadable)
+	reported  map[string]string // Timestamp of the last result handed over per probe ID, guarded by mu
 	ticker    *time.Ticker
 	cancel    context.CancelFunc
+	stopped   <-chan struct{} // Closed when reporting stops; interrupts backoff waits
+
+	stateMu    sync.RWMutex
+	state      ConnectionState // Upload health, see ConnectionState()
+	statusFile string          // Optional file the state is persisted to
 	wg        sync.WaitGroup
 	mu        sync.Mutex
 	reporting bool
+
+	reregister     ReregisterFunc // Optional, called when Pulse does not know the node
+	lastReregister time.Time
 }
 
 // NewHeartbeatData creates a new HeartbeatData with current timestamp
 func NewHeartbeatData(nodeID string, latencyMs, packetLossRate, jitterMs float64) *HeartbeatData {
 	return &HeartbeatData{
 		NodeID:         nodeID,
+		Success:        packetLossRate < 100,
 		LatencyMs:      latencyMs,
 		PacketLossRate: packetLossRate,
 		JitterMs:       jitterMs,
@@ -72,14 +259,16 @@ func NewHeartbeatData(nodeID string, latencyMs, packetLossRate, jitterMs float64
 
 // NewPulseAPIClient creates a new Pulse API client with TLS support
 func NewPulseAPIClient(serverURL string, timeout time.Duration) *PulseAPIClient {
-	// Create HTTP client with TLS config
+	return NewPulseAPIClientWithTLS(serverURL, timeout, &tls.Config{
+		MinVersion: tls.VersionTLS12, // Enforce TLS 1.2 or higher (NFR-SEC-001)
+	})
+}
+
+// NewPulseAPIClientWithTLS creates a new Pulse API client using the given TLS
+// config (custom CA bundle, client certificate for mTLS, server name)
+func NewPulseAPIClientWithTLS(serverURL string, timeout time.Duration, tlsConfig *tls.Config) *PulseAPIClient {
 	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			MinVersion:         tls.VersionTLS12, // Enforce TLS 1.2 or higher (NFR-SEC-001)
-			InsecureSkipVerify: false,            // Require certificate validation
-			// In production, you may want to add:
-			// RootCAs: customCertPool,
-		},
+		TLSClientConfig: tlsConfig,
 	}
 
 	httpClient := &http.Client{
@@ -94,16 +283,106 @@ func NewPulseAPIClient(serverURL string, timeout time.Duration) *PulseAPIClient
 	}
 }
 
+// SetAuthToken sets the per-node API token sent in the Authorization header
+func (c *PulseAPIClient) SetAuthToken(authToken string) {
+	c.authToken = authToken
+}
+
+// setHeaders sets the common request headers for Pulse API calls
+func (c *PulseAPIClient) setHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+}
+
 // NewHeartbeatReporter creates a new HeartbeatReporter with probe scheduler integration
 func NewHeartbeatReporter(apiClient *PulseAPIClient, nodeID string, scheduler ProbeScheduler) *HeartbeatReporter {
 	return &HeartbeatReporter{
 		apiClient: apiClient,
 		nodeID:    nodeID,
 		scheduler: scheduler,
+		backoff:   DefaultBackoffPolicy(),
+		state:     ConnectionState{Status: ConnectionUnknown},
 		reporting: false,
 	}
 }
 
+// SetBackoffPolicy replaces the retry policy used by subsequent reports
+func (r *HeartbeatReporter) SetBackoffPolicy(policy BackoffPolicy) {
+	r.mu.Lock()
+	changed := r.backoff != policy
+	r.backoff = policy
+	r.mu.Unlock()
+
+	if changed {
+		logger.WithFields(map[string]interface{}{
+			"component":   "reporter",
+			"max_retries": policy.MaxRetries,
+			"interval":    policy.Interval.String(),
+			"max_backoff": policy.MaxBackoff.String(),
+			"strategy":    policy.Strategy,
+		}).Info("Heartbeat retry policy updated")
+	}
+}
+
+// SetReregister sets the function used to register again when Pulse reports
+// the node as unknown. Without it such heartbeats are retried unchanged.
+func (r *HeartbeatReporter) SetReregister(fn ReregisterFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reregister = fn
+}
+
+// NodeID returns the node ID heartbeats are currently reported for
+func (r *HeartbeatReporter) NodeID() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.nodeID
+}
+
+// SetStatistics selects the RTT statistics (models.StatisticNames) included
+// in subsequent heartbeats
+func (r *HeartbeatReporter) SetStatistics(statistics []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats = append([]string(nil), statistics...)
+}
+
+// statistics returns the RTT statistics currently selected for reporting
+func (r *HeartbeatReporter) statistics() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats
+}
+
+// SetReportSamples enables or disables raw samples in subsequent heartbeats
+func (r *HeartbeatReporter) SetReportSamples(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples = enabled
+}
+
+// reportSamples returns whether raw samples are currently reported
+func (r *HeartbeatReporter) reportSamples() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.samples
+}
+
+// backoffPolicy returns the current retry policy
+func (r *HeartbeatReporter) backoffPolicy() BackoffPolicy {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.backoff
+}
+
+// SetOutbox enables queueing of heartbeats that could not be delivered.
+// Must be called before StartReporting.
+func (r *HeartbeatReporter) SetOutbox(o *outbox.Outbox) {
+	r.outbox = o
+}
+
 // SendHeartbeat sends heartbeat data to Pulse server with latency measurement
 func (c *PulseAPIClient) SendHeartbeat(data *HeartbeatData) error {
 	// Measure upload latency (NFR-PERF-001)
@@ -122,7 +401,7 @@ func (c *PulseAPIClient) SendHeartbeat(data *HeartbeatData) error {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	c.setHeaders(req)
 
 	// Send request with timeout
 	resp, err := c.httpClient.Do(req)
@@ -138,7 +417,7 @@ func (c *PulseAPIClient) SendHeartbeat(data *HeartbeatData) error {
 	if resp.StatusCode != http.StatusOK {
 		// Read error response body for debugging
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("pulse API returned error %d: %s", resp.StatusCode, string(body))
+		return newAPIError(resp.StatusCode, body)
 	}
 
 	// Validate upload latency
@@ -150,27 +429,167 @@ func (c *PulseAPIClient) SendHeartbeat(data *HeartbeatData) error {
 	return nil
 }
 
-// AggregateMetrics aggregates metrics from TCP and UDP probe results
-func (r *HeartbeatReporter) AggregateMetrics(tcpResults []*models.TCPProbeResult, udpResults []*models.UDPProbeResult) *HeartbeatData {
-	var totalLatency, totalPacketLoss, totalJitter float64
-	count := 0
+// SendHeartbeatBatch sends all probe heartbeats of this node in one request
+// and returns the per-item accept/reject status reported by Pulse.
+// Returns ErrBatchNotSupported if Pulse predates the batched endpoint.
+func (c *PulseAPIClient) SendHeartbeatBatch(nodeID string, records []*HeartbeatData) ([]HeartbeatItemResult, error) {
+	// Measure upload latency (NFR-PERF-001)
+	startTime := time.Now()
 
-	// Aggregate TCP probe results (only successful probes)
-	for _, result := range tcpResults {
-		if result.Success {
-			totalLatency += result.RTTMs
-			totalPacketLoss += result.PacketLossRate
-			totalJitter += result.JitterMs
-			count++
+	jsonData, err := json.Marshal(&HeartbeatBatch{NodeID: nodeID, Heartbeats: records})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal heartbeat batch: %w", err)
+	}
+
+	url := c.serverURL + "/api/v1/beacon/heartbeats"
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	elapsed := time.Since(startTime)
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		apiErr := newAPIError(resp.StatusCode, body)
+		if resp.StatusCode == http.StatusNotFound && apiErr.Code == "" {
+			return nil, ErrBatchNotSupported
 		}
+		return nil, apiErr
+	}
+
+	var batchResp struct {
+		Data struct {
+			Accepted int                   `json:"accepted"`
+			Rejected int                   `json:"rejected"`
+			Results  []HeartbeatItemResult `json:"results"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &batchResp); err != nil {
+		return nil, fmt.Errorf("failed to parse batch response: %w", err)
 	}
 
-	// Aggregate UDP probe results (only successful probes)
-	for _, result := range udpResults {
-		if result.Success {
-			totalLatency += result.RTTMs
-			totalPacketLoss += result.PacketLossRate
-			totalJitter += result.JitterMs
+	if elapsed > MaxUploadLatency {
+		logger.WithFields(map[string]interface{}{"component": "reporter", "latency": elapsed.String(), "threshold": MaxUploadLatency.String()}).Warn("Heartbeat upload latency exceeds requirement")
+	}
+
+	logger.WithFields(map[string]interface{}{"component": "reporter", "latency": elapsed.String(), "accepted": batchResp.Data.Accepted, "rejected": batchResp.Data.Rejected}).Info("Heartbeat batch reported successfully")
+	return batchResp.Data.Results, nil
+}
+
+// BuildProbeHeartbeats converts the latest probe results into one heartbeat
+// record per probe, so Pulse can tell which target degraded. Nil entries
+// (probes that have not produced a result yet) are skipped.
+func (r *HeartbeatReporter) BuildProbeHeartbeats(results []*models.ProbeResult) []*HeartbeatData {
+	records := make([]*HeartbeatData, 0, len(results))
+	nodeID := r.NodeID()
+	statistics := r.statistics()
+	reportSamples := r.reportSamples()
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		probeID := result.ProbeID
+		if probeID == "" {
+			probeID = models.ProbeKey(result.Type, result.Target, result.Port)
+		}
+		record := &HeartbeatData{
+			NodeID:          nodeID,
+			ProbeID:         probeID,
+			ProbeType:       result.Type,
+			Target:          result.Target,
+			Port:            result.Port,
+			Success:         result.Success,
+			LatencyMs:       result.MetricFloat(models.MetricRTTMs),
+			LatencyMedianMs: result.MetricFloat(models.MetricRTTMedianMs),
+			VarianceMs:      result.MetricFloat(models.MetricVarianceMs),
+			PacketLossRate:  result.MetricFloat(models.MetricPacketLossRate),
+			JitterMs:        result.MetricFloat(models.MetricJitterMs),
+			SampleCount:     int(result.MetricFloat(models.MetricSampleCount)),
+			Timestamp:       heartbeatTimestamp(result.Timestamp),
+			Partial:         result.Partial,
+		}
+		setMetricFields(record, result)
+		setStatisticFields(record, result.Statistics(), statistics)
+		if reportSamples {
+			record.Samples = result.Samples
+		}
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// setMetricFields copies the type-specific metrics the probe type reports
+// into the heartbeat record
+func setMetricFields(record *HeartbeatData, result *models.ProbeResult) {
+	for _, metric := range probe.ResultMetrics(result.Type) {
+		value, ok := metric.Value(result)
+		if !ok {
+			continue
+		}
+		if record.Metrics == nil {
+			record.Metrics = make(map[string]interface{})
+		}
+		record.Metrics[metric.HeartbeatField()] = value
+	}
+}
+
+// setStatisticFields copies the selected RTT statistics into the heartbeat record
+func setStatisticFields(record *HeartbeatData, stats models.RTTStatistics, selected []string) {
+	for _, name := range selected {
+		value, ok := stats.Value(name)
+		if !ok {
+			continue
+		}
+		switch name {
+		case models.StatisticMin:
+			record.LatencyMinMs = &value
+		case models.StatisticMax:
+			record.LatencyMaxMs = &value
+		case models.StatisticStdDev:
+			record.LatencyStdDevMs = &value
+		case models.StatisticP90:
+			record.LatencyP90Ms = &value
+		case models.StatisticP95:
+			record.LatencyP95Ms = &value
+		case models.StatisticP99:
+			record.LatencyP99Ms = &value
+		case models.StatisticJitterRFC3550:
+			record.JitterRFC3550Ms = &value
+		}
+	}
+}
+
+// heartbeatTimestamp returns the probe timestamp, falling back to now when unset
+func heartbeatTimestamp(timestamp string) string {
+	if timestamp == "" {
+		return time.Now().Format(time.RFC3339)
+	}
+	return timestamp
+}
+
+// AggregateMetrics aggregates metrics from probe results into a single
+// node-level summary (used for logging; Pulse receives per-probe records)
+func (r *HeartbeatReporter) AggregateMetrics(results []*models.ProbeResult) *HeartbeatData {
+	var totalLatency, totalPacketLoss, totalJitter float64
+	count := 0
+
+	// Aggregate probe results (only successful probes)
+	for _, result := range results {
+		if result != nil && result.Success {
+			totalLatency += result.MetricFloat(models.MetricRTTMs)
+			totalPacketLoss += result.MetricFloat(models.MetricPacketLossRate)
+			totalJitter += result.MetricFloat(models.MetricJitterMs)
 			count++
 		}
 	}
@@ -180,7 +599,7 @@ func (r *HeartbeatReporter) AggregateMetrics(tcpResults []*models.TCPProbeResult
 	// "low latency/jitter" but is the only valid JSON representation
 	if count == 0 {
 		return &HeartbeatData{
-			NodeID:         r.nodeID,
+			NodeID:         r.NodeID(),
 			LatencyMs:      0,   // 0 with 100% loss means "no successful probes"
 			PacketLossRate: 100, // All probes failed
 			JitterMs:       0,   // 0 with 100% loss means "no successful probes"
@@ -190,7 +609,7 @@ func (r *HeartbeatReporter) AggregateMetrics(tcpResults []*models.TCPProbeResult
 
 	// Calculate averages
 	return &HeartbeatData{
-		NodeID:         r.nodeID,
+		NodeID:         r.NodeID(),
 		LatencyMs:      totalLatency / float64(count),
 		PacketLossRate: totalPacketLoss / float64(count),
 		JitterMs:       totalJitter / float64(count),
@@ -212,6 +631,7 @@ func (r *HeartbeatReporter) StartReporting(ctx context.Context) {
 
 	// Create cancellable context
 	ctx, r.cancel = context.WithCancel(ctx)
+	r.stopped = ctx.Done()
 	r.mu.Unlock()
 
 	logger.WithFields(map[string]interface{}{"component": "reporter", "interval": ReportInterval.String()}).Info("Starting heartbeat reporter")
@@ -256,28 +676,368 @@ func (r *HeartbeatReporter) StopReporting() {
 	r.wg.Wait()
 }
 
-// reportWithRetry sends heartbeat with retry mechanism (max 3 retries, exponential backoff)
+// reportWithRetry sends one heartbeat per probe, retrying according to the
+// backoff policy within ReportRetryBudget. Only records that failed are
+// retried on subsequent attempts.
 func (r *HeartbeatReporter) reportWithRetry() {
-	// Get latest probe results from scheduler
-	tcpResults, udpResults := r.scheduler.GetLatestResults()
+	// Get latest probe results from scheduler. Probes run at most every
+	// ReportInterval, so results already reported are skipped.
+	results := r.scheduler.GetLatestResults()
 
-	// Aggregate metrics from actual probe results
-	data := r.AggregateMetrics(tcpResults, udpResults)
+	// Build per-probe records from actual probe results
+	pending := r.BuildProbeHeartbeats(r.unreportedResults(results))
 
-	for attempt := 0; attempt < MaxRetries; attempt++ {
-		err := r.apiClient.SendHeartbeat(data)
+	// Keep delivery order: queue new records behind the backlog, then replay it
+	if r.outbox != nil && r.outbox.Len() > 0 {
+		r.queueHeartbeats(pending)
+		r.markReported(pending, nil)
+		r.replayOutbox()
+		return
+	}
+
+	if len(pending) == 0 {
+		logger.WithField("component", "reporter").Debug("No new probe results available, skipping heartbeat report")
+		return
+	}
+	records := pending
+
+	summary := r.AggregateMetrics(results)
+	logger.WithFields(map[string]interface{}{
+		"component":        "reporter",
+		"probe_count":      len(pending),
+		"latency_ms":       summary.LatencyMs,
+		"packet_loss_rate": summary.PacketLossRate,
+		"jitter_ms":        summary.JitterMs,
+	}).Debug("Reporting per-probe heartbeats")
+
+	policy := r.backoffPolicy()
+	deadline := time.Now().Add(ReportRetryBudget)
+	attempts := 0
+	for attempt := 0; attempt < policy.MaxRetries; attempt++ {
+		attempts++
+		remaining, err := r.sendHeartbeats(pending)
 		if err == nil {
+			r.recordSuccess()
+			r.markReported(records, nil)
 			return // Success
 		}
+		pending = remaining
+		logger.WithFields(map[string]interface{}{"component": "reporter", "probe_count": len(pending), "attempt": attempt + 1, "max_retries": policy.MaxRetries, "error": err.Error()}).Error("Heartbeat report failed")
+
+		// Stop once the next attempt would run past the budget, so a long
+		// backoff policy never delays the following reports
+		delay := policy.Delay(attempt)
+		if attempt == policy.MaxRetries-1 || time.Now().Add(delay).After(deadline) {
+			r.recordFailure(err, 0)
+			break
+		}
+		r.recordFailure(err, delay)
+		if !r.waitBackoff(delay) {
+			break // Reporter stopping, keep what is left for the outbox
+		}
+	}
+
+	if r.outbox != nil {
+		r.queueHeartbeats(pending)
+		r.markReported(records, nil)
+		return
+	}
+	// Dropped results are sent again on the next report if still the latest
+	r.markReported(records, pending)
+	logger.WithFields(map[string]interface{}{"component": "reporter", "attempts": attempts, "dropped": len(pending)}).Error("Heartbeat report failed after retries, giving up")
+}
+
+// unreportedResults returns the results newer than the last result reported
+// for their probe. Results without a timestamp are always returned.
+func (r *HeartbeatReporter) unreportedResults(results []*models.ProbeResult) []*models.ProbeResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := make(map[string]bool, len(results))
+	unreported := make([]*models.ProbeResult, 0, len(results))
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		probeID := result.ProbeID
+		if probeID == "" {
+			probeID = models.ProbeKey(result.Type, result.Target, result.Port)
+		}
+		current[probeID] = true
+		if last, ok := r.reported[probeID]; ok && result.Timestamp != "" && !isNewerTimestamp(result.Timestamp, last) {
+			continue
+		}
+		unreported = append(unreported, result)
+	}
+
+	// Forget removed probes
+	for probeID := range r.reported {
+		if !current[probeID] {
+			delete(r.reported, probeID)
+		}
+	}
+	return unreported
+}
 
-		logger.WithFields(map[string]interface{}{"component": "reporter", "attempt": attempt + 1, "max_retries": MaxRetries, "error": err.Error()}).Error("Heartbeat report failed")
+// markReported records the records handed over to Pulse or the outbox,
+// except the dropped ones
+func (r *HeartbeatReporter) markReported(records, dropped []*HeartbeatData) {
+	skip := make(map[*HeartbeatData]bool, len(dropped))
+	for _, record := range dropped {
+		skip[record] = true
+	}
 
-		if attempt < MaxRetries-1 {
-			// Exponential backoff: 1s, 2s, 4s
-			backoff := time.Duration(1<<uint(attempt)) * time.Second
-			time.Sleep(backoff)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.reported == nil {
+		r.reported = make(map[string]string)
+	}
+	for _, record := range records {
+		if !skip[record] {
+			r.reported[record.ProbeID] = record.Timestamp
 		}
 	}
+}
 
-	logger.WithFields(map[string]interface{}{"component": "reporter", "attempts": MaxRetries}).Error("Heartbeat report failed after retries, giving up")
+// isNewerTimestamp reports whether timestamp is later than last. Timestamps
+// that do not parse as RFC 3339 are newer whenever they differ.
+func isNewerTimestamp(timestamp, last string) bool {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return timestamp != last
+	}
+	l, err := time.Parse(time.RFC3339, last)
+	if err != nil {
+		return timestamp != last
+	}
+	return t.After(l)
+}
+
+// waitBackoff sleeps for delay and returns false if reporting stopped meanwhile
+func (r *HeartbeatReporter) waitBackoff(delay time.Duration) bool {
+	r.mu.Lock()
+	stopped := r.stopped
+	r.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-stopped:
+		return false
+	}
+}
+
+// queueHeartbeats appends undelivered records to the outbox
+func (r *HeartbeatReporter) queueHeartbeats(records []*HeartbeatData) {
+	if len(records) == 0 {
+		return
+	}
+
+	payloads := make([]interface{}, len(records))
+	for i, record := range records {
+		payloads[i] = record
+	}
+	if err := r.outbox.Enqueue(payloads...); err != nil {
+		logger.WithFields(map[string]interface{}{"component": "reporter", "dropped": len(records), "error": err.Error()}).Error("Failed to queue undelivered heartbeats")
+		return
+	}
+
+	stats := r.outbox.Stats()
+	logger.WithFields(map[string]interface{}{"component": "reporter", "queued": len(records), "queue_size": stats.Size}).Warn("Queued undelivered heartbeats in outbox")
+}
+
+// replayOutbox sends queued heartbeats oldest first, at most
+// OutboxReplayMaxBatches requests within OutboxReplayBudget per report, so a
+// large backlog drains over several reports without delaying them.
+// Consecutive batches are spaced by the base retry delay so the backlog does
+// not hit a recovering Pulse back-to-back, and a failed batch is retried
+// according to the backoff policy; undelivered entries stay queued for the
+// next report.
+func (r *HeartbeatReporter) replayOutbox() {
+	replayed := 0
+	defer func() {
+		if replayed > 0 {
+			logger.WithFields(map[string]interface{}{"component": "reporter", "replayed": replayed, "queue_size": r.outbox.Len()}).Info("Replayed queued heartbeats")
+		}
+	}()
+
+	policy := r.backoffPolicy()
+	deadline := time.Now().Add(OutboxReplayBudget)
+	attempt := 0
+	for sent := 1; ; sent++ {
+		entries, err := r.outbox.Peek(OutboxReplayBatchSize)
+		if err != nil {
+			logger.WithFields(map[string]interface{}{"component": "reporter", "error": err.Error()}).Error("Failed to read heartbeat outbox")
+			return
+		}
+		if len(entries) == 0 {
+			return
+		}
+
+		// Unreadable entries can never be delivered and are acked with the batch
+		records := make([]*HeartbeatData, 0, len(entries))
+		seqByRecord := make(map[*HeartbeatData]uint64, len(entries))
+		acked := make([]uint64, 0, len(entries))
+		for _, entry := range entries {
+			var record HeartbeatData
+			if err := json.Unmarshal(entry.Payload, &record); err != nil {
+				acked = append(acked, entry.Seq)
+				continue
+			}
+			records = append(records, &record)
+			seqByRecord[&record] = entry.Seq
+		}
+
+		remaining, sendErr := r.sendHeartbeats(records)
+		undelivered := make(map[*HeartbeatData]bool, len(remaining))
+		for _, record := range remaining {
+			undelivered[record] = true
+		}
+		for _, record := range records {
+			if !undelivered[record] {
+				acked = append(acked, seqByRecord[record])
+			}
+		}
+		replayed += len(records) - len(remaining)
+
+		if err := r.outbox.Ack(acked...); err != nil {
+			logger.WithFields(map[string]interface{}{"component": "reporter", "error": err.Error()}).Error("Failed to update heartbeat outbox")
+			return
+		}
+		if sendErr != nil {
+			if attempt >= policy.MaxRetries-1 || sent >= OutboxReplayMaxBatches {
+				r.recordFailure(sendErr, 0)
+				logger.WithFields(map[string]interface{}{"component": "reporter", "queue_size": r.outbox.Len(), "attempts": attempt + 1, "error": sendErr.Error()}).Warn("Heartbeat outbox replay failed, retrying on next report")
+				return
+			}
+			delay := policy.Delay(attempt)
+			r.recordFailure(sendErr, delay)
+			logger.WithFields(map[string]interface{}{"component": "reporter", "queue_size": r.outbox.Len(), "attempt": attempt + 1, "max_retries": policy.MaxRetries, "error": sendErr.Error()}).Warn("Heartbeat outbox replay failed")
+			attempt++
+			if !r.waitReplay(delay, deadline) {
+				return // Out of budget or reporter stopping, entries stay queued
+			}
+			continue
+		}
+		r.recordSuccess()
+		attempt = 0
+		if r.outbox.Len() == 0 || sent >= OutboxReplayMaxBatches || !r.waitReplay(policy.Delay(0), deadline) {
+			return // Drained, or the rest is replayed on the next report
+		}
+	}
+}
+
+// waitReplay waits delay before the next replay request and returns false if
+// that would exceed the replay deadline or reporting stopped meanwhile
+func (r *HeartbeatReporter) waitReplay(delay time.Duration, deadline time.Time) bool {
+	if time.Now().Add(delay).After(deadline) {
+		return false
+	}
+	return r.waitBackoff(delay)
+}
+
+// sendHeartbeats reports records for the current node ID and returns the
+// records that still need to be sent. When Pulse does not know the node, the
+// beacon registers again and resends with the new identity.
+func (r *HeartbeatReporter) sendHeartbeats(records []*HeartbeatData) ([]*HeartbeatData, error) {
+	remaining, err := r.deliverHeartbeats(records)
+	if isUnknownNode(err) && r.reregisterNode(err) {
+		return r.deliverHeartbeats(remaining)
+	}
+	return remaining, err
+}
+
+// reregisterNode registers the node again, at most once per ReregisterInterval,
+// and returns true if heartbeats should be resent with the new identity
+func (r *HeartbeatReporter) reregisterNode(cause error) bool {
+	r.mu.Lock()
+	reregister := r.reregister
+	due := time.Since(r.lastReregister) >= ReregisterInterval
+	if reregister != nil && due {
+		r.lastReregister = time.Now()
+	}
+	r.mu.Unlock()
+	if reregister == nil || !due {
+		return false
+	}
+
+	logger.WithFields(map[string]interface{}{"component": "reporter", "node_id": r.NodeID(), "error": cause.Error()}).Warn("Pulse does not know this node, registering again")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	nodeID, apiToken, err := reregister(ctx)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{"component": "reporter", "error": err.Error()}).Error("Re-registration failed, heartbeats stay undelivered")
+		return false
+	}
+
+	r.mu.Lock()
+	r.nodeID = nodeID
+	r.mu.Unlock()
+	r.apiClient.SetAuthToken(apiToken)
+	logger.WithFields(map[string]interface{}{"component": "reporter", "node_id": nodeID}).Info("Node registered again, resending heartbeats")
+	return true
+}
+
+// deliverHeartbeats reports all records in one batched request and returns the
+// records that still need to be sent. Items rejected by Pulse, per item or
+// with a permanent 4xx for the whole request, are logged and dropped, since
+// resending invalid data cannot succeed.
+// Falls back to one request per record when Pulse lacks the batched endpoint.
+func (r *HeartbeatReporter) deliverHeartbeats(records []*HeartbeatData) ([]*HeartbeatData, error) {
+	// Queued records may predate a re-registration
+	nodeID := r.NodeID()
+	for _, record := range records {
+		record.NodeID = nodeID
+	}
+
+	results, err := r.apiClient.SendHeartbeatBatch(nodeID, records)
+	if errors.Is(err, ErrBatchNotSupported) {
+		return r.sendHeartbeatsIndividually(records)
+	}
+	if isPermanentRejection(err) {
+		logger.WithFields(map[string]interface{}{"component": "reporter", "dropped": len(records), "error": err.Error()}).Error("Heartbeat batch rejected by Pulse, dropping")
+		return nil, nil
+	}
+	if err != nil {
+		return records, err
+	}
+
+	for _, result := range results {
+		if result.Accepted {
+			continue
+		}
+		fields := map[string]interface{}{"component": "reporter", "probe_id": result.ProbeID}
+		if result.Error != nil {
+			fields["code"] = result.Error.Code
+			fields["reason"] = result.Error.Message
+		}
+		logger.WithFields(fields).Warn("Heartbeat rejected by Pulse")
+	}
+	return nil, nil
+}
+
+// sendHeartbeatsIndividually sends one request per record (legacy Pulse) and
+// returns the records that failed, so a retry only resends failures. Records
+// rejected with a permanent 4xx are logged and dropped.
+func (r *HeartbeatReporter) sendHeartbeatsIndividually(records []*HeartbeatData) ([]*HeartbeatData, error) {
+	var lastErr error
+	failed := make([]*HeartbeatData, 0)
+	for _, data := range records {
+		err := r.apiClient.SendHeartbeat(data)
+		if isPermanentRejection(err) {
+			logger.WithFields(map[string]interface{}{"component": "reporter", "probe_id": data.ProbeID, "error": err.Error()}).Warn("Heartbeat rejected by Pulse")
+			continue
+		}
+		if err != nil {
+			lastErr = err
+			failed = append(failed, data)
+		}
+	}
+	if lastErr != nil {
+		return failed, fmt.Errorf("%d of %d heartbeats failed: %w", len(failed), len(records), lastErr)
+	}
+	return nil, nil
 }
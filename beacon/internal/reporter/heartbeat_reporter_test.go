@@ -4,12 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"beacon/internal/config"
 	"beacon/internal/logger"
 	"beacon/internal/models"
+	"beacon/internal/outbox"
 )
 
 // TestHeartbeatDataSerialization tests that HeartbeatData can be serialized to JSON correctly
@@ -52,6 +55,36 @@ func TestHeartbeatDataSerialization(t *testing.T) {
 	}
 }
 
+// TestHeartbeatDataJSON_Metrics tests that type-specific metrics are encoded
+// as top-level fields and survive a round trip through the outbox encoding
+func TestHeartbeatDataJSON_Metrics(t *testing.T) {
+	// Arrange
+	data := &HeartbeatData{
+		NodeID:    "test-node-uuid-123",
+		ProbeType: "dns_probe",
+		Timestamp: "2026-01-30T12:34:56Z",
+		Metrics:   map[string]interface{}{"rcode": 0, "answers_match": true},
+	}
+
+	// Act
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("Failed to marshal heartbeat data: %v", err)
+	}
+	var decoded HeartbeatData
+	if err := json.Unmarshal(jsonData, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal heartbeat data: %v", err)
+	}
+
+	// Assert
+	if !strings.Contains(string(jsonData), `"probe_type":"dns_probe"`) || !strings.Contains(string(jsonData), `"rcode":0`) || !strings.Contains(string(jsonData), `"answers_match":true`) {
+		t.Errorf("Unexpected heartbeat JSON: %s", jsonData)
+	}
+	if decoded.ProbeType != "dns_probe" || len(decoded.Metrics) != 2 || decoded.Metrics["rcode"] != 0.0 || decoded.Metrics["answers_match"] != true {
+		t.Errorf("Unexpected decoded heartbeat: %+v", decoded)
+	}
+}
+
 // TestHeartbeatDataJSONFormat tests the JSON field names match the API specification
 func TestHeartbeatDataJSONFormat(t *testing.T) {
 	// Arrange
@@ -186,10 +219,32 @@ func TestHeartbeatReporterCreation(t *testing.T) {
 type mockProbeScheduler struct {
 	tcpResults []*models.TCPProbeResult
 	udpResults []*models.UDPProbeResult
+	results    []*models.ProbeResult
 }
 
-func (m *mockProbeScheduler) GetLatestResults() ([]*models.TCPProbeResult, []*models.UDPProbeResult) {
-	return m.tcpResults, m.udpResults
+func (m *mockProbeScheduler) GetLatestResults() []*models.ProbeResult {
+	return probeResults(m.tcpResults, m.udpResults, m.results...)
+}
+
+// probeResults converts TCP and UDP results to generic results as the
+// scheduler does, followed by the given generic results
+func probeResults(tcpResults []*models.TCPProbeResult, udpResults []*models.UDPProbeResult, results ...*models.ProbeResult) []*models.ProbeResult {
+	all := make([]*models.ProbeResult, 0, len(tcpResults)+len(udpResults)+len(results))
+	for _, result := range tcpResults {
+		if result == nil {
+			all = append(all, nil) // Probe without a result yet
+			continue
+		}
+		all = append(all, result.ToGenericResult())
+	}
+	for _, result := range udpResults {
+		if result == nil {
+			all = append(all, nil)
+			continue
+		}
+		all = append(all, result.ToGenericResult())
+	}
+	return append(all, results...)
 }
 
 // TestAggregateMetricsFromTCPProbes tests aggregating metrics from multiple successful TCP probes
@@ -221,7 +276,7 @@ func TestAggregateMetricsFromTCPProbes(t *testing.T) {
 	}
 
 	// Act
-	data := reporter.AggregateMetrics(tcpResults, nil)
+	data := reporter.AggregateMetrics(probeResults(tcpResults, nil))
 
 	// Assert - averages should be (100+200+150)/3, (0+1+0.5)/3, (2+3+2.5)/3
 	if data.NodeID != "test-node-id" {
@@ -244,7 +299,7 @@ func TestAggregateMetricsNoProbes(t *testing.T) {
 	reporter := NewHeartbeatReporter(NewPulseAPIClient("https://pulse.example.com", 5*time.Second), "test-node-id", &mockProbeScheduler{})
 
 	// Act
-	data := reporter.AggregateMetrics(nil, nil)
+	data := reporter.AggregateMetrics(nil)
 
 	// Assert - default values should be used
 	if data.NodeID != "test-node-id" {
@@ -286,7 +341,7 @@ func TestAggregateMetricsPartialFailures(t *testing.T) {
 	}
 
 	// Act
-	data := reporter.AggregateMetrics(tcpResults, nil)
+	data := reporter.AggregateMetrics(probeResults(tcpResults, nil))
 
 	// Assert - only successful probes should be averaged
 	if data.LatencyMs != 150.0 { // (100 + 200) / 2 (excluding failed probe)
@@ -322,7 +377,7 @@ func TestAggregateMetricsUDPProbes(t *testing.T) {
 	}
 
 	// Act
-	data := reporter.AggregateMetrics(nil, udpResults)
+	data := reporter.AggregateMetrics(probeResults(nil, udpResults))
 
 	// Assert
 	if data.LatencyMs != 150.0 { // (120 + 180) / 2
@@ -361,7 +416,7 @@ func TestAggregateMetricsMixedProbes(t *testing.T) {
 	}
 
 	// Act
-	data := reporter.AggregateMetrics(tcpResults, udpResults)
+	data := reporter.AggregateMetrics(probeResults(tcpResults, udpResults))
 
 	// Assert - should average across both TCP and UDP results
 	if data.LatencyMs != 150.0 { // (100 + 200) / 2
@@ -388,7 +443,7 @@ func TestAggregateMetricsAllFailed(t *testing.T) {
 	}
 
 	// Act
-	data := reporter.AggregateMetrics(tcpResults, nil)
+	data := reporter.AggregateMetrics(probeResults(tcpResults, nil))
 
 	// Assert - should use default values for all failed probes
 	if data.LatencyMs != 0 {
@@ -514,3 +569,902 @@ func TestReportWithRetryFailure(t *testing.T) {
 		t.Errorf("Expected 3 heartbeat requests (MaxRetries), got %d", mockServer.GetHeartbeatCount())
 	}
 }
+
+// TestReportWithRetry_SkipsReportedResults tests that a result is reported
+// once, although it stays the latest result for several reports
+func TestReportWithRetry_SkipsReportedResults(t *testing.T) {
+	// Arrange
+	mockServer := NewMockPulseServer()
+	defer mockServer.Close()
+
+	apiClient := NewPulseAPIClient(mockServer.GetURL(), 5*time.Second)
+	result := &models.TCPProbeResult{Success: true, RTTMs: 100.0, Target: "8.8.8.8", Port: 80, Timestamp: "2026-01-30T12:00:00Z"}
+	mockScheduler := &mockProbeScheduler{tcpResults: []*models.TCPProbeResult{result}}
+	reporter := NewHeartbeatReporter(apiClient, "test-node-uuid", mockScheduler)
+
+	// Act - report twice without a new probe run
+	reporter.reportWithRetry()
+	reporter.reportWithRetry()
+
+	// Assert
+	if mockServer.GetHeartbeatCount() != 1 {
+		t.Errorf("Expected the result to be reported once, got %d heartbeats", mockServer.GetHeartbeatCount())
+	}
+
+	// Act - the probe runs again
+	result.Timestamp = "2026-01-30T12:05:00Z"
+	reporter.reportWithRetry()
+
+	// Assert
+	if mockServer.GetHeartbeatCount() != 2 {
+		t.Errorf("Expected the new result to be reported, got %d heartbeats", mockServer.GetHeartbeatCount())
+	}
+}
+
+// TestBuildProbeHeartbeats tests that each probe result becomes its own heartbeat record
+func TestBuildProbeHeartbeats(t *testing.T) {
+	// Arrange
+	reporter := NewHeartbeatReporter(NewPulseAPIClient("https://pulse.example.com", 5*time.Second), "test-node-id", &mockProbeScheduler{})
+
+	tcpResults := []*models.TCPProbeResult{
+		{
+			Success:        true,
+			RTTMs:          100.0,
+			RTTMedianMs:    95.0,
+			VarianceMs:     4.0,
+			PacketLossRate: 0.0,
+			JitterMs:       2.0,
+			SampleCount:    10,
+			Timestamp:      "2026-01-30T12:34:56Z",
+			ProbeID:        "7f1c2d3e-0000-4000-8000-000000000001",
+			Target:         "10.0.0.1",
+			Port:           443,
+		},
+		nil, // Probe without a result yet - should be skipped
+	}
+	udpResults := []*models.UDPProbeResult{
+		{
+			Success:        false,
+			PacketLossRate: 100.0,
+			SampleCount:    10,
+			Target:         "10.0.0.2",
+			Port:           53,
+		},
+	}
+
+	results := []*models.ProbeResult{
+		{
+			Type:    "icmp_ping",
+			Target:  "10.0.0.3",
+			Success: true,
+			Metrics: map[string]interface{}{
+				models.MetricRTTMs:          12.5,
+				models.MetricRTTMedianMs:    12.0,
+				models.MetricJitterMs:       0.8,
+				models.MetricPacketLossRate: 10.0,
+				models.MetricSampleCount:    10,
+			},
+		},
+	}
+
+	// Act
+	records := reporter.BuildProbeHeartbeats(probeResults(tcpResults, udpResults, results...))
+
+	// Assert
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 heartbeat records, got %d", len(records))
+	}
+
+	tcp := records[0]
+	if tcp.NodeID != "test-node-id" {
+		t.Errorf("Expected NodeID 'test-node-id', got %s", tcp.NodeID)
+	}
+	if tcp.ProbeID != "7f1c2d3e-0000-4000-8000-000000000001" {
+		t.Errorf("Expected configured ProbeID, got %s", tcp.ProbeID)
+	}
+	if tcp.ProbeType != "tcp_ping" || tcp.Target != "10.0.0.1" || tcp.Port != 443 {
+		t.Errorf("Unexpected TCP probe identity: %s %s:%d", tcp.ProbeType, tcp.Target, tcp.Port)
+	}
+	if !tcp.Success || tcp.LatencyMs != 100.0 || tcp.LatencyMedianMs != 95.0 || tcp.VarianceMs != 4.0 || tcp.SampleCount != 10 {
+		t.Errorf("Unexpected TCP metrics: %+v", tcp)
+	}
+	if tcp.Timestamp != "2026-01-30T12:34:56Z" {
+		t.Errorf("Expected probe timestamp to be preserved, got %s", tcp.Timestamp)
+	}
+
+	udp := records[1]
+	if udp.ProbeID != "udp_ping:10.0.0.2:53" {
+		t.Errorf("Expected derived ProbeID 'udp_ping:10.0.0.2:53', got %s", udp.ProbeID)
+	}
+	if udp.Success {
+		t.Error("Expected failed UDP probe to be reported with success=false")
+	}
+	if udp.PacketLossRate != 100.0 {
+		t.Errorf("Expected PacketLossRate 100.0, got %f", udp.PacketLossRate)
+	}
+	if _, err := time.Parse(time.RFC3339, udp.Timestamp); err != nil {
+		t.Errorf("Expected fallback timestamp in ISO 8601 format: %v", err)
+	}
+
+	icmp := records[2]
+	if icmp.ProbeID != "icmp_ping:10.0.0.3" || icmp.ProbeType != "icmp_ping" || icmp.Port != 0 {
+		t.Errorf("Unexpected ICMP probe identity: %s %s port=%d", icmp.ProbeID, icmp.ProbeType, icmp.Port)
+	}
+	if !icmp.Success || icmp.LatencyMs != 12.5 || icmp.LatencyMedianMs != 12.0 || icmp.JitterMs != 0.8 || icmp.PacketLossRate != 10.0 || icmp.SampleCount != 10 {
+		t.Errorf("Unexpected ICMP metrics: %+v", icmp)
+	}
+}
+
+// TestBuildProbeHeartbeats_HTTPFields tests that http_probe phase timing and
+// status code are carried in the heartbeat and omitted for other probe types
+func TestBuildProbeHeartbeats_HTTPFields(t *testing.T) {
+	// Arrange
+	reporter := NewHeartbeatReporter(NewPulseAPIClient("https://pulse.example.com", 5*time.Second), "test-node-id", &mockProbeScheduler{})
+	results := []*models.ProbeResult{
+		{
+			Type:    "http_probe",
+			ProbeID: "http_probe:https://api.example.com/health",
+			Target:  "https://api.example.com/health",
+			Success: true,
+			Metrics: map[string]interface{}{
+				models.MetricRTTMs:          85.0,
+				models.MetricDNSMs:          4.5,
+				models.MetricConnectMs:      12.0,
+				models.MetricTLSHandshakeMs: 30.5,
+				models.MetricTTFBMs:         80.0,
+				models.MetricTotalMs:        85.0,
+				models.MetricStatusCode:     200,
+			},
+		},
+		{
+			Type:    "icmp_ping",
+			Target:  "10.0.0.3",
+			Success: true,
+			Metrics: map[string]interface{}{models.MetricRTTMs: 1.0},
+		},
+	}
+
+	// Act
+	records := reporter.BuildProbeHeartbeats(results)
+
+	// Assert
+	httpRecord := records[0]
+	if httpRecord.Metrics["status_code"] != 200 {
+		t.Errorf("Expected status_code=200, got %v", httpRecord.Metrics["status_code"])
+	}
+	for _, name := range []string{"dns_ms", "connect_ms", "tls_handshake_ms", "ttfb_ms", "total_ms"} {
+		if _, ok := httpRecord.Metrics[name]; !ok {
+			t.Errorf("Expected %s to be set", name)
+		}
+	}
+	if httpRecord.Metrics["tls_handshake_ms"] != 30.5 {
+		t.Errorf("Expected tls_handshake_ms=30.5, got %v", httpRecord.Metrics["tls_handshake_ms"])
+	}
+	if httpRecord.Metrics["ttfb_ms"] != 80.0 {
+		t.Errorf("Expected ttfb_ms=80, got %v", httpRecord.Metrics["ttfb_ms"])
+	}
+
+	body, err := json.Marshal(records[1])
+	if err != nil {
+		t.Fatalf("Failed to marshal record: %v", err)
+	}
+	for _, field := range []string{"dns_ms", "connect_ms", "tls_handshake_ms", "ttfb_ms", "total_ms", "status_code"} {
+		if strings.Contains(string(body), field) {
+			t.Errorf("Expected %s to be omitted for icmp_ping, got %s", field, body)
+		}
+	}
+}
+
+// TestBuildProbeHeartbeats_DNSFields tests that dns_probe response status is
+// carried in the heartbeat, including a NOERROR rcode of 0
+func TestBuildProbeHeartbeats_DNSFields(t *testing.T) {
+	// Arrange
+	reporter := NewHeartbeatReporter(NewPulseAPIClient("https://pulse.example.com", 5*time.Second), "test-node-id", &mockProbeScheduler{})
+	results := []*models.ProbeResult{
+		{
+			Type:    "dns_probe",
+			ProbeID: "dns_probe:10.0.0.53:53/example.com/A",
+			Target:  "10.0.0.53",
+			Port:    53,
+			Success: true,
+			Metrics: map[string]interface{}{
+				models.MetricRTTMs:        3.2,
+				models.MetricRcode:        0,
+				models.MetricAnswerCount:  2,
+				models.MetricAnswersMatch: true,
+			},
+		},
+		{
+			Type:    "icmp_ping",
+			Target:  "10.0.0.3",
+			Success: true,
+			Metrics: map[string]interface{}{models.MetricRTTMs: 1.0},
+		},
+	}
+
+	// Act
+	records := reporter.BuildProbeHeartbeats(results)
+
+	// Assert
+	dnsRecord := records[0]
+	if rcode, ok := dnsRecord.Metrics["rcode"]; !ok || rcode != 0 {
+		t.Errorf("Expected rcode=0, got %v", rcode)
+	}
+	if dnsRecord.Metrics["answer_count"] != 2 {
+		t.Errorf("Expected answer_count=2, got %v", dnsRecord.Metrics["answer_count"])
+	}
+	if dnsRecord.Metrics["answers_match"] != true {
+		t.Error("Expected answers_match=true")
+	}
+
+	body, err := json.Marshal(dnsRecord)
+	if err != nil {
+		t.Fatalf("Failed to marshal record: %v", err)
+	}
+	if !strings.Contains(string(body), `"rcode":0`) {
+		t.Errorf("Expected NOERROR rcode to be sent, got %s", body)
+	}
+
+	body, err = json.Marshal(records[1])
+	if err != nil {
+		t.Fatalf("Failed to marshal record: %v", err)
+	}
+	if strings.Contains(string(body), "rcode") || strings.Contains(string(body), "answer") {
+		t.Errorf("Expected DNS fields to be omitted for icmp_ping, got %s", body)
+	}
+}
+
+// TestBuildProbeHeartbeats_CertificateFields tests that tls_probe certificate
+// status is carried in the heartbeat and omitted for other probe types
+func TestBuildProbeHeartbeats_CertificateFields(t *testing.T) {
+	// Arrange
+	reporter := NewHeartbeatReporter(NewPulseAPIClient("https://pulse.example.com", 5*time.Second), "test-node-id", &mockProbeScheduler{})
+	results := []*models.ProbeResult{
+		{
+			Type:    "tls_probe",
+			Target:  "api.example.com",
+			Port:    443,
+			Success: true,
+			Metrics: map[string]interface{}{
+				models.MetricRTTMs:             18.2,
+				models.MetricTLSVersion:        "TLS 1.3",
+				models.MetricCipherSuite:       "TLS_AES_128_GCM_SHA256",
+				models.MetricChainValid:        true,
+				models.MetricCertNotAfter:      "2026-03-01T00:00:00Z",
+				models.MetricCertDaysRemaining: 12.5,
+			},
+		},
+		{
+			Type:    "icmp_ping",
+			Target:  "10.0.0.3",
+			Success: true,
+			Metrics: map[string]interface{}{models.MetricRTTMs: 1.0},
+		},
+	}
+
+	// Act
+	records := reporter.BuildProbeHeartbeats(results)
+
+	// Assert
+	tlsRecord := records[0]
+	if tlsRecord.ProbeID != "tls_probe:api.example.com:443" || tlsRecord.LatencyMs != 18.2 {
+		t.Errorf("Unexpected TLS probe record: %+v", tlsRecord)
+	}
+	if tlsRecord.Metrics["tls_version"] != "TLS 1.3" || tlsRecord.Metrics["tls_cipher"] != "TLS_AES_128_GCM_SHA256" || tlsRecord.Metrics["cert_not_after"] != "2026-03-01T00:00:00Z" {
+		t.Errorf("Unexpected TLS details: %+v", tlsRecord.Metrics)
+	}
+	if tlsRecord.Metrics["cert_chain_valid"] != true {
+		t.Error("Expected cert_chain_valid=true")
+	}
+	if tlsRecord.Metrics["cert_days_remaining"] != 12.5 {
+		t.Errorf("Expected cert_days_remaining=12.5, got %v", tlsRecord.Metrics["cert_days_remaining"])
+	}
+
+	body, err := json.Marshal(records[1])
+	if err != nil {
+		t.Fatalf("Failed to marshal record: %v", err)
+	}
+	if strings.Contains(string(body), "cert_") || strings.Contains(string(body), "tls_") {
+		t.Errorf("Expected certificate fields to be omitted for icmp_ping, got %s", body)
+	}
+}
+
+// TestReportWithRetryPerProbe tests that one heartbeat is sent per probe
+func TestReportWithRetryPerProbe(t *testing.T) {
+	// Arrange - start mock Pulse server
+	mockServer := NewMockPulseServer()
+	defer mockServer.Close()
+
+	apiClient := NewPulseAPIClient(mockServer.GetURL(), 5*time.Second)
+	mockScheduler := &mockProbeScheduler{
+		tcpResults: []*models.TCPProbeResult{
+			{Success: true, RTTMs: 100.0, Target: "10.0.0.1", Port: 80},
+			{Success: true, RTTMs: 150.0, Target: "10.0.0.3", Port: 80},
+		},
+		udpResults: []*models.UDPProbeResult{
+			{Success: true, RTTMs: 20.0, Target: "10.0.0.2", Port: 53},
+		},
+	}
+	reporter := NewHeartbeatReporter(apiClient, "test-node-uuid", mockScheduler)
+
+	// Act
+	reporter.reportWithRetry()
+
+	// Assert - one record per probe, all in a single request
+	if mockServer.GetHeartbeatCount() != 3 {
+		t.Errorf("Expected 3 heartbeats (one per probe), got %d", mockServer.GetHeartbeatCount())
+	}
+	if mockServer.GetRequestCount() != 1 {
+		t.Errorf("Expected 1 batched request, got %d", mockServer.GetRequestCount())
+	}
+}
+
+// TestReportWithRetryLegacyFallback tests fallback to per-record requests
+// when Pulse does not expose the batched endpoint
+func TestReportWithRetryLegacyFallback(t *testing.T) {
+	// Arrange - mock server without /heartbeats
+	mockServer := NewMockPulseServer()
+	mockServer.SetBatchEnabled(false)
+	defer mockServer.Close()
+
+	apiClient := NewPulseAPIClient(mockServer.GetURL(), 5*time.Second)
+	mockScheduler := &mockProbeScheduler{
+		tcpResults: []*models.TCPProbeResult{
+			{Success: true, RTTMs: 100.0, Target: "10.0.0.1", Port: 80},
+			{Success: true, RTTMs: 150.0, Target: "10.0.0.3", Port: 80},
+		},
+	}
+	reporter := NewHeartbeatReporter(apiClient, "test-node-uuid", mockScheduler)
+
+	// Act
+	reporter.reportWithRetry()
+
+	// Assert - 1 rejected batch request + 2 single requests
+	if mockServer.GetHeartbeatCount() != 2 {
+		t.Errorf("Expected 2 heartbeats, got %d", mockServer.GetHeartbeatCount())
+	}
+	if mockServer.GetRequestCount() != 3 {
+		t.Errorf("Expected 3 requests, got %d", mockServer.GetRequestCount())
+	}
+}
+
+// TestSendHeartbeatBatchRejectedItems tests that rejected items are reported
+// per item and do not trigger retries
+func TestSendHeartbeatBatchRejectedItems(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange
+	mockServer := NewMockPulseServer()
+	defer mockServer.Close()
+
+	apiClient := NewPulseAPIClient(mockServer.GetURL(), 5*time.Second)
+	records := []*HeartbeatData{
+		{NodeID: "test-node-uuid", ProbeID: "tcp_ping:10.0.0.1:80", LatencyMs: 10},
+		{NodeID: "test-node-uuid", ProbeID: "tcp_ping:10.0.0.2:80", LatencyMs: 70000},
+	}
+
+	// Act
+	results, err := apiClient.SendHeartbeatBatch("test-node-uuid", records)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 item results, got %d", len(results))
+	}
+	if !results[0].Accepted {
+		t.Error("Expected first item to be accepted")
+	}
+	if results[1].Accepted || results[1].Error == nil || results[1].Error.Code != "ERR_INVALID_LATENCY" {
+		t.Errorf("Expected second item rejected with ERR_INVALID_LATENCY, got %+v", results[1])
+	}
+}
+
+// TestPulseAPIClientSendsAuthToken tests that the per-node API token is sent
+// as a Bearer token on single and batched heartbeats
+func TestPulseAPIClientSendsAuthToken(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange
+	mockServer := NewMockPulseServer()
+	defer mockServer.Close()
+
+	apiClient := NewPulseAPIClient(mockServer.GetURL(), 5*time.Second)
+	apiClient.SetAuthToken("npb_test_token")
+	record := &HeartbeatData{NodeID: "test-node-uuid", ProbeID: "tcp_ping:10.0.0.1:80", LatencyMs: 10}
+
+	// Act & Assert - single heartbeat
+	if err := apiClient.SendHeartbeat(record); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := mockServer.GetLastAuthorization(); got != "Bearer npb_test_token" {
+		t.Errorf("Expected Bearer token on heartbeat, got %q", got)
+	}
+
+	// Act & Assert - batched heartbeat
+	if _, err := apiClient.SendHeartbeatBatch("test-node-uuid", []*HeartbeatData{record}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := mockServer.GetLastAuthorization(); got != "Bearer npb_test_token" {
+		t.Errorf("Expected Bearer token on batch, got %q", got)
+	}
+}
+
+// initTestLogger initializes the logger for tests that run the API client directly
+func initTestLogger(t *testing.T) {
+	if err := logger.InitLogger(&config.Config{
+		LogLevel:      "INFO",
+		LogFile:       "/tmp/test-reporter.log",
+		LogMaxSize:    10,
+		LogMaxAge:     7,
+		LogMaxBackups: 3,
+	}); err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+}
+
+// TestReportWithRetryQueuesToOutbox tests that undelivered heartbeats are queued
+// on disk and replayed in order once Pulse is reachable again
+func TestReportWithRetryQueuesToOutbox(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange - Pulse is down
+	mockServer := NewMockPulseServer()
+	mockServer.SetResponseStatusCode(http.StatusInternalServerError)
+	defer mockServer.Close()
+
+	heartbeatOutbox, err := outbox.Open(filepath.Join(t.TempDir(), "outbox.jsonl"), 100, time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to open outbox: %v", err)
+	}
+	mockScheduler := &mockProbeScheduler{
+		tcpResults: []*models.TCPProbeResult{
+			{Success: true, RTTMs: 100.0, Target: "10.0.0.1", Port: 80},
+		},
+	}
+	reporter := NewHeartbeatReporter(NewPulseAPIClient(mockServer.GetURL(), 5*time.Second), "test-node-uuid", mockScheduler)
+	reporter.SetOutbox(heartbeatOutbox)
+	policy := BackoffPolicy{MaxRetries: 2, Interval: 10 * time.Millisecond, MaxBackoff: time.Second, Strategy: config.BackoffConstant}
+	reporter.SetBackoffPolicy(policy)
+
+	// Act - first report fails after retries
+	reporter.reportWithRetry()
+
+	// Assert - record queued instead of dropped
+	if heartbeatOutbox.Len() != 1 {
+		t.Fatalf("Expected 1 queued heartbeat, got %d", heartbeatOutbox.Len())
+	}
+
+	// Act - while the backlog exists, new records are queued behind it and
+	// the replay is retried according to the backoff policy
+	requestsBefore := mockServer.GetRequestCount()
+	reporter.reportWithRetry()
+
+	// Assert
+	if attempts := mockServer.GetRequestCount() - requestsBefore; attempts != policy.MaxRetries {
+		t.Errorf("Expected %d replay attempts while Pulse is down, got %d", policy.MaxRetries, attempts)
+	}
+	if heartbeatOutbox.Len() != 2 {
+		t.Fatalf("Expected 2 queued heartbeats, got %d", heartbeatOutbox.Len())
+	}
+
+	// Act - Pulse recovers
+	mockServer.SetResponseStatusCode(http.StatusOK)
+	mockServer.ResetHeartbeatCount()
+	reporter.reportWithRetry()
+
+	// Assert - backlog and the new record delivered, outbox drained
+	if mockServer.GetHeartbeatCount() != 3 {
+		t.Errorf("Expected 3 heartbeats (2 queued + 1 new), got %d", mockServer.GetHeartbeatCount())
+	}
+	if heartbeatOutbox.Len() != 0 {
+		t.Errorf("Expected empty outbox after replay, got %d", heartbeatOutbox.Len())
+	}
+}
+
+// TestReportWithRetryBoundedPerReport tests that retries stop once the backoff
+// would exceed ReportRetryBudget and the rest is queued in the outbox
+func TestReportWithRetryBoundedPerReport(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange - Pulse is down, backoff longer than one report interval
+	mockServer := NewMockPulseServer()
+	mockServer.SetResponseStatusCode(http.StatusInternalServerError)
+	defer mockServer.Close()
+
+	heartbeatOutbox, err := outbox.Open(filepath.Join(t.TempDir(), "outbox.jsonl"), 100, time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to open outbox: %v", err)
+	}
+	mockScheduler := &mockProbeScheduler{
+		tcpResults: []*models.TCPProbeResult{
+			{Success: true, RTTMs: 100.0, Target: "10.0.0.1", Port: 80},
+		},
+	}
+	reporter := NewHeartbeatReporter(NewPulseAPIClient(mockServer.GetURL(), 5*time.Second), "test-node-uuid", mockScheduler)
+	reporter.SetOutbox(heartbeatOutbox)
+	reporter.SetBackoffPolicy(BackoffPolicy{MaxRetries: 100, Interval: ReportRetryBudget + time.Second, MaxBackoff: time.Hour, Strategy: config.BackoffConstant})
+
+	// Act
+	start := time.Now()
+	reporter.reportWithRetry()
+
+	// Assert - one attempt, no wait past the budget, record kept for replay
+	if got := mockServer.GetRequestCount(); got != 1 {
+		t.Errorf("Expected 1 attempt, got %d", got)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected report to stop instead of waiting past its budget, took %v", elapsed)
+	}
+	if heartbeatOutbox.Len() != 1 {
+		t.Errorf("Expected 1 queued heartbeat, got %d", heartbeatOutbox.Len())
+	}
+}
+
+// TestReportWithRetryReregistersUnknownNode tests that the beacon registers
+// again when Pulse reports its node as unknown and resends with the new identity
+func TestReportWithRetryReregistersUnknownNode(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange - Pulse only knows the node assigned by re-registration
+	const newNodeID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	mockServer := NewMockPulseServer()
+	mockServer.SetKnownNodeID(newNodeID)
+	defer mockServer.Close()
+
+	apiClient := NewPulseAPIClient(mockServer.GetURL(), 5*time.Second)
+	mockScheduler := &mockProbeScheduler{
+		tcpResults: []*models.TCPProbeResult{
+			{Success: true, RTTMs: 100.0, Target: "10.0.0.1", Port: 80},
+		},
+	}
+	reporter := NewHeartbeatReporter(apiClient, "deleted-node-uuid", mockScheduler)
+	calls := 0
+	reporter.SetReregister(func(ctx context.Context) (string, string, error) {
+		calls++
+		return newNodeID, "npb_new", nil
+	})
+
+	// Act
+	reporter.reportWithRetry()
+
+	// Assert - registered once, heartbeat delivered with the new identity
+	if calls != 1 {
+		t.Errorf("Expected 1 re-registration, got %d", calls)
+	}
+	if reporter.NodeID() != newNodeID {
+		t.Errorf("Expected node ID %s, got %s", newNodeID, reporter.NodeID())
+	}
+	if mockServer.GetHeartbeatCount() != 1 {
+		t.Errorf("Expected 1 delivered heartbeat, got %d", mockServer.GetHeartbeatCount())
+	}
+	if got := mockServer.GetLastAuthorization(); got != "Bearer npb_new" {
+		t.Errorf("Expected new API token, got %q", got)
+	}
+
+	// Act - Pulse forgets the node again: no new attempt within ReregisterInterval
+	mockServer.SetKnownNodeID("another-node-uuid")
+	reporter.reportWithRetry()
+
+	// Assert
+	if calls != 1 {
+		t.Errorf("Expected re-registration to be rate limited, got %d calls", calls)
+	}
+}
+
+// newReplayTestReporter returns a reporter whose outbox already holds count heartbeats
+func newReplayTestReporter(t *testing.T, serverURL string, count int) (*HeartbeatReporter, *outbox.Outbox) {
+	t.Helper()
+	heartbeatOutbox, err := outbox.Open(filepath.Join(t.TempDir(), "outbox.jsonl"), 2000, time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to open outbox: %v", err)
+	}
+	payloads := make([]interface{}, count)
+	for i := range payloads {
+		payloads[i] = &HeartbeatData{NodeID: "test-node-uuid", ProbeID: "tcp_ping:10.0.0.1:80", LatencyMs: 10}
+	}
+	if err := heartbeatOutbox.Enqueue(payloads...); err != nil {
+		t.Fatalf("Failed to queue heartbeats: %v", err)
+	}
+
+	reporter := NewHeartbeatReporter(NewPulseAPIClient(serverURL, 5*time.Second), "test-node-uuid", &mockProbeScheduler{})
+	reporter.SetOutbox(heartbeatOutbox)
+	return reporter, heartbeatOutbox
+}
+
+// TestReplayOutboxBackoff tests that replay batches are paced and failed
+// batches retried according to the backoff policy
+func TestReplayOutboxBackoff(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange - Pulse is down, backlog spans two replay batches
+	mockServer := NewMockPulseServer()
+	mockServer.SetResponseStatusCode(http.StatusServiceUnavailable)
+	defer mockServer.Close()
+
+	reporter, heartbeatOutbox := newReplayTestReporter(t, mockServer.GetURL(), OutboxReplayBatchSize+50)
+	reporter.SetBackoffPolicy(BackoffPolicy{MaxRetries: 3, Interval: 50 * time.Millisecond, MaxBackoff: time.Second, Strategy: config.BackoffLinear})
+
+	// Act
+	start := time.Now()
+	reporter.replayOutbox()
+
+	// Assert - 3 attempts separated by 50ms and 100ms, nothing dropped
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("Expected replay retries to back off for at least 150ms, took %v", elapsed)
+	}
+	if got := mockServer.GetRequestCount(); got != 3 {
+		t.Errorf("Expected 3 replay attempts, got %d", got)
+	}
+	if heartbeatOutbox.Len() != OutboxReplayBatchSize+50 {
+		t.Errorf("Expected %d queued heartbeats, got %d", OutboxReplayBatchSize+50, heartbeatOutbox.Len())
+	}
+
+	// Act - Pulse recovers
+	mockServer.SetResponseStatusCode(http.StatusOK)
+	requestsBefore := mockServer.GetRequestCount()
+	start = time.Now()
+	reporter.replayOutbox()
+
+	// Assert - two batches, spaced by the base interval
+	if attempts := mockServer.GetRequestCount() - requestsBefore; attempts != 2 {
+		t.Errorf("Expected 2 replay batches, got %d", attempts)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected replay batches to be spaced by at least 50ms, took %v", elapsed)
+	}
+	if heartbeatOutbox.Len() != 0 {
+		t.Errorf("Expected empty outbox after replay, got %d", heartbeatOutbox.Len())
+	}
+}
+
+// TestReplayOutboxBoundedPerReport tests that one report replays at most
+// OutboxReplayMaxBatches batches within OutboxReplayBudget
+func TestReplayOutboxBoundedPerReport(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange
+	mockServer := NewMockPulseServer()
+	defer mockServer.Close()
+
+	queued := OutboxReplayMaxBatches*OutboxReplayBatchSize + 50
+	reporter, heartbeatOutbox := newReplayTestReporter(t, mockServer.GetURL(), queued)
+	reporter.SetBackoffPolicy(BackoffPolicy{MaxRetries: 3, Interval: time.Millisecond, MaxBackoff: time.Second, Strategy: config.BackoffConstant})
+
+	// Act
+	reporter.replayOutbox()
+
+	// Assert - the rest is left for the next report
+	if got := mockServer.GetRequestCount(); got != OutboxReplayMaxBatches {
+		t.Errorf("Expected %d replay batches, got %d", OutboxReplayMaxBatches, got)
+	}
+	if heartbeatOutbox.Len() != 50 {
+		t.Errorf("Expected 50 heartbeats left for the next report, got %d", heartbeatOutbox.Len())
+	}
+
+	// Arrange - pacing longer than the replay budget
+	reporter.SetBackoffPolicy(BackoffPolicy{MaxRetries: 3, Interval: OutboxReplayBudget + time.Second, MaxBackoff: 2 * OutboxReplayBudget, Strategy: config.BackoffConstant})
+	for i := 0; i < OutboxReplayBatchSize; i++ {
+		heartbeatOutbox.Enqueue(&HeartbeatData{NodeID: "test-node-uuid", ProbeID: "tcp_ping:10.0.0.1:80", LatencyMs: 10})
+	}
+	requestsBefore := mockServer.GetRequestCount()
+
+	// Act
+	start := time.Now()
+	reporter.replayOutbox()
+
+	// Assert - one batch sent without waiting past the budget
+	if attempts := mockServer.GetRequestCount() - requestsBefore; attempts != 1 {
+		t.Errorf("Expected 1 replay batch, got %d", attempts)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected replay to stop instead of waiting past its budget, took %v", elapsed)
+	}
+	if heartbeatOutbox.Len() != 50 {
+		t.Errorf("Expected 50 heartbeats left for the next report, got %d", heartbeatOutbox.Len())
+	}
+}
+
+// TestReplayOutboxDropsPermanentRejection tests that heartbeats rejected with
+// a non-retryable 4xx are dropped instead of blocking the outbox
+func TestReplayOutboxDropsPermanentRejection(t *testing.T) {
+	initTestLogger(t)
+
+	tests := []struct {
+		name       string
+		statusCode int
+		batch      bool
+		wantQueued int
+	}{
+		{name: "batch 400 dropped", statusCode: http.StatusBadRequest, batch: true, wantQueued: 0},
+		{name: "batch 422 dropped", statusCode: http.StatusUnprocessableEntity, batch: true, wantQueued: 0},
+		{name: "single 400 dropped", statusCode: http.StatusBadRequest, batch: false, wantQueued: 0},
+		{name: "batch 401 kept", statusCode: http.StatusUnauthorized, batch: true, wantQueued: 3},
+		{name: "batch 429 kept", statusCode: http.StatusTooManyRequests, batch: true, wantQueued: 3},
+		{name: "single 503 kept", statusCode: http.StatusServiceUnavailable, batch: false, wantQueued: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockServer := NewMockPulseServer()
+			mockServer.SetResponseStatusCode(tt.statusCode)
+			mockServer.SetBatchEnabled(tt.batch)
+			defer mockServer.Close()
+
+			reporter, heartbeatOutbox := newReplayTestReporter(t, mockServer.GetURL(), 3)
+			reporter.SetBackoffPolicy(BackoffPolicy{MaxRetries: 2, Interval: time.Millisecond, MaxBackoff: time.Second, Strategy: config.BackoffConstant})
+
+			// Act
+			reporter.replayOutbox()
+
+			// Assert
+			if heartbeatOutbox.Len() != tt.wantQueued {
+				t.Errorf("Expected %d queued heartbeats, got %d", tt.wantQueued, heartbeatOutbox.Len())
+			}
+		})
+	}
+}
+
+// TestBuildProbeHeartbeats_PathHops tests that the path_probe hop list is
+// carried in the heartbeat
+func TestBuildProbeHeartbeats_PathHops(t *testing.T) {
+	// Arrange
+	reporter := NewHeartbeatReporter(NewPulseAPIClient("https://pulse.example.com", 5*time.Second), "test-node-id", &mockProbeScheduler{})
+	hops := []models.PathHop{
+		{TTL: 1, Address: "10.0.0.1", Sent: 10, Received: 10, RTTMs: 0.4, BestMs: 0.3, WorstMs: 0.6},
+		{TTL: 2, Sent: 10, PacketLossRate: 100},
+		{TTL: 3, Address: "8.8.8.8", Sent: 10, Received: 9, PacketLossRate: 10, RTTMs: 12.1, BestMs: 11.8, WorstMs: 13},
+	}
+	results := []*models.ProbeResult{
+		{
+			Type:    "path_probe",
+			Target:  "8.8.8.8",
+			Success: true,
+			Metrics: map[string]interface{}{
+				models.MetricRTTMs:          12.1,
+				models.MetricPacketLossRate: 10.0,
+				models.MetricHopCount:       3,
+				models.MetricHops:           hops,
+			},
+		},
+	}
+
+	// Act
+	records := reporter.BuildProbeHeartbeats(results)
+
+	// Assert
+	if len(records) != 1 || records[0].ProbeID != "path_probe:8.8.8.8" {
+		t.Fatalf("Unexpected records: %+v", records)
+	}
+	if got, _ := records[0].Metrics["hops"].([]models.PathHop); len(got) != 3 || got[2].Address != "8.8.8.8" || got[1].Address != "" {
+		t.Errorf("Unexpected hops: %+v", records[0].Metrics["hops"])
+	}
+	if _, ok := records[0].Metrics["hop_count"]; ok {
+		t.Error("Expected hop_count to stay local to the beacon")
+	}
+
+	body, err := json.Marshal(records[0])
+	if err != nil {
+		t.Fatalf("Failed to marshal record: %v", err)
+	}
+	if !strings.Contains(string(body), `"hops":[{"ttl":1,"address":"10.0.0.1"`) {
+		t.Errorf("Expected hop list in heartbeat, got %s", body)
+	}
+}
+
+func TestBuildProbeHeartbeats_UDPEchoFields(t *testing.T) {
+	// Arrange
+	reporter := NewHeartbeatReporter(NewPulseAPIClient("https://pulse.example.com", 5*time.Second), "test-node-id", &mockProbeScheduler{})
+	forward, reverse := 4.2, 5.1
+	udpResults := []*models.UDPProbeResult{
+		{Success: true, RTTMs: 9.3, SentPackets: 10, ReceivedPackets: 10, SampleCount: 10, Target: "10.0.0.20", Port: 7331, ReorderedPackets: 2, DuplicatePackets: 1, OneWayForwardMs: &forward, OneWayReverseMs: &reverse},
+		{Success: true, RTTMs: 1.2, SentPackets: 10, ReceivedPackets: 10, SampleCount: 10, Target: "8.8.8.8", Port: 53},
+	}
+
+	// Act
+	records := reporter.BuildProbeHeartbeats(probeResults(nil, udpResults))
+
+	// Assert
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	echo := records[0]
+	if echo.Metrics["reordered_packets"] != 2 || echo.Metrics["duplicate_packets"] != 1 || echo.Metrics["one_way_forward_ms"] != 4.2 || echo.Metrics["one_way_reverse_ms"] != 5.1 {
+		t.Errorf("Unexpected echo fields: %+v", echo.Metrics)
+	}
+
+	body, err := json.Marshal(records[1])
+	if err != nil {
+		t.Fatalf("Failed to marshal record: %v", err)
+	}
+	if strings.Contains(string(body), "reordered_packets") || strings.Contains(string(body), "one_way_forward_ms") {
+		t.Errorf("Expected echo fields omitted for plain udp_ping, got %s", body)
+	}
+}
+
+func TestBuildProbeHeartbeats_Statistics(t *testing.T) {
+	// Arrange
+	reporter := NewHeartbeatReporter(NewPulseAPIClient("https://pulse.example.com", 5*time.Second), "test-node-id", &mockProbeScheduler{})
+	stats := models.RTTStatistics{RTTMinMs: 9.1, RTTMaxMs: 31.4, RTTStdDevMs: 4.2, RTTP90Ms: 18.5, RTTP95Ms: 24.7, RTTP99Ms: 30.9, JitterRFC3550Ms: 1.3}
+	tcpResults := []*models.TCPProbeResult{
+		{Success: true, RTTMs: 12.5, SampleCount: 10, Target: "8.8.8.8", Port: 80, RTTStatistics: stats},
+	}
+	results := []*models.ProbeResult{
+		models.NewProbeResult("icmp_ping", "1.1.1.1", true, map[string]interface{}{models.MetricRTTMs: 12.5}, ""),
+	}
+	stats.SetMetrics(results[0].Metrics)
+
+	// Act: no statistics selected
+	records := reporter.BuildProbeHeartbeats(probeResults(tcpResults, nil, results...))
+
+	// Assert
+	body, err := json.Marshal(records[0])
+	if err != nil {
+		t.Fatalf("Failed to marshal record: %v", err)
+	}
+	if strings.Contains(string(body), "latency_p95_ms") {
+		t.Errorf("Expected statistics omitted by default, got %s", body)
+	}
+
+	// Act: select p95 and RFC 3550 jitter
+	reporter.SetStatistics([]string{models.StatisticP95, models.StatisticJitterRFC3550})
+	records = reporter.BuildProbeHeartbeats(probeResults(tcpResults, nil, results...))
+
+	// Assert
+	for _, record := range records {
+		if record.LatencyP95Ms == nil || *record.LatencyP95Ms != 24.7 {
+			t.Errorf("%s: expected latency_p95_ms 24.7, got %v", record.ProbeType, record.LatencyP95Ms)
+		}
+		if record.JitterRFC3550Ms == nil || *record.JitterRFC3550Ms != 1.3 {
+			t.Errorf("%s: expected jitter_rfc3550_ms 1.3, got %v", record.ProbeType, record.JitterRFC3550Ms)
+		}
+		if record.LatencyMinMs != nil || record.LatencyP99Ms != nil {
+			t.Errorf("%s: expected unselected statistics omitted, got %+v", record.ProbeType, record)
+		}
+	}
+}
+
+func TestBuildProbeHeartbeats_Samples(t *testing.T) {
+	// Arrange
+	reporter := NewHeartbeatReporter(NewPulseAPIClient("https://pulse.example.com", 5*time.Second), "test-node-id", &mockProbeScheduler{})
+	samples := []models.RawSample{
+		{Timestamp: "2026-01-01T00:00:00Z", Success: true, RTTMs: 12.5},
+		{Timestamp: "2026-01-01T00:00:01Z", Success: false, ErrorClass: models.SampleErrorRefused},
+	}
+	tcpResults := []*models.TCPProbeResult{{Success: true, RTTMs: 12.5, SampleCount: 2, Target: "8.8.8.8", Port: 80, Samples: samples}}
+	udpResults := []*models.UDPProbeResult{{Success: true, RTTMs: 9.3, SampleCount: 2, Target: "10.0.0.20", Port: 7331, Samples: samples}}
+	icmp := models.NewProbeResult("icmp_ping", "1.1.1.1", true, map[string]interface{}{models.MetricRTTMs: 12.5}, "")
+	icmp.Samples = samples
+
+	// Act: samples are not reported by default
+	records := reporter.BuildProbeHeartbeats(probeResults(tcpResults, udpResults, icmp))
+
+	// Assert
+	body, err := json.Marshal(records)
+	if err != nil {
+		t.Fatalf("Failed to marshal records: %v", err)
+	}
+	if strings.Contains(string(body), `"samples"`) {
+		t.Errorf("Expected samples omitted by default, got %s", body)
+	}
+
+	// Act: enable raw sample reporting
+	reporter.SetReportSamples(true)
+	records = reporter.BuildProbeHeartbeats(probeResults(tcpResults, udpResults, icmp))
+
+	// Assert
+	for _, record := range records {
+		if len(record.Samples) != 2 || record.Samples[1].ErrorClass != models.SampleErrorRefused {
+			t.Errorf("%s: expected 2 samples with error class, got %+v", record.ProbeType, record.Samples)
+		}
+	}
+	body, err = json.Marshal(records[0])
+	if err != nil {
+		t.Fatalf("Failed to marshal record: %v", err)
+	}
+	if !strings.Contains(string(body), `"samples":[{"timestamp":"2026-01-01T00:00:00Z","success":true,"rtt_ms":12.5},{"timestamp":"2026-01-01T00:00:01Z","success":false,"rtt_ms":0,"error_class":"refused"}]`) {
+		t.Errorf("Unexpected samples encoding: %s", body)
+	}
+}
@@ -15,6 +15,10 @@ type MockPulseServer struct {
 	mu          sync.Mutex
 	responseStatusCode int
 	delay       time.Duration
+	requestCount int
+	batchDisabled bool // Simulate a Pulse without /heartbeats
+	lastAuthorization string
+	knownNodeID string // When set, other node IDs get 404 ERR_NODE_NOT_FOUND
 }
 
 // NewMockPulseServer creates a new mock Pulse API server
@@ -38,6 +42,17 @@ func (m *MockPulseServer) handleRequest(w http.ResponseWriter, r *http.Request)
 		time.Sleep(m.delay)
 	}
 
+	m.mu.Lock()
+	m.requestCount++
+	m.lastAuthorization = r.Header.Get("Authorization")
+	batchDisabled := m.batchDisabled
+	m.mu.Unlock()
+
+	if r.URL.Path == "/api/v1/beacon/heartbeats" && r.Method == "POST" && !batchDisabled {
+		m.handleBatchRequest(w, r)
+		return
+	}
+
 	// Only handle POST to /api/v1/beacon/heartbeat
 	if r.URL.Path != "/api/v1/beacon/heartbeat" || r.Method != "POST" {
 		w.WriteHeader(http.StatusNotFound)
@@ -59,6 +74,9 @@ func (m *MockPulseServer) handleRequest(w http.ResponseWriter, r *http.Request)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid node_id"})
 		return
 	}
+	if m.unknownNode(w, nodeID) {
+		return
+	}
 
 	// Validate metric ranges
 	latencyMs, _ := data["latency_ms"].(float64)
@@ -85,6 +103,76 @@ func (m *MockPulseServer) handleRequest(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// handleBatchRequest handles POST /api/v1/beacon/heartbeats with per-item status
+func (m *MockPulseServer) handleBatchRequest(w http.ResponseWriter, r *http.Request) {
+	var batch struct {
+		NodeID     string                   `json:"node_id"`
+		Heartbeats []map[string]interface{} `json:"heartbeats"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil || batch.NodeID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body"})
+		return
+	}
+	if m.unknownNode(w, batch.NodeID) {
+		return
+	}
+
+	m.mu.Lock()
+	m.heartbeatCount += len(batch.Heartbeats)
+	statusCode := m.responseStatusCode
+	m.mu.Unlock()
+
+	w.WriteHeader(statusCode)
+	if statusCode != http.StatusOK {
+		json.NewEncoder(w).Encode(map[string]string{"error": "Internal server error"})
+		return
+	}
+
+	results := make([]map[string]interface{}, 0, len(batch.Heartbeats))
+	accepted := 0
+	for i, hb := range batch.Heartbeats {
+		latencyMs, _ := hb["latency_ms"].(float64)
+		ok := latencyMs >= 0 && latencyMs <= 60000
+		result := map[string]interface{}{"index": i, "probe_id": hb["probe_id"], "accepted": ok}
+		if ok {
+			accepted++
+		} else {
+			result["error"] = map[string]string{"code": "ERR_INVALID_LATENCY", "message": "Invalid metrics"}
+		}
+		results = append(results, result)
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": map[string]interface{}{
+			"node_id":  batch.NodeID,
+			"accepted": accepted,
+			"rejected": len(batch.Heartbeats) - accepted,
+			"results":  results,
+		},
+	})
+}
+
+// unknownNode answers 404 ERR_NODE_NOT_FOUND for node IDs other than the known one
+func (m *MockPulseServer) unknownNode(w http.ResponseWriter, nodeID string) bool {
+	m.mu.Lock()
+	knownNodeID := m.knownNodeID
+	m.mu.Unlock()
+	if knownNodeID == "" || nodeID == knownNodeID {
+		return false
+	}
+	w.WriteHeader(http.StatusNotFound)
+	json.NewEncoder(w).Encode(map[string]string{"code": "ERR_NODE_NOT_FOUND", "message": "Node not found"})
+	return true
+}
+
+// SetKnownNodeID makes the server reject heartbeats of all other node IDs
+func (m *MockPulseServer) SetKnownNodeID(nodeID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.knownNodeID = nodeID
+}
+
 // GetURL returns the mock server URL
 func (m *MockPulseServer) GetURL() string {
 	return m.server.URL
@@ -97,6 +185,27 @@ func (m *MockPulseServer) GetHeartbeatCount() int {
 	return m.heartbeatCount
 }
 
+// GetRequestCount returns the number of HTTP requests received
+func (m *MockPulseServer) GetRequestCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.requestCount
+}
+
+// GetLastAuthorization returns the Authorization header of the last request
+func (m *MockPulseServer) GetLastAuthorization() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastAuthorization
+}
+
+// SetBatchEnabled toggles support for the batched heartbeat endpoint
+func (m *MockPulseServer) SetBatchEnabled(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.batchDisabled = !enabled
+}
+
 // SetResponseStatusCode sets the response status code for heartbeats
 func (m *MockPulseServer) SetResponseStatusCode(code int) {
 	m.mu.Lock()
@@ -0,0 +1,216 @@
+package reporter
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"beacon/internal/config"
+)
+
+// testPKI is a throwaway CA with a server certificate for 127.0.0.1 and a
+// beacon client certificate, written as PEM files
+type testPKI struct {
+	caPool     *x509.CertPool
+	serverCert tls.Certificate
+	caFile     string
+	certFile   string
+	keyFile    string
+}
+
+// newTestPKI generates the CA and certificates in a temp directory
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate CA key: %v", err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("Failed to create CA certificate: %v", err)
+	}
+	caCert, _ := x509.ParseCertificate(caDER)
+
+	issue := func(serial int64, cn string, usage x509.ExtKeyUsage) ([]byte, *ecdsa.PrivateKey) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("Failed to generate key: %v", err)
+		}
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: cn},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			DNSNames:     []string{"pulse.internal"},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatalf("Failed to create certificate: %v", err)
+		}
+		return der, key
+	}
+
+	writePEM := func(name, blockType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		return path
+	}
+
+	serverDER, serverKey := issue(2, "pulse", x509.ExtKeyUsageServerAuth)
+	clientDER, clientKey := issue(3, "550e8400-e29b-41d4-a716-446655440000", x509.ExtKeyUsageClientAuth)
+	clientKeyDER, err := x509.MarshalECPrivateKey(clientKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal client key: %v", err)
+	}
+
+	pki := &testPKI{
+		caPool:     x509.NewCertPool(),
+		serverCert: tls.Certificate{Certificate: [][]byte{serverDER}, PrivateKey: serverKey},
+		caFile:     writePEM("ca.pem", "CERTIFICATE", caDER),
+		certFile:   writePEM("client.pem", "CERTIFICATE", clientDER),
+		keyFile:    writePEM("client.key", "EC PRIVATE KEY", clientKeyDER),
+	}
+	pki.caPool.AddCert(caCert)
+	return pki
+}
+
+// newMTLSPulseServer starts an in-process Pulse mock that requires client certificates
+func newMTLSPulseServer(t *testing.T, pki *testPKI) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{pki.serverCert},
+		ClientCAs:    pki.caPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestPulseAPIClientMutualTLS tests heartbeats over mTLS with a custom CA
+func TestPulseAPIClientMutualTLS(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange
+	pki := newTestPKI(t)
+	server := newMTLSPulseServer(t, pki)
+	tlsConfig, err := config.TLSConfig{CAFile: pki.caFile, CertFile: pki.certFile, KeyFile: pki.keyFile}.ClientTLSConfig()
+	if err != nil {
+		t.Fatalf("Failed to build TLS config: %v", err)
+	}
+	apiClient := NewPulseAPIClientWithTLS(server.URL, 5*time.Second, tlsConfig)
+
+	// Act
+	err = apiClient.SendHeartbeat(NewHeartbeatData("test-node-uuid", 10, 0, 1))
+
+	// Assert
+	if err != nil {
+		t.Errorf("Expected heartbeat over mTLS to succeed, got %v", err)
+	}
+}
+
+// TestPulseAPIClientMutualTLS_NoClientCert tests that Pulse rejects the handshake
+// when no client certificate is configured
+func TestPulseAPIClientMutualTLS_NoClientCert(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange
+	pki := newTestPKI(t)
+	server := newMTLSPulseServer(t, pki)
+	tlsConfig, err := config.TLSConfig{CAFile: pki.caFile}.ClientTLSConfig()
+	if err != nil {
+		t.Fatalf("Failed to build TLS config: %v", err)
+	}
+	apiClient := NewPulseAPIClientWithTLS(server.URL, 5*time.Second, tlsConfig)
+
+	// Act
+	err = apiClient.SendHeartbeat(NewHeartbeatData("test-node-uuid", 10, 0, 1))
+
+	// Assert
+	if err == nil {
+		t.Error("Expected error without client certificate")
+	}
+}
+
+// TestPulseAPIClientTLS_ServerName tests verifying Pulse under an overridden server name
+func TestPulseAPIClientTLS_ServerName(t *testing.T) {
+	initTestLogger(t)
+
+	// Arrange
+	pki := newTestPKI(t)
+	server := newMTLSPulseServer(t, pki)
+
+	for _, tt := range []struct {
+		serverName string
+		wantErr    bool
+	}{
+		{"pulse.internal", false},
+		{"other.example.com", true},
+	} {
+		tlsConfig, err := config.TLSConfig{
+			CAFile: pki.caFile, CertFile: pki.certFile, KeyFile: pki.keyFile, ServerName: tt.serverName,
+		}.ClientTLSConfig()
+		if err != nil {
+			t.Fatalf("Failed to build TLS config: %v", err)
+		}
+
+		// Act
+		err = NewPulseAPIClientWithTLS(server.URL, 5*time.Second, tlsConfig).SendHeartbeat(NewHeartbeatData("test-node-uuid", 10, 0, 1))
+
+		// Assert
+		if (err != nil) != tt.wantErr {
+			t.Errorf("server_name %s: expected error %v, got %v", tt.serverName, tt.wantErr, err)
+		}
+	}
+}
+
+// TestClientTLSConfig_Invalid tests TLS config validation errors
+func TestClientTLSConfig_Invalid(t *testing.T) {
+	pki := newTestPKI(t)
+
+	for name, tlsCfg := range map[string]config.TLSConfig{
+		"cert without key": {CertFile: pki.certFile},
+		"missing CA file":  {CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+		"CA not PEM":       {CAFile: pki.keyFile},
+	} {
+		if _, err := tlsCfg.ClientTLSConfig(); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package responder
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"beacon/internal/logger"
+)
+
+// DefaultListenAddress is the address the responder listens on when none is configured
+const DefaultListenAddress = ":7331"
+
+// Echo packet layout (big endian). The prober fills in the session, sequence
+// number and its send time; the responder fills in its receive and send
+// times and returns the packet to the sender.
+//
+//	0  magic        "BCNE"
+//	4  version      1
+//	5  flags        0 = request, 1 = reply
+//	6  reserved
+//	8  session      random per probe batch, tells stale replies apart
+//	16 seq          sequence number within the batch
+//	20 reserved
+//	24 sent         prober send time (Unix nanoseconds, prober clock)
+//	32 received     responder receive time (Unix nanoseconds, responder clock)
+//	40 replied      responder send time (Unix nanoseconds, responder clock)
+const (
+	PacketSize = 48
+
+	packetVersion = 1
+	flagReply     = 1
+)
+
+var packetMagic = [4]byte{'B', 'C', 'N', 'E'}
+
+// ErrInvalidPacket reports a datagram that is not an echo packet
+var ErrInvalidPacket = errors.New("not a beacon echo packet")
+
+// Packet is a decoded echo packet
+type Packet struct {
+	Reply    bool
+	Session  uint64
+	Seq      uint32
+	Sent     time.Time // Prober send time
+	Received time.Time // Responder receive time (zero in requests)
+	Replied  time.Time // Responder send time (zero in requests)
+}
+
+// Marshal encodes the packet
+func (p *Packet) Marshal() []byte {
+	b := make([]byte, PacketSize)
+	copy(b[0:4], packetMagic[:])
+	b[4] = packetVersion
+	if p.Reply {
+		b[5] = flagReply
+	}
+	binary.BigEndian.PutUint64(b[8:], p.Session)
+	binary.BigEndian.PutUint32(b[16:], p.Seq)
+	binary.BigEndian.PutUint64(b[24:], unixNano(p.Sent))
+	binary.BigEndian.PutUint64(b[32:], unixNano(p.Received))
+	binary.BigEndian.PutUint64(b[40:], unixNano(p.Replied))
+	return b
+}
+
+// ParsePacket decodes an echo packet
+func ParsePacket(b []byte) (*Packet, error) {
+	if len(b) < PacketSize || [4]byte(b[0:4]) != packetMagic || b[4] != packetVersion {
+		return nil, ErrInvalidPacket
+	}
+	return &Packet{
+		Reply:    b[5]&flagReply != 0,
+		Session:  binary.BigEndian.Uint64(b[8:]),
+		Seq:      binary.BigEndian.Uint32(b[16:]),
+		Sent:     fromUnixNano(b[24:]),
+		Received: fromUnixNano(b[32:]),
+		Replied:  fromUnixNano(b[40:]),
+	}, nil
+}
+
+// unixNano encodes a time, zero for the zero time
+func unixNano(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.UnixNano())
+}
+
+// fromUnixNano decodes a time encoded by unixNano
+func fromUnixNano(b []byte) time.Time {
+	ns := binary.BigEndian.Uint64(b)
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(ns))
+}
+
+// Server echoes beacon echo packets back to their sender. Replies are the
+// same size as requests, and anything that is not an echo request is
+// dropped, so the responder cannot be used to amplify traffic.
+type Server struct {
+	address string
+
+	mu      sync.Mutex
+	conn    net.PacketConn
+	done    chan struct{}
+	replies uint64
+}
+
+// NewServer creates a responder listening on address (host:port)
+func NewServer(address string) *Server {
+	if address == "" {
+		address = DefaultListenAddress
+	}
+	return &Server{address: address}
+}
+
+// Start opens the UDP socket and starts echoing in the background
+func (s *Server) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil {
+		return fmt.Errorf("responder already started")
+	}
+
+	conn, err := net.ListenPacket("udp", s.address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.address, err)
+	}
+	s.conn = conn
+	s.done = make(chan struct{})
+
+	go s.serve(conn, s.done)
+
+	logger.WithFields(map[string]interface{}{"component": "responder", "address": conn.LocalAddr().String()}).Info("UDP responder started")
+	return nil
+}
+
+// Stop closes the socket and waits for the echo loop to exit
+func (s *Server) Stop() {
+	s.mu.Lock()
+	conn, done := s.conn, s.done
+	s.conn = nil
+	s.mu.Unlock()
+
+	if conn == nil {
+		return
+	}
+	conn.Close()
+	<-done
+
+	logger.WithFields(map[string]interface{}{"component": "responder"}).Info("UDP responder stopped")
+}
+
+// Addr returns the address the responder listens on, or nil if it is not running
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.LocalAddr()
+}
+
+// Replies returns the number of echo replies sent
+func (s *Server) Replies() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.replies
+}
+
+// serve echoes requests until the socket is closed
+func (s *Server) serve(conn net.PacketConn, done chan struct{}) {
+	defer close(done)
+
+	buffer := make([]byte, 1500)
+	for {
+		n, addr, err := conn.ReadFrom(buffer)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			logger.WithFields(map[string]interface{}{"component": "responder", "error": err.Error()}).Warn("UDP responder read failed")
+			continue
+		}
+		received := time.Now()
+
+		packet, err := ParsePacket(buffer[:n])
+		if err != nil || packet.Reply {
+			continue
+		}
+
+		packet.Reply = true
+		packet.Received = received
+		packet.Replied = time.Now()
+		if _, err := conn.WriteTo(packet.Marshal(), addr); err != nil {
+			logger.WithFields(map[string]interface{}{"component": "responder", "peer": addr.String(), "error": err.Error()}).Debug("UDP responder reply failed")
+			continue
+		}
+
+		s.mu.Lock()
+		s.replies++
+		s.mu.Unlock()
+	}
+}
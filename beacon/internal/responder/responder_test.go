@@ -0,0 +1,164 @@
+package responder
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"beacon/internal/config"
+	"beacon/internal/logger"
+)
+
+func initTestLogger(t *testing.T) {
+	if err := logger.InitLogger(&config.Config{
+		LogLevel:      "INFO",
+		LogFile:       filepath.Join(t.TempDir(), "responder.log"),
+		LogMaxSize:    10,
+		LogMaxAge:     7,
+		LogMaxBackups: 3,
+	}); err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+}
+
+// startTestServer starts a responder on a random loopback port
+func startTestServer(t *testing.T) (*Server, net.Conn) {
+	initTestLogger(t)
+
+	server := NewServer("127.0.0.1:0")
+	if err := server.Start(); err != nil {
+		t.Fatalf("Failed to start responder: %v", err)
+	}
+	t.Cleanup(server.Stop)
+
+	conn, err := net.Dial("udp", server.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to dial responder: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return server, conn
+}
+
+func TestPacketRoundTrip(t *testing.T) {
+	sent := time.Unix(0, 1700000000123456789)
+	packet := &Packet{Reply: true, Session: 0xdeadbeefcafe, Seq: 42, Sent: sent, Received: sent.Add(time.Millisecond), Replied: sent.Add(2 * time.Millisecond)}
+
+	encoded := packet.Marshal()
+	if len(encoded) != PacketSize {
+		t.Fatalf("Expected %d bytes, got %d", PacketSize, len(encoded))
+	}
+
+	decoded, err := ParsePacket(encoded)
+	if err != nil {
+		t.Fatalf("ParsePacket failed: %v", err)
+	}
+	if !decoded.Reply || decoded.Session != packet.Session || decoded.Seq != 42 {
+		t.Errorf("Unexpected header: %+v", decoded)
+	}
+	if !decoded.Sent.Equal(packet.Sent) || !decoded.Received.Equal(packet.Received) || !decoded.Replied.Equal(packet.Replied) {
+		t.Errorf("Unexpected timestamps: %+v", decoded)
+	}
+
+	request, _ := ParsePacket((&Packet{Seq: 1, Sent: sent}).Marshal())
+	if request.Reply || !request.Received.IsZero() || !request.Replied.IsZero() {
+		t.Errorf("Expected request without responder times, got %+v", request)
+	}
+}
+
+func TestParsePacket_Invalid(t *testing.T) {
+	valid := (&Packet{Seq: 1, Sent: time.Now()}).Marshal()
+
+	badMagic := append([]byte(nil), valid...)
+	badMagic[0] = 'X'
+	badVersion := append([]byte(nil), valid...)
+	badVersion[4] = 2
+
+	for name, b := range map[string][]byte{
+		"short":       valid[:PacketSize-1],
+		"bad magic":   badMagic,
+		"bad version": badVersion,
+		"plain ping":  []byte("PING"),
+	} {
+		if _, err := ParsePacket(b); err != ErrInvalidPacket {
+			t.Errorf("%s: expected ErrInvalidPacket, got %v", name, err)
+		}
+	}
+}
+
+func TestServer_Echo(t *testing.T) {
+	// Arrange
+	server, conn := startTestServer(t)
+	sent := time.Now()
+	request := &Packet{Session: 7, Seq: 3, Sent: sent}
+
+	// Act
+	if _, err := conn.Write(request.Marshal()); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buffer := make([]byte, 1500)
+	n, err := conn.Read(buffer)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected echo reply, got: %v", err)
+	}
+	if n != PacketSize {
+		t.Errorf("Expected reply the size of the request (%d), got %d", PacketSize, n)
+	}
+	reply, err := ParsePacket(buffer[:n])
+	if err != nil {
+		t.Fatalf("Invalid reply: %v", err)
+	}
+	if !reply.Reply || reply.Session != 7 || reply.Seq != 3 || !reply.Sent.Equal(sent) {
+		t.Errorf("Unexpected reply: %+v", reply)
+	}
+	if reply.Received.Before(sent) || reply.Replied.Before(reply.Received) {
+		t.Errorf("Expected responder times after send time, got received=%v replied=%v", reply.Received, reply.Replied)
+	}
+	if server.Replies() != 1 {
+		t.Errorf("Expected 1 reply, got %d", server.Replies())
+	}
+}
+
+func TestServer_IgnoresNonRequests(t *testing.T) {
+	// Arrange
+	server, conn := startTestServer(t)
+
+	// Act - garbage and replies must not be answered
+	conn.Write([]byte("PING"))
+	conn.Write((&Packet{Reply: true, Seq: 1, Sent: time.Now()}).Marshal())
+	conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	_, err := conn.Read(make([]byte, 1500))
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected no reply to non-request packets")
+	}
+	if server.Replies() != 0 {
+		t.Errorf("Expected 0 replies, got %d", server.Replies())
+	}
+}
+
+func TestServer_StartStop(t *testing.T) {
+	initTestLogger(t)
+
+	server := NewServer("")
+	if server.address != DefaultListenAddress {
+		t.Errorf("Expected default address %s, got %s", DefaultListenAddress, server.address)
+	}
+
+	server = NewServer("127.0.0.1:0")
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := server.Start(); err == nil {
+		t.Error("Expected error starting a running responder")
+	}
+	server.Stop()
+	if server.Addr() != nil {
+		t.Error("Expected no address after Stop")
+	}
+	server.Stop() // Stopping twice is a no-op
+}
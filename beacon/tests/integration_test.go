@@ -166,7 +166,7 @@ probes:
     port: 53
     timeout_seconds: 3
     interval: 120
-    count: 5
+    count: 10
 `, logFile)
 	if err := os.WriteFile(tmpFile, []byte(configContent), 0644); err != nil {
 		t.Fatalf("Failed to create test config: %v", err)
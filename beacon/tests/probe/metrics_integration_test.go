@@ -1,6 +1,7 @@
 package probe_test
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"testing"
@@ -27,7 +28,7 @@ func TestTCPCoreMetricsIntegration(t *testing.T) {
 		}
 
 		pinger := probe.NewTCPPinger(config)
-		result, err := pinger.ExecuteBatch(10)
+		result, err := pinger.ExecuteBatch(context.Background(), 10)
 
 		if err != nil {
 			t.Fatalf("ExecuteBatch() failed: %v", err)
@@ -88,7 +89,7 @@ func TestTCPCoreMetricsIntegration(t *testing.T) {
 		pinger := probe.NewTCPPinger(config)
 
 		startTime := time.Now()
-		result, err := pinger.ExecuteBatch(100)
+		result, err := pinger.ExecuteBatch(context.Background(), 100)
 		elapsed := time.Since(startTime)
 
 		if err != nil {
@@ -126,7 +127,7 @@ func TestUDPCoreMetricsIntegration(t *testing.T) {
 		}
 
 		pinger := probe.NewUDPPinger(config)
-		result, err := pinger.ExecuteBatch(10)
+		result, err := pinger.ExecuteBatch(context.Background(), 10)
 
 		if err != nil {
 			t.Fatalf("ExecuteBatch() failed: %v", err)
@@ -197,7 +198,7 @@ func TestMeasurementPrecisionWithRealServer(t *testing.T) {
 		}
 
 		pinger := probe.NewTCPPinger(config)
-		result, err := pinger.ExecuteBatch(100)
+		result, err := pinger.ExecuteBatch(context.Background(), 100)
 
 		if err != nil {
 			t.Fatalf("ExecuteBatch() failed: %v", err)
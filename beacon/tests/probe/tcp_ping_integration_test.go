@@ -1,6 +1,7 @@
 package probe
 
 import (
+	"context"
 	"net"
 	"os"
 	"os/signal"
@@ -12,6 +13,7 @@ import (
 
 	"beacon/internal/config"
 	"beacon/internal/logger"
+	"beacon/internal/models"
 	"beacon/internal/probe"
 )
 
@@ -62,7 +64,7 @@ func TestIntegration_TCPProbeWithRealServer(t *testing.T) {
 	pinger := probe.NewTCPPinger(cfg)
 
 	// Execute batch probes with core metrics
-	result, err := pinger.ExecuteBatch(cfg.Count)
+	result, err := pinger.ExecuteBatch(context.Background(), cfg.Count)
 	if err != nil {
 		t.Fatalf("ExecuteBatch failed: %v", err)
 	}
@@ -354,7 +356,7 @@ func TestIntegration_ExecuteProbeNow(t *testing.T) {
 	}
 
 	// Execute probe manually without starting scheduler
-	result, err := scheduler.ExecuteProbeNow(0)
+	result, err := scheduler.ExecuteProbeNow(context.Background(), 0)
 	if err != nil {
 		t.Fatalf("ExecuteProbeNow failed: %v", err)
 	}
@@ -363,12 +365,12 @@ func TestIntegration_ExecuteProbeNow(t *testing.T) {
 		t.Errorf("Expected successful probe, got error: %s", result.ErrorMessage)
 	}
 
-	if result.RTTMs <= 0 {
-		t.Errorf("Expected RTT > 0, got %f", result.RTTMs)
+	if rtt := result.MetricFloat(models.MetricRTTMs); rtt <= 0 {
+		t.Errorf("Expected RTT > 0, got %f", rtt)
 	}
 
 	// Test invalid index
-	_, err = scheduler.ExecuteProbeNow(99)
+	_, err = scheduler.ExecuteProbeNow(context.Background(), 99)
 	if err == nil {
 		t.Error("Expected error for invalid probe index")
 	}
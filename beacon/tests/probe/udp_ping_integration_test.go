@@ -1,6 +1,7 @@
 package probe
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"path/filepath"
@@ -59,7 +60,7 @@ func TestUDPProbeIntegration(t *testing.T) {
 		}
 
 		pinger := probe.NewUDPPinger(udpConfig)
-		result, err := pinger.ExecuteBatch(10)
+		result, err := pinger.ExecuteBatch(context.Background(), 10)
 
 		if err != nil {
 			t.Errorf("ExecuteBatch() error = %v", err)
@@ -100,7 +101,7 @@ func TestUDPProbeIntegration(t *testing.T) {
 		}
 
 		pinger := probe.NewUDPPinger(udpConfig)
-		result, err := pinger.ExecuteBatch(10)
+		result, err := pinger.ExecuteBatch(context.Background(), 10)
 
 		if err != nil {
 			t.Errorf("ExecuteBatch() error = %v", err)
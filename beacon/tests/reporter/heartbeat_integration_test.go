@@ -130,10 +130,18 @@ func TestIntegration_HeartbeatReporterSuccess(t *testing.T) {
 type mockProbeScheduler struct {
 	tcpResults []*models.TCPProbeResult
 	udpResults []*models.UDPProbeResult
+	results    []*models.ProbeResult
 }
 
-func (m *mockProbeScheduler) GetLatestResults() ([]*models.TCPProbeResult, []*models.UDPProbeResult) {
-	return m.tcpResults, m.udpResults
+func (m *mockProbeScheduler) GetLatestResults() []*models.ProbeResult {
+	results := make([]*models.ProbeResult, 0, len(m.tcpResults)+len(m.udpResults)+len(m.results))
+	for _, result := range m.tcpResults {
+		results = append(results, result.ToGenericResult())
+	}
+	for _, result := range m.udpResults {
+		results = append(results, result.ToGenericResult())
+	}
+	return append(results, m.results...)
 }
 
 // TestIntegration_HeartbeatReporterRetry tests retry mechanism on server errors
@@ -226,7 +234,7 @@ func TestIntegration_AggregateMetricsFromProbes(t *testing.T) {
 	heartbeatReporter := reporter.NewHeartbeatReporter(apiClient, "test-node-uuid", mockScheduler)
 
 	// Act - aggregate metrics
-	data := heartbeatReporter.AggregateMetrics(tcpResults, udpResults)
+	data := heartbeatReporter.AggregateMetrics(mockScheduler.GetLatestResults())
 
 	// Assert - averages should be (100+200+150)/3, (0+1+0.5)/3, (2+3+2.5)/3
 	if data.LatencyMs != 150.0 {
@@ -50,11 +50,36 @@ func main() {
 		healthChecker = health.New(database, nil)
 	}
 
+	// Load TLS configuration (HTTPS and beacon mTLS)
+	tlsConfig, err := config.LoadTLSConfig()
+	if err != nil {
+		log.Fatalf("[Pulse] Failed to load TLS config: %v", err)
+	}
+
+	// Load mesh configuration (beacon-to-beacon probing)
+	meshConfig, err := config.LoadMeshConfig()
+	if err != nil {
+		log.Fatalf("[Pulse] Failed to load mesh config: %v", err)
+	}
+	if meshConfig.Enabled {
+		log.Printf("[Pulse] Mesh probing enabled (group_by: %s, protocol: %s, port: %d)",
+			meshConfig.GroupBy, meshConfig.Protocol, meshConfig.Port)
+	}
+
+	// Load enrollment configuration (beacon self-registration)
+	enrollmentConfig, err := config.LoadEnrollmentConfig()
+	if err != nil {
+		log.Fatalf("[Pulse] Failed to load enrollment config: %v", err)
+	}
+	if !enrollmentConfig.Enabled() && !tlsConfig.BeaconMTLS {
+		log.Println("[Pulse] Beacon self-registration disabled (set PULSE_BEACON_ENROLLMENT_TOKEN to enable)")
+	}
+
 	// Initialize Gin router
 	router := gin.Default()
 
 	// Setup routes and get cache manager for shutdown
-	cacheManager := api.SetupRoutes(router, healthChecker, database.Pool)
+	cacheManager := api.SetupRoutes(router, healthChecker, database.Pool, tlsConfig, meshConfig, enrollmentConfig)
 
 	// Initialize scheduler for background tasks (Story 3.12)
 	sched, err := scheduler.NewScheduler()
@@ -114,10 +139,24 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	if tlsConfig.Enabled() {
+		srv.TLSConfig, err = tlsConfig.ServerTLSConfig()
+		if err != nil {
+			log.Fatalf("[Pulse] Failed to build TLS config: %v", err)
+		}
+	}
+
 	// Start server in a goroutine
 	go func() {
-		log.Printf("[Pulse] API server starting on port %s...", port)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if tlsConfig.Enabled() {
+			log.Printf("[Pulse] API server starting on port %s (HTTPS, beacon mTLS required: %v)...", port, tlsConfig.BeaconMTLS)
+			err = srv.ListenAndServeTLS(tlsConfig.CertFile, tlsConfig.KeyFile)
+		} else {
+			log.Printf("[Pulse] API server starting on port %s...", port)
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatalf("[Pulse] Failed to start server: %v", err)
 		}
 	}()
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/kevin/node-pulse/pulse-api/internal/config"
+	"github.com/kevin/node-pulse/pulse-api/internal/db"
+	"github.com/kevin/node-pulse/pulse-api/internal/models"
+)
+
+// BeaconConfigHandler serves centrally managed probe configuration to beacons
+type BeaconConfigHandler struct {
+	nodeQuerier  db.NodesQuerier
+	probeQuerier db.ProbesQuerier
+	mesh         *config.MeshConfig // nil when mesh probing is disabled
+}
+
+// NewBeaconConfigHandler creates a new BeaconConfigHandler
+func NewBeaconConfigHandler(nodeQuerier db.NodesQuerier, probeQuerier db.ProbesQuerier) *BeaconConfigHandler {
+	return &BeaconConfigHandler{
+		nodeQuerier:  nodeQuerier,
+		probeQuerier: probeQuerier,
+	}
+}
+
+// SetMeshConfig enables mesh probing: each node's probe configuration then
+// also contains a probe against every peer in its mesh group
+func (h *BeaconConfigHandler) SetMeshConfig(cfg *config.MeshConfig) {
+	if cfg != nil && !cfg.Enabled {
+		cfg = nil
+	}
+	h.mesh = cfg
+}
+
+// HandleGetProbeConfig handles GET /api/v1/beacon/nodes/:node_id/probes
+// Returns the probes assigned to a node with an ETag version. Beacons send
+// the last seen version in If-None-Match and get 304 when nothing changed.
+func (h *BeaconConfigHandler) HandleGetProbeConfig(c *gin.Context) {
+	// Validate node ID format
+	rawNodeID := c.Param("node_id")
+	nodeID, err := uuid.Parse(rawNodeID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    "ERR_INVALID_NODE_ID",
+			Message: "节点 ID 格式无效",
+			Details: map[string]interface{}{
+				"node_id": rawNodeID,
+				"error":   err.Error(),
+			},
+		})
+		return
+	}
+
+	// Validate node ID belongs to the authenticated beacon
+	if status, errResp := authorizeNode(c, nodeID); errResp != nil {
+		c.JSON(status, errResp)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// Validate node ID exists
+	node, err := h.nodeQuerier.GetNodeByID(ctx, nodeID)
+	if err != nil {
+		if err == db.ErrNodeNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    ErrNodeNotFound,
+				Message: "节点不存在",
+				Details: map[string]interface{}{
+					"node_id": rawNodeID,
+				},
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "ERR_DATABASE_ERROR",
+			Message: "节点查询失败",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	probes, err := h.probeQuerier.GetProbesByNode(ctx, nodeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "ERR_DATABASE_ERROR",
+			Message: "探测配置查询失败",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	configs := toBeaconProbeConfigs(probes)
+
+	if h.mesh != nil {
+		nodes, err := h.nodeQuerier.GetNodes(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    "ERR_DATABASE_ERROR",
+				Message: "节点查询失败",
+				Details: err.Error(),
+			})
+			return
+		}
+		configs = append(configs, meshProbeConfigs(h.mesh, node, nodes)...)
+		sortBeaconProbeConfigs(configs)
+	}
+
+	version := probeConfigVersion(configs)
+	etag := `"` + version + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache")
+
+	if match := c.GetHeader("If-None-Match"); match == etag || match == version {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BeaconProbeConfigResponse{
+		Data: models.BeaconProbeConfigData{
+			NodeID:  nodeID.String(),
+			Version: version,
+			Probes:  configs,
+		},
+		Message:   "获取探测配置成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
+
+// toBeaconProbeConfigs converts stored probes to beacon probe config,
+// sorted by ID so the version is stable across queries
+func toBeaconProbeConfigs(probes []*models.Probe) []models.BeaconProbeConfig {
+	configs := make([]models.BeaconProbeConfig, 0, len(probes))
+	for _, probe := range probes {
+		configs = append(configs, models.BeaconProbeConfig{
+			ID:              probe.ID,
+			Type:            strings.ToLower(probe.Type) + "_ping", // TCP -> tcp_ping
+			Target:          probe.Target,
+			Port:            probe.Port,
+			IntervalSeconds: probe.IntervalSeconds,
+			Count:           probe.Count,
+			TimeoutSeconds:  probe.TimeoutSeconds,
+		})
+	}
+
+	sortBeaconProbeConfigs(configs)
+	return configs
+}
+
+// sortBeaconProbeConfigs sorts probe configs by ID
+func sortBeaconProbeConfigs(configs []models.BeaconProbeConfig) {
+	sort.Slice(configs, func(i, j int) bool {
+		return configs[i].ID < configs[j].ID
+	})
+}
+
+// probeConfigVersion returns a content hash of the probe configs
+func probeConfigVersion(configs []models.BeaconProbeConfig) string {
+	data, _ := json.Marshal(configs)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
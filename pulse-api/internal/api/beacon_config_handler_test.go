@@ -0,0 +1,193 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/kevin/node-pulse/pulse-api/internal/config"
+	"github.com/kevin/node-pulse/pulse-api/internal/db"
+	"github.com/kevin/node-pulse/pulse-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupBeaconConfigRouter creates a test router with the probe config sync endpoint
+func setupBeaconConfigRouter(nodeQuerier db.NodesQuerier, probeQuerier db.ProbesQuerier) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	handler := NewBeaconConfigHandler(nodeQuerier, probeQuerier)
+	router.Use(authenticateRequestNode())
+	router.GET("/api/v1/beacon/nodes/:node_id/probes", handler.HandleGetProbeConfig)
+
+	return router
+}
+
+func TestHandleGetProbeConfig_ReturnsProbesWithETag(t *testing.T) {
+	// Arrange
+	testNodeID := uuid.New()
+	probeID := uuid.New().String()
+	nodeQuerier := &MockNodesQuerier{
+		getNodeByIDFunc: func(ctx context.Context, nodeID uuid.UUID) (*models.Node, error) {
+			return &models.Node{ID: testNodeID.String()}, nil
+		},
+	}
+	probeQuerier := &MockProbesQuerier{
+		getProbesByNodeFunc: func(ctx context.Context, nodeID uuid.UUID) ([]*models.Probe, error) {
+			return []*models.Probe{
+				{ID: probeID, NodeID: testNodeID.String(), Type: "TCP", Target: "8.8.8.8", Port: 80, IntervalSeconds: 60, Count: 10, TimeoutSeconds: 5},
+			}, nil
+		},
+	}
+	router := setupBeaconConfigRouter(nodeQuerier, probeQuerier)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/api/v1/beacon/nodes/"+testNodeID.String()+"/probes", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	var resp models.BeaconProbeConfigResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	assert.Equal(t, `"`+resp.Data.Version+`"`, etag)
+	require.Len(t, resp.Data.Probes, 1)
+	assert.Equal(t, probeID, resp.Data.Probes[0].ID)
+	assert.Equal(t, "tcp_ping", resp.Data.Probes[0].Type)
+	assert.Equal(t, 60, resp.Data.Probes[0].IntervalSeconds)
+
+	// Act - same version returns 304
+	req, _ = http.NewRequest("GET", "/api/v1/beacon/nodes/"+testNodeID.String()+"/probes", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.Bytes())
+}
+
+func TestHandleGetProbeConfig_VersionChangesWithProbes(t *testing.T) {
+	// Arrange
+	probes := []*models.Probe{
+		{ID: "b", Type: "TCP", Target: "1.1.1.1", Port: 80, IntervalSeconds: 60, Count: 5, TimeoutSeconds: 5},
+		{ID: "a", Type: "UDP", Target: "8.8.8.8", Port: 53, IntervalSeconds: 60, Count: 5, TimeoutSeconds: 5},
+	}
+	reordered := []*models.Probe{probes[1], probes[0]}
+	changed := []*models.Probe{
+		probes[0],
+		{ID: "a", Type: "UDP", Target: "8.8.8.8", Port: 53, IntervalSeconds: 120, Count: 5, TimeoutSeconds: 5},
+	}
+
+	// Act
+	v1 := probeConfigVersion(toBeaconProbeConfigs(probes))
+	v2 := probeConfigVersion(toBeaconProbeConfigs(reordered))
+	v3 := probeConfigVersion(toBeaconProbeConfigs(changed))
+
+	// Assert
+	assert.Equal(t, v1, v2, "version must not depend on query order")
+	assert.NotEqual(t, v1, v3, "version must change when a probe changes")
+}
+
+func TestHandleGetProbeConfig_NodeNotFound_Returns404(t *testing.T) {
+	// Arrange
+	nodeQuerier := &MockNodesQuerier{
+		getNodeByIDFunc: func(ctx context.Context, nodeID uuid.UUID) (*models.Node, error) {
+			return nil, db.ErrNodeNotFound
+		},
+	}
+	router := setupBeaconConfigRouter(nodeQuerier, &MockProbesQuerier{})
+
+	// Act
+	req, _ := http.NewRequest("GET", "/api/v1/beacon/nodes/"+uuid.New().String()+"/probes", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var resp models.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	assert.Equal(t, ErrNodeNotFound, resp.Code)
+}
+
+func TestHandleGetProbeConfig_IncludesMeshPeers(t *testing.T) {
+	// Arrange
+	testNodeID := uuid.New()
+	peerID := uuid.New().String()
+	self := &models.Node{ID: testNodeID.String(), IP: "10.0.0.1", Region: "eu"}
+	nodeQuerier := &MockNodesQuerier{
+		getNodeByIDFunc: func(ctx context.Context, nodeID uuid.UUID) (*models.Node, error) {
+			return self, nil
+		},
+		getNodesFunc: func(ctx context.Context) ([]*models.Node, error) {
+			return []*models.Node{self, {ID: peerID, IP: "10.0.0.2", Region: "eu"}, {ID: uuid.New().String(), IP: "10.1.0.1", Region: "us"}}, nil
+		},
+	}
+	probeQuerier := &MockProbesQuerier{
+		getProbesByNodeFunc: func(ctx context.Context, nodeID uuid.UUID) ([]*models.Probe, error) {
+			return []*models.Probe{
+				{ID: uuid.New().String(), NodeID: testNodeID.String(), Type: "TCP", Target: "8.8.8.8", Port: 80, IntervalSeconds: 60, Count: 10, TimeoutSeconds: 5},
+			}, nil
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewBeaconConfigHandler(nodeQuerier, probeQuerier)
+	handler.SetMeshConfig(&config.MeshConfig{Enabled: true, GroupBy: "region", Protocol: "udp", Port: 7331, IntervalSeconds: 60, Count: 10, TimeoutSeconds: 2})
+	router.Use(authenticateRequestNode())
+	router.GET("/api/v1/beacon/nodes/:node_id/probes", handler.HandleGetProbeConfig)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/api/v1/beacon/nodes/"+testNodeID.String()+"/probes", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.BeaconProbeConfigResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data.Probes, 2)
+	mesh := resp.Data.Probes[1]
+	assert.Equal(t, "mesh:"+peerID, mesh.ID)
+	assert.Equal(t, "udp_ping", mesh.Type)
+	assert.Equal(t, "10.0.0.2", mesh.Target)
+	assert.True(t, mesh.Responder)
+}
+
+func TestBeaconConfigHandler_SetMeshConfig_Disabled(t *testing.T) {
+	handler := NewBeaconConfigHandler(&MockNodesQuerier{}, &MockProbesQuerier{})
+
+	handler.SetMeshConfig(&config.MeshConfig{Enabled: false})
+
+	assert.Nil(t, handler.mesh)
+}
+
+func TestHandleGetProbeConfig_Unauthenticated_Returns401(t *testing.T) {
+	// Arrange - route registered without BeaconAuthMiddleware
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewBeaconConfigHandler(&MockNodesQuerier{}, &MockProbesQuerier{})
+	router.GET("/api/v1/beacon/nodes/:node_id/probes", handler.HandleGetProbeConfig)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/api/v1/beacon/nodes/"+uuid.New().String()+"/probes", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), ErrNodeNotAuthorized)
+}
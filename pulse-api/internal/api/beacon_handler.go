@@ -1,12 +1,16 @@
 package api
 
 import (
-	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/kevin/node-pulse/pulse-api/internal/auth"
 	"github.com/kevin/node-pulse/pulse-api/internal/cache"
 	"github.com/kevin/node-pulse/pulse-api/internal/db"
 	"github.com/kevin/node-pulse/pulse-api/internal/models"
@@ -18,20 +22,51 @@ var (
 	ErrInvalidPacketLoss = "ERR_INVALID_PACKET_LOSS"
 	ErrInvalidJitter     = "ERR_INVALID_JITTER"
 	ErrInvalidTimestamp  = "ERR_INVALID_TIMESTAMP"
+	ErrInvalidProbeType  = "ERR_INVALID_PROBE_TYPE"
+	ErrInvalidProbeStats = "ERR_INVALID_PROBE_STATS"
+	ErrInvalidCertStats  = "ERR_INVALID_CERT_STATS"
+	ErrInvalidPathStats  = "ERR_INVALID_PATH_STATS"
+	ErrInvalidSamples    = "ERR_INVALID_SAMPLES"
 	ErrRateLimitExceeded = "ERR_RATE_LIMIT_EXCEEDED"
+	ErrNodeIDMismatch    = "ERR_NODE_ID_MISMATCH"
+	ErrBatchTooLarge     = "ERR_BATCH_TOO_LARGE"
+	ErrNodeTokenMismatch = "ERR_NODE_TOKEN_MISMATCH"
+	ErrInvalidNodeToken  = "ERR_INVALID_NODE_TOKEN"
+	ErrNodeTokenRevoked  = "ERR_NODE_TOKEN_REVOKED"
+	ErrNodeTokenRequired = "ERR_NODE_TOKEN_REQUIRED"
+	ErrNodeNotAuthorized = "ERR_NODE_NOT_AUTHORIZED"
 )
 
+// heartbeatProbeTypes lists the probe types beacons may report
+var heartbeatProbeTypes = []string{"tcp_ping", "udp_ping", "icmp_ping", "http_probe", "dns_probe", "tls_probe", "path_probe"}
+
+// isHeartbeatProbeType reports whether probeType is a known beacon probe type
+func isHeartbeatProbeType(probeType string) bool {
+	for _, t := range heartbeatProbeTypes {
+		if t == probeType {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxHeartbeatBatchSize is the maximum number of heartbeats per batch request
+// (kept well below the batch writer buffer so one batch always fits)
+const MaxHeartbeatBatchSize = 500
+
 // BeaconHandler handles beacon heartbeat API requests
 type BeaconHandler struct {
 	nodeQuerier  db.NodesQuerier
+	tokenQuerier db.NodeTokensQuerier
 	memoryCache  *cache.MemoryCache
 	batchWriter  *cache.BatchWriter
 }
 
 // NewBeaconHandler creates a new BeaconHandler
-func NewBeaconHandler(nodeQuerier db.NodesQuerier, memoryCache *cache.MemoryCache, batchWriter *cache.BatchWriter) *BeaconHandler {
+func NewBeaconHandler(nodeQuerier db.NodesQuerier, tokenQuerier db.NodeTokensQuerier, memoryCache *cache.MemoryCache, batchWriter *cache.BatchWriter) *BeaconHandler {
 	return &BeaconHandler{
-		nodeQuerier: nodeQuerier,
+		nodeQuerier:  nodeQuerier,
+		tokenQuerier: tokenQuerier,
 		memoryCache: memoryCache,
 		batchWriter: batchWriter,
 	}
@@ -50,59 +85,222 @@ func (h *BeaconHandler) HandleHeartbeat(c *gin.Context) {
 		return
 	}
 
-	// Validate node ID format
-	nodeID, err := uuid.Parse(req.NodeID)
-	if err != nil {
+	// Validate node ID format and existence
+	if status, errResp := h.validateNode(c, req.NodeID); errResp != nil {
+		c.JSON(status, errResp)
+		return
+	}
+
+	// Validate probe metrics
+	parsedTime, errResp := validateHeartbeat(&req)
+	if errResp != nil {
+		c.JSON(http.StatusBadRequest, errResp)
+		return
+	}
+
+	metricRecord := h.cacheHeartbeat(&req, parsedTime)
+
+	// Send to batch writer buffer (non-blocking)
+	if metricRecord != nil {
+		if err := h.batchWriter.Write(metricRecord); err != nil {
+			logBatchWriteError(err, req.NodeID, 1)
+			// Don't return error to avoid affecting Beacon reporting
+		}
+	}
+
+	c.JSON(http.StatusOK, models.HeartbeatSuccessResponse{
+		Data: models.HeartbeatData{
+			Received:  true,
+			NodeID:    req.NodeID,
+			ProbeID:   req.ProbeID,
+			Timestamp: time.Now(),
+		},
+		Message:   "心跳数据接收成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
+
+// HandleHeartbeatBatch handles POST /api/v1/beacon/heartbeats
+// Accepts all probe results of one beacon in a single request and reports
+// accept/reject status per item.
+func (h *BeaconHandler) HandleHeartbeatBatch(c *gin.Context) {
+	// Parse request body
+	var req models.HeartbeatBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Code:    "ERR_INVALID_NODE_ID",
-			Message: "节点 ID 格式无效",
-			Details: map[string]interface{}{
-				"node_id": req.NodeID,
-				"error":   err.Error(),
-			},
+			Code:    "ERR_INVALID_REQUEST",
+			Message: "请求参数无效",
+			Details: err.Error(),
 		})
 		return
 	}
 
-	// Validate probe_id format (max length check)
-	if len(req.ProbeID) > 255 {
+	if len(req.Heartbeats) > MaxHeartbeatBatchSize {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Code:    "ERR_INVALID_PROBE_ID",
-			Message: "探针 ID 格式无效",
+			Code:    ErrBatchTooLarge,
+			Message: "批量心跳数据过多",
 			Details: map[string]interface{}{
-				"probe_id": req.ProbeID,
-				"reason":   "probe_id must be <= 255 characters",
+				"count": len(req.Heartbeats),
+				"max":   MaxHeartbeatBatchSize,
 			},
 		})
 		return
 	}
 
+	// The whole batch belongs to one node, so the node is checked once
+	if status, errResp := h.validateNode(c, req.NodeID); errResp != nil {
+		c.JSON(status, errResp)
+		return
+	}
+
+	results := make([]models.HeartbeatItemResult, len(req.Heartbeats))
+	records := make([]*cache.MetricRecord, 0, len(req.Heartbeats))
+	accepted := 0
+
+	for i := range req.Heartbeats {
+		item := &req.Heartbeats[i]
+		results[i] = models.HeartbeatItemResult{Index: i, ProbeID: item.ProbeID}
+
+		// Items may omit node_id; it defaults to the batch node_id
+		if item.NodeID == "" {
+			item.NodeID = req.NodeID
+		} else if item.NodeID != req.NodeID {
+			results[i].Error = &models.ErrorResponse{
+				Code:    ErrNodeIDMismatch,
+				Message: "节点 ID 与批量请求不一致",
+				Details: map[string]interface{}{
+					"node_id":       item.NodeID,
+					"batch_node_id": req.NodeID,
+				},
+			}
+			continue
+		}
+
+		parsedTime, errResp := validateHeartbeat(item)
+		if errResp != nil {
+			results[i].Error = errResp
+			continue
+		}
+
+		results[i].Accepted = true
+		accepted++
+
+		if record := h.cacheHeartbeat(item, parsedTime); record != nil {
+			records = append(records, record)
+		}
+	}
+
+	// Send accepted records to batch writer as one unit (non-blocking)
+	if len(records) > 0 {
+		if err := h.batchWriter.WriteBatch(records); err != nil {
+			logBatchWriteError(err, req.NodeID, len(records))
+			// Don't return error to avoid affecting Beacon reporting
+		}
+	}
+
+	c.JSON(http.StatusOK, models.HeartbeatBatchResponse{
+		Data: models.HeartbeatBatchData{
+			NodeID:    req.NodeID,
+			Accepted:  accepted,
+			Rejected:  len(req.Heartbeats) - accepted,
+			Results:   results,
+			Timestamp: time.Now(),
+		},
+		Message:   "批量心跳数据接收完成",
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
+
+// validateNode checks node ID format, that it matches the beacon API token and
+// that the node exists. Returns the HTTP status and error response on failure.
+func (h *BeaconHandler) validateNode(c *gin.Context, rawNodeID string) (int, *models.ErrorResponse) {
+	// Validate node ID format
+	nodeID, err := uuid.Parse(rawNodeID)
+	if err != nil {
+		return http.StatusBadRequest, &models.ErrorResponse{
+			Code:    "ERR_INVALID_NODE_ID",
+			Message: "节点 ID 格式无效",
+			Details: map[string]interface{}{
+				"node_id": rawNodeID,
+				"error":   err.Error(),
+			},
+		}
+	}
+
+	// Validate node ID belongs to the authenticated beacon
+	if status, errResp := authorizeNode(c, nodeID); errResp != nil {
+		return status, errResp
+	}
+
 	// Validate node ID exists
-	ctx := context.Background()
-	_, err = h.nodeQuerier.GetNodeByID(ctx, nodeID)
+	_, err = h.nodeQuerier.GetNodeByID(c.Request.Context(), nodeID)
 	if err != nil {
 		if err == db.ErrNodeNotFound {
-			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			return http.StatusBadRequest, &models.ErrorResponse{
 				Code:    ErrNodeNotFound,
 				Message: "节点不存在",
 				Details: map[string]interface{}{
-					"node_id": req.NodeID,
+					"node_id": rawNodeID,
 				},
-			})
-			return
+			}
 		}
 
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		return http.StatusInternalServerError, &models.ErrorResponse{
 			Code:    "ERR_DATABASE_ERROR",
 			Message: "节点查询失败",
 			Details: err.Error(),
-		})
-		return
+		}
+	}
+
+	return http.StatusOK, nil
+}
+
+// authorizeNode rejects requests for a node other than the one the beacon API
+// token was issued for. The token itself is validated by auth.BeaconAuthMiddleware;
+// requests that did not pass it (e.g. a route registered without the
+// middleware) are rejected rather than trusted.
+func authorizeNode(c *gin.Context, nodeID uuid.UUID) (int, *models.ErrorResponse) {
+	authNodeID, exists := c.Get(auth.BeaconNodeIDKey)
+	if !exists {
+		return http.StatusUnauthorized, &models.ErrorResponse{
+			Code:    ErrNodeNotAuthorized,
+			Message: "请求未经过 Beacon 身份认证",
+			Details: map[string]interface{}{
+				"node_id": nodeID.String(),
+			},
+		}
+	}
+	if authNodeID == nodeID.String() {
+		return http.StatusOK, nil
+	}
+
+	return http.StatusForbidden, &models.ErrorResponse{
+		Code:    ErrNodeTokenMismatch,
+		Message: "API 令牌与节点 ID 不匹配",
+		Details: map[string]interface{}{
+			"node_id": nodeID.String(),
+		},
+	}
+}
+
+// validateHeartbeat validates the probe fields of a single heartbeat and
+// returns the parsed timestamp. Shared by single and batch endpoints.
+func validateHeartbeat(req *models.HeartbeatRequest) (time.Time, *models.ErrorResponse) {
+	// Validate probe_id format (required, max length check)
+	if req.ProbeID == "" || len(req.ProbeID) > 255 {
+		return time.Time{}, &models.ErrorResponse{
+			Code:    "ERR_INVALID_PROBE_ID",
+			Message: "探针 ID 格式无效",
+			Details: map[string]interface{}{
+				"probe_id": req.ProbeID,
+				"reason":   "probe_id must be 1-255 characters",
+			},
+		}
 	}
 
 	// Validate latency range (0-60000ms)
 	if req.LatencyMs < 0 || req.LatencyMs > 60000 {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		return time.Time{}, &models.ErrorResponse{
 			Code:    ErrInvalidLatency,
 			Message: "时延超出范围",
 			Details: map[string]interface{}{
@@ -112,13 +310,12 @@ func (h *BeaconHandler) HandleHeartbeat(c *gin.Context) {
 				"max":       60000,
 				"unit":      "ms",
 			},
-		})
-		return
+		}
 	}
 
 	// Validate packet loss rate range (0-100%)
 	if req.PacketLossRate < 0 || req.PacketLossRate > 100 {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		return time.Time{}, &models.ErrorResponse{
 			Code:    ErrInvalidPacketLoss,
 			Message: "丢包率超出范围",
 			Details: map[string]interface{}{
@@ -128,13 +325,12 @@ func (h *BeaconHandler) HandleHeartbeat(c *gin.Context) {
 				"max":       100,
 				"unit":      "%",
 			},
-		})
-		return
+		}
 	}
 
 	// Validate jitter range (0-50000ms)
 	if req.JitterMs < 0 || req.JitterMs > 50000 {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		return time.Time{}, &models.ErrorResponse{
 			Code:    ErrInvalidJitter,
 			Message: "抖动超出范围",
 			Details: map[string]interface{}{
@@ -144,14 +340,68 @@ func (h *BeaconHandler) HandleHeartbeat(c *gin.Context) {
 				"max":       50000,
 				"unit":      "ms",
 			},
-		})
-		return
+		}
+	}
+
+	// Validate optional per-probe fields
+	if req.ProbeType != "" && !isHeartbeatProbeType(req.ProbeType) {
+		return time.Time{}, &models.ErrorResponse{
+			Code:    ErrInvalidProbeType,
+			Message: "探测类型无效",
+			Details: map[string]interface{}{
+				"field":   "probe_type",
+				"value":   req.ProbeType,
+				"allowed": heartbeatProbeTypes,
+			},
+		}
+	}
+
+	if req.Port < 0 || req.Port > 65535 || req.SampleCount < 0 ||
+		req.LatencyMedianMs < 0 || req.LatencyMedianMs > 60000 || req.VarianceMs < 0 {
+		return time.Time{}, &models.ErrorResponse{
+			Code:    ErrInvalidProbeStats,
+			Message: "探测统计数据超出范围",
+			Details: map[string]interface{}{
+				"port":              req.Port,
+				"sample_count":      req.SampleCount,
+				"latency_median_ms": req.LatencyMedianMs,
+				"variance_ms":       req.VarianceMs,
+			},
+		}
+	}
+
+	if errResp := validateHTTPFields(req); errResp != nil {
+		return time.Time{}, errResp
+	}
+
+	if errResp := validateDNSFields(req); errResp != nil {
+		return time.Time{}, errResp
+	}
+
+	if errResp := validateCertificateFields(req); errResp != nil {
+		return time.Time{}, errResp
+	}
+
+	if errResp := validatePathHops(req); errResp != nil {
+		return time.Time{}, errResp
+	}
+
+	if errResp := validateEchoFields(req); errResp != nil {
+		return time.Time{}, errResp
+	}
+
+	if errResp := validateLatencyStatistics(req); errResp != nil {
+		return time.Time{}, errResp
+	}
+
+	if errResp := validateSamples(req); errResp != nil {
+		return time.Time{}, errResp
 	}
 
 	// Validate timestamp format
 	parsedTime, err := time.Parse(time.RFC3339, req.Timestamp)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		return time.Time{}, &models.ErrorResponse{
 			Code:    ErrInvalidTimestamp,
 			Message: "时间戳格式无效",
 			Details: map[string]interface{}{
@@ -160,13 +410,267 @@ func (h *BeaconHandler) HandleHeartbeat(c *gin.Context) {
 				"expected":  "ISO 8601 format (e.g., 2024-01-01T00:00:00Z)",
 				"error":     err.Error(),
 			},
-		})
-		return
+		}
+	}
+
+	return parsedTime, nil
+}
+
+// validateHTTPFields validates the optional http_probe phase timing and status code
+func validateHTTPFields(req *models.HeartbeatRequest) *models.ErrorResponse {
+	invalid := req.StatusCode != 0 && (req.StatusCode < 100 || req.StatusCode > 599)
+	for _, phase := range []*float64{req.DNSMs, req.ConnectMs, req.TLSHandshakeMs, req.TTFBMs, req.TotalMs} {
+		if phase != nil && (*phase < 0 || *phase > 60000) {
+			invalid = true
+		}
+	}
+
+	if !invalid {
+		return nil
+	}
+	return &models.ErrorResponse{
+		Code:    ErrInvalidProbeStats,
+		Message: "探测统计数据超出范围",
+		Details: map[string]interface{}{
+			"dns_ms":           req.DNSMs,
+			"connect_ms":       req.ConnectMs,
+			"tls_handshake_ms": req.TLSHandshakeMs,
+			"ttfb_ms":          req.TTFBMs,
+			"total_ms":         req.TotalMs,
+			"status_code":      req.StatusCode,
+		},
 	}
+}
+
+// validateDNSFields validates the optional dns_probe response status: an
+// rcode of -1 (no response) to 4095 and at most 65535 answers
+func validateDNSFields(req *models.HeartbeatRequest) *models.ErrorResponse {
+	invalid := req.Rcode != nil && (*req.Rcode < -1 || *req.Rcode > 4095)
+	if req.AnswerCount != nil && (*req.AnswerCount < 0 || *req.AnswerCount > 65535) {
+		invalid = true
+	}
+
+	if !invalid {
+		return nil
+	}
+	return &models.ErrorResponse{
+		Code:    ErrInvalidProbeStats,
+		Message: "探测统计数据超出范围",
+		Details: map[string]interface{}{
+			"rcode":        req.Rcode,
+			"answer_count": req.AnswerCount,
+		},
+	}
+}
 
+// validateCertificateFields validates the optional tls_probe certificate status
+func validateCertificateFields(req *models.HeartbeatRequest) *models.ErrorResponse {
+	invalid := len(req.TLSVersion) > 32 || len(req.TLSCipher) > 128
+	if req.CertDaysRemaining != nil && (*req.CertDaysRemaining < -36500 || *req.CertDaysRemaining > 36500) {
+		invalid = true
+	}
+	if req.CertNotAfter != "" {
+		if _, err := time.Parse(time.RFC3339, req.CertNotAfter); err != nil {
+			invalid = true
+		}
+	}
+
+	if !invalid {
+		return nil
+	}
+	return &models.ErrorResponse{
+		Code:    ErrInvalidCertStats,
+		Message: "证书数据无效",
+		Details: map[string]interface{}{
+			"tls_version":         req.TLSVersion,
+			"tls_cipher":          req.TLSCipher,
+			"cert_not_after":      req.CertNotAfter,
+			"cert_days_remaining": req.CertDaysRemaining,
+		},
+	}
+}
+
+// validateEchoFields validates the optional udp_ping responder statistics.
+// One-way delays may be negative when the two clocks are slightly apart.
+func validateEchoFields(req *models.HeartbeatRequest) *models.ErrorResponse {
+	invalid := req.ReorderedPackets < 0 || req.DuplicatePackets < 0
+	if req.SampleCount > 0 && req.ReorderedPackets > req.SampleCount {
+		invalid = true
+	}
+	for _, delay := range []*float64{req.OneWayForwardMs, req.OneWayReverseMs} {
+		if delay != nil && (*delay < -60000 || *delay > 60000) {
+			invalid = true
+		}
+	}
+
+	if !invalid {
+		return nil
+	}
+	return &models.ErrorResponse{
+		Code:    ErrInvalidProbeStats,
+		Message: "探测统计数据超出范围",
+		Details: map[string]interface{}{
+			"reordered_packets":  req.ReorderedPackets,
+			"duplicate_packets":  req.DuplicatePackets,
+			"one_way_forward_ms": req.OneWayForwardMs,
+			"one_way_reverse_ms": req.OneWayReverseMs,
+		},
+	}
+}
+
+// validateLatencyStatistics validates the optional RTT statistics: each within
+// the latency range, and min <= p90 <= p95 <= p99 <= max for those present
+func validateLatencyStatistics(req *models.HeartbeatRequest) *models.ErrorResponse {
+	stats := req.LatencyStatistics
+	invalid := false
+	for _, value := range []*float64{stats.LatencyStdDevMs, stats.JitterRFC3550Ms} {
+		if value != nil && (*value < 0 || *value > 60000) {
+			invalid = true
+		}
+	}
+	previous := 0.0
+	for _, value := range []*float64{stats.LatencyMinMs, stats.LatencyP90Ms, stats.LatencyP95Ms, stats.LatencyP99Ms, stats.LatencyMaxMs} {
+		if value == nil {
+			continue
+		}
+		if *value < previous || *value > 60000 {
+			invalid = true
+		}
+		previous = *value
+	}
+
+	if !invalid {
+		return nil
+	}
+	return &models.ErrorResponse{
+		Code:    ErrInvalidProbeStats,
+		Message: "探测统计数据超出范围",
+		Details: map[string]interface{}{
+			"latency_min_ms":    stats.LatencyMinMs,
+			"latency_max_ms":    stats.LatencyMaxMs,
+			"latency_stddev_ms": stats.LatencyStdDevMs,
+			"latency_p90_ms":    stats.LatencyP90Ms,
+			"latency_p95_ms":    stats.LatencyP95Ms,
+			"latency_p99_ms":    stats.LatencyP99Ms,
+			"jitter_rfc3550_ms": stats.JitterRFC3550Ms,
+		},
+	}
+}
+
+// validateSamples validates the optional raw samples: at most
+// MaxProbeSamples, each with a valid timestamp, an RTT within range and an
+// error class from SampleErrorClasses when it failed
+func validateSamples(req *models.HeartbeatRequest) *models.ErrorResponse {
+	invalid := func(index int, reason string) *models.ErrorResponse {
+		return &models.ErrorResponse{
+			Code:    ErrInvalidSamples,
+			Message: "采样数据无效",
+			Details: map[string]interface{}{
+				"field":  "samples",
+				"index":  index,
+				"reason": reason,
+			},
+		}
+	}
+
+	if len(req.Samples) > models.MaxProbeSamples {
+		return invalid(models.MaxProbeSamples, "too many samples")
+	}
+
+	for i, sample := range req.Samples {
+		if _, err := time.Parse(time.RFC3339, sample.Timestamp); err != nil {
+			return invalid(i, "timestamp is not ISO 8601")
+		}
+		if sample.RTTMs < 0 || sample.RTTMs > 60000 {
+			return invalid(i, "rtt out of range")
+		}
+		if sample.Success && sample.ErrorClass != "" {
+			return invalid(i, "error_class set on a successful sample")
+		}
+		if !sample.Success && sample.ErrorClass != "" && !isSampleErrorClass(sample.ErrorClass) {
+			return invalid(i, "unknown error_class")
+		}
+	}
+
+	return nil
+}
+
+// isSampleErrorClass reports whether class is a known sample error class
+func isSampleErrorClass(class string) bool {
+	for _, c := range models.SampleErrorClasses {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePathHops validates the optional path_probe hop list: at most
+// MaxPathHops hops in increasing TTL order, each with an IP address (or none
+// for a silent hop) and statistics within range
+func validatePathHops(req *models.HeartbeatRequest) *models.ErrorResponse {
+	invalid := func(index int, reason string) *models.ErrorResponse {
+		return &models.ErrorResponse{
+			Code:    ErrInvalidPathStats,
+			Message: "路径数据无效",
+			Details: map[string]interface{}{
+				"field":  "hops",
+				"index":  index,
+				"reason": reason,
+			},
+		}
+	}
+
+	if len(req.Hops) > models.MaxPathHops {
+		return invalid(models.MaxPathHops, "too many hops")
+	}
+
+	previousTTL := 0
+	for i, hop := range req.Hops {
+		if hop.TTL <= previousTTL || hop.TTL > models.MaxPathHops {
+			return invalid(i, "ttl out of order or out of range")
+		}
+		previousTTL = hop.TTL
+
+		if hop.Address != "" && net.ParseIP(hop.Address) == nil {
+			return invalid(i, "address is not an IP address")
+		}
+		if hop.Sent < 0 || hop.Received < 0 || hop.Received > hop.Sent || hop.Sent > 100 {
+			return invalid(i, "sent/received out of range")
+		}
+		if hop.PacketLossRate < 0 || hop.PacketLossRate > 100 {
+			return invalid(i, "packet_loss_rate out of range")
+		}
+		if hop.RTTMs < 0 || hop.RTTMs > 60000 || hop.BestMs < 0 || hop.WorstMs < hop.BestMs || hop.WorstMs > 60000 {
+			return invalid(i, "rtt out of range")
+		}
+	}
+
+	return nil
+}
+
+// pathHash identifies the route of a trace by the sequence of responding
+// hop addresses. Silent hops are skipped so that a router dropping some
+// replies does not count as a path change.
+func pathHash(hops []models.PathHop) string {
+	addresses := make([]string, 0, len(hops))
+	for _, hop := range hops {
+		if hop.Address != "" {
+			addresses = append(addresses, hop.Address)
+		}
+	}
+	sum := sha256.Sum256([]byte(strings.Join(addresses, ",")))
+	return hex.EncodeToString(sum[:])
+}
+
+// cacheHeartbeat writes a validated heartbeat to the memory cache and returns
+// the record to persist, or nil when the probe is not registered in Pulse,
+// is not a mesh probe and carries neither a path trace nor a certificate.
+func (h *BeaconHandler) cacheHeartbeat(req *models.HeartbeatRequest, parsedTime time.Time) *cache.MetricRecord {
 	// Write to memory cache (Story 3.2 implementation)
 	metricPoint := &cache.MetricPoint{
 		Timestamp:      parsedTime,
+		ProbeID:        req.ProbeID,
 		LatencyMs:      req.LatencyMs,
 		PacketLossRate: req.PacketLossRate,
 		JitterMs:       req.JitterMs,
@@ -179,8 +683,36 @@ func (h *BeaconHandler) HandleHeartbeat(c *gin.Context) {
 		// Don't return error to avoid affecting Beacon reporting
 	}
 
-	// Send to batch writer buffer (non-blocking)
-	metricRecord := &cache.MetricRecord{
+	// Mesh probe results go to the node pair's mesh_matrix entry
+	if peerID, ok := meshPeerID(req.ProbeID); ok && peerID != strings.ToLower(req.NodeID) {
+		return &cache.MetricRecord{
+			NodeID:           req.NodeID,
+			ProbeID:          req.ProbeID,
+			Timestamp:        parsedTime,
+			LatencyMs:        req.LatencyMs,
+			PacketLossRate:   req.PacketLossRate,
+			JitterMs:         req.JitterMs,
+			SampleCount:      req.SampleCount,
+			Success:          req.Success,
+			ProbeType:        req.ProbeType,
+			MeshTargetNodeID: peerID,
+		}
+	}
+
+	// metrics.probe_id references probes(id), so only probes configured in
+	// Pulse (UUID IDs) are persisted; other probes stay in the memory cache.
+	// Path traces and certificates are stored for every probe.
+	_, err := uuid.Parse(req.ProbeID)
+	registered := err == nil
+	certificate := certificateInfo(req)
+	if !registered && len(req.Hops) == 0 && certificate == nil {
+		slog.Debug("Probe is not registered in Pulse, skipping persistence",
+			"node_id", req.NodeID,
+			"probe_id", req.ProbeID)
+		return nil
+	}
+
+	record := &cache.MetricRecord{
 		NodeID:         req.NodeID,
 		ProbeID:        req.ProbeID,
 		Timestamp:      parsedTime,
@@ -188,28 +720,91 @@ func (h *BeaconHandler) HandleHeartbeat(c *gin.Context) {
 		PacketLossRate: req.PacketLossRate,
 		JitterMs:       req.JitterMs,
 		IsAggregated:   false,
+
+		LatencyMedianMs: req.LatencyMedianMs,
+		VarianceMs:      req.VarianceMs,
+		SampleCount:     req.SampleCount,
+		Success:         req.Success,
+		Partial:         req.Partial,
+		Statistics:      req.LatencyStatistics,
+
+		ReorderedPackets: req.ReorderedPackets,
+		DuplicatePackets: req.DuplicatePackets,
+		OneWayForwardMs:  req.OneWayForwardMs,
+		OneWayReverseMs:  req.OneWayReverseMs,
+
+		Target:       req.Target,
+		Certificate:  certificate,
+		Unregistered: !registered,
+	}
+	if registered {
+		record.Samples = sampleRecords(req.Samples)
 	}
+	if len(req.Hops) > 0 {
+		record.PathHops = req.Hops
+		record.PathHash = pathHash(req.Hops)
+		record.PathReached = req.Success != nil && *req.Success
+	}
+	return record
+}
 
-	if err := h.batchWriter.Write(metricRecord); err != nil {
-		if err == cache.ErrBufferFull {
-			slog.Warn("Batch writer buffer full, dropping metric",
-				"node_id", req.NodeID,
-				"probe_id", req.ProbeID)
-		} else {
-			slog.Error("Failed to write to batch buffer",
-				"node_id", req.NodeID,
-				"error", err)
+// sampleRecords converts validated raw samples for persistence in probe_samples
+func sampleRecords(samples []models.ProbeSample) []cache.SampleRecord {
+	if len(samples) == 0 {
+		return nil
+	}
+	records := make([]cache.SampleRecord, 0, len(samples))
+	for _, sample := range samples {
+		timestamp, err := time.Parse(time.RFC3339, sample.Timestamp)
+		if err != nil {
+			continue
 		}
-		// Don't return error to avoid affecting Beacon reporting
+		records = append(records, cache.SampleRecord{
+			Timestamp:  timestamp,
+			Success:    sample.Success,
+			RTTMs:      sample.RTTMs,
+			ErrorClass: sample.ErrorClass,
+		})
 	}
+	return records
+}
 
-	c.JSON(http.StatusOK, models.HeartbeatSuccessResponse{
-		Data: models.HeartbeatData{
-			Received:  true,
-			NodeID:    req.NodeID,
-			Timestamp: time.Now(),
-		},
-		Message:   "心跳数据接收成功",
-		Timestamp: time.Now().Format(time.RFC3339),
-	})
+// certificateInfo returns the certificate status of a tls_probe heartbeat,
+// or nil when the heartbeat carries no certificate
+func certificateInfo(req *models.HeartbeatRequest) *cache.CertificateInfo {
+	notAfter := parseCertNotAfter(req.CertNotAfter)
+	if notAfter == nil {
+		return nil
+	}
+	return &cache.CertificateInfo{
+		NotAfter:   *notAfter,
+		ChainValid: req.CertChainValid,
+		TLSVersion: req.TLSVersion,
+		TLSCipher:  req.TLSCipher,
+	}
+}
+
+// parseCertNotAfter parses a validated cert_not_after value; empty gives nil
+func parseCertNotAfter(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	notAfter, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &notAfter
+}
+
+// logBatchWriteError logs a failed batch writer enqueue
+func logBatchWriteError(err error, nodeID string, count int) {
+	if err == cache.ErrBufferFull {
+		slog.Warn("Batch writer buffer full, dropping metrics",
+			"node_id", nodeID,
+			"count", count)
+		return
+	}
+	slog.Error("Failed to write to batch buffer",
+		"node_id", nodeID,
+		"error", err)
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/kevin/node-pulse/pulse-api/internal/auth"
 	"github.com/kevin/node-pulse/pulse-api/internal/cache"
 	"github.com/kevin/node-pulse/pulse-api/internal/db"
 	"github.com/kevin/node-pulse/pulse-api/internal/models"
@@ -18,17 +20,38 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// authenticateRequestNode stands in for auth.BeaconAuthMiddleware in handler
+// tests: it authenticates the request as the node it targets (the :node_id
+// path parameter, or the node_id of the JSON body)
+func authenticateRequestNode() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		nodeID := c.Param("node_id")
+		if nodeID == "" && c.Request.Body != nil {
+			body, _ := io.ReadAll(c.Request.Body)
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+			var req struct {
+				NodeID string `json:"node_id"`
+			}
+			_ = json.Unmarshal(body, &req)
+			nodeID = req.NodeID
+		}
+		c.Set(auth.BeaconNodeIDKey, nodeID)
+	}
+}
+
 // setupTestRouter creates a test router with beacon heartbeat endpoint
 func setupTestRouter(nodeQuerier db.NodesQuerier) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
+	router.Use(authenticateRequestNode())
 
 	// Create memory cache and batch writer for testing
 	memoryCache := cache.NewMemoryCache()
 	batchWriter := cache.NewBatchWriter(nil, 1000, 100) // nil DB for testing
 
-	beaconHandler := NewBeaconHandler(nodeQuerier, memoryCache, batchWriter)
+	beaconHandler := NewBeaconHandler(nodeQuerier, &MockNodeTokensQuerier{}, memoryCache, batchWriter)
 	router.POST("/api/v1/beacon/heartbeat", beaconHandler.HandleHeartbeat)
+	router.POST("/api/v1/beacon/heartbeats", beaconHandler.HandleHeartbeatBatch)
 
 	return router
 }
@@ -431,3 +454,816 @@ func TestHandleHeartbeat_InvalidProbeID_Returns400(t *testing.T) {
 		assert.Contains(t, resp.Message, "探针 ID 格式无效")
 	})
 }
+
+func TestHandleHeartbeat_PerProbeZeroLoss_Returns200(t *testing.T) {
+	// Arrange
+	testNodeID := uuid.New()
+	mockQuerier := &MockNodesQuerier{
+		getNodeByIDFunc: func(ctx context.Context, nodeID uuid.UUID) (*models.Node, error) {
+			return &models.Node{ID: testNodeID.String(), Name: "test-node"}, nil
+		},
+	}
+
+	router := setupTestRouter(mockQuerier)
+
+	success := true
+	reqBody := models.HeartbeatRequest{
+		NodeID:          testNodeID.String(),
+		ProbeID:         "tcp_ping:8.8.8.8:80",
+		ProbeType:       "tcp_ping",
+		Target:          "8.8.8.8",
+		Port:            80,
+		Success:         &success,
+		LatencyMs:       12.3,
+		LatencyMedianMs: 12.0,
+		VarianceMs:      0.4,
+		PacketLossRate:  0, // 0% loss must be accepted
+		JitterMs:        0,
+		SampleCount:     10,
+		Timestamp:       time.Now().Format(time.RFC3339),
+	}
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	req, _ := http.NewRequest("POST", "/api/v1/beacon/heartbeat", bytes.NewBuffer(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Act
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.HeartbeatSuccessResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	assert.Equal(t, "tcp_ping:8.8.8.8:80", resp.Data.ProbeID)
+}
+
+// TestHandleHeartbeat_ProbeTypes_Returns200 tests that every probe type in
+// heartbeatProbeTypes is accepted
+func TestHandleHeartbeat_ProbeTypes_Returns200(t *testing.T) {
+	testNodeID := uuid.New()
+	mockQuerier := &MockNodesQuerier{
+		getNodeByIDFunc: func(ctx context.Context, nodeID uuid.UUID) (*models.Node, error) {
+			return &models.Node{ID: testNodeID.String(), Name: "test-node"}, nil
+		},
+	}
+	router := setupTestRouter(mockQuerier)
+
+	for _, probeType := range heartbeatProbeTypes {
+		t.Run(probeType, func(t *testing.T) {
+			// Arrange
+			success := true
+			reqBody := models.HeartbeatRequest{
+				NodeID:         testNodeID.String(),
+				ProbeID:        probeType + ":10.0.0.1:443",
+				ProbeType:      probeType,
+				Target:         "10.0.0.1",
+				Port:           443,
+				Success:        &success,
+				LatencyMs:      12.3,
+				PacketLossRate: 10,
+				JitterMs:       0.4,
+				SampleCount:    10,
+				Timestamp:      time.Now().Format(time.RFC3339),
+			}
+
+			bodyBytes, _ := json.Marshal(reqBody)
+			req, _ := http.NewRequest("POST", "/api/v1/beacon/heartbeat", bytes.NewBuffer(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+
+			// Act
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var resp models.HeartbeatSuccessResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, reqBody.ProbeID, resp.Data.ProbeID)
+		})
+	}
+}
+
+func TestHandleHeartbeat_InvalidHTTPFields_Returns400(t *testing.T) {
+	testNodeID := uuid.New()
+	mockQuerier := &MockNodesQuerier{
+		getNodeByIDFunc: func(ctx context.Context, nodeID uuid.UUID) (*models.Node, error) {
+			return &models.Node{ID: testNodeID.String(), Name: "test-node"}, nil
+		},
+	}
+
+	router := setupTestRouter(mockQuerier)
+	negative := -1.0
+	tooLarge := 90000.0
+
+	tests := []struct {
+		name   string
+		mutate func(req *models.HeartbeatRequest)
+	}{
+		{name: "negative phase time", mutate: func(req *models.HeartbeatRequest) { req.ConnectMs = &negative }},
+		{name: "phase time out of range", mutate: func(req *models.HeartbeatRequest) { req.TTFBMs = &tooLarge }},
+		{name: "invalid status code", mutate: func(req *models.HeartbeatRequest) { req.StatusCode = 42 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqBody := models.HeartbeatRequest{
+				NodeID:      testNodeID.String(),
+				ProbeID:     "http_probe:https://api.example.com/health",
+				ProbeType:   "http_probe",
+				Target:      "https://api.example.com/health",
+				LatencyMs:   85.0,
+				SampleCount: 10,
+				StatusCode:  200,
+				Timestamp:   time.Now().Format(time.RFC3339),
+			}
+			tt.mutate(&reqBody)
+
+			bodyBytes, _ := json.Marshal(reqBody)
+			req, _ := http.NewRequest("POST", "/api/v1/beacon/heartbeat", bytes.NewBuffer(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, ErrInvalidProbeStats, resp.Code)
+		})
+	}
+}
+
+func TestHandleHeartbeat_InvalidDNSFields_Returns400(t *testing.T) {
+	testNodeID := uuid.New()
+	mockQuerier := &MockNodesQuerier{
+		getNodeByIDFunc: func(ctx context.Context, nodeID uuid.UUID) (*models.Node, error) {
+			return &models.Node{ID: testNodeID.String(), Name: "test-node"}, nil
+		},
+	}
+
+	router := setupTestRouter(mockQuerier)
+	badRcode := -2
+	tooManyAnswers := 70000
+
+	tests := []struct {
+		name   string
+		mutate func(req *models.HeartbeatRequest)
+	}{
+		{name: "rcode out of range", mutate: func(req *models.HeartbeatRequest) { req.Rcode = &badRcode }},
+		{name: "answer count out of range", mutate: func(req *models.HeartbeatRequest) { req.AnswerCount = &tooManyAnswers }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqBody := models.HeartbeatRequest{
+				NodeID:      testNodeID.String(),
+				ProbeID:     "dns_probe:10.0.0.53:53/example.com/A",
+				ProbeType:   "dns_probe",
+				Target:      "10.0.0.53",
+				Port:        53,
+				LatencyMs:   3.2,
+				SampleCount: 10,
+				Timestamp:   time.Now().Format(time.RFC3339),
+			}
+			tt.mutate(&reqBody)
+
+			bodyBytes, _ := json.Marshal(reqBody)
+			req, _ := http.NewRequest("POST", "/api/v1/beacon/heartbeat", bytes.NewBuffer(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, ErrInvalidProbeStats, resp.Code)
+		})
+	}
+}
+
+func TestCacheHeartbeat_CertificateOfLocalProbe(t *testing.T) {
+	handler := NewBeaconHandler(&MockNodesQuerier{}, &MockNodeTokensQuerier{}, cache.NewMemoryCache(), cache.NewBatchWriter(nil, 1000, 100))
+	chainValid := true
+	notAfter := time.Now().Add(300 * time.Hour).UTC().Truncate(time.Second)
+
+	// A tls_probe that completed a handshake persists only its certificate
+	record := handler.cacheHeartbeat(&models.HeartbeatRequest{
+		NodeID: uuid.New().String(), ProbeID: "tls_probe:example.com:443", ProbeType: "tls_probe",
+		Target: "example.com", Port: 443, TLSVersion: "TLS 1.3", TLSCipher: "TLS_AES_128_GCM_SHA256",
+		CertChainValid: &chainValid, CertNotAfter: notAfter.Format(time.RFC3339),
+	}, time.Now())
+	require.NotNil(t, record)
+	assert.True(t, record.Unregistered)
+	assert.Equal(t, "example.com", record.Target)
+	require.NotNil(t, record.Certificate)
+	assert.True(t, notAfter.Equal(record.Certificate.NotAfter))
+	assert.Equal(t, "TLS 1.3", record.Certificate.TLSVersion)
+	assert.Equal(t, &chainValid, record.Certificate.ChainValid)
+
+	// A tls_probe without a handshake is not persisted
+	assert.Nil(t, handler.cacheHeartbeat(&models.HeartbeatRequest{
+		NodeID: uuid.New().String(), ProbeID: "tls_probe:10.0.0.9:443", ProbeType: "tls_probe", Target: "10.0.0.9",
+	}, time.Now()))
+}
+
+func TestHandleHeartbeat_InvalidCertNotAfter_Returns400(t *testing.T) {
+	// Arrange
+	testNodeID := uuid.New()
+	mockQuerier := &MockNodesQuerier{
+		getNodeByIDFunc: func(ctx context.Context, nodeID uuid.UUID) (*models.Node, error) {
+			return &models.Node{ID: testNodeID.String(), Name: "test-node"}, nil
+		},
+	}
+
+	router := setupTestRouter(mockQuerier)
+
+	reqBody := models.HeartbeatRequest{
+		NodeID:       testNodeID.String(),
+		ProbeID:      "tls_probe:example.com:443",
+		ProbeType:    "tls_probe",
+		Target:       "example.com",
+		Port:         443,
+		LatencyMs:    18.2,
+		Timestamp:    time.Now().Format(time.RFC3339),
+		CertNotAfter: "next tuesday",
+	}
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	req, _ := http.NewRequest("POST", "/api/v1/beacon/heartbeat", bytes.NewBuffer(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Act
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp models.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	assert.Equal(t, ErrInvalidCertStats, resp.Code)
+}
+
+func TestCacheHeartbeat_PathTraceOfLocalProbe(t *testing.T) {
+	handler := NewBeaconHandler(&MockNodesQuerier{}, &MockNodeTokensQuerier{}, cache.NewMemoryCache(), cache.NewBatchWriter(nil, 1000, 100))
+	success := true
+	hops := []models.PathHop{{TTL: 1, Address: "8.8.8.8", Sent: 10, Received: 10}}
+
+	// A local probe carrying a trace persists only the trace
+	record := handler.cacheHeartbeat(&models.HeartbeatRequest{
+		NodeID: uuid.New().String(), ProbeID: "path_probe:8.8.8.8", ProbeType: "path_probe",
+		Target: "8.8.8.8", Success: &success, Hops: hops,
+	}, time.Now())
+	require.NotNil(t, record)
+	assert.True(t, record.Unregistered)
+	assert.True(t, record.PathReached)
+	assert.Equal(t, pathHash(hops), record.PathHash)
+
+	// A local probe without a trace is not persisted
+	assert.Nil(t, handler.cacheHeartbeat(&models.HeartbeatRequest{
+		NodeID: uuid.New().String(), ProbeID: "icmp_ping:8.8.8.8", ProbeType: "icmp_ping",
+	}, time.Now()))
+}
+
+func TestHandleHeartbeat_InvalidPathHops_Returns400(t *testing.T) {
+	testNodeID := uuid.New()
+	mockQuerier := &MockNodesQuerier{
+		getNodeByIDFunc: func(ctx context.Context, nodeID uuid.UUID) (*models.Node, error) {
+			return &models.Node{ID: testNodeID.String(), Name: "test-node"}, nil
+		},
+	}
+
+	router := setupTestRouter(mockQuerier)
+
+	tests := []struct {
+		name string
+		hops []models.PathHop
+	}{
+		{name: "ttl out of order", hops: []models.PathHop{{TTL: 2, Sent: 1}, {TTL: 1, Sent: 1}}},
+		{name: "address not an IP", hops: []models.PathHop{{TTL: 1, Address: "router.example.com", Sent: 1, Received: 1}}},
+		{name: "received exceeds sent", hops: []models.PathHop{{TTL: 1, Address: "10.0.0.1", Sent: 1, Received: 2}}},
+		{name: "loss out of range", hops: []models.PathHop{{TTL: 1, Sent: 1, PacketLossRate: 150}}},
+		{name: "too many hops", hops: make([]models.PathHop, models.MaxPathHops+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqBody := models.HeartbeatRequest{
+				NodeID:    testNodeID.String(),
+				ProbeID:   "path_probe:8.8.8.8",
+				ProbeType: "path_probe",
+				Target:    "8.8.8.8",
+				LatencyMs: 12.1,
+				Timestamp: time.Now().Format(time.RFC3339),
+				Hops:      tt.hops,
+			}
+
+			bodyBytes, _ := json.Marshal(reqBody)
+			req, _ := http.NewRequest("POST", "/api/v1/beacon/heartbeat", bytes.NewBuffer(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, ErrInvalidPathStats, resp.Code)
+		})
+	}
+}
+
+func TestCacheHeartbeat_UDPEchoFields(t *testing.T) {
+	handler := NewBeaconHandler(&MockNodesQuerier{}, &MockNodeTokensQuerier{}, cache.NewMemoryCache(), cache.NewBatchWriter(nil, 1000, 100))
+	forward, reverse := 4.2, -0.3
+
+	record := handler.cacheHeartbeat(&models.HeartbeatRequest{
+		NodeID: uuid.New().String(), ProbeID: uuid.New().String(), ProbeType: "udp_ping",
+		Target: "10.0.0.20", Port: 7331, SampleCount: 10,
+		ReorderedPackets: 2, DuplicatePackets: 1, OneWayForwardMs: &forward, OneWayReverseMs: &reverse,
+	}, time.Now())
+
+	require.NotNil(t, record)
+	assert.Equal(t, 2, record.ReorderedPackets)
+	assert.Equal(t, 1, record.DuplicatePackets)
+	require.NotNil(t, record.OneWayForwardMs)
+	assert.Equal(t, 4.2, *record.OneWayForwardMs)
+	require.NotNil(t, record.OneWayReverseMs)
+	assert.Equal(t, -0.3, *record.OneWayReverseMs)
+}
+
+func TestCacheHeartbeat_MeshProbe(t *testing.T) {
+	handler := NewBeaconHandler(&MockNodesQuerier{}, &MockNodeTokensQuerier{}, cache.NewMemoryCache(), cache.NewBatchWriter(nil, 1000, 100))
+	nodeID, peerID := uuid.New().String(), uuid.New().String()
+	success := true
+
+	// Mesh results are persisted to the node pair's matrix entry
+	record := handler.cacheHeartbeat(&models.HeartbeatRequest{
+		NodeID: nodeID, ProbeID: "mesh:" + peerID, ProbeType: "udp_ping",
+		Target: "10.0.0.2", Port: 7331, Success: &success, LatencyMs: 1.5, SampleCount: 10,
+	}, time.Now())
+	require.NotNil(t, record)
+	assert.Equal(t, peerID, record.MeshTargetNodeID)
+	assert.Equal(t, "udp_ping", record.ProbeType)
+	assert.Equal(t, 1.5, record.LatencyMs)
+
+	// A node cannot report a mesh result against itself
+	assert.Nil(t, handler.cacheHeartbeat(&models.HeartbeatRequest{
+		NodeID: nodeID, ProbeID: "mesh:" + nodeID, ProbeType: "udp_ping",
+	}, time.Now()))
+}
+
+func TestHandleHeartbeat_InvalidUDPEchoFields_Returns400(t *testing.T) {
+	testNodeID := uuid.New()
+	mockQuerier := &MockNodesQuerier{
+		getNodeByIDFunc: func(ctx context.Context, nodeID uuid.UUID) (*models.Node, error) {
+			return &models.Node{ID: testNodeID.String(), Name: "test-node"}, nil
+		},
+	}
+
+	router := setupTestRouter(mockQuerier)
+	tooLarge := 90000.0
+
+	tests := []struct {
+		name   string
+		mutate func(req *models.HeartbeatRequest)
+	}{
+		{name: "negative duplicates", mutate: func(req *models.HeartbeatRequest) { req.DuplicatePackets = -1 }},
+		{name: "more reordered than samples", mutate: func(req *models.HeartbeatRequest) { req.ReorderedPackets = 11 }},
+		{name: "one-way delay out of range", mutate: func(req *models.HeartbeatRequest) { req.OneWayForwardMs = &tooLarge }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqBody := models.HeartbeatRequest{
+				NodeID:      testNodeID.String(),
+				ProbeID:     "udp_ping:10.0.0.20:7331",
+				ProbeType:   "udp_ping",
+				Target:      "10.0.0.20",
+				Port:        7331,
+				LatencyMs:   9.3,
+				SampleCount: 10,
+				Timestamp:   time.Now().Format(time.RFC3339),
+			}
+			tt.mutate(&reqBody)
+
+			bodyBytes, _ := json.Marshal(reqBody)
+			req, _ := http.NewRequest("POST", "/api/v1/beacon/heartbeat", bytes.NewBuffer(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, ErrInvalidProbeStats, resp.Code)
+		})
+	}
+}
+
+func TestCacheHeartbeat_LatencyStatistics(t *testing.T) {
+	handler := NewBeaconHandler(&MockNodesQuerier{}, &MockNodeTokensQuerier{}, cache.NewMemoryCache(), cache.NewBatchWriter(nil, 1000, 100))
+	p95, jitter := 24.7, 1.3
+
+	record := handler.cacheHeartbeat(&models.HeartbeatRequest{
+		NodeID: uuid.New().String(), ProbeID: uuid.New().String(), ProbeType: "tcp_ping",
+		Target: "8.8.8.8", Port: 80, LatencyMs: 12.5, SampleCount: 10,
+		LatencyStatistics: models.LatencyStatistics{LatencyP95Ms: &p95, JitterRFC3550Ms: &jitter},
+	}, time.Now())
+
+	require.NotNil(t, record)
+	require.NotNil(t, record.Statistics.LatencyP95Ms)
+	assert.Equal(t, 24.7, *record.Statistics.LatencyP95Ms)
+	require.NotNil(t, record.Statistics.JitterRFC3550Ms)
+	assert.Equal(t, 1.3, *record.Statistics.JitterRFC3550Ms)
+	assert.Nil(t, record.Statistics.LatencyMinMs)
+	assert.Nil(t, record.Statistics.LatencyP99Ms)
+}
+
+func TestHandleHeartbeat_InvalidLatencyStatistics_Returns400(t *testing.T) {
+	testNodeID := uuid.New()
+	mockQuerier := &MockNodesQuerier{
+		getNodeByIDFunc: func(ctx context.Context, nodeID uuid.UUID) (*models.Node, error) {
+			return &models.Node{ID: testNodeID.String(), Name: "test-node"}, nil
+		},
+	}
+
+	router := setupTestRouter(mockQuerier)
+	negative, tooLarge, low, high := -1.0, 90000.0, 10.0, 20.0
+
+	tests := []struct {
+		name   string
+		mutate func(req *models.HeartbeatRequest)
+	}{
+		{name: "negative stddev", mutate: func(req *models.HeartbeatRequest) { req.LatencyStdDevMs = &negative }},
+		{name: "p99 out of range", mutate: func(req *models.HeartbeatRequest) { req.LatencyP99Ms = &tooLarge }},
+		{name: "p95 above max", mutate: func(req *models.HeartbeatRequest) { req.LatencyP95Ms, req.LatencyMaxMs = &high, &low }},
+		{name: "min above p90", mutate: func(req *models.HeartbeatRequest) { req.LatencyMinMs, req.LatencyP90Ms = &high, &low }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqBody := models.HeartbeatRequest{
+				NodeID:      testNodeID.String(),
+				ProbeID:     "tcp_ping:8.8.8.8:80",
+				ProbeType:   "tcp_ping",
+				Target:      "8.8.8.8",
+				Port:        80,
+				LatencyMs:   12.5,
+				SampleCount: 10,
+				Timestamp:   time.Now().Format(time.RFC3339),
+			}
+			tt.mutate(&reqBody)
+
+			bodyBytes, _ := json.Marshal(reqBody)
+			req, _ := http.NewRequest("POST", "/api/v1/beacon/heartbeat", bytes.NewBuffer(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, ErrInvalidProbeStats, resp.Code)
+		})
+	}
+}
+
+func TestCacheHeartbeat_Samples(t *testing.T) {
+	handler := NewBeaconHandler(&MockNodesQuerier{}, &MockNodeTokensQuerier{}, cache.NewMemoryCache(), cache.NewBatchWriter(nil, 1000, 100))
+	now := time.Now().UTC().Truncate(time.Second)
+	samples := []models.ProbeSample{
+		{Timestamp: now.Format(time.RFC3339), Success: true, RTTMs: 12.5},
+		{Timestamp: now.Add(time.Second).Format(time.RFC3339), Success: false, ErrorClass: "timeout"},
+	}
+
+	// Samples of registered probes are persisted with the aggregate
+	record := handler.cacheHeartbeat(&models.HeartbeatRequest{
+		NodeID: uuid.New().String(), ProbeID: uuid.New().String(), ProbeType: "tcp_ping",
+		Target: "8.8.8.8", Port: 80, LatencyMs: 12.5, SampleCount: 2, Samples: samples,
+	}, now)
+	require.NotNil(t, record)
+	require.Len(t, record.Samples, 2)
+	assert.True(t, record.Samples[0].Timestamp.Equal(now))
+	assert.True(t, record.Samples[0].Success)
+	assert.Equal(t, 12.5, record.Samples[0].RTTMs)
+	assert.False(t, record.Samples[1].Success)
+	assert.Equal(t, "timeout", record.Samples[1].ErrorClass)
+
+	// Mesh results never carry samples
+	record = handler.cacheHeartbeat(&models.HeartbeatRequest{
+		NodeID: uuid.New().String(), ProbeID: "mesh:" + uuid.New().String(), ProbeType: "udp_ping",
+		Target: "10.0.0.2", Port: 7331, LatencyMs: 1.5, SampleCount: 2, Samples: samples,
+	}, now)
+	require.NotNil(t, record)
+	assert.Empty(t, record.Samples)
+}
+
+func TestCacheHeartbeat_Partial(t *testing.T) {
+	handler := NewBeaconHandler(&MockNodesQuerier{}, &MockNodeTokensQuerier{}, cache.NewMemoryCache(), cache.NewBatchWriter(nil, 1000, 100))
+	now := time.Now().UTC().Truncate(time.Second)
+
+	record := handler.cacheHeartbeat(&models.HeartbeatRequest{
+		NodeID: uuid.New().String(), ProbeID: uuid.New().String(), ProbeType: "tcp_ping",
+		Target: "8.8.8.8", Port: 80, LatencyMs: 12.5, SampleCount: 4, Partial: true,
+	}, now)
+	require.NotNil(t, record)
+	assert.True(t, record.Partial)
+
+	// Beacons without abortable batches omit the field
+	var req models.HeartbeatRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"node_id":"n","probe_id":"p","timestamp":"t"}`), &req))
+	assert.False(t, req.Partial)
+}
+
+func TestHandleHeartbeat_InvalidSamples_Returns400(t *testing.T) {
+	testNodeID := uuid.New()
+	mockQuerier := &MockNodesQuerier{
+		getNodeByIDFunc: func(ctx context.Context, nodeID uuid.UUID) (*models.Node, error) {
+			return &models.Node{ID: testNodeID.String(), Name: "test-node"}, nil
+		},
+	}
+
+	router := setupTestRouter(mockQuerier)
+	timestamp := time.Now().Format(time.RFC3339)
+
+	tests := []struct {
+		name   string
+		sample models.ProbeSample
+	}{
+		{name: "invalid timestamp", sample: models.ProbeSample{Timestamp: "yesterday", Success: true, RTTMs: 1}},
+		{name: "negative rtt", sample: models.ProbeSample{Timestamp: timestamp, Success: true, RTTMs: -1}},
+		{name: "error class on success", sample: models.ProbeSample{Timestamp: timestamp, Success: true, RTTMs: 1, ErrorClass: "timeout"}},
+		{name: "unknown error class", sample: models.ProbeSample{Timestamp: timestamp, ErrorClass: "gremlins"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqBody := models.HeartbeatRequest{
+				NodeID:      testNodeID.String(),
+				ProbeID:     "tcp_ping:8.8.8.8:80",
+				ProbeType:   "tcp_ping",
+				Target:      "8.8.8.8",
+				Port:        80,
+				LatencyMs:   12.5,
+				SampleCount: 10,
+				Timestamp:   timestamp,
+				Samples:     []models.ProbeSample{tt.sample},
+			}
+
+			bodyBytes, _ := json.Marshal(reqBody)
+			req, _ := http.NewRequest("POST", "/api/v1/beacon/heartbeat", bytes.NewBuffer(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, ErrInvalidSamples, resp.Code)
+		})
+	}
+}
+
+func TestHandleHeartbeat_InvalidProbeType_Returns400(t *testing.T) {
+	// Arrange
+	testNodeID := uuid.New()
+	mockQuerier := &MockNodesQuerier{
+		getNodeByIDFunc: func(ctx context.Context, nodeID uuid.UUID) (*models.Node, error) {
+			return &models.Node{ID: testNodeID.String(), Name: "test-node"}, nil
+		},
+	}
+
+	router := setupTestRouter(mockQuerier)
+
+	reqBody := models.HeartbeatRequest{
+		NodeID:    testNodeID.String(),
+		ProbeID:   "probe-001",
+		ProbeType: "smtp_ping",
+		LatencyMs: 10,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	req, _ := http.NewRequest("POST", "/api/v1/beacon/heartbeat", bytes.NewBuffer(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Act
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp models.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	assert.Equal(t, ErrInvalidProbeType, resp.Code)
+}
+
+func TestHandleHeartbeatBatch_PerItemStatus(t *testing.T) {
+	// Arrange
+	testNodeID := uuid.New()
+	lookups := 0
+	mockQuerier := &MockNodesQuerier{
+		getNodeByIDFunc: func(ctx context.Context, nodeID uuid.UUID) (*models.Node, error) {
+			lookups++
+			return &models.Node{ID: testNodeID.String(), Name: "test-node"}, nil
+		},
+	}
+
+	router := setupTestRouter(mockQuerier)
+
+	now := time.Now().Format(time.RFC3339)
+	reqBody := models.HeartbeatBatchRequest{
+		NodeID: testNodeID.String(),
+		Heartbeats: []models.HeartbeatRequest{
+			{ProbeID: uuid.New().String(), LatencyMs: 10, Timestamp: now},
+			{ProbeID: "tcp_ping:8.8.8.8:80", LatencyMs: 70000, Timestamp: now},
+			{NodeID: uuid.New().String(), ProbeID: "udp_ping:1.1.1.1:53", Timestamp: now},
+			{ProbeID: "udp_ping:8.8.4.4:53", PacketLossRate: 0, Timestamp: now},
+		},
+	}
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	req, _ := http.NewRequest("POST", "/api/v1/beacon/heartbeats", bytes.NewBuffer(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Act
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, lookups, "node should be looked up once per batch")
+
+	var resp models.HeartbeatBatchResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.Data.Accepted)
+	assert.Equal(t, 2, resp.Data.Rejected)
+	require.Len(t, resp.Data.Results, 4)
+
+	assert.True(t, resp.Data.Results[0].Accepted)
+	assert.False(t, resp.Data.Results[1].Accepted)
+	assert.Equal(t, ErrInvalidLatency, resp.Data.Results[1].Error.Code)
+	assert.False(t, resp.Data.Results[2].Accepted)
+	assert.Equal(t, ErrNodeIDMismatch, resp.Data.Results[2].Error.Code)
+	assert.True(t, resp.Data.Results[3].Accepted)
+}
+
+func TestHandleHeartbeatBatch_NodeNotFound_Returns400(t *testing.T) {
+	// Arrange
+	mockQuerier := &MockNodesQuerier{
+		getNodeByIDFunc: func(ctx context.Context, nodeID uuid.UUID) (*models.Node, error) {
+			return nil, db.ErrNodeNotFound
+		},
+	}
+
+	router := setupTestRouter(mockQuerier)
+
+	reqBody := models.HeartbeatBatchRequest{
+		NodeID: uuid.New().String(),
+		Heartbeats: []models.HeartbeatRequest{
+			{ProbeID: "probe-001", LatencyMs: 10, Timestamp: time.Now().Format(time.RFC3339)},
+		},
+	}
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	req, _ := http.NewRequest("POST", "/api/v1/beacon/heartbeats", bytes.NewBuffer(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Act
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp models.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	assert.Equal(t, ErrNodeNotFound, resp.Code)
+}
+
+func TestHandleHeartbeatBatch_EmptyOrOversized_Returns400(t *testing.T) {
+	// Arrange
+	mockQuerier := &MockNodesQuerier{}
+	router := setupTestRouter(mockQuerier)
+
+	oversized := make([]models.HeartbeatRequest, MaxHeartbeatBatchSize+1)
+	cases := map[string][]models.HeartbeatRequest{
+		"empty":     {},
+		"oversized": oversized,
+	}
+
+	for name, heartbeats := range cases {
+		t.Run(name, func(t *testing.T) {
+			bodyBytes, _ := json.Marshal(models.HeartbeatBatchRequest{
+				NodeID:     uuid.New().String(),
+				Heartbeats: heartbeats,
+			})
+			req, _ := http.NewRequest("POST", "/api/v1/beacon/heartbeats", bytes.NewBuffer(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+
+			// Act
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestHandleHeartbeat_TokenForOtherNode_Returns403(t *testing.T) {
+	// Arrange - token belongs to another node
+	testNodeID := uuid.New()
+	tokenNodeID := uuid.New()
+	mockQuerier := &MockNodesQuerier{
+		getNodeByIDFunc: func(ctx context.Context, nodeID uuid.UUID) (*models.Node, error) {
+			return &models.Node{ID: nodeID.String()}, nil
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	beaconHandler := NewBeaconHandler(mockQuerier, &MockNodeTokensQuerier{}, cache.NewMemoryCache(), cache.NewBatchWriter(nil, 1000, 100))
+	router.Use(func(c *gin.Context) {
+		c.Set(auth.BeaconNodeIDKey, tokenNodeID.String())
+	})
+	router.POST("/api/v1/beacon/heartbeat", beaconHandler.HandleHeartbeat)
+
+	bodyBytes, _ := json.Marshal(models.HeartbeatRequest{
+		NodeID:    testNodeID.String(),
+		ProbeID:   "probe-001",
+		LatencyMs: 10,
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+	req, _ := http.NewRequest("POST", "/api/v1/beacon/heartbeat", bytes.NewBuffer(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Act
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), ErrNodeTokenMismatch)
+}
+
+func TestHandleHeartbeat_Unauthenticated_Returns401(t *testing.T) {
+	// Arrange - route registered without BeaconAuthMiddleware
+	testNodeID := uuid.New()
+	mockQuerier := &MockNodesQuerier{
+		getNodeByIDFunc: func(ctx context.Context, nodeID uuid.UUID) (*models.Node, error) {
+			return &models.Node{ID: nodeID.String()}, nil
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	beaconHandler := NewBeaconHandler(mockQuerier, &MockNodeTokensQuerier{}, cache.NewMemoryCache(), cache.NewBatchWriter(nil, 1000, 100))
+	router.POST("/api/v1/beacon/heartbeat", beaconHandler.HandleHeartbeat)
+	router.POST("/api/v1/beacon/heartbeats", beaconHandler.HandleHeartbeatBatch)
+
+	heartbeat := models.HeartbeatRequest{
+		NodeID:    testNodeID.String(),
+		ProbeID:   "probe-001",
+		LatencyMs: 10,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	requests := map[string]interface{}{
+		"/api/v1/beacon/heartbeat":  heartbeat,
+		"/api/v1/beacon/heartbeats": models.HeartbeatBatchRequest{NodeID: testNodeID.String(), Heartbeats: []models.HeartbeatRequest{heartbeat}},
+	}
+
+	for path, body := range requests {
+		bodyBytes, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(bodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert - fails closed instead of accepting data for any node
+		assert.Equal(t, http.StatusUnauthorized, w.Code, path)
+		assert.Contains(t, w.Body.String(), ErrNodeNotAuthorized, path)
+	}
+}
@@ -0,0 +1,224 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/kevin/node-pulse/pulse-api/internal/auth"
+	"github.com/kevin/node-pulse/pulse-api/internal/db"
+	"github.com/kevin/node-pulse/pulse-api/internal/models"
+)
+
+// DefaultBeaconRegion is used when a self-registering beacon has no region configured
+const DefaultBeaconRegion = "default"
+
+// HandleRegister handles POST /api/v1/beacon/register
+// Registration is idempotent: a beacon that already holds a known node_id gets
+// that node back, otherwise an existing node with the same name+IP is reused
+// before a new node is created. New nodes receive their API token in the
+// response; re-registering a node requires its current token. Tokens for
+// existing nodes are never issued here: a node whose token was revoked, or
+// that was created before tokens existed, needs an admin token rotation.
+func (h *BeaconHandler) HandleRegister(c *gin.Context) {
+	// Parse request body
+	var req models.BeaconRegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    "ERR_INVALID_REQUEST",
+			Message: "请求参数无效",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if !isValidIPv4(req.IP) && !isValidIPv6(req.IP) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    ErrNodeIPInvalid,
+			Message: "节点 IP 地址格式无效",
+			Details: map[string]interface{}{
+				"field": "ip",
+				"value": req.IP,
+			},
+		})
+		return
+	}
+
+	if req.Region == "" {
+		req.Region = DefaultBeaconRegion
+	}
+
+	ctx := c.Request.Context()
+
+	existing, err := h.findRegisteredNode(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    ErrDatabaseError,
+			Message: "节点查询失败",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	status := http.StatusOK
+	message := "节点已存在，已更新信息"
+	var nodeID uuid.UUID
+	var apiToken string
+
+	if existing != nil {
+		nodeID, err = uuid.Parse(existing.ID)
+		if err == nil {
+			// An existing node may only be re-registered by the holder of its token
+			var tokenState db.NodeTokenState
+			tokenState, err = h.tokenQuerier.GetNodeTokenState(ctx, nodeID)
+			if err == nil {
+				if status, errResp := authorizeReregistration(c, existing.ID, tokenState); errResp != nil {
+					c.JSON(status, errResp)
+					return
+				}
+			}
+		}
+		if err == nil {
+			err = h.nodeQuerier.UpdateNode(ctx, nodeID, map[string]interface{}{
+				"name":   req.NodeName,
+				"ip":     req.IP,
+				"region": req.Region,
+				"tags":   tagsToMap(req.Tags),
+			})
+		}
+	} else {
+		nodeID = uuid.New()
+		status = http.StatusCreated
+		message = "节点注册成功"
+		apiToken, err = createNodeWithToken(ctx, h.nodeQuerier, h.tokenQuerier, nodeID, req.NodeName, req.IP, req.Region, tagsToMap(req.Tags))
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    ErrDatabaseError,
+			Message: "节点注册失败",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	node, err := h.nodeQuerier.GetNodeByID(ctx, nodeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    ErrDatabaseError,
+			Message: "节点注册失败",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(status, models.CreateNodeResponse{
+		Data: models.CreateNodeData{
+			ID:        node.ID,
+			Name:      node.Name,
+			IP:        node.IP,
+			Region:    node.Region,
+			Tags:      node.Tags,
+			CreatedAt: node.CreatedAt,
+			UpdatedAt: node.UpdatedAt,
+			APIToken:  apiToken,
+		},
+		Message:   message,
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
+
+// findRegisteredNode looks up the node by the beacon's node_id, then by name+IP.
+// Returns nil when the beacon is not registered yet.
+func (h *BeaconHandler) findRegisteredNode(ctx context.Context, req *models.BeaconRegisterRequest) (*models.Node, error) {
+	if nodeID, err := uuid.Parse(req.NodeID); err == nil {
+		node, err := h.nodeQuerier.GetNodeByID(ctx, nodeID)
+		if err == nil && node != nil {
+			return node, nil
+		}
+		if err != nil && err != db.ErrNodeNotFound {
+			return nil, err
+		}
+		// Unknown node_id (e.g. node deleted in Pulse), fall through
+	}
+
+	return h.nodeQuerier.GetNodeByNameAndIP(ctx, req.NodeName, req.IP)
+}
+
+// authorizeReregistration checks the API token presented for an existing node.
+// Nodes without an active token are rejected instead of receiving one, since
+// registration is unauthenticated: revoked nodes get 401, nodes created
+// before tokens existed get 409. Both need an admin token rotation.
+func authorizeReregistration(c *gin.Context, nodeID string, tokenState db.NodeTokenState) (int, *models.ErrorResponse) {
+	details := map[string]interface{}{
+		"node_id": nodeID,
+	}
+
+	switch {
+	case tokenState.Revoked:
+		return http.StatusUnauthorized, &models.ErrorResponse{
+			Code:    ErrNodeTokenRevoked,
+			Message: "节点 API 令牌已撤销，需管理员重新签发",
+			Details: details,
+		}
+	case tokenState.Hash == "":
+		return http.StatusConflict, &models.ErrorResponse{
+			Code:    ErrNodeTokenRequired,
+			Message: "节点尚未签发 API 令牌，需管理员签发",
+			Details: details,
+		}
+	case auth.HashNodeToken(auth.BearerToken(c.GetHeader("Authorization"))) != tokenState.Hash:
+		return http.StatusUnauthorized, &models.ErrorResponse{
+			Code:    ErrInvalidNodeToken,
+			Message: "节点已注册，API 令牌无效",
+			Details: details,
+		}
+	}
+
+	return http.StatusOK, nil
+}
+
+// createNodeWithToken creates a node and issues its API token. If the token
+// cannot be stored the node is deleted again: a node without a token could
+// only be re-registered after an admin token rotation.
+func createNodeWithToken(ctx context.Context, nodeQuerier db.NodesQuerier, tokenQuerier db.NodeTokensQuerier, nodeID uuid.UUID, name, ip, region string, tags map[string]interface{}) (string, error) {
+	if err := nodeQuerier.CreateNode(ctx, nodeID, name, ip, region, tags); err != nil {
+		return "", err
+	}
+
+	token, err := issueNodeToken(ctx, tokenQuerier, nodeID)
+	if err != nil {
+		// Clean up even if the request was cancelled meanwhile
+		if deleteErr := nodeQuerier.DeleteNode(context.WithoutCancel(ctx), nodeID); deleteErr != nil {
+			slog.Error("Failed to delete node without API token",
+				"node_id", nodeID.String(),
+				"error", deleteErr)
+		}
+		return "", err
+	}
+	return token, nil
+}
+
+// issueNodeToken generates a new API token for a node and stores its hash.
+// The plain token is returned to the caller only once.
+func issueNodeToken(ctx context.Context, tokenQuerier db.NodeTokensQuerier, nodeID uuid.UUID) (string, error) {
+	token, err := auth.GenerateNodeToken()
+	if err != nil {
+		return "", err
+	}
+	if err := tokenQuerier.SetNodeToken(ctx, nodeID, auth.HashNodeToken(token)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// tagsToMap converts beacon tag list to the JSONB tags map stored on nodes
+func tagsToMap(tags []string) map[string]interface{} {
+	result := make(map[string]interface{}, len(tags))
+	for _, tag := range tags {
+		result[tag] = true
+	}
+	return result
+}
@@ -0,0 +1,269 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/kevin/node-pulse/pulse-api/internal/auth"
+	"github.com/kevin/node-pulse/pulse-api/internal/cache"
+	"github.com/kevin/node-pulse/pulse-api/internal/db"
+	"github.com/kevin/node-pulse/pulse-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRegisterMock returns a node querier backed by an in-memory node map
+func newRegisterMock(nodes map[uuid.UUID]*models.Node) *MockNodesQuerier {
+	return &MockNodesQuerier{
+		getNodeByIDFunc: func(ctx context.Context, nodeID uuid.UUID) (*models.Node, error) {
+			if node, ok := nodes[nodeID]; ok {
+				return node, nil
+			}
+			return nil, db.ErrNodeNotFound
+		},
+		getNodeByNameAndIPFunc: func(ctx context.Context, name string, ip string) (*models.Node, error) {
+			for _, node := range nodes {
+				if node.Name == name && node.IP == ip {
+					return node, nil
+				}
+			}
+			return nil, nil
+		},
+		createNodeFunc: func(ctx context.Context, nodeID uuid.UUID, name string, ip string, region string, tags map[string]interface{}) error {
+			nodes[nodeID] = &models.Node{ID: nodeID.String(), Name: name, IP: ip, Region: region}
+			return nil
+		},
+		updateNodeFunc: func(ctx context.Context, nodeID uuid.UUID, updates map[string]interface{}) error {
+			node := nodes[nodeID]
+			node.Name = updates["name"].(string)
+			node.IP = updates["ip"].(string)
+			node.Region = updates["region"].(string)
+			return nil
+		},
+		deleteNodeFunc: func(ctx context.Context, nodeID uuid.UUID) error {
+			delete(nodes, nodeID)
+			return nil
+		},
+	}
+}
+
+// postRegister sends a registration request to a test router, authenticated
+// with apiToken if it is not empty
+func postRegister(t *testing.T, nodeQuerier db.NodesQuerier, tokenQuerier db.NodeTokensQuerier, apiToken string, req models.BeaconRegisterRequest) (*httptest.ResponseRecorder, models.CreateNodeResponse) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewBeaconHandler(nodeQuerier, tokenQuerier, cache.NewMemoryCache(), cache.NewBatchWriter(nil, 10, 10))
+	router.POST("/api/v1/beacon/register", handler.HandleRegister)
+
+	bodyBytes, _ := json.Marshal(req)
+	httpReq, _ := http.NewRequest("POST", "/api/v1/beacon/register", bytes.NewBuffer(bodyBytes))
+	httpReq.Header.Set("Content-Type", "application/json")
+	if apiToken != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+apiToken)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httpReq)
+
+	var resp models.CreateNodeResponse
+	if w.Code < 300 {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	}
+	return w, resp
+}
+
+func TestHandleRegister_NewNodeThenIdempotent(t *testing.T) {
+	// Arrange
+	nodes := map[uuid.UUID]*models.Node{}
+	mock := newRegisterMock(nodes)
+	tokens := newTokenMock()
+	req := models.BeaconRegisterRequest{NodeName: "beacon-01", IP: "10.0.0.1", Tags: []string{"edge"}}
+
+	// Act - first registration creates node and issues its token
+	w, resp := postRegister(t, mock, tokens, "", req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+	nodeID, err := uuid.Parse(resp.Data.ID)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultBeaconRegion, resp.Data.Region)
+	require.NotEmpty(t, resp.Data.APIToken)
+	assert.Equal(t, auth.HashNodeToken(resp.Data.APIToken), tokens.hashes[nodeID])
+
+	// Act - registering again without node_id finds node by name+IP
+	w, again := postRegister(t, mock, tokens, resp.Data.APIToken, req)
+
+	// Assert - same node, token is not re-issued
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, resp.Data.ID, again.Data.ID)
+	assert.Empty(t, again.Data.APIToken)
+	assert.Len(t, nodes, 1)
+}
+
+func TestHandleRegister_TokenFailureRemovesNode(t *testing.T) {
+	// Arrange - token storage fails on the first registration
+	nodes := map[uuid.UUID]*models.Node{}
+	mock := newRegisterMock(nodes)
+	tokens := newTokenMock()
+	tokens.err = assert.AnError
+	req := models.BeaconRegisterRequest{NodeName: "beacon-01", IP: "10.0.0.1"}
+
+	// Act
+	w, _ := postRegister(t, mock, tokens, "", req)
+
+	// Assert - no node is left without a token
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, nodes)
+
+	// Act - the beacon retries once token storage works again
+	tokens.err = nil
+	w, resp := postRegister(t, mock, tokens, "", req)
+
+	// Assert - registered with a token instead of 409
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.NotEmpty(t, resp.Data.APIToken)
+	assert.Len(t, nodes, 1)
+}
+
+func TestHandleRegister_ExistingNodeRequiresToken(t *testing.T) {
+	// Arrange
+	nodes := map[uuid.UUID]*models.Node{}
+	mock := newRegisterMock(nodes)
+	tokens := newTokenMock()
+	req := models.BeaconRegisterRequest{NodeName: "beacon-01", IP: "10.0.0.1"}
+	_, resp := postRegister(t, mock, tokens, "", req)
+
+	// Act - another client claims the node without its token
+	w, _ := postRegister(t, mock, tokens, "", models.BeaconRegisterRequest{
+		NodeID: resp.Data.ID, NodeName: "hijacked", IP: "10.0.0.9",
+	})
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), ErrInvalidNodeToken)
+	assert.Equal(t, "beacon-01", nodes[uuid.MustParse(resp.Data.ID)].Name)
+}
+
+func TestHandleRegister_KnownNodeIDFollowsIPChange(t *testing.T) {
+	// Arrange
+	nodeID := uuid.New()
+	nodes := map[uuid.UUID]*models.Node{
+		nodeID: {ID: nodeID.String(), Name: "beacon-01", IP: "10.0.0.1", Region: "us-east"},
+	}
+	mock := newRegisterMock(nodes)
+	tokens := newTokenMock()
+	tokens.hashes[nodeID] = auth.HashNodeToken("npb_current")
+
+	// Act - beacon moved to a new IP but still holds its node_id and token
+	w, resp := postRegister(t, mock, tokens, "npb_current", models.BeaconRegisterRequest{
+		NodeID: nodeID.String(), NodeName: "beacon-01", IP: "10.0.0.2", Region: "us-east",
+	})
+
+	// Assert - same node updated, no duplicate, token is not re-issued
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, nodeID.String(), resp.Data.ID)
+	assert.Equal(t, "10.0.0.2", resp.Data.IP)
+	assert.Len(t, nodes, 1)
+	assert.Empty(t, resp.Data.APIToken)
+}
+
+func TestHandleRegister_LegacyNodeRequiresRotation(t *testing.T) {
+	// Arrange - node created before tokens existed
+	nodeID := uuid.New()
+	nodes := map[uuid.UUID]*models.Node{
+		nodeID: {ID: nodeID.String(), Name: "beacon-01", IP: "10.0.0.1", Region: "us-east"},
+	}
+	tokens := newTokenMock()
+
+	// Act
+	w, _ := postRegister(t, newRegisterMock(nodes), tokens, "", models.BeaconRegisterRequest{
+		NodeID: nodeID.String(), NodeName: "beacon-01", IP: "10.0.0.1",
+	})
+
+	// Assert - no token is issued without an admin
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), ErrNodeTokenRequired)
+	assert.Empty(t, tokens.hashes[nodeID])
+}
+
+func TestHandleRegister_RevokedNodeRejected(t *testing.T) {
+	// Arrange - registered node whose token an admin revoked
+	nodes := map[uuid.UUID]*models.Node{}
+	mock := newRegisterMock(nodes)
+	tokens := newTokenMock()
+	req := models.BeaconRegisterRequest{NodeName: "beacon-01", IP: "10.0.0.1"}
+	_, resp := postRegister(t, mock, tokens, "", req)
+	nodeID := uuid.MustParse(resp.Data.ID)
+
+	w := httptest.NewRecorder()
+	router := setupTokenRouter(tokens)
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/nodes/"+nodeID.String()+"/token", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	tests := []struct {
+		name     string
+		apiToken string
+		req      models.BeaconRegisterRequest
+	}{
+		{name: "by node_id", req: models.BeaconRegisterRequest{NodeID: nodeID.String(), NodeName: "beacon-01", IP: "10.0.0.1"}},
+		{name: "by name and IP", req: req},
+		{name: "with revoked token", apiToken: resp.Data.APIToken, req: req},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			w, _ := postRegister(t, mock, tokens, tt.apiToken, tt.req)
+
+			// Assert - rejected, and no token is issued
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Contains(t, w.Body.String(), ErrNodeTokenRevoked)
+			assert.Empty(t, tokens.hashes[nodeID])
+		})
+	}
+
+	// Act - admin rotation issues a new token that re-registration accepts
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/nodes/"+nodeID.String()+"/token", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var rotated models.NodeTokenResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rotated))
+
+	w, _ = postRegister(t, mock, tokens, rotated.Data.APIToken, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestHandleRegister_UnknownNodeIDCreatesNode(t *testing.T) {
+	// Arrange
+	nodes := map[uuid.UUID]*models.Node{}
+	mock := newRegisterMock(nodes)
+	staleID := uuid.New().String()
+
+	// Act
+	w, resp := postRegister(t, mock, newTokenMock(), "", models.BeaconRegisterRequest{
+		NodeID: staleID, NodeName: "beacon-01", IP: "10.0.0.1",
+	})
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.NotEqual(t, staleID, resp.Data.ID)
+	assert.Len(t, nodes, 1)
+}
+
+func TestHandleRegister_InvalidIP_Returns400(t *testing.T) {
+	// Act
+	w, _ := postRegister(t, newRegisterMock(map[uuid.UUID]*models.Node{}), newTokenMock(), "", models.BeaconRegisterRequest{
+		NodeName: "beacon-01", IP: "not-an-ip",
+	})
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
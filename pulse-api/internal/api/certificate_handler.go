@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kevin/node-pulse/pulse-api/internal/db"
+	"github.com/kevin/node-pulse/pulse-api/internal/models"
+	"github.com/kevin/node-pulse/pulse-api/pkg/middleware"
+)
+
+const (
+	// DefaultCertExpiryDays is the expiry window used when days is not given
+	DefaultCertExpiryDays = 30
+	// MaxCertExpiryDays bounds the expiry window
+	MaxCertExpiryDays = 3650
+)
+
+// CertificateHandler serves certificate status reported by tls_probe heartbeats
+type CertificateHandler struct {
+	certificateQuerier db.CertificatesQuerier
+}
+
+// NewCertificateHandler creates a new CertificateHandler
+func NewCertificateHandler(certificateQuerier db.CertificatesQuerier) *CertificateHandler {
+	return &CertificateHandler{certificateQuerier: certificateQuerier}
+}
+
+// GetExpiringCertificatesHandler handles GET /api/v1/certificates/expiring?days=N
+// Lists the latest certificate of every tls_probe that expires within N days
+// (default 30), including expired ones, ordered by expiry.
+func (h *CertificateHandler) GetExpiringCertificatesHandler(c *gin.Context) {
+	days := DefaultCertExpiryDays
+	if raw := c.Query("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 || parsed > MaxCertExpiryDays {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    middleware.ERR_INVALID_REQUEST,
+				Message: "无效的天数参数",
+				Details: map[string]interface{}{
+					"field": "days",
+					"value": raw,
+					"min":   0,
+					"max":   MaxCertExpiryDays,
+				},
+			})
+			return
+		}
+		days = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	certificates, err := h.certificateQuerier.GetExpiringCertificates(ctx, now.Add(time.Duration(days)*24*time.Hour))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    ErrDatabaseError,
+			Message: "证书查询失败",
+		})
+		return
+	}
+	for _, certificate := range certificates {
+		certificate.DaysRemaining = math.Round(certificate.NotAfter.Sub(now).Hours()/24*100) / 100
+	}
+
+	c.JSON(http.StatusOK, models.ExpiringCertificatesResponse{
+		Data: models.ExpiringCertificatesData{
+			WithinDays:   days,
+			Certificates: certificates,
+		},
+		Message:   "即将过期证书查询成功",
+		Timestamp: now.Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kevin/node-pulse/pulse-api/internal/models"
+	"github.com/kevin/node-pulse/pulse-api/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCertificatesQuerier is a mock for CertificatesQuerier interface
+type MockCertificatesQuerier struct {
+	getExpiringCertificatesFunc func(context.Context, time.Time) ([]*models.CertificateStatus, error)
+}
+
+func (m *MockCertificatesQuerier) GetExpiringCertificates(ctx context.Context, before time.Time) ([]*models.CertificateStatus, error) {
+	if m.getExpiringCertificatesFunc != nil {
+		return m.getExpiringCertificatesFunc(ctx, before)
+	}
+	return nil, nil
+}
+
+// setupCertificateRouter creates a test router with the expiring certificates endpoint
+func setupCertificateRouter(certificateQuerier *MockCertificatesQuerier) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	certificateHandler := NewCertificateHandler(certificateQuerier)
+	router.GET("/api/v1/certificates/expiring", certificateHandler.GetExpiringCertificatesHandler)
+
+	return router
+}
+
+func TestGetExpiringCertificates_DefaultWindow(t *testing.T) {
+	// Arrange
+	valid := false
+	now := time.Now()
+	var queriedBefore []time.Time
+	querier := &MockCertificatesQuerier{
+		getExpiringCertificatesFunc: func(ctx context.Context, before time.Time) ([]*models.CertificateStatus, error) {
+			queriedBefore = append(queriedBefore, before)
+			return []*models.CertificateStatus{
+				{NodeID: "node-1", ProbeID: "tls-expired", Target: "old.example.com", NotAfter: now.Add(-24 * time.Hour), LastSeen: now},
+				{NodeID: "node-1", ProbeID: "tls-soon", Target: "soon.example.com", NotAfter: now.Add(7 * 24 * time.Hour), ChainValid: &valid, LastSeen: now},
+			}, nil
+		},
+	}
+	router := setupCertificateRouter(querier)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/api/v1/certificates/expiring", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.ExpiringCertificatesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, DefaultCertExpiryDays, resp.Data.WithinDays)
+	require.Len(t, resp.Data.Certificates, 2)
+	assert.InDelta(t, -1, resp.Data.Certificates[0].DaysRemaining, 0.01)
+	assert.Equal(t, "tls-soon", resp.Data.Certificates[1].ProbeID)
+	assert.Equal(t, "soon.example.com", resp.Data.Certificates[1].Target)
+	assert.InDelta(t, 7, resp.Data.Certificates[1].DaysRemaining, 0.01)
+	require.NotNil(t, resp.Data.Certificates[1].ChainValid)
+	assert.False(t, *resp.Data.Certificates[1].ChainValid)
+
+	// A wider window queries further ahead
+	req, _ = http.NewRequest("GET", "/api/v1/certificates/expiring?days=90", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, queriedBefore, 2)
+	assert.WithinDuration(t, now.Add(DefaultCertExpiryDays*24*time.Hour), queriedBefore[0], time.Minute)
+	assert.WithinDuration(t, now.Add(90*24*time.Hour), queriedBefore[1], time.Minute)
+}
+
+func TestGetExpiringCertificates_DatabaseError_Returns500(t *testing.T) {
+	router := setupCertificateRouter(&MockCertificatesQuerier{
+		getExpiringCertificatesFunc: func(ctx context.Context, before time.Time) ([]*models.CertificateStatus, error) {
+			return nil, errors.New("connection refused")
+		},
+	})
+
+	req, _ := http.NewRequest("GET", "/api/v1/certificates/expiring", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var resp models.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, ErrDatabaseError, resp.Code)
+}
+
+func TestGetExpiringCertificates_InvalidDays_Returns400(t *testing.T) {
+	router := setupCertificateRouter(&MockCertificatesQuerier{})
+
+	for _, days := range []string{"abc", "-1", "3651"} {
+		req, _ := http.NewRequest("GET", "/api/v1/certificates/expiring?days="+days, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, "days=%s", days)
+
+		var resp models.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, middleware.ERR_INVALID_REQUEST, resp.Code)
+	}
+}
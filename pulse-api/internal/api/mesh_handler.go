@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/kevin/node-pulse/pulse-api/internal/config"
+	"github.com/kevin/node-pulse/pulse-api/internal/db"
+	"github.com/kevin/node-pulse/pulse-api/internal/models"
+)
+
+// MeshHandler serves the node-pair matrix built from mesh probe results
+type MeshHandler struct {
+	nodeQuerier db.NodesQuerier
+	meshQuerier db.MeshQuerier
+}
+
+// NewMeshHandler creates a new MeshHandler
+func NewMeshHandler(nodeQuerier db.NodesQuerier, meshQuerier db.MeshQuerier) *MeshHandler {
+	return &MeshHandler{
+		nodeQuerier: nodeQuerier,
+		meshQuerier: meshQuerier,
+	}
+}
+
+// GetMeshMatrixHandler handles GET /api/v1/mesh/matrix
+// Returns the nodes and the latest latency/loss result of every node pair
+// that reported one. Optional query parameter: region, limiting the matrix
+// to nodes of that region.
+func (h *MeshHandler) GetMeshMatrixHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+
+	var nodes []*models.Node
+	var err error
+	if region := c.Query("region"); region != "" {
+		nodes, err = h.nodeQuerier.GetNodesByRegion(ctx, region)
+	} else {
+		nodes, err = h.nodeQuerier.GetNodes(ctx)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    ErrDatabaseError,
+			Message: "节点查询失败",
+		})
+		return
+	}
+
+	links, err := h.meshQuerier.GetMeshLinks(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    ErrDatabaseError,
+			Message: "网格矩阵查询失败",
+		})
+		return
+	}
+
+	data := models.MeshMatrixData{
+		Nodes: make([]*models.MeshNode, 0, len(nodes)),
+		Links: make([]*models.MeshLink, 0, len(links)),
+	}
+	included := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		included[node.ID] = true
+		data.Nodes = append(data.Nodes, &models.MeshNode{ID: node.ID, Name: node.Name, IP: node.IP, Region: node.Region})
+	}
+	for _, link := range links {
+		if included[link.SourceNodeID] && included[link.TargetNodeID] {
+			data.Links = append(data.Links, link)
+		}
+	}
+
+	c.JSON(http.StatusOK, models.MeshMatrixResponse{
+		Data:      data,
+		Message:   "网格矩阵查询成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
+
+// meshProbeID returns the probe ID of the mesh probe against a peer
+func meshProbeID(peerID string) string {
+	return models.MeshProbeIDPrefix + peerID
+}
+
+// meshPeerID extracts the peer node ID from a mesh probe ID
+func meshPeerID(probeID string) (string, bool) {
+	if !strings.HasPrefix(probeID, models.MeshProbeIDPrefix) {
+		return "", false
+	}
+	peerID, err := uuid.Parse(strings.TrimPrefix(probeID, models.MeshProbeIDPrefix))
+	if err != nil {
+		return "", false
+	}
+	return peerID.String(), true
+}
+
+// meshGroup returns the mesh group of a node, or "" when the node has no
+// region or lacks the grouping tag and therefore takes no part in the mesh
+func meshGroup(cfg *config.MeshConfig, node *models.Node) string {
+	tag := cfg.GroupTag()
+	if tag == "" {
+		return node.Region
+	}
+
+	var tags map[string]interface{}
+	if node.Tags == "" || json.Unmarshal([]byte(node.Tags), &tags) != nil {
+		return ""
+	}
+	value, ok := tags[tag]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// meshPeers returns the nodes in the same mesh group as self, ordered by ID.
+// Nodes without an IP address cannot be probed and are skipped.
+func meshPeers(cfg *config.MeshConfig, self *models.Node, nodes []*models.Node) []*models.Node {
+	group := meshGroup(cfg, self)
+	if group == "" {
+		return nil
+	}
+
+	peers := make([]*models.Node, 0)
+	for _, node := range nodes {
+		if node.ID == self.ID || node.IP == "" || meshGroup(cfg, node) != group {
+			continue
+		}
+		peers = append(peers, node)
+	}
+
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].ID < peers[j].ID
+	})
+	return peers
+}
+
+// meshProbeConfigs builds the mesh probes a node runs against its peers
+func meshProbeConfigs(cfg *config.MeshConfig, self *models.Node, nodes []*models.Node) []models.BeaconProbeConfig {
+	peers := meshPeers(cfg, self, nodes)
+	configs := make([]models.BeaconProbeConfig, 0, len(peers))
+	for _, peer := range peers {
+		configs = append(configs, models.BeaconProbeConfig{
+			ID:              meshProbeID(peer.ID),
+			Type:            cfg.Protocol + "_ping",
+			Target:          peer.IP,
+			Port:            cfg.Port,
+			IntervalSeconds: cfg.IntervalSeconds,
+			Count:           cfg.Count,
+			TimeoutSeconds:  cfg.TimeoutSeconds,
+			Responder:       cfg.Protocol == "udp",
+		})
+	}
+	return configs
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/kevin/node-pulse/pulse-api/internal/config"
+	"github.com/kevin/node-pulse/pulse-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MockMeshQuerier is a mock for MeshQuerier interface
+type MockMeshQuerier struct {
+	getMeshLinksFunc func(context.Context) ([]*models.MeshLink, error)
+}
+
+func (m *MockMeshQuerier) GetMeshLinks(ctx context.Context) ([]*models.MeshLink, error) {
+	if m.getMeshLinksFunc != nil {
+		return m.getMeshLinksFunc(ctx)
+	}
+	return nil, nil
+}
+
+// setupMeshRouter creates a test router with the mesh matrix endpoint
+func setupMeshRouter(nodeQuerier *MockNodesQuerier, meshQuerier *MockMeshQuerier) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	meshHandler := NewMeshHandler(nodeQuerier, meshQuerier)
+	router.GET("/api/v1/mesh/matrix", meshHandler.GetMeshMatrixHandler)
+
+	return router
+}
+
+func testMeshConfig(groupBy string) *config.MeshConfig {
+	return &config.MeshConfig{Enabled: true, GroupBy: groupBy, Protocol: "udp", Port: 7331, IntervalSeconds: 60, Count: 10, TimeoutSeconds: 2}
+}
+
+func TestGetMeshMatrix_Success(t *testing.T) {
+	// Arrange
+	a, b, other := uuid.New().String(), uuid.New().String(), uuid.New().String()
+	var gotRegion string
+	nodeQuerier := &MockNodesQuerier{
+		getNodesByRegionFunc: func(ctx context.Context, region string) ([]*models.Node, error) {
+			gotRegion = region
+			return []*models.Node{
+				{ID: a, Name: "fra-1", IP: "10.0.0.1", Region: "eu"},
+				{ID: b, Name: "fra-2", IP: "10.0.0.2", Region: "eu"},
+			}, nil
+		},
+	}
+	success := true
+	meshQuerier := &MockMeshQuerier{
+		getMeshLinksFunc: func(ctx context.Context) ([]*models.MeshLink, error) {
+			return []*models.MeshLink{
+				{SourceNodeID: a, TargetNodeID: b, ProbeType: "udp_ping", Success: &success, LatencyMs: 1.5, SampleCount: 10, UpdatedAt: time.Now()},
+				{SourceNodeID: b, TargetNodeID: a, ProbeType: "udp_ping", Success: &success, LatencyMs: 1.6, PacketLossRate: 10, SampleCount: 10, UpdatedAt: time.Now()},
+				{SourceNodeID: a, TargetNodeID: other, ProbeType: "udp_ping", LatencyMs: 80, SampleCount: 10, UpdatedAt: time.Now()},
+			}, nil
+		},
+	}
+	router := setupMeshRouter(nodeQuerier, meshQuerier)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/api/v1/mesh/matrix?region=eu", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "eu", gotRegion)
+
+	var resp models.MeshMatrixResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Data.Nodes, 2)
+	require.Len(t, resp.Data.Links, 2, "links to nodes outside the region are dropped")
+	assert.Equal(t, 10.0, resp.Data.Links[1].PacketLossRate)
+}
+
+func TestGetMeshMatrix_DatabaseError_Returns500(t *testing.T) {
+	nodeQuerier := &MockNodesQuerier{}
+	meshQuerier := &MockMeshQuerier{
+		getMeshLinksFunc: func(ctx context.Context) ([]*models.MeshLink, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+	router := setupMeshRouter(nodeQuerier, meshQuerier)
+
+	req, _ := http.NewRequest("GET", "/api/v1/mesh/matrix", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "connection refused")
+}
+
+func TestMeshPeers_ByRegion(t *testing.T) {
+	self := &models.Node{ID: "b", IP: "10.0.0.2", Region: "eu"}
+	nodes := []*models.Node{
+		{ID: "c", IP: "10.0.0.3", Region: "eu"},
+		self,
+		{ID: "a", IP: "10.0.0.1", Region: "eu"},
+		{ID: "d", IP: "10.1.0.1", Region: "us"},
+		{ID: "e", IP: "", Region: "eu"},
+	}
+
+	peers := meshPeers(testMeshConfig("region"), self, nodes)
+
+	require.Len(t, peers, 2)
+	assert.Equal(t, "a", peers[0].ID)
+	assert.Equal(t, "c", peers[1].ID)
+
+	assert.Empty(t, meshPeers(testMeshConfig("region"), &models.Node{ID: "x", IP: "10.9.0.1"}, nodes), "nodes without a region are not meshed")
+}
+
+func TestMeshPeers_ByTag(t *testing.T) {
+	self := &models.Node{ID: "a", IP: "10.0.0.1", Region: "eu", Tags: `{"dc": "fra1"}`}
+	nodes := []*models.Node{
+		self,
+		{ID: "b", IP: "10.0.0.2", Region: "us", Tags: `{"dc": "fra1", "role": "edge"}`},
+		{ID: "c", IP: "10.0.0.3", Region: "eu", Tags: `{"dc": "ams1"}`},
+		{ID: "d", IP: "10.0.0.4", Region: "eu"},
+	}
+
+	peers := meshPeers(testMeshConfig("tag:dc"), self, nodes)
+
+	require.Len(t, peers, 1)
+	assert.Equal(t, "b", peers[0].ID)
+}
+
+func TestMeshProbeConfigs(t *testing.T) {
+	self := &models.Node{ID: uuid.New().String(), IP: "10.0.0.1", Region: "eu"}
+	peer := &models.Node{ID: uuid.New().String(), IP: "10.0.0.2", Region: "eu"}
+
+	configs := meshProbeConfigs(testMeshConfig("region"), self, []*models.Node{self, peer})
+
+	require.Len(t, configs, 1)
+	assert.Equal(t, models.BeaconProbeConfig{
+		ID: "mesh:" + peer.ID, Type: "udp_ping", Target: "10.0.0.2", Port: 7331,
+		IntervalSeconds: 60, Count: 10, TimeoutSeconds: 2, Responder: true,
+	}, configs[0])
+
+	tcp := testMeshConfig("region")
+	tcp.Protocol, tcp.Port = "tcp", 22
+	configs = meshProbeConfigs(tcp, self, []*models.Node{self, peer})
+	require.Len(t, configs, 1)
+	assert.Equal(t, "tcp_ping", configs[0].Type)
+	assert.False(t, configs[0].Responder)
+}
+
+func TestMeshPeerID(t *testing.T) {
+	peerID := uuid.New().String()
+
+	got, ok := meshPeerID(meshProbeID(peerID))
+	assert.True(t, ok)
+	assert.Equal(t, peerID, got)
+
+	for _, probeID := range []string{peerID, "mesh:not-a-uuid", "udp_ping:10.0.0.2:7331"} {
+		_, ok := meshPeerID(probeID)
+		assert.False(t, ok, probeID)
+	}
+}
@@ -28,13 +28,15 @@ var (
 
 // NodeHandler handles node API requests
 type NodeHandler struct {
-	nodeQuerier db.NodesQuerier
+	nodeQuerier  db.NodesQuerier
+	tokenQuerier db.NodeTokensQuerier
 }
 
 // NewNodeHandler creates a new NodeHandler
-func NewNodeHandler(nodeQuerier db.NodesQuerier) *NodeHandler {
+func NewNodeHandler(nodeQuerier db.NodesQuerier, tokenQuerier db.NodeTokensQuerier) *NodeHandler {
 	return &NodeHandler{
-		nodeQuerier: nodeQuerier,
+		nodeQuerier:  nodeQuerier,
+		tokenQuerier: tokenQuerier,
 	}
 }
 
@@ -200,8 +202,9 @@ func (h *NodeHandler) CreateNodeHandler(c *gin.Context) {
 	// Generate UUID for new node
 	nodeID := uuid.New()
 
-	// Create node in database
-	err = h.nodeQuerier.CreateNode(ctx, nodeID, req.Name, req.IP, req.Region, req.Tags)
+	// Create node in database with its beacon API token (returned only in
+	// this response)
+	apiToken, err := createNodeWithToken(ctx, h.nodeQuerier, h.tokenQuerier, nodeID, req.Name, req.IP, req.Region, req.Tags)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Code:    ErrDatabaseError,
@@ -231,6 +234,7 @@ func (h *NodeHandler) CreateNodeHandler(c *gin.Context) {
 			Tags:      node.Tags,
 			CreatedAt: node.CreatedAt,
 			UpdatedAt: node.UpdatedAt,
+			APIToken:  apiToken,
 		},
 		Message:   "节点创建成功",
 		Timestamp: time.Now().Format(time.RFC3339),
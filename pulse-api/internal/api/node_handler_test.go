@@ -115,7 +115,7 @@ func setupUnauthenticatedContext() (*gin.Context, *httptest.ResponseRecorder) {
 func TestCreateNodeSuccess(t *testing.T) {
 	// Setup
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 
 	createCalled := false
 	var capturedNodeID uuid.UUID
@@ -165,7 +165,7 @@ func TestCreateNodeSuccess(t *testing.T) {
 func TestCreateNodeEmptyName(t *testing.T) {
 	// Setup
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 
 	// Act
 	c, w := setupTestContext()
@@ -190,7 +190,7 @@ func TestCreateNodeEmptyName(t *testing.T) {
 func TestCreateNodeInvalidIP(t *testing.T) {
 	// Setup
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 
 	// Act
 	c, w := setupTestContext()
@@ -212,7 +212,7 @@ func TestCreateNodeInvalidIP(t *testing.T) {
 func TestCreateNodePermissionDenied(t *testing.T) {
 	// Setup
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 
 	createCalled := false
 	var capturedNodeID uuid.UUID
@@ -261,7 +261,7 @@ func TestCreateNodePermissionDenied(t *testing.T) {
 func TestCreateNodeUnauthenticated(t *testing.T) {
 	// Setup
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 
 	createCalled := false
 	var capturedNodeID uuid.UUID
@@ -310,7 +310,7 @@ func TestCreateNodeUnauthenticated(t *testing.T) {
 func TestGetNodesSuccess(t *testing.T) {
 	// Setup
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 	router := gin.Default()
 	router.GET("/api/v1/nodes", handler.GetNodesHandler)
 
@@ -351,7 +351,7 @@ func TestGetNodesSuccess(t *testing.T) {
 func TestGetNodesByRegion(t *testing.T) {
 	// Setup
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 	router := gin.Default()
 	router.GET("/api/v1/nodes", handler.GetNodesHandler)
 
@@ -384,7 +384,7 @@ func TestGetNodesByRegion(t *testing.T) {
 func TestUpdateNodeSuccess(t *testing.T) {
 	// Setup
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 	nodeID := uuid.New()
 	updatedNode := &models.Node{
 		ID:        nodeID.String(),
@@ -432,7 +432,7 @@ func TestUpdateNodeSuccess(t *testing.T) {
 func TestUpdateNodeNotFound(t *testing.T) {
 	// Setup
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 	nodeID := uuid.New()
 
 	mockQuerier.updateNodeFunc = func(ctx context.Context, nID uuid.UUID, updates map[string]interface{}) error {
@@ -461,7 +461,7 @@ func TestUpdateNodeNotFound(t *testing.T) {
 func TestUpdateNodePermissionDenied(t *testing.T) {
 	// Setup
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 	nodeID := uuid.New()
 	updatedNode := &models.Node{
 		ID:        nodeID.String(),
@@ -507,7 +507,7 @@ func TestUpdateNodePermissionDenied(t *testing.T) {
 func TestDeleteNodeSuccess(t *testing.T) {
 	// Setup
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 	nodeID := uuid.New()
 
 	deleteCalled := false
@@ -536,7 +536,7 @@ func TestDeleteNodeSuccess(t *testing.T) {
 func TestDeleteNodeWithoutConfirmation(t *testing.T) {
 	// Setup
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 	nodeID := uuid.New()
 
 	// Act
@@ -557,7 +557,7 @@ func TestDeleteNodeWithoutConfirmation(t *testing.T) {
 func TestDeleteNodeNotFound(t *testing.T) {
 	// Setup
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 	nodeID := uuid.New()
 
 	mockQuerier.deleteNodeFunc = func(ctx context.Context, nID uuid.UUID) error {
@@ -583,7 +583,7 @@ func TestDeleteNodeNotFound(t *testing.T) {
 func TestDeleteNodePermissionDenied(t *testing.T) {
 	// Setup
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 	nodeID := uuid.New()
 
 	deleteCalled := false
@@ -613,7 +613,7 @@ func TestDeleteNodePermissionDenied(t *testing.T) {
 func TestGetNodeByIDSuccess(t *testing.T) {
 	// Setup
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 	nodeID := uuid.New()
 	expectedNode := &models.Node{
 		ID:     nodeID.String(),
@@ -646,7 +646,7 @@ func TestGetNodeByIDSuccess(t *testing.T) {
 func TestGetNodeByIDNotFound(t *testing.T) {
 	// Setup
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 	nodeID := uuid.New()
 
 	mockQuerier.getNodeByIDFunc = func(ctx context.Context, nID uuid.UUID) (*models.Node, error) {
@@ -671,7 +671,7 @@ func TestGetNodeByIDNotFound(t *testing.T) {
 func TestGetNodeByIDUnauthenticated(t *testing.T) {
 	// Setup
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 	nodeID := uuid.New()
 	expectedNode := &models.Node{
 		Name:   "Test Node",
@@ -704,7 +704,7 @@ func TestGetNodeByIDUnauthenticated(t *testing.T) {
 func TestIPValidationRejectsPartialIPv4(t *testing.T) {
 	// Setup
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 
 	testCases := []string{
 		"192.168",
@@ -735,7 +735,7 @@ func TestIPValidationRejectsPartialIPv4(t *testing.T) {
 func TestIPValidationAcceptsValidIPv4(t *testing.T) {
 	// Setup
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 	nodeID := uuid.New()
 	expectedNode := &models.Node{
 		ID:        nodeID.String(),
@@ -797,7 +797,7 @@ func TestGetNodeStatusHandler_OnlineNode(t *testing.T) {
 			}, nil
 		},
 	}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 	router := gin.New()
 	router.GET("/api/v1/nodes/:id/status", handler.GetNodeStatusHandler)
 
@@ -837,7 +837,7 @@ func TestGetNodeStatusHandler_OfflineNode(t *testing.T) {
 			}, nil
 		},
 	}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 	router := gin.New()
 	router.GET("/api/v1/nodes/:id/status", handler.GetNodeStatusHandler)
 
@@ -867,7 +867,7 @@ func TestGetNodeStatusHandler_ConnectingNode(t *testing.T) {
 			}, nil
 		},
 	}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 	router := gin.New()
 	router.GET("/api/v1/nodes/:id/status", handler.GetNodeStatusHandler)
 
@@ -891,7 +891,7 @@ func TestGetNodeStatusHandler_NodeNotFound(t *testing.T) {
 			return nil, db.ErrNodeNotFound
 		},
 	}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 	router := gin.New()
 	router.GET("/api/v1/nodes/:id/status", handler.GetNodeStatusHandler)
 
@@ -912,7 +912,7 @@ func TestGetNodeStatusHandler_NodeNotFound(t *testing.T) {
 // Test GetNodeStatusHandler - invalid UUID
 func TestGetNodeStatusHandler_InvalidUUID(t *testing.T) {
 	mockQuerier := &MockNodesQuerier{}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 	router := gin.New()
 	router.GET("/api/v1/nodes/:id/status", handler.GetNodeStatusHandler)
 
@@ -988,7 +988,7 @@ func TestGetNodeStatusHandler_DatabaseError(t *testing.T) {
 			return nil, errors.New("database connection failed")
 		},
 	}
-	handler := NewNodeHandler(mockQuerier)
+	handler := NewNodeHandler(mockQuerier, &MockNodeTokensQuerier{})
 	router := gin.New()
 	router.GET("/api/v1/nodes/:id/status", handler.GetNodeStatusHandler)
 
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/kevin/node-pulse/pulse-api/internal/db"
+	"github.com/kevin/node-pulse/pulse-api/internal/models"
+	"github.com/kevin/node-pulse/pulse-api/pkg/middleware"
+)
+
+// RotateNodeTokenHandler handles POST /api/v1/nodes/:id/token
+// Issues a new beacon API token; the previous token stops working immediately.
+func (h *NodeHandler) RotateNodeTokenHandler(c *gin.Context) {
+	// RBAC is handled by middleware - only admin can reach this handler
+	nodeID, ok := parseNodeIDParam(c)
+	if !ok {
+		return
+	}
+
+	apiToken, err := issueNodeToken(c.Request.Context(), h.tokenQuerier, nodeID)
+	if err != nil {
+		if errors.Is(err, db.ErrNodeNotFound) {
+			respondNodeNotFound(c, nodeID)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    ErrDatabaseError,
+			Message: "节点令牌更新失败",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NodeTokenResponse{
+		Data: models.NodeTokenData{
+			NodeID:    nodeID.String(),
+			APIToken:  apiToken,
+			CreatedAt: time.Now(),
+		},
+		Message:   "节点令牌已更新",
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
+
+// RevokeNodeTokenHandler handles DELETE /api/v1/nodes/:id/token
+// The beacon is rejected until a new token is issued via rotation.
+func (h *NodeHandler) RevokeNodeTokenHandler(c *gin.Context) {
+	// RBAC is handled by middleware - only admin can reach this handler
+	nodeID, ok := parseNodeIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.tokenQuerier.RevokeNodeToken(c.Request.Context(), nodeID); err != nil {
+		if errors.Is(err, db.ErrNodeNotFound) {
+			respondNodeNotFound(c, nodeID)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    ErrDatabaseError,
+			Message: "节点令牌撤销失败",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.DeleteNodeResponse{
+		Message:   "节点令牌已撤销",
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
+
+// parseNodeIDParam parses the :id path parameter, writing a 400 response on failure
+func parseNodeIDParam(c *gin.Context) (uuid.UUID, bool) {
+	idParam := c.Param("id")
+	nodeID, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    middleware.ERR_INVALID_REQUEST,
+			Message: "无效的节点 ID 格式",
+			Details: map[string]interface{}{
+				"node_id": idParam,
+				"error":   err.Error(),
+			},
+		})
+		return uuid.Nil, false
+	}
+	return nodeID, true
+}
+
+// respondNodeNotFound writes the 404 response for an unknown node
+func respondNodeNotFound(c *gin.Context, nodeID uuid.UUID) {
+	c.JSON(http.StatusNotFound, models.ErrorResponse{
+		Code:    ErrNodeNotFound,
+		Message: "节点不存在",
+		Details: map[string]interface{}{
+			"node_id": nodeID.String(),
+		},
+	})
+}
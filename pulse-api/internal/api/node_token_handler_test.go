@@ -0,0 +1,177 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kevin/node-pulse/pulse-api/internal/auth"
+	"github.com/kevin/node-pulse/pulse-api/internal/db"
+	"github.com/kevin/node-pulse/pulse-api/internal/models"
+)
+
+// MockNodeTokensQuerier is an in-memory mock for NodeTokensQuerier interface
+type MockNodeTokensQuerier struct {
+	hashes  map[uuid.UUID]string
+	revoked map[uuid.UUID]bool
+	err     error // Returned by every method when set
+}
+
+// newTokenMock creates an empty in-memory token store
+func newTokenMock() *MockNodeTokensQuerier {
+	return &MockNodeTokensQuerier{hashes: map[uuid.UUID]string{}, revoked: map[uuid.UUID]bool{}}
+}
+
+func (m *MockNodeTokensQuerier) SetNodeToken(ctx context.Context, nodeID uuid.UUID, tokenHash string) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.hashes == nil {
+		m.hashes = map[uuid.UUID]string{}
+	}
+	m.hashes[nodeID] = tokenHash
+	delete(m.revoked, nodeID)
+	return nil
+}
+
+func (m *MockNodeTokensQuerier) GetNodeTokenState(ctx context.Context, nodeID uuid.UUID) (db.NodeTokenState, error) {
+	if m.err != nil {
+		return db.NodeTokenState{}, m.err
+	}
+	return db.NodeTokenState{Hash: m.hashes[nodeID], Revoked: m.revoked[nodeID]}, nil
+}
+
+func (m *MockNodeTokensQuerier) GetNodeIDByTokenHash(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	if m.err != nil {
+		return uuid.Nil, m.err
+	}
+	for nodeID, hash := range m.hashes {
+		if hash == tokenHash {
+			return nodeID, nil
+		}
+	}
+	return uuid.Nil, db.ErrNodeNotFound
+}
+
+func (m *MockNodeTokensQuerier) RevokeNodeToken(ctx context.Context, nodeID uuid.UUID) error {
+	if m.err != nil {
+		return m.err
+	}
+	delete(m.hashes, nodeID)
+	if m.revoked == nil {
+		m.revoked = map[uuid.UUID]bool{}
+	}
+	m.revoked[nodeID] = true
+	return nil
+}
+
+// setupTokenRouter creates a test router with token admin endpoints and a
+// beacon endpoint protected by BeaconAuthMiddleware
+func setupTokenRouter(tokens *MockNodeTokensQuerier) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	handler := NewNodeHandler(&MockNodesQuerier{}, tokens)
+	router.POST("/api/v1/nodes/:id/token", handler.RotateNodeTokenHandler)
+	router.DELETE("/api/v1/nodes/:id/token", handler.RevokeNodeTokenHandler)
+
+	router.GET("/beacon", auth.BeaconAuthMiddleware(tokens), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString(auth.BeaconNodeIDKey))
+	})
+
+	return router
+}
+
+// beaconRequest calls the protected beacon endpoint with the given token
+func beaconRequest(router *gin.Engine, apiToken string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/beacon", nil)
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRotateNodeToken_InvalidatesPreviousToken(t *testing.T) {
+	// Arrange
+	nodeID := uuid.New()
+	tokens := newTokenMock()
+	router := setupTokenRouter(tokens)
+
+	rotate := func() string {
+		req, _ := http.NewRequest("POST", "/api/v1/nodes/"+nodeID.String()+"/token", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp models.NodeTokenResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, nodeID.String(), resp.Data.NodeID)
+		return resp.Data.APIToken
+	}
+
+	// Act
+	oldToken := rotate()
+	newToken := rotate()
+
+	// Assert
+	assert.NotEqual(t, oldToken, newToken)
+	assert.Equal(t, http.StatusUnauthorized, beaconRequest(router, oldToken).Code)
+
+	w := beaconRequest(router, newToken)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, nodeID.String(), w.Body.String())
+}
+
+func TestRevokeNodeToken_RejectsBeacon(t *testing.T) {
+	// Arrange
+	nodeID := uuid.New()
+	apiToken, err := auth.GenerateNodeToken()
+	require.NoError(t, err)
+	tokens := newTokenMock()
+	tokens.hashes[nodeID] = auth.HashNodeToken(apiToken)
+	router := setupTokenRouter(tokens)
+	require.Equal(t, http.StatusOK, beaconRequest(router, apiToken).Code)
+
+	// Act
+	req, _ := http.NewRequest("DELETE", "/api/v1/nodes/"+nodeID.String()+"/token", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusUnauthorized, beaconRequest(router, apiToken).Code)
+}
+
+func TestRotateNodeToken_NodeNotFound_Returns404(t *testing.T) {
+	// Arrange
+	router := setupTokenRouter(&MockNodeTokensQuerier{err: db.ErrNodeNotFound})
+
+	// Act
+	req, _ := http.NewRequest("POST", "/api/v1/nodes/"+uuid.New().String()+"/token", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), ErrNodeNotFound)
+}
+
+func TestBeaconAuthMiddleware_MissingToken_Returns401(t *testing.T) {
+	// Arrange
+	router := setupTokenRouter(newTokenMock())
+	req, _ := http.NewRequest("GET", "/beacon", nil)
+
+	// Act
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
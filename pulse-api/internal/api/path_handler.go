@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/kevin/node-pulse/pulse-api/internal/db"
+	"github.com/kevin/node-pulse/pulse-api/internal/models"
+	"github.com/kevin/node-pulse/pulse-api/pkg/middleware"
+)
+
+const (
+	// DefaultPathTraceLimit is the number of traces returned when limit is not given
+	DefaultPathTraceLimit = 50
+	// MaxPathTraceLimit bounds the limit query parameter
+	MaxPathTraceLimit = 1000
+)
+
+// PathHandler serves path traces reported by path_probe heartbeats
+type PathHandler struct {
+	pathQuerier db.PathTracesQuerier
+}
+
+// NewPathHandler creates a new PathHandler
+func NewPathHandler(pathQuerier db.PathTracesQuerier) *PathHandler {
+	return &PathHandler{pathQuerier: pathQuerier}
+}
+
+// GetPathTracesHandler handles GET /api/v1/nodes/:id/paths
+// Lists the node's most recent path traces, newest first. Optional query
+// parameters: probe_id, changes_only=true (only traces whose route differs
+// from the previous trace of the probe) and limit (default 50).
+func (h *PathHandler) GetPathTracesHandler(c *gin.Context) {
+	idParam := c.Param("id")
+	nodeID, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    middleware.ERR_INVALID_REQUEST,
+			Message: "无效的节点 ID 格式",
+			Details: map[string]interface{}{
+				"node_id": idParam,
+				"error":   err.Error(),
+			},
+		})
+		return
+	}
+
+	changesOnly := false
+	if raw := c.Query("changes_only"); raw != "" {
+		changesOnly, err = strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    middleware.ERR_INVALID_REQUEST,
+				Message: "无效的 changes_only 参数",
+				Details: map[string]interface{}{
+					"field": "changes_only",
+					"value": raw,
+				},
+			})
+			return
+		}
+	}
+
+	limit := DefaultPathTraceLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > MaxPathTraceLimit {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    middleware.ERR_INVALID_REQUEST,
+				Message: "无效的 limit 参数",
+				Details: map[string]interface{}{
+					"field": "limit",
+					"value": raw,
+					"min":   1,
+					"max":   MaxPathTraceLimit,
+				},
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+
+	traces, err := h.pathQuerier.GetPathTraces(ctx, nodeID, c.Query("probe_id"), changesOnly, limit)
+	if err != nil {
+		// Don't expose internal error details to client (security best practice)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    ErrDatabaseError,
+			Message: "路径查询失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PathTracesResponse{
+		Data:      traces,
+		Message:   "路径查询成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
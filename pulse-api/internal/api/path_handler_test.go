@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/kevin/node-pulse/pulse-api/internal/models"
+	"github.com/kevin/node-pulse/pulse-api/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MockPathTracesQuerier is a mock for PathTracesQuerier interface
+type MockPathTracesQuerier struct {
+	getPathTracesFunc func(context.Context, uuid.UUID, string, bool, int) ([]*models.PathTrace, error)
+}
+
+func (m *MockPathTracesQuerier) GetPathTraces(ctx context.Context, nodeID uuid.UUID, probeID string, changesOnly bool, limit int) ([]*models.PathTrace, error) {
+	if m.getPathTracesFunc != nil {
+		return m.getPathTracesFunc(ctx, nodeID, probeID, changesOnly, limit)
+	}
+	return nil, nil
+}
+
+// setupPathRouter creates a test router with the path traces endpoint
+func setupPathRouter(pathQuerier *MockPathTracesQuerier) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	pathHandler := NewPathHandler(pathQuerier)
+	router.GET("/api/v1/nodes/:id/paths", pathHandler.GetPathTracesHandler)
+
+	return router
+}
+
+func TestGetPathTraces_Success(t *testing.T) {
+	// Arrange
+	nodeID := uuid.New()
+	var gotProbeID string
+	var gotChangesOnly bool
+	var gotLimit int
+	querier := &MockPathTracesQuerier{
+		getPathTracesFunc: func(ctx context.Context, id uuid.UUID, probeID string, changesOnly bool, limit int) ([]*models.PathTrace, error) {
+			assert.Equal(t, nodeID, id)
+			gotProbeID, gotChangesOnly, gotLimit = probeID, changesOnly, limit
+			return []*models.PathTrace{
+				{
+					ID:          7,
+					NodeID:      nodeID.String(),
+					ProbeID:     "path_probe:8.8.8.8",
+					Target:      "8.8.8.8",
+					Timestamp:   time.Now(),
+					Reached:     true,
+					Hops:        []models.PathHop{{TTL: 1, Address: "10.0.0.1", Sent: 10, Received: 10}},
+					PathChanged: true,
+				},
+			}, nil
+		},
+	}
+	router := setupPathRouter(querier)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/api/v1/nodes/"+nodeID.String()+"/paths?probe_id=path_probe:8.8.8.8&changes_only=true&limit=10", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "path_probe:8.8.8.8", gotProbeID)
+	assert.True(t, gotChangesOnly)
+	assert.Equal(t, 10, gotLimit)
+
+	var resp models.PathTracesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.True(t, resp.Data[0].PathChanged)
+	assert.Equal(t, "10.0.0.1", resp.Data[0].Hops[0].Address)
+}
+
+func TestGetPathTraces_Defaults(t *testing.T) {
+	var gotChangesOnly bool
+	var gotLimit int
+	querier := &MockPathTracesQuerier{
+		getPathTracesFunc: func(ctx context.Context, id uuid.UUID, probeID string, changesOnly bool, limit int) ([]*models.PathTrace, error) {
+			gotChangesOnly, gotLimit = changesOnly, limit
+			return []*models.PathTrace{}, nil
+		},
+	}
+	router := setupPathRouter(querier)
+
+	req, _ := http.NewRequest("GET", "/api/v1/nodes/"+uuid.New().String()+"/paths", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, gotChangesOnly)
+	assert.Equal(t, DefaultPathTraceLimit, gotLimit)
+}
+
+func TestGetPathTraces_InvalidParams_Returns400(t *testing.T) {
+	router := setupPathRouter(&MockPathTracesQuerier{})
+	nodeID := uuid.New().String()
+
+	for _, path := range []string{
+		"/api/v1/nodes/not-a-uuid/paths",
+		"/api/v1/nodes/" + nodeID + "/paths?changes_only=maybe",
+		"/api/v1/nodes/" + nodeID + "/paths?limit=0",
+		"/api/v1/nodes/" + nodeID + "/paths?limit=1001",
+	} {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+
+		var resp models.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, middleware.ERR_INVALID_REQUEST, resp.Code)
+	}
+}
+
+func TestGetPathTraces_DatabaseError_Returns500(t *testing.T) {
+	querier := &MockPathTracesQuerier{
+		getPathTracesFunc: func(ctx context.Context, id uuid.UUID, probeID string, changesOnly bool, limit int) ([]*models.PathTrace, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+	router := setupPathRouter(querier)
+
+	req, _ := http.NewRequest("GET", "/api/v1/nodes/"+uuid.New().String()+"/paths", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "connection refused")
+}
+
+func TestPathHash_IgnoresSilentHops(t *testing.T) {
+	full := []models.PathHop{{TTL: 1, Address: "10.0.0.1"}, {TTL: 2, Address: "10.0.1.1"}, {TTL: 3, Address: "8.8.8.8"}}
+	silent := []models.PathHop{{TTL: 1, Address: "10.0.0.1"}, {TTL: 2}, {TTL: 3, Address: "8.8.8.8"}}
+	rerouted := []models.PathHop{{TTL: 1, Address: "10.0.0.1"}, {TTL: 2, Address: "10.0.2.1"}, {TTL: 3, Address: "8.8.8.8"}}
+
+	assert.Len(t, pathHash(full), 64)
+	assert.Equal(t, pathHash(silent), pathHash([]models.PathHop{{TTL: 1, Address: "10.0.0.1"}, {TTL: 3, Address: "8.8.8.8"}}))
+	assert.NotEqual(t, pathHash(full), pathHash(rerouted))
+}
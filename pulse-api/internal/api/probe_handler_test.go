@@ -92,7 +92,7 @@ func TestCreateProbeHandler_ValidTCPProbe(t *testing.T) {
 			assert.Equal(t, "example.com", target)
 			assert.Equal(t, 80, port)
 			assert.Equal(t, 60, intervalSeconds)
-			assert.Equal(t, 5, count)
+			assert.Equal(t, 10, count)
 			assert.Equal(t, 10, timeoutSeconds)
 			return nil
 		},
@@ -104,7 +104,7 @@ func TestCreateProbeHandler_ValidTCPProbe(t *testing.T) {
 				Target:          "example.com",
 				Port:            80,
 				IntervalSeconds: 60,
-				Count:           5,
+				Count:           10,
 				TimeoutSeconds:  10,
 				CreatedAt:       time.Now(),
 				UpdatedAt:       time.Now(),
@@ -120,7 +120,7 @@ func TestCreateProbeHandler_ValidTCPProbe(t *testing.T) {
 		Target:          "example.com",
 		Port:            80,
 		IntervalSeconds: 60,
-		Count:           5,
+		Count:           10,
 		TimeoutSeconds:  10,
 	}
 
@@ -167,7 +167,7 @@ func TestCreateProbeHandler_InvalidProbeType(t *testing.T) {
 		"target": "example.com",
 		"port": 80,
 		"interval_seconds": 60,
-		"count": 5,
+		"count": 10,
 		"timeout_seconds": 10
 	}`
 
@@ -202,7 +202,7 @@ func TestCreateProbeHandler_InvalidInterval(t *testing.T) {
 		"target": "example.com",
 		"port": 80,
 		"interval_seconds": 30,
-		"count": 5,
+		"count": 10,
 		"timeout_seconds": 10
 	}`
 
@@ -242,7 +242,7 @@ func TestCreateProbeHandler_NodeNotFound(t *testing.T) {
 		Target:          "example.com",
 		Port:            80,
 		IntervalSeconds: 60,
-		Count:           5,
+		Count:           10,
 		TimeoutSeconds:  10,
 	}
 
@@ -287,7 +287,7 @@ func TestCreateProbeHandler_InvalidTarget(t *testing.T) {
 		Target:          "-invalid-domain", // Invalid domain format
 		Port:            80,
 		IntervalSeconds: 60,
-		Count:           5,
+		Count:           10,
 		TimeoutSeconds:  10,
 	}
 
@@ -322,7 +322,7 @@ func TestGetProbesHandler_Success(t *testing.T) {
 			Target:          "example.com",
 			Port:            80,
 			IntervalSeconds: 60,
-			Count:           5,
+			Count:           10,
 			TimeoutSeconds:  10,
 			CreatedAt:       time.Now(),
 			UpdatedAt:       time.Now(),
@@ -483,6 +483,36 @@ func TestUpdateProbeHandler_Success(t *testing.T) {
 	assert.Equal(t, newInterval, response.Data.Probe.IntervalSeconds)
 }
 
+// TestUpdateProbeHandler_CountTooLow tests that updates keep the count
+// beacon ping probes accept
+func TestUpdateProbeHandler_CountTooLow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	probeID := uuid.New()
+	count := 5
+	updated := false
+
+	mockProbeQuerier := &MockProbesQuerier{
+		updateProbeFunc: func(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error {
+			updated = true
+			return nil
+		},
+	}
+	handler := NewProbeHandler(mockProbeQuerier, &MockNodesQuerier{})
+
+	body, _ := json.Marshal(models.UpdateProbeRequest{Count: &count})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("PUT", "/api/v1/probes/"+probeID.String(), strings.NewReader(string(body)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{gin.Param{Key: "id", Value: probeID.String()}}
+
+	handler.UpdateProbeHandler(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.False(t, updated, "Probe should not be updated")
+}
+
 // TestDeleteProbeHandler_Success tests deleting a probe
 func TestDeleteProbeHandler_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -572,7 +602,7 @@ func TestCreateProbeHandler_CaseInsensitiveType(t *testing.T) {
 						Target:          "example.com",
 						Port:            80,
 						IntervalSeconds: 60,
-						Count:           5,
+						Count:           10,
 						TimeoutSeconds:  10,
 						CreatedAt:       time.Now(),
 						UpdatedAt:       time.Now(),
@@ -588,7 +618,7 @@ func TestCreateProbeHandler_CaseInsensitiveType(t *testing.T) {
 				Target:          "example.com",
 				Port:            80,
 				IntervalSeconds: 60,
-				Count:           5,
+				Count:           10,
 				TimeoutSeconds:  10,
 			}
 
@@ -644,7 +674,7 @@ func TestCreateProbeHandler_PortBoundaryValues(t *testing.T) {
 				Target:          "example.com",
 				Port:            tc.port,
 				IntervalSeconds: 60,
-				Count:           5,
+				Count:           10,
 				TimeoutSeconds:  10,
 			}
 
@@ -703,7 +733,7 @@ func TestCreateProbeHandler_IntervalBoundaryValues(t *testing.T) {
 				Target:          "example.com",
 				Port:            80,
 				IntervalSeconds: tc.interval,
-				Count:           5,
+				Count:           10,
 				TimeoutSeconds:  10,
 			}
 
@@ -735,9 +765,10 @@ func TestCreateProbeHandler_CountBoundaryValues(t *testing.T) {
 		count       int
 		shouldPass  bool
 	}{
-		{"count_min_valid", 1, true},
+		{"count_min_valid", 10, true},
 		{"count_max_valid", 100, true},
 		{"count_zero", 0, false},
+		{"count_too_low_for_ping", 9, false},
 		{"count_too_high", 101, false},
 	}
 
@@ -821,7 +852,7 @@ func TestCreateProbeHandler_TimeoutBoundaryValues(t *testing.T) {
 				Target:          "example.com",
 				Port:            80,
 				IntervalSeconds: 60,
-				Count:           5,
+				Count:           10,
 				TimeoutSeconds:  tc.timeout,
 			}
 
@@ -5,6 +5,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/kevin/node-pulse/pulse-api/internal/cache"
+	"github.com/kevin/node-pulse/pulse-api/internal/config"
 	"github.com/kevin/node-pulse/pulse-api/internal/db"
 	"github.com/kevin/node-pulse/pulse-api/internal/health"
 	"github.com/kevin/node-pulse/pulse-api/internal/auth"
@@ -17,8 +18,11 @@ type CacheManager struct {
 	BatchWriter *cache.BatchWriter
 }
 
-// SetupRoutes configures all API routes and returns cache manager for shutdown
-func SetupRoutes(router *gin.Engine, healthChecker *health.HealthChecker, pool *pgxpool.Pool) *CacheManager {
+// SetupRoutes configures all API routes and returns cache manager for shutdown.
+// tlsConfig may be nil when the server does not terminate TLS itself,
+// meshConfig may be nil when mesh probing is disabled, and enrollmentConfig
+// may be nil when beacon self-registration is disabled.
+func SetupRoutes(router *gin.Engine, healthChecker *health.HealthChecker, pool *pgxpool.Pool, tlsConfig *config.TLSConfig, meshConfig *config.MeshConfig, enrollmentConfig *config.EnrollmentConfig) *CacheManager {
 	// Initialize rate limiter
 	middleware.InitRateLimiter()
 
@@ -43,12 +47,39 @@ func SetupRoutes(router *gin.Engine, healthChecker *health.HealthChecker, pool *
 		// Health check endpoint (public)
 		v1.GET("/health", healthChecker.Handler)
 
-		// Beacon endpoints (public - no auth required for MVP)
-		beaconHandler := NewBeaconHandler(db.NewPoolQuerier(pool), memoryCache, batchWriter)
+		// Beacon endpoints (per-node API token auth)
+		tokenQuerier := db.NewPoolQuerier(pool)
+		beaconHandler := NewBeaconHandler(db.NewPoolQuerier(pool), tokenQuerier, memoryCache, batchWriter)
+		beaconConfigHandler := NewBeaconConfigHandler(db.NewPoolQuerier(pool), db.NewPoolQuerier(pool))
+		beaconConfigHandler.SetMeshConfig(meshConfig)
 		beacon := v1.Group("/beacon")
 		{
-			// POST /api/v1/beacon/heartbeat - Receive heartbeat data (public)
+			// Client certificates identify the node when beacon mTLS is required
+			// (registration only needs a certificate from the trusted CA)
+			requireClientCert := tlsConfig != nil && tlsConfig.BeaconMTLS
+
+			// POST /api/v1/beacon/register - Beacon self-registration (issues API token)
+			if requireClientCert {
+				beacon.POST("/register", auth.BeaconClientCertMiddleware(false), beaconHandler.HandleRegister)
+				beacon.Use(auth.BeaconClientCertMiddleware(true))
+			} else {
+				// Without client certificates, new identities require the shared
+				// enrollment token (registration is disabled when none is set)
+				enrollmentToken := ""
+				if enrollmentConfig.Enabled() {
+					enrollmentToken = enrollmentConfig.Token
+				}
+				beacon.POST("/register", auth.BeaconEnrollmentMiddleware(enrollmentToken), beaconHandler.HandleRegister)
+			}
+
+			beacon.Use(auth.BeaconAuthMiddleware(tokenQuerier))
+
+			// POST /api/v1/beacon/heartbeat - Receive heartbeat data (node token)
 			beacon.POST("/heartbeat", beaconHandler.HandleHeartbeat)
+			// POST /api/v1/beacon/heartbeats - Receive batched heartbeat data (node token)
+			beacon.POST("/heartbeats", beaconHandler.HandleHeartbeatBatch)
+			// GET /api/v1/beacon/nodes/:node_id/probes - Probe config sync (node token)
+			beacon.GET("/nodes/:node_id/probes", beaconConfigHandler.HandleGetProbeConfig)
 		}
 
 		// Auth endpoints (public)
@@ -62,7 +93,7 @@ func SetupRoutes(router *gin.Engine, healthChecker *health.HealthChecker, pool *
 		// Node management routes (require auth)
 		sessionService := auth.NewSessionService(pool)
 		nodeQuerier := db.NewPoolQuerier(pool)
-		nodeHandler := NewNodeHandler(nodeQuerier)
+		nodeHandler := NewNodeHandler(nodeQuerier, tokenQuerier)
 
 		// Nodes group with auth middleware
 		nodes := v1.Group("/nodes")
@@ -75,6 +106,10 @@ func SetupRoutes(router *gin.Engine, healthChecker *health.HealthChecker, pool *
 		// CRITICAL: Specific route must come before generic /:id route
 		nodes.GET("/:id/status", nodeHandler.GetNodeStatusHandler)
 
+		// GET /api/v1/nodes/:id/paths - Get path_probe traces of a node (all roles)
+		pathHandler := NewPathHandler(db.NewPoolQuerier(pool))
+		nodes.GET("/:id/paths", pathHandler.GetPathTracesHandler)
+
 		// GET /api/v1/nodes/:id - Get node by ID (all roles)
 		nodes.GET("/:id", nodeHandler.GetNodeByIDHandler)
 
@@ -90,6 +125,12 @@ func SetupRoutes(router *gin.Engine, healthChecker *health.HealthChecker, pool *
 		// DELETE /api/v1/nodes/:id - Delete node (admin/operator only)
 		nodes.DELETE("/:id", nodeHandler.DeleteNodeHandler)
 
+		// POST /api/v1/nodes/:id/token - Rotate beacon API token (admin only)
+		nodes.POST("/:id/token", auth.RBACMiddleware([]string{"admin"}), nodeHandler.RotateNodeTokenHandler)
+
+		// DELETE /api/v1/nodes/:id/token - Revoke beacon API token (admin only)
+		nodes.DELETE("/:id/token", auth.RBACMiddleware([]string{"admin"}), nodeHandler.RevokeNodeTokenHandler)
+
 		// Probe management routes (require auth)
 		probeQuerier := db.NewPoolQuerier(pool)
 		probeHandler := NewProbeHandler(probeQuerier, nodeQuerier)
@@ -115,6 +156,22 @@ func SetupRoutes(router *gin.Engine, healthChecker *health.HealthChecker, pool *
 
 		// DELETE /api/v1/probes/:id - Delete probe (admin/operator only)
 		probes.DELETE("/:id", probeHandler.DeleteProbeHandler)
+
+		// Certificate status routes (require auth)
+		certificateHandler := NewCertificateHandler(db.NewPoolQuerier(pool))
+		certificates := v1.Group("/certificates")
+		certificates.Use(auth.AuthMiddleware(sessionService))
+
+		// GET /api/v1/certificates/expiring - Certificates expiring within N days (all roles)
+		certificates.GET("/expiring", certificateHandler.GetExpiringCertificatesHandler)
+
+		// Mesh matrix routes (require auth)
+		meshHandler := NewMeshHandler(nodeQuerier, db.NewPoolQuerier(pool))
+		mesh := v1.Group("/mesh")
+		mesh.Use(auth.AuthMiddleware(sessionService))
+
+		// GET /api/v1/mesh/matrix - Latest latency/loss between node pairs (all roles)
+		mesh.GET("/matrix", meshHandler.GetMeshMatrixHandler)
 	}
 
 	// Return cache manager for graceful shutdown
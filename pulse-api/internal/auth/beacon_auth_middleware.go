@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/kevin/node-pulse/pulse-api/internal/db"
+)
+
+// BeaconNodeIDKey is the context key holding the node ID authenticated by BeaconAuthMiddleware
+const BeaconNodeIDKey = "beacon_node_id"
+
+// NodeTokenResolver resolves a beacon API token hash to its node ID
+type NodeTokenResolver interface {
+	GetNodeIDByTokenHash(ctx context.Context, tokenHash string) (uuid.UUID, error)
+}
+
+// BeaconAuthMiddleware validates the beacon API token and sets the node ID context.
+// Handlers must still check that the node_id in the request matches BeaconNodeIDKey.
+func BeaconAuthMiddleware(resolver NodeTokenResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := BearerToken(c.GetHeader("Authorization"))
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"code":    "ERR_UNAUTHORIZED",
+				"message": "Beacon API token required",
+			})
+			return
+		}
+
+		nodeID, err := resolver.GetNodeIDByTokenHash(c.Request.Context(), HashNodeToken(token))
+		if err != nil {
+			if errors.Is(err, db.ErrNodeNotFound) {
+				// Unknown, rotated or revoked token
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"code":    "ERR_INVALID_NODE_TOKEN",
+					"message": "Invalid or revoked beacon API token",
+				})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"code":    "ERR_DATABASE_ERROR",
+				"message": "Failed to validate beacon API token",
+			})
+			return
+		}
+
+		// With mTLS the certificate and the token must identify the same node
+		if certNodeID, exists := c.Get(BeaconNodeIDKey); exists && certNodeID != nodeID.String() {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"code":    "ERR_NODE_TOKEN_MISMATCH",
+				"message": "Beacon API token does not match client certificate",
+			})
+			return
+		}
+
+		c.Set(BeaconNodeIDKey, nodeID.String())
+		c.Next()
+	}
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"crypto/x509"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BeaconClientCertMiddleware requires a client certificate verified against the
+// server's client CA bundle and maps it to a node ID (set in BeaconNodeIDKey).
+// With requireNodeID false (self-registration), any verified certificate is accepted.
+func BeaconClientCertMiddleware(requireNodeID bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		state := c.Request.TLS
+		if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"code":    "ERR_CLIENT_CERT_REQUIRED",
+				"message": "Verified client certificate required",
+			})
+			return
+		}
+
+		nodeID, ok := NodeIDFromCertificate(state.VerifiedChains[0][0])
+		if !ok {
+			if requireNodeID {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+					"code":    "ERR_CLIENT_CERT_NO_NODE_ID",
+					"message": "Client certificate does not identify a node",
+				})
+				return
+			}
+			c.Next()
+			return
+		}
+
+		c.Set(BeaconNodeIDKey, nodeID.String())
+		c.Next()
+	}
+}
+
+// NodeIDFromCertificate extracts the node ID from a beacon client certificate.
+// The first UUID found is used, checking in order: the subject CN, URI SANs
+// ("urn:uuid:<id>" or a path ending in the ID, e.g. spiffe://pulse/beacon/<id>)
+// and the first label of DNS SANs ("<id>.beacons.example.com").
+func NodeIDFromCertificate(cert *x509.Certificate) (uuid.UUID, bool) {
+	candidates := []string{cert.Subject.CommonName}
+
+	for _, u := range cert.URIs {
+		if strings.EqualFold(u.Scheme, "urn") {
+			candidates = append(candidates, strings.TrimPrefix(strings.ToLower(u.Opaque), "uuid:"))
+			continue
+		}
+		candidates = append(candidates, path.Base(u.Path))
+	}
+
+	for _, name := range cert.DNSNames {
+		label, _, _ := strings.Cut(name, ".")
+		candidates = append(candidates, label)
+	}
+
+	for _, candidate := range candidates {
+		// uuid.Parse also accepts "urn:uuid:" and braced forms; require the plain form
+		if len(candidate) != 36 {
+			continue
+		}
+		if nodeID, err := uuid.Parse(candidate); err == nil {
+			return nodeID, true
+		}
+	}
+
+	return uuid.Nil, false
+}
@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kevin/node-pulse/pulse-api/internal/testutil"
+)
+
+// newMTLSServer starts an in-process TLS server that verifies client
+// certificates against ca and echoes the node ID mapped by the middleware
+func newMTLSServer(t *testing.T, ca *testutil.TestCA, requireNodeID bool) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/beacon", BeaconClientCertMiddleware(requireNodeID), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString(BeaconNodeIDKey))
+	})
+
+	server := httptest.NewUnstartedServer(router)
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{ca.Issue(t, testutil.CertOptions{CommonName: "pulse"})},
+		ClientCAs:    ca.Pool(),
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+// getWithClientCert calls the server, presenting certs (if any) as client certificate
+func getWithClientCert(t *testing.T, server *httptest.Server, ca *testutil.TestCA, certs ...tls.Certificate) (int, string) {
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      ca.Pool(),
+		Certificates: certs,
+	}}}
+
+	resp, err := client.Get(server.URL + "/beacon")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+// TestBeaconClientCertMiddleware_MapsCommonName tests CN to node ID mapping
+func TestBeaconClientCertMiddleware_MapsCommonName(t *testing.T) {
+	// Arrange
+	ca := testutil.NewTestCA(t)
+	server := newMTLSServer(t, ca, true)
+	nodeID := uuid.New()
+
+	// Act
+	status, body := getWithClientCert(t, server, ca, ca.Issue(t, testutil.CertOptions{CommonName: nodeID.String(), Client: true}))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, nodeID.String(), body)
+}
+
+// TestBeaconClientCertMiddleware_NoCertificate tests that a certificate is required
+func TestBeaconClientCertMiddleware_NoCertificate(t *testing.T) {
+	// Arrange
+	ca := testutil.NewTestCA(t)
+	server := newMTLSServer(t, ca, false)
+
+	// Act
+	status, body := getWithClientCert(t, server, ca)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Contains(t, body, "ERR_CLIENT_CERT_REQUIRED")
+}
+
+// TestBeaconClientCertMiddleware_UntrustedCA tests that certificates from other CAs are rejected
+func TestBeaconClientCertMiddleware_UntrustedCA(t *testing.T) {
+	// Arrange
+	ca := testutil.NewTestCA(t)
+	otherCA := testutil.NewTestCA(t)
+	server := newMTLSServer(t, ca, true)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      ca.Pool(),
+		Certificates: []tls.Certificate{otherCA.Issue(t, testutil.CertOptions{CommonName: uuid.New().String(), Client: true})},
+	}}}
+
+	// Act
+	_, err := client.Get(server.URL + "/beacon")
+
+	// Assert - handshake fails
+	assert.Error(t, err)
+}
+
+// TestBeaconClientCertMiddleware_NoNodeID tests certificates without node identity
+func TestBeaconClientCertMiddleware_NoNodeID(t *testing.T) {
+	// Arrange
+	ca := testutil.NewTestCA(t)
+	cert := ca.Issue(t, testutil.CertOptions{CommonName: "beacon-01", Client: true})
+
+	// Act - node ID required (heartbeat routes)
+	status, _ := getWithClientCert(t, newMTLSServer(t, ca, true), ca, cert)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, status)
+
+	// Act - node ID optional (registration)
+	status, body := getWithClientCert(t, newMTLSServer(t, ca, false), ca, cert)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, status)
+	assert.Empty(t, body)
+}
+
+// TestNodeIDFromCertificate tests CN and SAN mapping rules
+func TestNodeIDFromCertificate(t *testing.T) {
+	nodeID := uuid.New()
+	mustURL := func(raw string) *url.URL {
+		u, err := url.Parse(raw)
+		require.NoError(t, err)
+		return u
+	}
+
+	tests := []struct {
+		name string
+		cert *x509.Certificate
+		want bool
+	}{
+		{"common name", &x509.Certificate{Subject: pkix.Name{CommonName: nodeID.String()}}, true},
+		{"urn uuid SAN", &x509.Certificate{URIs: []*url.URL{mustURL("urn:uuid:" + nodeID.String())}}, true},
+		{"spiffe SAN", &x509.Certificate{URIs: []*url.URL{mustURL("spiffe://pulse/beacon/" + nodeID.String())}}, true},
+		{"DNS SAN", &x509.Certificate{DNSNames: []string{nodeID.String() + ".beacons.example.com"}}, true},
+		{"no identity", &x509.Certificate{Subject: pkix.Name{CommonName: "beacon-01"}, DNSNames: []string{"beacon-01.example.com"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NodeIDFromCertificate(tt.cert)
+			assert.Equal(t, tt.want, ok)
+			if tt.want {
+				assert.Equal(t, nodeID, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EnrollmentTokenHeader carries the shared beacon enrollment token on self-registration
+const EnrollmentTokenHeader = "X-Beacon-Enrollment-Token"
+
+// BeaconEnrollmentMiddleware requires the shared enrollment token before a
+// beacon may self-register. With an empty token, self-registration is
+// disabled and every request is rejected.
+func BeaconEnrollmentMiddleware(enrollmentToken string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if enrollmentToken == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"code":    "ERR_REGISTRATION_DISABLED",
+				"message": "Beacon self-registration is disabled, ask an admin to create the node",
+			})
+			return
+		}
+
+		token := c.GetHeader(EnrollmentTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(enrollmentToken)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"code":    "ERR_INVALID_ENROLLMENT_TOKEN",
+				"message": "Valid beacon enrollment token required",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBeaconEnrollmentMiddleware tests that self-registration requires the
+// shared enrollment token and is disabled without one
+func TestBeaconEnrollmentMiddleware(t *testing.T) {
+	tests := []struct {
+		name            string
+		enrollmentToken string
+		header          string
+		wantStatus      int
+		wantCode        string
+	}{
+		{"valid token", "enroll-0123456789abcdef", "enroll-0123456789abcdef", http.StatusOK, ""},
+		{"missing token", "enroll-0123456789abcdef", "", http.StatusUnauthorized, "ERR_INVALID_ENROLLMENT_TOKEN"},
+		{"wrong token", "enroll-0123456789abcdef", "enroll-wrong", http.StatusUnauthorized, "ERR_INVALID_ENROLLMENT_TOKEN"},
+		{"registration disabled", "", "", http.StatusForbidden, "ERR_REGISTRATION_DISABLED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/register", BeaconEnrollmentMiddleware(tt.enrollmentToken), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/register", nil)
+			if tt.header != "" {
+				req.Header.Set(EnrollmentTokenHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantCode != "" {
+				assert.Contains(t, w.Body.String(), tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// NodeTokenPrefix marks beacon API tokens so they are recognizable in configs and logs
+const NodeTokenPrefix = "npb_"
+
+// GenerateNodeToken creates a random beacon API token (256 bits of entropy)
+func GenerateNodeToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return NodeTokenPrefix + hex.EncodeToString(buf), nil
+}
+
+// HashNodeToken returns the SHA-256 hex digest stored in place of the token.
+// Tokens are high-entropy random values, so a fast hash is sufficient and keeps
+// per-heartbeat verification cheap (unlike bcrypt for passwords).
+func HashNodeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer <token>" header.
+// Returns "" if the header is missing or uses another scheme.
+func BearerToken(header string) string {
+	scheme, token, found := strings.Cut(strings.TrimSpace(header), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
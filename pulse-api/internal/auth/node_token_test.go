@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerateNodeToken tests token format and uniqueness
+func TestGenerateNodeToken(t *testing.T) {
+	// Act
+	token1, err1 := GenerateNodeToken()
+	token2, err2 := GenerateNodeToken()
+
+	// Assert
+	require.NoError(t, err1)
+	require.NoError(t, err2)
+	assert.True(t, strings.HasPrefix(token1, NodeTokenPrefix))
+	assert.Len(t, token1, len(NodeTokenPrefix)+64)
+	assert.NotEqual(t, token1, token2, "Tokens should be unique")
+}
+
+// TestHashNodeToken tests the stored hash is deterministic and not the token
+func TestHashNodeToken(t *testing.T) {
+	// Arrange
+	token, err := GenerateNodeToken()
+	require.NoError(t, err)
+
+	// Act
+	hash := HashNodeToken(token)
+
+	// Assert
+	assert.Len(t, hash, 64)
+	assert.NotContains(t, hash, token)
+	assert.Equal(t, hash, HashNodeToken(token))
+	assert.NotEqual(t, hash, HashNodeToken(token+"x"))
+}
+
+// TestBearerToken tests Authorization header parsing
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"Bearer npb_abc", "npb_abc"},
+		{"bearer npb_abc", "npb_abc"},
+		{"  Bearer   npb_abc  ", "npb_abc"},
+		{"Basic dXNlcjpwYXNz", ""},
+		{"npb_abc", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, BearerToken(tt.header), "header %q", tt.header)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kevin/node-pulse/pulse-api/internal/models"
 )
 
 // MetricRecord represents a metric record to be written to PostgreSQL
@@ -19,6 +20,63 @@ type MetricRecord struct {
 	PacketLossRate float64
 	JitterMs       float64
 	IsAggregated   bool
+
+	// Per-probe statistics (optional, zero for older beacons)
+	LatencyMedianMs float64
+	VarianceMs      float64
+	SampleCount     int
+	Success         *bool
+	Partial         bool // Batch aborted before all attempts completed
+
+	// RTT statistics selected in the beacon config (optional)
+	Statistics models.LatencyStatistics
+
+	// Echo measurements reported by udp_ping against a beacon responder (optional)
+	ReorderedPackets int
+	DuplicatePackets int
+	OneWayForwardMs  *float64
+	OneWayReverseMs  *float64
+
+	// Raw samples of the probe batch (optional), stored in probe_samples
+	Samples []SampleRecord
+
+	// Probe target host, stored with path traces and certificates
+	Target string
+
+	// Path trace reported by path_probe (optional), stored in path_traces
+	PathHops    []models.PathHop
+	PathHash    string
+	PathReached bool
+
+	// MeshTargetNodeID is set for mesh probes; their result replaces the
+	// node pair's entry in mesh_matrix instead of adding a metrics row
+	MeshTargetNodeID string
+	ProbeType        string
+
+	// Certificate status reported by tls_probe (optional), replacing the
+	// probe's entry in certificate_status
+	Certificate *CertificateInfo
+
+	// Unregistered skips the metrics row for probes not registered in Pulse,
+	// which metrics.probe_id cannot reference; only the path trace and
+	// certificate status are stored
+	Unregistered bool
+}
+
+// CertificateInfo is the certificate status reported by a tls_probe
+type CertificateInfo struct {
+	NotAfter   time.Time // Leaf expiry
+	ChainValid *bool     // Chain verified by the beacon
+	TLSVersion string
+	TLSCipher  string
+}
+
+// SampleRecord is one raw probe attempt of a MetricRecord
+type SampleRecord struct {
+	Timestamp  time.Time
+	Success    bool
+	RTTMs      float64
+	ErrorClass string // Empty for successful samples
 }
 
 // BatchWriter handles async batch writing of metrics to PostgreSQL
@@ -30,6 +88,7 @@ type BatchWriter struct {
 	ctx         context.Context    // Context for cancellation
 	cancel      context.CancelFunc // Cancel function
 	wg          sync.WaitGroup     // Wait group for graceful shutdown
+	writeMu     sync.Mutex         // Serializes producers so WriteBatch is all-or-nothing
 }
 
 // NewBatchWriter creates a new batch writer
@@ -71,6 +130,9 @@ func (bw *BatchWriter) Write(record *MetricRecord) error {
 		return ErrNilMetricRecord
 	}
 
+	bw.writeMu.Lock()
+	defer bw.writeMu.Unlock()
+
 	select {
 	case bw.buffer <- record:
 		return nil
@@ -80,6 +142,30 @@ func (bw *BatchWriter) Write(record *MetricRecord) error {
 	}
 }
 
+// WriteBatch adds a group of metric records to the buffer as one unit (non-blocking).
+// Either all records are buffered or none are, in which case ErrBufferFull is returned.
+func (bw *BatchWriter) WriteBatch(records []*MetricRecord) error {
+	for _, record := range records {
+		if record == nil {
+			return ErrNilMetricRecord
+		}
+	}
+
+	bw.writeMu.Lock()
+	defer bw.writeMu.Unlock()
+
+	// Only the consumer drains the buffer while producers hold writeMu,
+	// so free capacity can only grow after this check
+	if cap(bw.buffer)-len(bw.buffer) < len(records) {
+		return ErrBufferFull
+	}
+
+	for _, record := range records {
+		bw.buffer <- record
+	}
+	return nil
+}
+
 // processBatches runs in background goroutine, processing batch writes
 func (bw *BatchWriter) processBatches() {
 	defer bw.wg.Done()
@@ -170,19 +256,154 @@ func (bw *BatchWriter) writeBatch(batch []*MetricRecord) error {
 	}
 	defer tx.Rollback(ctx)
 
-	// Batch insert statement
+	// Batch insert statement. Records of probes that were deleted in Pulse
+	// while beacons still report them are dropped rather than failing the
+	// shared transaction with a foreign key violation, and records already
+	// stored (a heartbeat delivered twice) are skipped
 	stmt := `
 		INSERT INTO metrics (
 			node_id, probe_id, timestamp,
 			latency_ms, packet_loss_rate, jitter_ms,
-			is_aggregated, created_at
+			is_aggregated, latency_median_ms, variance_ms,
+			sample_count, success, reordered_packets,
+			duplicate_packets, one_way_forward_ms, one_way_reverse_ms,
+			latency_min_ms, latency_max_ms, latency_stddev_ms,
+			latency_p90_ms, latency_p95_ms, latency_p99_ms,
+			jitter_rfc3550_ms, partial, created_at
+		)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14,
+			$15, $16, $17, $18, $19, $20, $21, $22, $23, NOW()
+		WHERE EXISTS (SELECT 1 FROM probes WHERE id = $2)
+		ON CONFLICT DO NOTHING
+	`
+
+	// A trace is marked changed when its path differs from the previous
+	// trace of the same probe (the first trace of a probe is not a change);
+	// a trace already stored is skipped
+	pathStmt := `
+		INSERT INTO path_traces (
+			node_id, probe_id, target, timestamp,
+			reached, hops, path_hash, path_changed
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, NOW()
+			$1, $2, $3, $4, $5, $6, $7,
+			COALESCE((
+				SELECT path_hash <> $7 FROM path_traces
+				WHERE node_id = $1 AND probe_id = $2
+				ORDER BY timestamp DESC, id DESC
+				LIMIT 1
+			), FALSE)
 		)
+		ON CONFLICT DO NOTHING
+	`
+
+	// Only newer certificates replace a probe's entry
+	certificateStmt := `
+		INSERT INTO certificate_status (
+			node_id, probe_id, target, not_after,
+			chain_valid, tls_version, tls_cipher, last_seen
+		) VALUES (
+			$1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8
+		)
+		ON CONFLICT (node_id, probe_id) DO UPDATE SET
+			target = EXCLUDED.target,
+			not_after = EXCLUDED.not_after,
+			chain_valid = EXCLUDED.chain_valid,
+			tls_version = EXCLUDED.tls_version,
+			tls_cipher = EXCLUDED.tls_cipher,
+			last_seen = EXCLUDED.last_seen
+		WHERE certificate_status.last_seen <= EXCLUDED.last_seen
+	`
+
+	// Raw samples of a record are inserted in one statement, numbered by
+	// their position in the batch; failed samples store no RTT, samples of
+	// deleted probes are dropped like metrics and stored samples are skipped
+	sampleStmt := `
+		INSERT INTO probe_samples (
+			node_id, probe_id, timestamp, seq, success, rtt_ms, error_class
+		)
+		SELECT $1, $2, s.timestamp, s.seq, s.success,
+			CASE WHEN s.success THEN s.rtt_ms END, NULLIF(s.error_class, '')
+		FROM unnest($3::timestamptz[], $4::boolean[], $5::real[], $6::text[])
+			WITH ORDINALITY AS s(timestamp, success, rtt_ms, error_class, seq)
+		WHERE EXISTS (SELECT 1 FROM probes WHERE id = $2)
+		ON CONFLICT DO NOTHING
+	`
+
+	// Only newer results replace a pair's entry, and results for peers that
+	// were deleted meanwhile are dropped rather than failing the batch
+	meshStmt := `
+		INSERT INTO mesh_matrix (
+			source_node_id, target_node_id, probe_type, success,
+			latency_ms, packet_loss_rate, jitter_ms, sample_count, updated_at
+		)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9
+		WHERE EXISTS (SELECT 1 FROM nodes WHERE id = $2)
+		ON CONFLICT (source_node_id, target_node_id) DO UPDATE SET
+			probe_type = EXCLUDED.probe_type,
+			success = EXCLUDED.success,
+			latency_ms = EXCLUDED.latency_ms,
+			packet_loss_rate = EXCLUDED.packet_loss_rate,
+			jitter_ms = EXCLUDED.jitter_ms,
+			sample_count = EXCLUDED.sample_count,
+			updated_at = EXCLUDED.updated_at
+		WHERE mesh_matrix.updated_at <= EXCLUDED.updated_at
 	`
 
 	// Execute insert for each record within transaction
 	for _, record := range batch {
+		if record.MeshTargetNodeID != "" {
+			_, err := tx.Exec(ctx, meshStmt,
+				record.NodeID,
+				record.MeshTargetNodeID,
+				record.ProbeType,
+				record.Success,
+				record.LatencyMs,
+				record.PacketLossRate,
+				record.JitterMs,
+				record.SampleCount,
+				record.Timestamp,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to upsert mesh result: %w", err)
+			}
+			continue
+		}
+
+		if len(record.PathHops) > 0 {
+			_, err := tx.Exec(ctx, pathStmt,
+				record.NodeID,
+				record.ProbeID,
+				record.Target,
+				record.Timestamp,
+				record.PathReached,
+				record.PathHops,
+				record.PathHash,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to insert path trace: %w", err)
+			}
+		}
+
+		if record.Certificate != nil {
+			_, err := tx.Exec(ctx, certificateStmt,
+				record.NodeID,
+				record.ProbeID,
+				record.Target,
+				record.Certificate.NotAfter,
+				record.Certificate.ChainValid,
+				record.Certificate.TLSVersion,
+				record.Certificate.TLSCipher,
+				record.Timestamp,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to upsert certificate status: %w", err)
+			}
+		}
+
+		if record.Unregistered {
+			continue
+		}
+
 		_, err := tx.Exec(ctx, stmt,
 			record.NodeID,
 			record.ProbeID,
@@ -191,10 +412,42 @@ func (bw *BatchWriter) writeBatch(batch []*MetricRecord) error {
 			record.PacketLossRate,
 			record.JitterMs,
 			record.IsAggregated,
+			record.LatencyMedianMs,
+			record.VarianceMs,
+			record.SampleCount,
+			record.Success,
+			record.ReorderedPackets,
+			record.DuplicatePackets,
+			record.OneWayForwardMs,
+			record.OneWayReverseMs,
+			record.Statistics.LatencyMinMs,
+			record.Statistics.LatencyMaxMs,
+			record.Statistics.LatencyStdDevMs,
+			record.Statistics.LatencyP90Ms,
+			record.Statistics.LatencyP95Ms,
+			record.Statistics.LatencyP99Ms,
+			record.Statistics.JitterRFC3550Ms,
+			record.Partial,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to insert record: %w", err)
 		}
+
+		if len(record.Samples) > 0 {
+			timestamps := make([]time.Time, len(record.Samples))
+			successes := make([]bool, len(record.Samples))
+			rtts := make([]float32, len(record.Samples))
+			errorClasses := make([]string, len(record.Samples))
+			for i, sample := range record.Samples {
+				timestamps[i] = sample.Timestamp
+				successes[i] = sample.Success
+				rtts[i] = float32(sample.RTTMs)
+				errorClasses[i] = sample.ErrorClass
+			}
+			if _, err := tx.Exec(ctx, sampleStmt, record.NodeID, record.ProbeID, timestamps, successes, rtts, errorClasses); err != nil {
+				return fmt.Errorf("failed to insert samples: %w", err)
+			}
+		}
 	}
 
 	if err := tx.Commit(ctx); err != nil {
@@ -279,3 +279,40 @@ func TestBatchWriter_TimeoutTrigger(t *testing.T) {
 		t.Errorf("Expected buffer to be flushed by timeout trigger, got size %d", finalBufferSize)
 	}
 }
+
+// TestBatchWriter_WriteBatch tests that a batch is buffered all-or-nothing
+func TestBatchWriter_WriteBatch(t *testing.T) {
+	bw := NewBatchWriter(nil, 5, 100)
+
+	newRecords := func(n int) []*MetricRecord {
+		records := make([]*MetricRecord, n)
+		for i := range records {
+			records[i] = &MetricRecord{
+				NodeID:    "550e8400-e29b-41d4-a716-446655440000",
+				ProbeID:   "550e8400-e29b-41d4-a716-446655440001",
+				Timestamp: time.Now(),
+				LatencyMs: 10.0,
+			}
+		}
+		return records
+	}
+
+	if err := bw.WriteBatch(newRecords(3)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if bw.GetBufferSize() != 3 {
+		t.Errorf("Expected buffer size 3, got %d", bw.GetBufferSize())
+	}
+
+	// Only 2 slots left: a batch of 3 must be rejected without partial writes
+	if err := bw.WriteBatch(newRecords(3)); err != ErrBufferFull {
+		t.Errorf("Expected ErrBufferFull, got %v", err)
+	}
+	if bw.GetBufferSize() != 3 {
+		t.Errorf("Expected buffer size to stay 3, got %d", bw.GetBufferSize())
+	}
+
+	if err := bw.WriteBatch([]*MetricRecord{nil}); err != ErrNilMetricRecord {
+		t.Errorf("Expected ErrNilMetricRecord, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 )
@@ -9,6 +10,7 @@ import (
 // MetricPoint represents a single metric data point for a node
 type MetricPoint struct {
 	Timestamp      time.Time
+	ProbeID        string // Probe that produced this point
 	LatencyMs      float64
 	PacketLossRate float64
 	JitterMs       float64
@@ -93,23 +95,20 @@ func (rb *RingBuffer) Size() int {
 // AggregateMetrics aggregates metrics in the buffer by 1-minute intervals
 // Returns aggregated metrics with mean, max, and min values
 func (rb *RingBuffer) AggregateMetrics() []*AggregatedMetrics {
-	rb.mutex.RLock()
-	defer rb.mutex.RUnlock()
+	return aggregatePoints(rb.ReadAll())
+}
 
-	if rb.size == 0 {
+// aggregatePoints aggregates metric points by 1-minute intervals
+// Returns aggregated metrics with mean, max, and min values
+func aggregatePoints(points []*MetricPoint) []*AggregatedMetrics {
+	if len(points) == 0 {
 		return nil
 	}
 
 	// Group metrics by minute (truncate to minute)
 	minuteBuckets := make(map[time.Time][]*MetricPoint)
 
-	for i := 0; i < rb.size; i++ {
-		idx := (rb.tail + i) % rb.capacity
-		point := rb.data[idx]
-		if point == nil {
-			continue
-		}
-
+	for _, point := range points {
 		minuteKey := point.Timestamp.Truncate(time.Minute)
 		minuteBuckets[minuteKey] = append(minuteBuckets[minuteKey], point)
 	}
@@ -155,8 +154,14 @@ func (rb *RingBuffer) AggregateMetrics() []*AggregatedMetrics {
 	return result
 }
 
-// MemoryCache stores node metrics in memory using sync.Map
-// Key: node_id (UUID), Value: *RingBuffer
+// bufferKey identifies the ring buffer of one probe of a node
+type bufferKey struct {
+	nodeID  string
+	probeID string
+}
+
+// MemoryCache stores probe metrics in memory using sync.Map
+// Key: bufferKey (node_id, probe_id), Value: *RingBuffer
 type MemoryCache struct {
 	nodes    sync.Map
 	ctx      context.Context
@@ -221,7 +226,8 @@ func (mc *MemoryCache) aggregateAllNodes() {
 	}
 }
 
-// Store writes a metric point to the cache for the specified node
+// Store writes a metric point to the cache for the specified node, in the
+// ring buffer of the probe that produced it
 func (mc *MemoryCache) Store(nodeID string, point *MetricPoint) error {
 	if nodeID == "" {
 		return ErrEmptyNodeID
@@ -230,8 +236,8 @@ func (mc *MemoryCache) Store(nodeID string, point *MetricPoint) error {
 		return ErrNilMetricPoint
 	}
 
-	// Get or create ring buffer for this node
-	actual, _ := mc.nodes.LoadOrStore(nodeID, NewRingBuffer(60))
+	// Get or create ring buffer for this probe
+	actual, _ := mc.nodes.LoadOrStore(bufferKey{nodeID: nodeID, probeID: point.ProbeID}, NewRingBuffer(60))
 	buffer := actual.(*RingBuffer)
 
 	// Write to buffer (FIFO eviction after 1 hour)
@@ -245,9 +251,24 @@ func (mc *MemoryCache) Store(nodeID string, point *MetricPoint) error {
 	return nil
 }
 
-// Get retrieves all metrics for a specific node
+// Get retrieves all metrics for a specific node, of all its probes, ordered
+// by timestamp
 func (mc *MemoryCache) Get(nodeID string) []*MetricPoint {
-	actual, ok := mc.nodes.Load(nodeID)
+	var points []*MetricPoint
+	mc.nodes.Range(func(key, value interface{}) bool {
+		if key.(bufferKey).nodeID == nodeID {
+			points = append(points, value.(*RingBuffer).ReadAll()...)
+		}
+		return true
+	})
+
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Timestamp.Before(points[j].Timestamp) })
+	return points
+}
+
+// GetProbe retrieves the metrics of one probe of a node
+func (mc *MemoryCache) GetProbe(nodeID, probeID string) []*MetricPoint {
+	actual, ok := mc.nodes.Load(bufferKey{nodeID: nodeID, probeID: probeID})
 	if !ok {
 		return nil
 	}
@@ -259,9 +280,14 @@ func (mc *MemoryCache) Get(nodeID string) []*MetricPoint {
 // GetAllNodeIDs returns all node IDs currently in the cache
 func (mc *MemoryCache) GetAllNodeIDs() []string {
 	var nodeIDs []string
+	seen := make(map[string]bool)
 
 	mc.nodes.Range(func(key, value interface{}) bool {
-		nodeIDs = append(nodeIDs, key.(string))
+		nodeID := key.(bufferKey).nodeID
+		if !seen[nodeID] {
+			seen[nodeID] = true
+			nodeIDs = append(nodeIDs, nodeID)
+		}
 		return true
 	})
 
@@ -270,21 +296,11 @@ func (mc *MemoryCache) GetAllNodeIDs() []string {
 
 // GetSize returns the number of nodes in the cache
 func (mc *MemoryCache) GetSize() int {
-	size := 0
-	mc.nodes.Range(func(key, value interface{}) bool {
-		size++
-		return true
-	})
-	return size
+	return len(mc.GetAllNodeIDs())
 }
 
-// AggregateMetricsByNode returns aggregated metrics for a specific node
+// AggregateMetricsByNode returns aggregated metrics for a specific node,
+// across all its probes
 func (mc *MemoryCache) AggregateMetricsByNode(nodeID string) []*AggregatedMetrics {
-	actual, ok := mc.nodes.Load(nodeID)
-	if !ok {
-		return nil
-	}
-
-	buffer := actual.(*RingBuffer)
-	return buffer.AggregateMetrics()
+	return aggregatePoints(mc.Get(nodeID))
 }
@@ -429,6 +429,37 @@ func TestMemoryCache_OneHourDataEviction(t *testing.T) {
 	}
 }
 
+// TestMemoryCache_PerProbeBuffers tests that each probe of a node keeps its
+// own hour of data
+func TestMemoryCache_PerProbeBuffers(t *testing.T) {
+	mc := NewMemoryCache()
+	defer mc.Stop()
+
+	nodeID := "test-node-123"
+	baseTime := time.Now()
+
+	// One point for probe-a, then a full hour for probe-b
+	mc.Store(nodeID, &MetricPoint{Timestamp: baseTime, ProbeID: "probe-a", LatencyMs: 1.0})
+	for i := 1; i <= 60; i++ {
+		mc.Store(nodeID, &MetricPoint{Timestamp: baseTime.Add(time.Duration(i) * time.Minute), ProbeID: "probe-b", LatencyMs: 2.0})
+	}
+
+	if points := mc.GetProbe(nodeID, "probe-a"); len(points) != 1 {
+		t.Errorf("Expected probe-a to keep its point, got %d points", len(points))
+	}
+	if points := mc.GetProbe(nodeID, "probe-b"); len(points) != 60 {
+		t.Errorf("Expected 60 points for probe-b, got %d", len(points))
+	}
+
+	points := mc.Get(nodeID)
+	if len(points) != 61 || points[0].ProbeID != "probe-a" {
+		t.Errorf("Expected 61 node points ordered by time, got %d", len(points))
+	}
+	if mc.GetSize() != 1 {
+		t.Errorf("Expected cache size 1, got %d", mc.GetSize())
+	}
+}
+
 // TestMemoryCache_BackgroundAggregation tests that background aggregation goroutine starts and stops cleanly
 func TestMemoryCache_BackgroundAggregation(t *testing.T) {
 	mc := NewMemoryCache()
@@ -72,8 +72,8 @@ func (c *CleanupTask) Execute(ctx context.Context) error {
 	defer func() { c.isRunning = false }()
 
 	if c.logger != nil {
-		c.logger.Printf("[Cleanup] Starting metrics data cleanup (retention_days: %d, timestamp: %s)",
-			c.cfg.RetentionDays, start.Format(time.RFC3339))
+		c.logger.Printf("[Cleanup] Starting metrics data cleanup (retention_days: %d, sample_retention_days: %d, timestamp: %s)",
+			c.cfg.RetentionDays, c.cfg.SampleRetention(), start.Format(time.RFC3339))
 	}
 
 	// Execute cleanup SQL with parameterized query to prevent SQL injection
@@ -90,6 +90,43 @@ func (c *CleanupTask) Execute(ctx context.Context) error {
 	// Get deleted row count
 	rowsAffected := result.RowsAffected()
 
+	// Raw probe samples have their own, usually shorter, retention
+	sampleSQL := "DELETE FROM probe_samples WHERE timestamp < NOW() - INTERVAL $1 * INTERVAL '1 day'"
+	sampleResult, err := c.db.Exec(ctx, sampleSQL, c.cfg.SampleRetention())
+	if err != nil {
+		c.lastError = err
+		if c.logger != nil {
+			c.logger.Printf("[Cleanup] ERROR: Failed to execute sample cleanup SQL: %v", err)
+		}
+		return fmt.Errorf("sample cleanup failed: %w", err)
+	}
+	samplesDeleted := sampleResult.RowsAffected()
+
+	// Certificates not reported within the metrics retention belong to
+	// removed tls_probes and would otherwise be listed as expiring forever
+	certificateSQL := "DELETE FROM certificate_status WHERE last_seen < NOW() - INTERVAL $1 * INTERVAL '1 day'"
+	certificateResult, err := c.db.Exec(ctx, certificateSQL, c.cfg.RetentionDays)
+	if err != nil {
+		c.lastError = err
+		if c.logger != nil {
+			c.logger.Printf("[Cleanup] ERROR: Failed to execute certificate cleanup SQL: %v", err)
+		}
+		return fmt.Errorf("certificate cleanup failed: %w", err)
+	}
+	certificatesDeleted := certificateResult.RowsAffected()
+
+	// Path traces are kept as long as metrics
+	pathSQL := "DELETE FROM path_traces WHERE timestamp < NOW() - INTERVAL $1 * INTERVAL '1 day'"
+	pathResult, err := c.db.Exec(ctx, pathSQL, c.cfg.RetentionDays)
+	if err != nil {
+		c.lastError = err
+		if c.logger != nil {
+			c.logger.Printf("[Cleanup] ERROR: Failed to execute path trace cleanup SQL: %v", err)
+		}
+		return fmt.Errorf("path trace cleanup failed: %w", err)
+	}
+	pathsDeleted := pathResult.RowsAffected()
+
 	duration := time.Since(start)
 
 	c.lastRun = start
@@ -98,8 +135,8 @@ func (c *CleanupTask) Execute(ctx context.Context) error {
 	c.runCount++
 
 	if c.logger != nil {
-		c.logger.Printf("[Cleanup] Metrics data cleanup completed (rows_deleted: %d, duration_ms: %d)",
-			rowsAffected, duration.Milliseconds())
+		c.logger.Printf("[Cleanup] Metrics data cleanup completed (rows_deleted: %d, samples_deleted: %d, certificates_deleted: %d, paths_deleted: %d, duration_ms: %d)",
+			rowsAffected, samplesDeleted, certificatesDeleted, pathsDeleted, duration.Milliseconds())
 	}
 
 	// Check for slow query
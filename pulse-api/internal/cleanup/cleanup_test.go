@@ -89,6 +89,15 @@ func TestCleanupTask_Execute_Success(t *testing.T) {
 	mock.ExpectExec("DELETE FROM metrics WHERE timestamp < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
 		WithArgs(7). // RetentionDays argument
 		WillReturnResult(pgxmock.NewResult("DELETE", 1234)) // Deleted 1234 rows
+	mock.ExpectExec("DELETE FROM probe_samples WHERE timestamp < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
+		WithArgs(7). // SampleRetentionDays unset, falls back to RetentionDays
+		WillReturnResult(pgxmock.NewResult("DELETE", 1234))
+	mock.ExpectExec("DELETE FROM certificate_status WHERE last_seen < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
+		WithArgs(7). // RetentionDays argument
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectExec("DELETE FROM path_traces WHERE timestamp < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
+		WithArgs(7). // RetentionDays argument
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
 
 	cfg := &config.CleanupConfig{
 		Enabled:         true,
@@ -137,6 +146,109 @@ func TestCleanupTask_Execute_DatabaseError(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestCleanupTask_Execute_SampleCleanupError(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectExec("DELETE FROM metrics WHERE timestamp < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
+		WithArgs(7). // RetentionDays argument
+		WillReturnResult(pgxmock.NewResult("DELETE", 10))
+	mock.ExpectExec("DELETE FROM probe_samples WHERE timestamp < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
+		WithArgs(2). // SampleRetentionDays argument
+		WillReturnError(&pgconn.PgError{
+			Code:    "42P01",
+			Message: "relation \"probe_samples\" does not exist",
+		})
+
+	cfg := &config.CleanupConfig{
+		Enabled:             true,
+		IntervalSeconds:     3600,
+		RetentionDays:       7,
+		SampleRetentionDays: 2,
+	}
+
+	task, err := NewCleanupTask(cfg, mock, nil)
+	require.NoError(t, err)
+
+	err = task.Execute(context.Background())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "sample cleanup failed")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCleanupTask_Execute_CertificateCleanupError(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectExec("DELETE FROM metrics WHERE timestamp < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
+		WithArgs(7). // RetentionDays argument
+		WillReturnResult(pgxmock.NewResult("DELETE", 10))
+	mock.ExpectExec("DELETE FROM probe_samples WHERE timestamp < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
+		WithArgs(7). // SampleRetentionDays unset, falls back to RetentionDays
+		WillReturnResult(pgxmock.NewResult("DELETE", 10))
+	mock.ExpectExec("DELETE FROM certificate_status WHERE last_seen < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
+		WithArgs(7). // RetentionDays argument
+		WillReturnError(&pgconn.PgError{
+			Code:    "42P01",
+			Message: "relation \"certificate_status\" does not exist",
+		})
+
+	cfg := &config.CleanupConfig{
+		Enabled:         true,
+		IntervalSeconds: 3600,
+		RetentionDays:   7,
+	}
+
+	task, err := NewCleanupTask(cfg, mock, nil)
+	require.NoError(t, err)
+
+	err = task.Execute(context.Background())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate cleanup failed")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCleanupTask_Execute_PathTraceCleanupError(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectExec("DELETE FROM metrics WHERE timestamp < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
+		WithArgs(7). // RetentionDays argument
+		WillReturnResult(pgxmock.NewResult("DELETE", 10))
+	mock.ExpectExec("DELETE FROM probe_samples WHERE timestamp < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
+		WithArgs(7). // SampleRetentionDays unset, falls back to RetentionDays
+		WillReturnResult(pgxmock.NewResult("DELETE", 10))
+	mock.ExpectExec("DELETE FROM certificate_status WHERE last_seen < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
+		WithArgs(7). // RetentionDays argument
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectExec("DELETE FROM path_traces WHERE timestamp < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
+		WithArgs(7). // RetentionDays argument
+		WillReturnError(&pgconn.PgError{
+			Code:    "42P01",
+			Message: "relation \"path_traces\" does not exist",
+		})
+
+	cfg := &config.CleanupConfig{
+		Enabled:         true,
+		IntervalSeconds: 3600,
+		RetentionDays:   7,
+	}
+
+	task, err := NewCleanupTask(cfg, mock, nil)
+	require.NoError(t, err)
+
+	err = task.Execute(context.Background())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "path trace cleanup failed")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestCleanupTask_Execute_SlowQuery(t *testing.T) {
 	mock, err := pgxmock.NewPool(pgxmock.MonitorPingsOption(true))
 	require.NoError(t, err)
@@ -146,6 +258,15 @@ func TestCleanupTask_Execute_SlowQuery(t *testing.T) {
 	mock.ExpectExec("DELETE FROM metrics WHERE timestamp < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
 		WithArgs(7). // RetentionDays argument
 		WillReturnResult(pgxmock.NewResult("DELETE", 100))
+	mock.ExpectExec("DELETE FROM probe_samples WHERE timestamp < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
+		WithArgs(7). // SampleRetentionDays unset, falls back to RetentionDays
+		WillReturnResult(pgxmock.NewResult("DELETE", 100))
+	mock.ExpectExec("DELETE FROM certificate_status WHERE last_seen < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
+		WithArgs(7). // RetentionDays argument
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectExec("DELETE FROM path_traces WHERE timestamp < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
+		WithArgs(7). // RetentionDays argument
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
 
 	cfg := &config.CleanupConfig{
 		Enabled:         true,
@@ -173,6 +294,15 @@ func TestCleanupTask_Execute_ZeroRows(t *testing.T) {
 	mock.ExpectExec("DELETE FROM metrics WHERE timestamp < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
 		WithArgs(7). // RetentionDays argument
 		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectExec("DELETE FROM probe_samples WHERE timestamp < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
+		WithArgs(7). // SampleRetentionDays unset, falls back to RetentionDays
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectExec("DELETE FROM certificate_status WHERE last_seen < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
+		WithArgs(7). // RetentionDays argument
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectExec("DELETE FROM path_traces WHERE timestamp < NOW\\(\\) - INTERVAL \\$1 \\* INTERVAL '1 day'").
+		WithArgs(7). // RetentionDays argument
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
 
 	cfg := &config.CleanupConfig{
 		Enabled:         true,
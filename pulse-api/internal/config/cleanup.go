@@ -12,6 +12,10 @@ type CleanupConfig struct {
 	IntervalSeconds int   `yaml:"interval_seconds" env:"CLEANUP_INTERVAL" default:"3600"`
 	RetentionDays   int   `yaml:"retention_days" env:"CLEANUP_RETENTION_DAYS" default:"7"`
 	SlowThresholdMs int64 `yaml:"slow_threshold_ms" env:"CLEANUP_SLOW_THRESHOLD" default:"30000"`
+
+	// Retention of raw probe samples, usually much shorter than metrics
+	// (0 means the same as RetentionDays)
+	SampleRetentionDays int `yaml:"sample_retention_days" env:"CLEANUP_SAMPLE_RETENTION_DAYS" default:"3"`
 }
 
 // LoadCleanupConfig loads cleanup configuration from environment variables
@@ -21,6 +25,8 @@ func LoadCleanupConfig() (*CleanupConfig, error) {
 		IntervalSeconds: getEnvInt("CLEANUP_INTERVAL", 3600),
 		RetentionDays:   getEnvInt("CLEANUP_RETENTION_DAYS", 7),
 		SlowThresholdMs: int64(getEnvInt("CLEANUP_SLOW_THRESHOLD", 30000)),
+
+		SampleRetentionDays: getEnvInt("CLEANUP_SAMPLE_RETENTION_DAYS", 3),
 	}
 
 	// Validate configuration
@@ -45,9 +51,21 @@ func (c *CleanupConfig) Validate() error {
 		return fmt.Errorf("slow_threshold_ms cannot be negative, got %d", c.SlowThresholdMs)
 	}
 
+	if c.SampleRetentionDays < 0 {
+		return fmt.Errorf("sample_retention_days cannot be negative, got %d", c.SampleRetentionDays)
+	}
+
 	return nil
 }
 
+// SampleRetention returns the number of days raw probe samples are kept
+func (c *CleanupConfig) SampleRetention() int {
+	if c.SampleRetentionDays == 0 {
+		return c.RetentionDays
+	}
+	return c.SampleRetentionDays
+}
+
 // getEnvBool gets a boolean environment variable with a default value
 func getEnvBool(key string, defaultValue bool) bool {
 	val := os.Getenv(key)
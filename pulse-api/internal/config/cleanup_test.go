@@ -19,6 +19,7 @@ func TestLoadCleanupConfig_Defaults(t *testing.T) {
 	assert.Equal(t, 3600, cfg.IntervalSeconds)
 	assert.Equal(t, 7, cfg.RetentionDays)
 	assert.Equal(t, int64(30000), cfg.SlowThresholdMs)
+	assert.Equal(t, 3, cfg.SampleRetentionDays)
 }
 
 func TestLoadCleanupConfig_CustomValues(t *testing.T) {
@@ -29,6 +30,7 @@ func TestLoadCleanupConfig_CustomValues(t *testing.T) {
 	os.Setenv("CLEANUP_INTERVAL", "7200")
 	os.Setenv("CLEANUP_RETENTION_DAYS", "14")
 	os.Setenv("CLEANUP_SLOW_THRESHOLD", "60000")
+	os.Setenv("CLEANUP_SAMPLE_RETENTION_DAYS", "1")
 
 	defer clearCleanupEnv()
 
@@ -39,6 +41,7 @@ func TestLoadCleanupConfig_CustomValues(t *testing.T) {
 	assert.Equal(t, 7200, cfg.IntervalSeconds)
 	assert.Equal(t, 14, cfg.RetentionDays)
 	assert.Equal(t, int64(60000), cfg.SlowThresholdMs)
+	assert.Equal(t, 1, cfg.SampleRetentionDays)
 }
 
 func TestLoadCleanupConfig_InvalidInterval(t *testing.T) {
@@ -74,6 +77,26 @@ func TestLoadCleanupConfig_InvalidSlowThreshold(t *testing.T) {
 	assert.Contains(t, err.Error(), "slow_threshold_ms cannot be negative")
 }
 
+func TestLoadCleanupConfig_InvalidSampleRetention(t *testing.T) {
+	clearCleanupEnv()
+	os.Setenv("CLEANUP_SAMPLE_RETENTION_DAYS", "-1")
+	defer clearCleanupEnv()
+
+	cfg, err := LoadCleanupConfig()
+	assert.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "sample_retention_days cannot be negative")
+}
+
+func TestCleanupConfig_SampleRetention(t *testing.T) {
+	cfg := &CleanupConfig{RetentionDays: 7, SampleRetentionDays: 2}
+	assert.Equal(t, 2, cfg.SampleRetention())
+
+	// Unset falls back to the metrics retention
+	cfg.SampleRetentionDays = 0
+	assert.Equal(t, 7, cfg.SampleRetention())
+}
+
 func TestLoadCleanupConfig_BoolParsing(t *testing.T) {
 	clearCleanupEnv()
 
@@ -121,4 +144,5 @@ func clearCleanupEnv() {
 	os.Unsetenv("CLEANUP_INTERVAL")
 	os.Unsetenv("CLEANUP_RETENTION_DAYS")
 	os.Unsetenv("CLEANUP_SLOW_THRESHOLD")
+	os.Unsetenv("CLEANUP_SAMPLE_RETENTION_DAYS")
 }
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+// MinEnrollmentTokenLength is the minimum length of the beacon enrollment token
+const MinEnrollmentTokenLength = 16
+
+// EnrollmentConfig defines beacon self-registration. Outside beacon mTLS
+// mode, /beacon/register requires the shared enrollment token; when no token
+// is configured, self-registration is disabled and nodes are created by an
+// admin (POST /nodes), which returns the node's API token.
+type EnrollmentConfig struct {
+	Token string `env:"PULSE_BEACON_ENROLLMENT_TOKEN"`
+}
+
+// LoadEnrollmentConfig loads enrollment configuration from environment variables
+func LoadEnrollmentConfig() (*EnrollmentConfig, error) {
+	cfg := &EnrollmentConfig{
+		Token: os.Getenv("PULSE_BEACON_ENROLLMENT_TOKEN"),
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid enrollment config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// Enabled reports whether beacons may self-register with the enrollment token
+func (c *EnrollmentConfig) Enabled() bool {
+	return c != nil && c.Token != ""
+}
+
+// Validate validates the enrollment configuration
+func (c *EnrollmentConfig) Validate() error {
+	if c.Token != "" && len(c.Token) < MinEnrollmentTokenLength {
+		return fmt.Errorf("enrollment token must be at least %d characters, got %d", MinEnrollmentTokenLength, len(c.Token))
+	}
+
+	return nil
+}
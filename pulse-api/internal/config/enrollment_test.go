@@ -0,0 +1,40 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadEnrollmentConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		enabled bool
+		err     string
+	}{
+		{"disabled", "", false, ""},
+		{"enabled", "enroll-0123456789abcdef", true, ""},
+		{"token too short", "short", false, "at least 16 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv("PULSE_BEACON_ENROLLMENT_TOKEN", tt.token)
+			defer os.Unsetenv("PULSE_BEACON_ENROLLMENT_TOKEN")
+
+			cfg, err := LoadEnrollmentConfig()
+			if tt.err != "" {
+				assert.Nil(t, cfg)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.enabled, cfg.Enabled())
+		})
+	}
+}
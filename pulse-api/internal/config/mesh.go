@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Mesh grouping modes for MeshConfig.GroupBy
+const (
+	MeshGroupByRegion    = "region"
+	MeshGroupByTagPrefix = "tag:"
+)
+
+// MeshConfig defines full-mesh beacon-to-beacon probing. When enabled, every
+// beacon is assigned a probe against each other beacon in its group, sent
+// along with its probe configuration.
+type MeshConfig struct {
+	Enabled         bool   `env:"PULSE_MESH_ENABLED" default:"false"`
+	GroupBy         string `env:"PULSE_MESH_GROUP_BY" default:"region"` // "region" or "tag:<key>"
+	Protocol        string `env:"PULSE_MESH_PROTOCOL" default:"udp"`    // udp (beacon responder echo) or tcp
+	Port            int    `env:"PULSE_MESH_PORT" default:"7331"`       // Peer port, the beacon responder port for udp
+	IntervalSeconds int    `env:"PULSE_MESH_INTERVAL" default:"60"`
+	Count           int    `env:"PULSE_MESH_COUNT" default:"10"`
+	TimeoutSeconds  int    `env:"PULSE_MESH_TIMEOUT" default:"2"`
+}
+
+// LoadMeshConfig loads mesh configuration from environment variables
+func LoadMeshConfig() (*MeshConfig, error) {
+	groupBy := os.Getenv("PULSE_MESH_GROUP_BY")
+	if groupBy == "" {
+		groupBy = MeshGroupByRegion
+	}
+	protocol := os.Getenv("PULSE_MESH_PROTOCOL")
+	if protocol == "" {
+		protocol = "udp"
+	}
+
+	cfg := &MeshConfig{
+		Enabled:         getEnvBool("PULSE_MESH_ENABLED", false),
+		GroupBy:         groupBy,
+		Protocol:        strings.ToLower(protocol),
+		Port:            getEnvInt("PULSE_MESH_PORT", 7331),
+		IntervalSeconds: getEnvInt("PULSE_MESH_INTERVAL", 60),
+		Count:           getEnvInt("PULSE_MESH_COUNT", 10),
+		TimeoutSeconds:  getEnvInt("PULSE_MESH_TIMEOUT", 2),
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid mesh config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// Validate validates the mesh configuration. The probe parameters follow
+// the limits beacons apply to their own probes.
+func (c *MeshConfig) Validate() error {
+	if c.GroupBy != MeshGroupByRegion && (!strings.HasPrefix(c.GroupBy, MeshGroupByTagPrefix) || c.GroupBy == MeshGroupByTagPrefix) {
+		return fmt.Errorf("group_by must be %q or %q<key>, got %q", MeshGroupByRegion, MeshGroupByTagPrefix, c.GroupBy)
+	}
+
+	if c.Protocol != "udp" && c.Protocol != "tcp" {
+		return fmt.Errorf("protocol must be udp or tcp, got %q", c.Protocol)
+	}
+
+	if c.Port < 1 || c.Port > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535, got %d", c.Port)
+	}
+
+	if c.IntervalSeconds < 60 || c.IntervalSeconds > 300 {
+		return fmt.Errorf("interval_seconds must be between 60 and 300, got %d", c.IntervalSeconds)
+	}
+
+	if c.Count < 10 || c.Count > 100 {
+		return fmt.Errorf("count must be between 10 and 100, got %d", c.Count)
+	}
+
+	if c.TimeoutSeconds < 1 || c.TimeoutSeconds > 30 {
+		return fmt.Errorf("timeout_seconds must be between 1 and 30, got %d", c.TimeoutSeconds)
+	}
+
+	// Beacons reject probes whose samples may not finish within the interval
+	if c.IntervalSeconds < c.TimeoutSeconds*c.Count {
+		return fmt.Errorf("interval_seconds %d is shorter than count %d x timeout_seconds %d", c.IntervalSeconds, c.Count, c.TimeoutSeconds)
+	}
+
+	return nil
+}
+
+// GroupTag returns the tag key nodes are grouped by, or "" when grouping by region
+func (c *MeshConfig) GroupTag() string {
+	if !strings.HasPrefix(c.GroupBy, MeshGroupByTagPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(c.GroupBy, MeshGroupByTagPrefix)
+}
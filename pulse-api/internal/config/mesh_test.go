@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clearMeshEnv() {
+	for _, key := range []string{"PULSE_MESH_ENABLED", "PULSE_MESH_GROUP_BY", "PULSE_MESH_PROTOCOL", "PULSE_MESH_PORT", "PULSE_MESH_INTERVAL", "PULSE_MESH_COUNT", "PULSE_MESH_TIMEOUT"} {
+		os.Unsetenv(key)
+	}
+}
+
+func TestLoadMeshConfig_Defaults(t *testing.T) {
+	clearMeshEnv()
+
+	cfg, err := LoadMeshConfig()
+	require.NoError(t, err)
+
+	assert.False(t, cfg.Enabled)
+	assert.Equal(t, MeshGroupByRegion, cfg.GroupBy)
+	assert.Equal(t, "", cfg.GroupTag())
+	assert.Equal(t, "udp", cfg.Protocol)
+	assert.Equal(t, 7331, cfg.Port)
+	assert.Equal(t, 60, cfg.IntervalSeconds)
+	assert.Equal(t, 10, cfg.Count)
+	assert.Equal(t, 2, cfg.TimeoutSeconds)
+}
+
+func TestLoadMeshConfig_CustomValues(t *testing.T) {
+	clearMeshEnv()
+	os.Setenv("PULSE_MESH_ENABLED", "true")
+	os.Setenv("PULSE_MESH_GROUP_BY", "tag:datacenter")
+	os.Setenv("PULSE_MESH_PROTOCOL", "TCP")
+	os.Setenv("PULSE_MESH_PORT", "22")
+	defer clearMeshEnv()
+
+	cfg, err := LoadMeshConfig()
+	require.NoError(t, err)
+
+	assert.True(t, cfg.Enabled)
+	assert.Equal(t, "datacenter", cfg.GroupTag())
+	assert.Equal(t, "tcp", cfg.Protocol)
+	assert.Equal(t, 22, cfg.Port)
+}
+
+func TestLoadMeshConfig_Invalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr string
+	}{
+		{"unknown grouping", "PULSE_MESH_GROUP_BY", "zone", "group_by must be"},
+		{"empty tag key", "PULSE_MESH_GROUP_BY", "tag:", "group_by must be"},
+		{"unknown protocol", "PULSE_MESH_PROTOCOL", "icmp", "protocol must be udp or tcp"},
+		{"port out of range", "PULSE_MESH_PORT", "70000", "port must be between"},
+		{"too few samples", "PULSE_MESH_COUNT", "5", "count must be between"},
+		{"samples exceed interval", "PULSE_MESH_TIMEOUT", "10", "shorter than count"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clearMeshEnv()
+			os.Setenv(tc.key, tc.value)
+			defer clearMeshEnv()
+
+			cfg, err := LoadMeshConfig()
+			assert.Error(t, err)
+			assert.Nil(t, cfg)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
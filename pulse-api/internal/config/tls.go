@@ -0,0 +1,83 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TLSConfig defines the HTTPS server configuration
+type TLSConfig struct {
+	CertFile     string `env:"PULSE_TLS_CERT_FILE"`
+	KeyFile      string `env:"PULSE_TLS_KEY_FILE"`
+	ClientCAFile string `env:"PULSE_TLS_CLIENT_CA_FILE"`                   // CA bundle used to verify beacon client certificates
+	BeaconMTLS   bool   `env:"PULSE_BEACON_MTLS_REQUIRED" default:"false"` // Require client certificates on /beacon routes
+}
+
+// LoadTLSConfig loads TLS configuration from environment variables
+func LoadTLSConfig() (*TLSConfig, error) {
+	cfg := &TLSConfig{
+		CertFile:     os.Getenv("PULSE_TLS_CERT_FILE"),
+		KeyFile:      os.Getenv("PULSE_TLS_KEY_FILE"),
+		ClientCAFile: os.Getenv("PULSE_TLS_CLIENT_CA_FILE"),
+		BeaconMTLS:   getEnvBool("PULSE_BEACON_MTLS_REQUIRED", false),
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid TLS config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// Enabled reports whether the server should serve HTTPS
+func (c *TLSConfig) Enabled() bool {
+	return c != nil && c.CertFile != ""
+}
+
+// Validate validates the TLS configuration
+func (c *TLSConfig) Validate() error {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return errors.New("cert_file and key_file must be set together")
+	}
+
+	if c.ClientCAFile != "" && c.CertFile == "" {
+		return errors.New("client_ca_file requires cert_file and key_file")
+	}
+
+	if c.BeaconMTLS && c.ClientCAFile == "" {
+		return errors.New("beacon mTLS requires client_ca_file")
+	}
+
+	return nil
+}
+
+// ServerTLSConfig builds the tls.Config for the HTTPS server.
+// Client certificates are verified when presented but not required at the
+// handshake, so the web UI keeps working; /beacon routes enforce them.
+func (c *TLSConfig) ServerTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if c.ClientCAFile == "" {
+		return tlsConfig, nil
+	}
+
+	caPEM, err := os.ReadFile(c.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file: %w", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in client CA file %s", c.ClientCAFile)
+	}
+
+	tlsConfig.ClientCAs = clientCAs
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+
+	return tlsConfig, nil
+}
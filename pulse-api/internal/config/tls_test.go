@@ -0,0 +1,94 @@
+package config
+
+import (
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kevin/node-pulse/pulse-api/internal/testutil"
+)
+
+func TestLoadTLSConfig_Disabled(t *testing.T) {
+	clearTLSEnv()
+
+	cfg, err := LoadTLSConfig()
+	require.NoError(t, err)
+
+	assert.False(t, cfg.Enabled())
+	assert.False(t, cfg.BeaconMTLS)
+}
+
+func TestLoadTLSConfig_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		err  string
+	}{
+		{"cert without key", map[string]string{"PULSE_TLS_CERT_FILE": "server.pem"}, "must be set together"},
+		{"client CA without cert", map[string]string{"PULSE_TLS_CLIENT_CA_FILE": "ca.pem"}, "requires cert_file"},
+		{"mTLS without client CA", map[string]string{
+			"PULSE_TLS_CERT_FILE":        "server.pem",
+			"PULSE_TLS_KEY_FILE":         "server.key",
+			"PULSE_BEACON_MTLS_REQUIRED": "true",
+		}, "requires client_ca_file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTLSEnv()
+			defer clearTLSEnv()
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			cfg, err := LoadTLSConfig()
+			assert.Nil(t, cfg)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestServerTLSConfig_ClientCA(t *testing.T) {
+	// Arrange
+	ca := testutil.NewTestCA(t)
+	cfg := &TLSConfig{
+		CertFile:     "server.pem",
+		KeyFile:      "server.key",
+		ClientCAFile: ca.WritePEM(t, t.TempDir()),
+		BeaconMTLS:   true,
+	}
+
+	// Act
+	tlsConfig, err := cfg.ServerTLSConfig()
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, tlsConfig.ClientAuth)
+	assert.NotNil(t, tlsConfig.ClientCAs)
+	assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+}
+
+func TestServerTLSConfig_InvalidClientCA(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(path, []byte("not a certificate"), 0600))
+	cfg := &TLSConfig{CertFile: "server.pem", KeyFile: "server.key", ClientCAFile: path}
+
+	// Act
+	_, err := cfg.ServerTLSConfig()
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func clearTLSEnv() {
+	os.Unsetenv("PULSE_TLS_CERT_FILE")
+	os.Unsetenv("PULSE_TLS_KEY_FILE")
+	os.Unsetenv("PULSE_TLS_CLIENT_CA_FILE")
+	os.Unsetenv("PULSE_BEACON_MTLS_REQUIRED")
+}
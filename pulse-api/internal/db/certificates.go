@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kevin/node-pulse/pulse-api/internal/models"
+)
+
+// CertificatesQuerier defines interface for certificate status database operations
+type CertificatesQuerier interface {
+	GetExpiringCertificates(ctx context.Context, before time.Time) ([]*models.CertificateStatus, error)
+}
+
+// GetExpiringCertificates retrieves the latest certificate of every tls_probe
+// that expires before the given time (including expired ones), ordered by
+// expiry. DaysRemaining is left for the caller to compute.
+func GetExpiringCertificates(ctx context.Context, pool *pgxpool.Pool, before time.Time) ([]*models.CertificateStatus, error) {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	query := `
+		SELECT node_id::text, probe_id, target, not_after, chain_valid,
+			COALESCE(tls_version, ''), COALESCE(tls_cipher, ''), last_seen
+		FROM certificate_status
+		WHERE not_after <= $1
+		ORDER BY not_after, node_id, probe_id
+	`
+
+	rows, err := conn.Query(ctx, query, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	certificates := make([]*models.CertificateStatus, 0)
+	for rows.Next() {
+		var certificate models.CertificateStatus
+		err := rows.Scan(&certificate.NodeID, &certificate.ProbeID, &certificate.Target, &certificate.NotAfter,
+			&certificate.ChainValid, &certificate.TLSVersion, &certificate.TLSCipher, &certificate.LastSeen)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, &certificate)
+	}
+
+	return certificates, rows.Err()
+}
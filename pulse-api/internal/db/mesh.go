@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kevin/node-pulse/pulse-api/internal/models"
+)
+
+// MeshQuerier defines interface for mesh matrix database operations
+type MeshQuerier interface {
+	GetMeshLinks(ctx context.Context) ([]*models.MeshLink, error)
+}
+
+// GetMeshLinks retrieves the latest mesh probe result of every node pair
+func GetMeshLinks(ctx context.Context, pool *pgxpool.Pool) ([]*models.MeshLink, error) {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	query := `
+		SELECT source_node_id::text, target_node_id::text, probe_type, success,
+			latency_ms, packet_loss_rate, jitter_ms, sample_count, updated_at
+		FROM mesh_matrix
+		ORDER BY source_node_id, target_node_id
+	`
+
+	rows, err := conn.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := make([]*models.MeshLink, 0)
+	for rows.Next() {
+		var link models.MeshLink
+		err := rows.Scan(&link.SourceNodeID, &link.TargetNodeID, &link.ProbeType, &link.Success,
+			&link.LatencyMs, &link.PacketLossRate, &link.JitterMs, &link.SampleCount, &link.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, &link)
+	}
+
+	return links, rows.Err()
+}
@@ -40,6 +40,42 @@ func Migrate(ctx context.Context, pool *pgxpool.Pool) error {
 		return err
 	}
 
+	if err := addMetricsProbeFields(ctx, pool); err != nil {
+		return err
+	}
+
+	if err := createCertificateStatusTable(ctx, pool); err != nil {
+		return err
+	}
+
+	if err := addMetricsUDPEchoFields(ctx, pool); err != nil {
+		return err
+	}
+
+	if err := addMetricsStatisticsFields(ctx, pool); err != nil {
+		return err
+	}
+
+	if err := createPathTracesTable(ctx, pool); err != nil {
+		return err
+	}
+
+	if err := createProbeSamplesTable(ctx, pool); err != nil {
+		return err
+	}
+
+	if err := addHeartbeatUniqueKeys(ctx, pool); err != nil {
+		return err
+	}
+
+	if err := createMeshMatrixTable(ctx, pool); err != nil {
+		return err
+	}
+
+	if err := addNodeTokenFields(ctx, pool); err != nil {
+		return err
+	}
+
 	if err := seedAdminUser(ctx, pool); err != nil {
 		return err
 	}
@@ -280,3 +316,352 @@ func createProbesTrigger(ctx context.Context, pool *pgxpool.Pool) error {
 	_, err := pool.Exec(ctx, query)
 	return err
 }
+
+// addMetricsProbeFields adds per-probe statistics columns to metrics table
+func addMetricsProbeFields(ctx context.Context, pool *pgxpool.Pool) error {
+	query := `
+		DO $$
+		BEGIN
+			-- Add latency_median_ms column
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='metrics' AND column_name='latency_median_ms'
+			) THEN
+				ALTER TABLE metrics ADD COLUMN latency_median_ms DECIMAL(10,2);
+			END IF;
+
+			-- Add variance_ms column
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='metrics' AND column_name='variance_ms'
+			) THEN
+				ALTER TABLE metrics ADD COLUMN variance_ms DECIMAL(12,2);
+			END IF;
+
+			-- Add sample_count column
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='metrics' AND column_name='sample_count'
+			) THEN
+				ALTER TABLE metrics ADD COLUMN sample_count INTEGER;
+			END IF;
+
+			-- Add success column
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='metrics' AND column_name='success'
+			) THEN
+				ALTER TABLE metrics ADD COLUMN success BOOLEAN;
+			END IF;
+
+			-- Add partial column (batch aborted before all attempts completed)
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='metrics' AND column_name='partial'
+			) THEN
+				ALTER TABLE metrics ADD COLUMN partial BOOLEAN NOT NULL DEFAULT FALSE;
+			END IF;
+		END $$;
+	`
+
+	_, err := pool.Exec(ctx, query)
+	return err
+}
+
+// createCertificateStatusTable creates certificate_status table holding the
+// latest certificate seen by every tls_probe. probe_id is not a foreign key
+// because tls_probe is configured in the beacon only.
+func createCertificateStatusTable(ctx context.Context, pool *pgxpool.Pool) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS certificate_status (
+			node_id UUID NOT NULL REFERENCES nodes(id) ON DELETE CASCADE,
+			probe_id VARCHAR(255) NOT NULL,
+			target VARCHAR(255) NOT NULL,
+			not_after TIMESTAMPTZ NOT NULL,
+			chain_valid BOOLEAN,
+			tls_version VARCHAR(32),
+			tls_cipher VARCHAR(128),
+			last_seen TIMESTAMPTZ NOT NULL,
+			PRIMARY KEY (node_id, probe_id)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_certificate_status_not_after ON certificate_status(not_after);
+	`
+
+	_, err := pool.Exec(ctx, query)
+	return err
+}
+
+// addMetricsUDPEchoFields adds udp_ping responder statistics columns to
+// metrics table
+func addMetricsUDPEchoFields(ctx context.Context, pool *pgxpool.Pool) error {
+	query := `
+		DO $$
+		BEGIN
+			-- Add reordered_packets column
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='metrics' AND column_name='reordered_packets'
+			) THEN
+				ALTER TABLE metrics ADD COLUMN reordered_packets INTEGER;
+			END IF;
+
+			-- Add duplicate_packets column
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='metrics' AND column_name='duplicate_packets'
+			) THEN
+				ALTER TABLE metrics ADD COLUMN duplicate_packets INTEGER;
+			END IF;
+
+			-- Add one_way_forward_ms column
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='metrics' AND column_name='one_way_forward_ms'
+			) THEN
+				ALTER TABLE metrics ADD COLUMN one_way_forward_ms DECIMAL(10,2);
+			END IF;
+
+			-- Add one_way_reverse_ms column
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='metrics' AND column_name='one_way_reverse_ms'
+			) THEN
+				ALTER TABLE metrics ADD COLUMN one_way_reverse_ms DECIMAL(10,2);
+			END IF;
+		END $$;
+	`
+
+	_, err := pool.Exec(ctx, query)
+	return err
+}
+
+// addMetricsStatisticsFields adds the optional RTT statistics columns
+// (min/max, standard deviation, percentiles, RFC 3550 jitter) to metrics table
+func addMetricsStatisticsFields(ctx context.Context, pool *pgxpool.Pool) error {
+	query := `
+		DO $$
+		BEGIN
+			-- Add latency_min_ms column
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='metrics' AND column_name='latency_min_ms'
+			) THEN
+				ALTER TABLE metrics ADD COLUMN latency_min_ms DECIMAL(10,2);
+			END IF;
+
+			-- Add latency_max_ms column
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='metrics' AND column_name='latency_max_ms'
+			) THEN
+				ALTER TABLE metrics ADD COLUMN latency_max_ms DECIMAL(10,2);
+			END IF;
+
+			-- Add latency_stddev_ms column
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='metrics' AND column_name='latency_stddev_ms'
+			) THEN
+				ALTER TABLE metrics ADD COLUMN latency_stddev_ms DECIMAL(10,2);
+			END IF;
+
+			-- Add latency_p90_ms column
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='metrics' AND column_name='latency_p90_ms'
+			) THEN
+				ALTER TABLE metrics ADD COLUMN latency_p90_ms DECIMAL(10,2);
+			END IF;
+
+			-- Add latency_p95_ms column
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='metrics' AND column_name='latency_p95_ms'
+			) THEN
+				ALTER TABLE metrics ADD COLUMN latency_p95_ms DECIMAL(10,2);
+			END IF;
+
+			-- Add latency_p99_ms column
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='metrics' AND column_name='latency_p99_ms'
+			) THEN
+				ALTER TABLE metrics ADD COLUMN latency_p99_ms DECIMAL(10,2);
+			END IF;
+
+			-- Add jitter_rfc3550_ms column
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='metrics' AND column_name='jitter_rfc3550_ms'
+			) THEN
+				ALTER TABLE metrics ADD COLUMN jitter_rfc3550_ms DECIMAL(10,2);
+			END IF;
+		END $$;
+	`
+
+	_, err := pool.Exec(ctx, query)
+	return err
+}
+
+// createPathTracesTable creates path_traces table for path_probe hop lists.
+// probe_id is not a foreign key because traces of probes configured only in
+// the beacon are stored too. CleanupTask applies the metrics retention.
+func createPathTracesTable(ctx context.Context, pool *pgxpool.Pool) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS path_traces (
+			id BIGSERIAL PRIMARY KEY,
+			node_id UUID NOT NULL REFERENCES nodes(id) ON DELETE CASCADE,
+			probe_id VARCHAR(255) NOT NULL,
+			target VARCHAR(255) NOT NULL,
+			timestamp TIMESTAMPTZ NOT NULL,
+			reached BOOLEAN NOT NULL,
+			hops JSONB NOT NULL,
+			path_hash CHAR(64) NOT NULL,
+			path_changed BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMPTZ DEFAULT NOW()
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_path_traces_node_probe_timestamp ON path_traces(node_id, probe_id, timestamp DESC);
+		CREATE INDEX IF NOT EXISTS idx_path_traces_changed ON path_traces(node_id, timestamp DESC) WHERE path_changed;
+		CREATE INDEX IF NOT EXISTS idx_path_traces_timestamp ON path_traces(timestamp);
+	`
+
+	_, err := pool.Exec(ctx, query)
+	return err
+}
+
+// createProbeSamplesTable creates probe_samples table for raw probe attempts.
+// It is kept compact (no surrogate key, REAL RTTs) because it grows with
+// count x probes x intervals; CleanupTask applies a separate retention.
+func createProbeSamplesTable(ctx context.Context, pool *pgxpool.Pool) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS probe_samples (
+			node_id UUID NOT NULL REFERENCES nodes(id) ON DELETE CASCADE,
+			probe_id UUID NOT NULL REFERENCES probes(id) ON DELETE CASCADE,
+			timestamp TIMESTAMPTZ NOT NULL,
+			success BOOLEAN NOT NULL,
+			rtt_ms REAL,
+			error_class VARCHAR(16)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_probe_samples_probe_timestamp ON probe_samples(probe_id, timestamp DESC);
+		CREATE INDEX IF NOT EXISTS idx_probe_samples_timestamp ON probe_samples(timestamp);
+	`
+
+	_, err := pool.Exec(ctx, query)
+	return err
+}
+
+// addHeartbeatUniqueKeys adds unique keys to the tables filled from beacon
+// heartbeats, so a heartbeat delivered twice (resent report, outbox replay,
+// batch-to-single fallback) is stored once. Existing duplicates are removed
+// before the keys are created. probe_samples gains seq, the position of the
+// sample in its batch, because attempts share second-precision timestamps.
+func addHeartbeatUniqueKeys(ctx context.Context, pool *pgxpool.Pool) error {
+	query := `
+		DO $$
+		BEGIN
+			IF NOT EXISTS (
+				SELECT 1 FROM pg_indexes WHERE indexname = 'idx_metrics_node_probe_timestamp_unique'
+			) THEN
+				DELETE FROM metrics a USING metrics b
+				WHERE a.id > b.id
+					AND a.node_id = b.node_id
+					AND a.probe_id = b.probe_id
+					AND a.timestamp = b.timestamp
+					AND a.is_aggregated IS NOT DISTINCT FROM b.is_aggregated;
+				CREATE UNIQUE INDEX idx_metrics_node_probe_timestamp_unique
+					ON metrics(node_id, probe_id, timestamp, is_aggregated);
+			END IF;
+
+			IF NOT EXISTS (
+				SELECT 1 FROM pg_indexes WHERE indexname = 'idx_path_traces_node_probe_timestamp_unique'
+			) THEN
+				DELETE FROM path_traces a USING path_traces b
+				WHERE a.id > b.id
+					AND a.node_id = b.node_id
+					AND a.probe_id = b.probe_id
+					AND a.timestamp = b.timestamp;
+				CREATE UNIQUE INDEX idx_path_traces_node_probe_timestamp_unique
+					ON path_traces(node_id, probe_id, timestamp);
+			END IF;
+
+			-- Samples stored before seq existed keep NULL and never conflict
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='probe_samples' AND column_name='seq'
+			) THEN
+				ALTER TABLE probe_samples ADD COLUMN seq SMALLINT;
+			END IF;
+		END $$;
+
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_probe_samples_node_probe_timestamp_seq_unique
+			ON probe_samples(node_id, probe_id, timestamp, seq);
+	`
+
+	_, err := pool.Exec(ctx, query)
+	return err
+}
+
+// createMeshMatrixTable creates mesh_matrix table holding the latest mesh
+// probe result of every node pair
+func createMeshMatrixTable(ctx context.Context, pool *pgxpool.Pool) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS mesh_matrix (
+			source_node_id UUID NOT NULL REFERENCES nodes(id) ON DELETE CASCADE,
+			target_node_id UUID NOT NULL REFERENCES nodes(id) ON DELETE CASCADE,
+			probe_type VARCHAR(32) NOT NULL,
+			success BOOLEAN,
+			latency_ms DECIMAL(10,2) NOT NULL,
+			packet_loss_rate DECIMAL(7,4) NOT NULL,
+			jitter_ms DECIMAL(10,2) NOT NULL,
+			sample_count INTEGER NOT NULL DEFAULT 0,
+			updated_at TIMESTAMPTZ NOT NULL,
+			PRIMARY KEY (source_node_id, target_node_id)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_mesh_matrix_target ON mesh_matrix(target_node_id);
+	`
+
+	_, err := pool.Exec(ctx, query)
+	return err
+}
+
+// addNodeTokenFields adds beacon API token columns to nodes table.
+// Only the SHA-256 hash of the token is stored.
+func addNodeTokenFields(ctx context.Context, pool *pgxpool.Pool) error {
+	query := `
+		DO $$
+		BEGIN
+			-- Add api_token_hash column
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='nodes' AND column_name='api_token_hash'
+			) THEN
+				ALTER TABLE nodes ADD COLUMN api_token_hash VARCHAR(64);
+			END IF;
+
+			-- Add api_token_created_at column
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='nodes' AND column_name='api_token_created_at'
+			) THEN
+				ALTER TABLE nodes ADD COLUMN api_token_created_at TIMESTAMPTZ;
+			END IF;
+
+			-- Add api_token_revoked_at column (revoked tokens are only re-issued by an admin)
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name='nodes' AND column_name='api_token_revoked_at'
+			) THEN
+				ALTER TABLE nodes ADD COLUMN api_token_revoked_at TIMESTAMPTZ;
+			END IF;
+		END $$;
+
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_nodes_api_token_hash ON nodes(api_token_hash) WHERE api_token_hash IS NOT NULL;
+	`
+
+	_, err := pool.Exec(ctx, query)
+	return err
+}
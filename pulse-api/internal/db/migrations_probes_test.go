@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -188,6 +189,238 @@ func TestCreateMetricsTable(t *testing.T) {
 	}
 }
 
+// TestCreatePathTracesTable tests path_traces table creation and path change detection
+func TestCreatePathTracesTable(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	// Run migration
+	if err := createPathTracesTable(ctx, pool); err != nil {
+		t.Fatalf("Failed to create path_traces table: %v", err)
+	}
+
+	// Verify columns exist
+	requiredColumns := []string{
+		"id", "node_id", "probe_id", "target", "timestamp",
+		"reached", "hops", "path_hash", "path_changed", "created_at",
+	}
+
+	for _, col := range requiredColumns {
+		var columnName string
+		err := pool.QueryRow(ctx, `
+			SELECT column_name
+			FROM information_schema.columns
+			WHERE table_name = 'path_traces' AND column_name = $1
+		`, col).Scan(&columnName)
+
+		if err != nil {
+			t.Errorf("Required column '%s' was not created: %v", col, err)
+		}
+	}
+
+	// Verify indexes exist
+	requiredIndexes := []string{
+		"idx_path_traces_node_probe_timestamp",
+		"idx_path_traces_changed",
+		"idx_path_traces_timestamp",
+	}
+
+	for _, idx := range requiredIndexes {
+		var indexName string
+		err := pool.QueryRow(ctx, `
+			SELECT indexname
+			FROM pg_indexes
+			WHERE indexname = $1
+		`, idx).Scan(&indexName)
+
+		if err != nil {
+			t.Errorf("Required index '%s' was not created: %v", idx, err)
+		}
+	}
+}
+
+// TestCreateProbeSamplesTable tests probe_samples table creation
+func TestCreateProbeSamplesTable(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	// Run migrations (probe_samples references nodes and probes)
+	if err := createProbesTable(ctx, pool); err != nil {
+		t.Fatalf("Failed to create probes table: %v", err)
+	}
+	if err := createProbeSamplesTable(ctx, pool); err != nil {
+		t.Fatalf("Failed to create probe_samples table: %v", err)
+	}
+
+	// Verify columns exist
+	requiredColumns := []string{
+		"node_id", "probe_id", "timestamp", "success", "rtt_ms", "error_class",
+	}
+
+	for _, col := range requiredColumns {
+		var columnName string
+		err := pool.QueryRow(ctx, `
+			SELECT column_name
+			FROM information_schema.columns
+			WHERE table_name = 'probe_samples' AND column_name = $1
+		`, col).Scan(&columnName)
+
+		if err != nil {
+			t.Errorf("Required column '%s' was not created: %v", col, err)
+		}
+	}
+
+	// Verify indexes exist
+	requiredIndexes := []string{
+		"idx_probe_samples_probe_timestamp",
+		"idx_probe_samples_timestamp",
+	}
+
+	for _, idx := range requiredIndexes {
+		var indexName string
+		err := pool.QueryRow(ctx, `
+			SELECT indexname
+			FROM pg_indexes
+			WHERE indexname = $1
+		`, idx).Scan(&indexName)
+
+		if err != nil {
+			t.Errorf("Required index '%s' was not created: %v", idx, err)
+		}
+	}
+}
+
+// TestAddHeartbeatUniqueKeys tests the unique keys that keep heartbeats
+// delivered twice from being stored twice
+func TestAddHeartbeatUniqueKeys(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	// Run migrations twice: the keys are only created once
+	for _, migrate := range []func(context.Context, *pgxpool.Pool) error{
+		createProbesTable, createMetricsTable, addMetricsProbeFields,
+		createPathTracesTable, createProbeSamplesTable,
+		addHeartbeatUniqueKeys, addHeartbeatUniqueKeys,
+	} {
+		if err := migrate(ctx, pool); err != nil {
+			t.Fatalf("Migration failed: %v", err)
+		}
+	}
+
+	// Verify the sample position column exists
+	var columnName string
+	if err := pool.QueryRow(ctx, `
+		SELECT column_name
+		FROM information_schema.columns
+		WHERE table_name = 'probe_samples' AND column_name = 'seq'
+	`).Scan(&columnName); err != nil {
+		t.Errorf("Required column 'seq' was not created: %v", err)
+	}
+
+	// Verify unique indexes exist
+	requiredIndexes := []string{
+		"idx_metrics_node_probe_timestamp_unique",
+		"idx_path_traces_node_probe_timestamp_unique",
+		"idx_probe_samples_node_probe_timestamp_seq_unique",
+	}
+
+	for _, idx := range requiredIndexes {
+		var indexDef string
+		err := pool.QueryRow(ctx, `
+			SELECT indexdef
+			FROM pg_indexes
+			WHERE indexname = $1
+		`, idx).Scan(&indexDef)
+
+		if err != nil {
+			t.Errorf("Required index '%s' was not created: %v", idx, err)
+		} else if !strings.Contains(indexDef, "UNIQUE") {
+			t.Errorf("Index '%s' is not unique: %s", idx, indexDef)
+		}
+	}
+}
+
+// TestCreateCertificateStatusTable tests certificate_status table creation
+func TestCreateCertificateStatusTable(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	// Run migrations (certificate_status references nodes)
+	if err := createNodesTable(ctx, pool); err != nil {
+		t.Fatalf("Failed to create nodes table: %v", err)
+	}
+	if err := createCertificateStatusTable(ctx, pool); err != nil {
+		t.Fatalf("Failed to create certificate_status table: %v", err)
+	}
+
+	// Verify columns exist
+	requiredColumns := []string{
+		"node_id", "probe_id", "target", "not_after",
+		"chain_valid", "tls_version", "tls_cipher", "last_seen",
+	}
+
+	for _, col := range requiredColumns {
+		var columnName string
+		err := pool.QueryRow(ctx, `
+			SELECT column_name
+			FROM information_schema.columns
+			WHERE table_name = 'certificate_status' AND column_name = $1
+		`, col).Scan(&columnName)
+
+		if err != nil {
+			t.Errorf("Required column '%s' was not created: %v", col, err)
+		}
+	}
+
+	var indexName string
+	if err := pool.QueryRow(ctx, `SELECT indexname FROM pg_indexes WHERE indexname = 'idx_certificate_status_not_after'`).Scan(&indexName); err != nil {
+		t.Errorf("Required index 'idx_certificate_status_not_after' was not created: %v", err)
+	}
+}
+
+// TestCreateMeshMatrixTable tests mesh_matrix table creation
+func TestCreateMeshMatrixTable(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	// Run migrations (mesh_matrix references nodes)
+	if err := createNodesTable(ctx, pool); err != nil {
+		t.Fatalf("Failed to create nodes table: %v", err)
+	}
+	if err := createMeshMatrixTable(ctx, pool); err != nil {
+		t.Fatalf("Failed to create mesh_matrix table: %v", err)
+	}
+
+	// Verify columns exist
+	requiredColumns := []string{
+		"source_node_id", "target_node_id", "probe_type", "success",
+		"latency_ms", "packet_loss_rate", "jitter_ms", "sample_count", "updated_at",
+	}
+
+	for _, col := range requiredColumns {
+		var columnName string
+		err := pool.QueryRow(ctx, `
+			SELECT column_name
+			FROM information_schema.columns
+			WHERE table_name = 'mesh_matrix' AND column_name = $1
+		`, col).Scan(&columnName)
+
+		if err != nil {
+			t.Errorf("Required column '%s' was not created: %v", col, err)
+		}
+	}
+
+	var indexName string
+	if err := pool.QueryRow(ctx, `SELECT indexname FROM pg_indexes WHERE indexname = 'idx_mesh_matrix_target'`).Scan(&indexName); err != nil {
+		t.Errorf("Required index 'idx_mesh_matrix_target' was not created: %v", err)
+	}
+}
+
 // setupTestDB creates a test database connection pool
 func setupTestDB(t *testing.T) *pgxpool.Pool {
 	ctx := context.Background()
@@ -201,6 +434,9 @@ func setupTestDB(t *testing.T) *pgxpool.Pool {
 	}
 
 	// Clean up any existing probes/metrics tables from previous tests
+	pool.Exec(ctx, "DROP TABLE IF EXISTS mesh_matrix CASCADE")
+	pool.Exec(ctx, "DROP TABLE IF EXISTS probe_samples CASCADE")
+	pool.Exec(ctx, "DROP TABLE IF EXISTS path_traces CASCADE")
 	pool.Exec(ctx, "DROP TABLE IF EXISTS metrics CASCADE")
 	pool.Exec(ctx, "DROP TABLE IF EXISTS probes CASCADE")
 	pool.Exec(ctx, "DROP TABLE IF EXISTS nodes CASCADE")
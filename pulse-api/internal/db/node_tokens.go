@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NodeTokenState is the beacon API token state of a node
+type NodeTokenState struct {
+	Hash    string // SHA-256 hash of the current token, "" if none is active
+	Revoked bool   // Token revoked by an admin; only rotation issues a new one
+}
+
+// NodeTokensQuerier defines interface for beacon API token operations
+type NodeTokensQuerier interface {
+	SetNodeToken(ctx context.Context, nodeID uuid.UUID, tokenHash string) error
+	GetNodeTokenState(ctx context.Context, nodeID uuid.UUID) (NodeTokenState, error)
+	GetNodeIDByTokenHash(ctx context.Context, tokenHash string) (uuid.UUID, error)
+	RevokeNodeToken(ctx context.Context, nodeID uuid.UUID) error
+}
+
+// SetNodeToken stores a new token hash for a node, replacing any previous
+// token and clearing a revocation
+func SetNodeToken(ctx context.Context, pool *pgxpool.Pool, nodeID uuid.UUID, tokenHash string) error {
+	query := `
+		UPDATE nodes
+		SET api_token_hash = $2, api_token_created_at = NOW(), api_token_revoked_at = NULL
+		WHERE id = $1
+	`
+
+	result, err := pool.Exec(ctx, query, nodeID, tokenHash)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNodeNotFound
+	}
+
+	return nil
+}
+
+// GetNodeTokenState returns the token state of a node. A node without an
+// active token was either revoked or created before tokens existed.
+func GetNodeTokenState(ctx context.Context, pool *pgxpool.Pool, nodeID uuid.UUID) (NodeTokenState, error) {
+	query := `
+		SELECT COALESCE(api_token_hash, ''), api_token_revoked_at IS NOT NULL
+		FROM nodes
+		WHERE id = $1
+	`
+
+	var state NodeTokenState
+	err := pool.QueryRow(ctx, query, nodeID).Scan(&state.Hash, &state.Revoked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return NodeTokenState{}, ErrNodeNotFound
+		}
+		return NodeTokenState{}, err
+	}
+
+	return state, nil
+}
+
+// GetNodeIDByTokenHash resolves a token hash to the node it was issued for
+func GetNodeIDByTokenHash(ctx context.Context, pool *pgxpool.Pool, tokenHash string) (uuid.UUID, error) {
+	query := `
+		SELECT id
+		FROM nodes
+		WHERE api_token_hash = $1
+	`
+
+	var nodeID uuid.UUID
+	err := pool.QueryRow(ctx, query, tokenHash).Scan(&nodeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrNodeNotFound
+		}
+		return uuid.Nil, err
+	}
+
+	return nodeID, nil
+}
+
+// RevokeNodeToken removes the token of a node and records the revocation; the
+// beacon is rejected until an admin issues a new token
+func RevokeNodeToken(ctx context.Context, pool *pgxpool.Pool, nodeID uuid.UUID) error {
+	query := `
+		UPDATE nodes
+		SET api_token_hash = NULL, api_token_created_at = NULL, api_token_revoked_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := pool.Exec(ctx, query, nodeID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNodeNotFound
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -89,3 +90,38 @@ func (p *PoolQuerier) UpdateProbe(ctx context.Context, probeID uuid.UUID, update
 func (p *PoolQuerier) DeleteProbe(ctx context.Context, probeID uuid.UUID) error {
 	return DeleteProbe(ctx, p.pool, probeID)
 }
+
+// SetNodeToken implements NodeTokensQuerier
+func (p *PoolQuerier) SetNodeToken(ctx context.Context, nodeID uuid.UUID, tokenHash string) error {
+	return SetNodeToken(ctx, p.pool, nodeID, tokenHash)
+}
+
+// GetNodeTokenState implements NodeTokensQuerier
+func (p *PoolQuerier) GetNodeTokenState(ctx context.Context, nodeID uuid.UUID) (NodeTokenState, error) {
+	return GetNodeTokenState(ctx, p.pool, nodeID)
+}
+
+// GetNodeIDByTokenHash implements NodeTokensQuerier
+func (p *PoolQuerier) GetNodeIDByTokenHash(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	return GetNodeIDByTokenHash(ctx, p.pool, tokenHash)
+}
+
+// RevokeNodeToken implements NodeTokensQuerier
+func (p *PoolQuerier) RevokeNodeToken(ctx context.Context, nodeID uuid.UUID) error {
+	return RevokeNodeToken(ctx, p.pool, nodeID)
+}
+
+// GetPathTraces implements PathTracesQuerier
+func (p *PoolQuerier) GetPathTraces(ctx context.Context, nodeID uuid.UUID, probeID string, changesOnly bool, limit int) ([]*models.PathTrace, error) {
+	return GetPathTraces(ctx, p.pool, nodeID, probeID, changesOnly, limit)
+}
+
+// GetExpiringCertificates implements CertificatesQuerier
+func (p *PoolQuerier) GetExpiringCertificates(ctx context.Context, before time.Time) ([]*models.CertificateStatus, error) {
+	return GetExpiringCertificates(ctx, p.pool, before)
+}
+
+// GetMeshLinks implements MeshQuerier
+func (p *PoolQuerier) GetMeshLinks(ctx context.Context) ([]*models.MeshLink, error) {
+	return GetMeshLinks(ctx, p.pool)
+}
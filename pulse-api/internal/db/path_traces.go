@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kevin/node-pulse/pulse-api/internal/models"
+)
+
+// PathTracesQuerier defines interface for path trace database operations
+type PathTracesQuerier interface {
+	GetPathTraces(ctx context.Context, nodeID uuid.UUID, probeID string, changesOnly bool, limit int) ([]*models.PathTrace, error)
+}
+
+// GetPathTraces retrieves the most recent path traces of a node, newest
+// first, optionally filtered by probe and to traces whose path changed
+func GetPathTraces(ctx context.Context, pool *pgxpool.Pool, nodeID uuid.UUID, probeID string, changesOnly bool, limit int) ([]*models.PathTrace, error) {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	query := `
+		SELECT id, node_id, probe_id, target, timestamp, reached, hops, path_hash, path_changed
+		FROM path_traces
+		WHERE node_id = $1
+			AND ($2 = '' OR probe_id = $2)
+			AND (NOT $3 OR path_changed)
+		ORDER BY timestamp DESC, id DESC
+		LIMIT $4
+	`
+
+	rows, err := conn.Query(ctx, query, nodeID, probeID, changesOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	traces := make([]*models.PathTrace, 0)
+	for rows.Next() {
+		var trace models.PathTrace
+		err := rows.Scan(&trace.ID, &trace.NodeID, &trace.ProbeID, &trace.Target, &trace.Timestamp, &trace.Reached, &trace.Hops, &trace.PathHash, &trace.PathChanged)
+		if err != nil {
+			return nil, err
+		}
+		traces = append(traces, &trace)
+	}
+
+	return traces, rows.Err()
+}
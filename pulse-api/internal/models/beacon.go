@@ -2,14 +2,73 @@ package models
 
 import "time"
 
-// HeartbeatRequest represents beacon heartbeat data request
+// HeartbeatRequest represents beacon heartbeat data request.
+// Each request carries the result of a single probe; the probe identity and
+// extended statistics fields are optional for older beacons.
+// Metric fields are not marked required because 0 is a valid measurement.
 type HeartbeatRequest struct {
 	NodeID          string  `json:"node_id" binding:"required"`
 	ProbeID         string  `json:"probe_id" binding:"required"`
-	LatencyMs       float64 `json:"latency_ms" binding:"required"`
-	PacketLossRate  float64 `json:"packet_loss_rate" binding:"required"`
-	JitterMs        float64 `json:"jitter_ms" binding:"required"`
+	ProbeType       string  `json:"probe_type,omitempty"`        // tcp_ping, udp_ping, icmp_ping, http_probe, dns_probe, tls_probe or path_probe
+	Target          string  `json:"target,omitempty"`            // Probe target host (URL for http_probe)
+	Port            int     `json:"port,omitempty"`              // Probe target port
+	Success         *bool   `json:"success,omitempty"`           // Probe success status
+	LatencyMs       float64 `json:"latency_ms"`
+	LatencyMedianMs float64 `json:"latency_median_ms,omitempty"` // RTT median
+	VarianceMs      float64 `json:"variance_ms,omitempty"`       // RTT variance (ms²)
+	PacketLossRate  float64 `json:"packet_loss_rate"`
+	JitterMs        float64 `json:"jitter_ms"`
+	SampleCount     int     `json:"sample_count,omitempty"`      // Number of samples
+	Partial         bool    `json:"partial,omitempty"`           // Batch aborted early (beacon stop, reload or run deadline)
 	Timestamp       string  `json:"timestamp" binding:"required"`
+
+	// RTT statistics selected in the beacon config (optional)
+	LatencyStatistics
+
+	// Request phase timing and response status reported by http_probe (optional)
+	DNSMs          *float64 `json:"dns_ms,omitempty"`           // Mean DNS lookup time
+	ConnectMs      *float64 `json:"connect_ms,omitempty"`       // Mean TCP connect time
+	TLSHandshakeMs *float64 `json:"tls_handshake_ms,omitempty"` // Mean TLS handshake time (0 for plain HTTP)
+	TTFBMs         *float64 `json:"ttfb_ms,omitempty"`          // Mean time to first response byte
+	TotalMs        *float64 `json:"total_ms,omitempty"`         // Mean total request time
+	StatusCode     int      `json:"status_code,omitempty"`      // Last received status code
+
+	// Last response reported by dns_probe (optional)
+	Rcode        *int  `json:"rcode,omitempty"`         // Response code, -1 if no response was received
+	AnswerCount  *int  `json:"answer_count,omitempty"`  // Answers of the queried type
+	AnswersMatch *bool `json:"answers_match,omitempty"` // Answers matched the expected set
+
+	// Certificate status reported by tls_probe (optional)
+	TLSVersion        string   `json:"tls_version,omitempty"`         // Negotiated version, e.g. "TLS 1.3"
+	TLSCipher         string   `json:"tls_cipher,omitempty"`          // Negotiated cipher suite
+	CertChainValid    *bool    `json:"cert_chain_valid,omitempty"`    // Chain verified by the beacon
+	CertNotAfter      string   `json:"cert_not_after,omitempty"`      // Leaf expiry (RFC 3339)
+	CertDaysRemaining *float64 `json:"cert_days_remaining,omitempty"` // Days until leaf expiry, negative once expired
+
+	// Hop list reported by path_probe (optional)
+	Hops []PathHop `json:"hops,omitempty"`
+
+	// Individual attempts of the probe batch (optional, opt-in on the beacon)
+	Samples []ProbeSample `json:"samples,omitempty"`
+
+	// Echo measurements reported by udp_ping against a beacon responder (optional)
+	ReorderedPackets int      `json:"reordered_packets,omitempty"`  // Replies arriving out of order
+	DuplicatePackets int      `json:"duplicate_packets,omitempty"`  // Duplicated replies
+	OneWayForwardMs  *float64 `json:"one_way_forward_ms,omitempty"` // Mean prober→responder delay
+	OneWayReverseMs  *float64 `json:"one_way_reverse_ms,omitempty"` // Mean responder→prober delay
+}
+
+// LatencyStatistics are the optional RTT distribution statistics of a
+// heartbeat, in milliseconds. Beacons only send those selected in their
+// `statistics` config setting.
+type LatencyStatistics struct {
+	LatencyMinMs    *float64 `json:"latency_min_ms,omitempty"`    // Lowest RTT
+	LatencyMaxMs    *float64 `json:"latency_max_ms,omitempty"`    // Highest RTT
+	LatencyStdDevMs *float64 `json:"latency_stddev_ms,omitempty"` // RTT standard deviation
+	LatencyP90Ms    *float64 `json:"latency_p90_ms,omitempty"`    // 90th percentile RTT
+	LatencyP95Ms    *float64 `json:"latency_p95_ms,omitempty"`    // 95th percentile RTT
+	LatencyP99Ms    *float64 `json:"latency_p99_ms,omitempty"`    // 99th percentile RTT
+	JitterRFC3550Ms *float64 `json:"jitter_rfc3550_ms,omitempty"` // RFC 3550 interarrival jitter
 }
 
 // HeartbeatSuccessResponse represents successful heartbeat response
@@ -23,5 +82,47 @@ type HeartbeatSuccessResponse struct {
 type HeartbeatData struct {
 	Received  bool      `json:"received"`
 	NodeID    string    `json:"node_id"`
+	ProbeID   string    `json:"probe_id,omitempty"`
 	Timestamp time.Time `json:"timestamp"`
 }
+
+// HeartbeatBatchRequest represents a batch of heartbeats from one beacon.
+// Items may omit node_id, in which case the batch node_id is used.
+type HeartbeatBatchRequest struct {
+	NodeID     string             `json:"node_id" binding:"required"`
+	Heartbeats []HeartbeatRequest `json:"heartbeats" binding:"required,min=1"`
+}
+
+// HeartbeatBatchResponse represents batch heartbeat response
+type HeartbeatBatchResponse struct {
+	Data      HeartbeatBatchData `json:"data"`
+	Message   string             `json:"message"`
+	Timestamp string             `json:"timestamp"`
+}
+
+// HeartbeatBatchData represents batch heartbeat response data
+type HeartbeatBatchData struct {
+	NodeID    string                `json:"node_id"`
+	Accepted  int                   `json:"accepted"`
+	Rejected  int                   `json:"rejected"`
+	Results   []HeartbeatItemResult `json:"results"`
+	Timestamp time.Time             `json:"timestamp"`
+}
+
+// HeartbeatItemResult represents accept/reject status of one batch item
+type HeartbeatItemResult struct {
+	Index    int            `json:"index"`
+	ProbeID  string         `json:"probe_id"`
+	Accepted bool           `json:"accepted"`
+	Error    *ErrorResponse `json:"error,omitempty"`
+}
+
+// BeaconRegisterRequest represents beacon self-registration request.
+// NodeID is the ID the beacon already holds (from config or state file), if any.
+type BeaconRegisterRequest struct {
+	NodeID   string   `json:"node_id,omitempty"`
+	NodeName string   `json:"node_name" binding:"required,max=255"`
+	IP       string   `json:"ip" binding:"required,max=45"`
+	Region   string   `json:"region" binding:"max=100"`
+	Tags     []string `json:"tags,omitempty"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// CertificateStatus represents the latest certificate seen by a tls_probe
+type CertificateStatus struct {
+	NodeID        string    `json:"node_id"`
+	ProbeID       string    `json:"probe_id"`
+	Target        string    `json:"target,omitempty"`
+	NotAfter      time.Time `json:"not_after"`
+	DaysRemaining float64   `json:"days_remaining"` // Negative once expired
+	ChainValid    *bool     `json:"chain_valid,omitempty"`
+	TLSVersion    string    `json:"tls_version,omitempty"`
+	TLSCipher     string    `json:"tls_cipher,omitempty"`
+	LastSeen      time.Time `json:"last_seen"`
+}
+
+// ExpiringCertificatesResponse represents expiring certificates list response
+type ExpiringCertificatesResponse struct {
+	Data      ExpiringCertificatesData `json:"data"`
+	Message   string                   `json:"message"`
+	Timestamp string                   `json:"timestamp"`
+}
+
+// ExpiringCertificatesData represents certificates expiring within a window
+type ExpiringCertificatesData struct {
+	WithinDays   int                  `json:"within_days"`
+	Certificates []*CertificateStatus `json:"certificates"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// MeshProbeIDPrefix prefixes the probe ID of mesh probes; the rest of the ID
+// is the probed peer's node ID
+const MeshProbeIDPrefix = "mesh:"
+
+// MeshLink represents the latest mesh probe result from one node to another
+type MeshLink struct {
+	SourceNodeID   string    `json:"source_node_id"`
+	TargetNodeID   string    `json:"target_node_id"`
+	ProbeType      string    `json:"probe_type"` // tcp_ping or udp_ping
+	Success        *bool     `json:"success,omitempty"`
+	LatencyMs      float64   `json:"latency_ms"`
+	PacketLossRate float64   `json:"packet_loss_rate"`
+	JitterMs       float64   `json:"jitter_ms"`
+	SampleCount    int       `json:"sample_count"`
+	UpdatedAt      time.Time `json:"updated_at"` // Heartbeat timestamp of the result
+}
+
+// MeshNode represents a node in the mesh matrix
+type MeshNode struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	IP     string `json:"ip"`
+	Region string `json:"region"`
+}
+
+// MeshMatrixData represents the latest latency/loss grid between nodes.
+// Links only exist for node pairs that have reported a result.
+type MeshMatrixData struct {
+	Nodes []*MeshNode `json:"nodes"`
+	Links []*MeshLink `json:"links"`
+}
+
+// MeshMatrixResponse represents mesh matrix response
+type MeshMatrixResponse struct {
+	Data      MeshMatrixData `json:"data"`
+	Message   string         `json:"message"`
+	Timestamp string         `json:"timestamp"`
+}
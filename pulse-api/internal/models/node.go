@@ -43,6 +43,7 @@ type CreateNodeData struct {
 	Tags      string    `json:"tags,omitempty"`           // JSONB stored as string
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	APIToken  string    `json:"api_token,omitempty"` // Beacon API token, only returned when issued
 }
 
 // CreateNodeResponse represents successful node creation response
@@ -111,3 +112,18 @@ type GetNodeStatusResponse struct {
 	Message string       `json:"message"`
 	Timestamp string     `json:"timestamp"`
 }
+
+
+// NodeTokenData represents a newly issued beacon API token
+type NodeTokenData struct {
+	NodeID    string    `json:"node_id"`
+	APIToken  string    `json:"api_token"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NodeTokenResponse represents successful token rotation response
+type NodeTokenResponse struct {
+	Data      NodeTokenData `json:"data"`
+	Message   string        `json:"message"`
+	Timestamp string        `json:"timestamp"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// MaxPathHops bounds the hop list of a path_probe heartbeat
+const MaxPathHops = 64
+
+// PathHop represents one hop of a path_probe trace
+type PathHop struct {
+	TTL            int     `json:"ttl"`               // Hop number (IP TTL / hop limit)
+	Address        string  `json:"address,omitempty"` // Responding router, empty if no reply
+	Sent           int     `json:"sent"`
+	Received       int     `json:"received"`
+	PacketLossRate float64 `json:"packet_loss_rate"` // 0-100%
+	RTTMs          float64 `json:"rtt_ms"`           // Mean RTT
+	BestMs         float64 `json:"best_ms"`
+	WorstMs        float64 `json:"worst_ms"`
+}
+
+// PathTrace represents a stored path_probe trace
+type PathTrace struct {
+	ID          int64     `json:"id"`
+	NodeID      string    `json:"node_id"`
+	ProbeID     string    `json:"probe_id"`
+	Target      string    `json:"target"`
+	Timestamp   time.Time `json:"timestamp"`
+	Reached     bool      `json:"reached"`
+	Hops        []PathHop `json:"hops"`
+	PathHash    string    `json:"path_hash"`    // Hash of the hop address sequence
+	PathChanged bool      `json:"path_changed"` // Differs from the previous trace of the probe
+}
+
+// PathTracesResponse represents path trace list response
+type PathTracesResponse struct {
+	Data      []*PathTrace `json:"data"`
+	Message   string       `json:"message"`
+	Timestamp string       `json:"timestamp"`
+}
@@ -10,7 +10,7 @@ type Probe struct {
 	Target          string    `json:"target" db:"target"`                       // IP or domain
 	Port            int       `json:"port" db:"port"`                           // 1-65535
 	IntervalSeconds int       `json:"interval_seconds" db:"interval_seconds"`   // 60-300
-	Count           int       `json:"count" db:"count"`                         // 1-100
+	Count           int       `json:"count" db:"count"`                         // 10-100
 	TimeoutSeconds  int       `json:"timeout_seconds" db:"timeout_seconds"`     // 1-30
 	CreatedAt       time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
@@ -23,7 +23,7 @@ type CreateProbeRequest struct {
 	Target          string `json:"target" binding:"required"`
 	Port            int    `json:"port" binding:"required,min=1,max=65535"`
 	IntervalSeconds int    `json:"interval_seconds" binding:"required,min=60,max=300"`
-	Count           int    `json:"count" binding:"required,min=1,max=100"`
+	Count           int    `json:"count" binding:"required,min=10,max=100"` // Beacon ping probes need ≥10 samples
 	TimeoutSeconds  int    `json:"timeout_seconds" binding:"required,min=1,max=30"`
 }
 
@@ -33,7 +33,7 @@ type UpdateProbeRequest struct {
 	Target          *string `json:"target,omitempty"`
 	Port            *int    `json:"port,omitempty" binding:"omitempty,min=1,max=65535"`
 	IntervalSeconds *int    `json:"interval_seconds,omitempty" binding:"omitempty,min=60,max=300"`
-	Count           *int    `json:"count,omitempty" binding:"omitempty,min=1,max=100"`
+	Count           *int    `json:"count,omitempty" binding:"omitempty,min=10,max=100"`
 	TimeoutSeconds  *int    `json:"timeout_seconds,omitempty" binding:"omitempty,min=1,max=30"`
 }
 
@@ -80,3 +80,29 @@ type DeleteProbeResponse struct {
 	Message   string `json:"message"`
 	Timestamp string `json:"timestamp"`
 }
+
+// BeaconProbeConfig represents a probe as consumed by beacons
+type BeaconProbeConfig struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"` // tcp_ping or udp_ping
+	Target          string `json:"target"`
+	Port            int    `json:"port"`
+	IntervalSeconds int    `json:"interval"`
+	Count           int    `json:"count"`
+	TimeoutSeconds  int    `json:"timeout_seconds"`
+	Responder       bool   `json:"responder,omitempty"` // udp_ping against a beacon responder (mesh probes)
+}
+
+// BeaconProbeConfigResponse represents probe configuration sync response
+type BeaconProbeConfigResponse struct {
+	Data      BeaconProbeConfigData `json:"data"`
+	Message   string                `json:"message"`
+	Timestamp string                `json:"timestamp"`
+}
+
+// BeaconProbeConfigData represents versioned probe configuration of a node
+type BeaconProbeConfigData struct {
+	NodeID  string              `json:"node_id"`
+	Version string              `json:"version"`
+	Probes  []BeaconProbeConfig `json:"probes"`
+}
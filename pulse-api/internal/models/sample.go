@@ -0,0 +1,17 @@
+package models
+
+// MaxProbeSamples bounds the raw samples of a heartbeat (a beacon probe
+// batch has at most 100 attempts)
+const MaxProbeSamples = 100
+
+// SampleErrorClasses lists the error classes beacons report for failed samples
+var SampleErrorClasses = []string{"timeout", "refused", "reset", "unreachable", "dns", "tls", "response", "other"}
+
+// ProbeSample is one attempt of a probe batch, reported by beacons with
+// `report_samples` enabled
+type ProbeSample struct {
+	Timestamp  string  `json:"timestamp"`             // Attempt time (ISO 8601)
+	Success    bool    `json:"success"`               // Attempt got a valid answer
+	RTTMs      float64 `json:"rtt_ms"`                // Round-trip time, 0 if failed
+	ErrorClass string  `json:"error_class,omitempty"` // One of SampleErrorClasses if failed
+}
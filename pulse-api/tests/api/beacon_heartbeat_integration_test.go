@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -17,6 +18,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/kevin/node-pulse/pulse-api/internal/api"
+	"github.com/kevin/node-pulse/pulse-api/internal/auth"
 	"github.com/kevin/node-pulse/pulse-api/internal/cache"
 	"github.com/kevin/node-pulse/pulse-api/internal/db"
 	"github.com/kevin/node-pulse/pulse-api/internal/models"
@@ -32,12 +34,27 @@ func setupIntegrationTestRouter(pool *pgxpool.Pool) *gin.Engine {
 	batchWriter := cache.NewBatchWriter(pool, 1000, 100)
 	batchWriter.Start()
 
-	beaconHandler := api.NewBeaconHandler(db.NewPoolQuerier(pool), memoryCache, batchWriter)
+	beaconHandler := api.NewBeaconHandler(db.NewPoolQuerier(pool), db.NewPoolQuerier(pool), memoryCache, batchWriter)
+	router.Use(authenticateRequestNode())
 	router.POST("/api/v1/beacon/heartbeat", beaconHandler.HandleHeartbeat)
 
 	return router
 }
 
+// authenticateRequestNode stands in for auth.BeaconAuthMiddleware: it
+// authenticates the request as the node_id of its JSON body
+func authenticateRequestNode() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		var req struct {
+			NodeID string `json:"node_id"`
+		}
+		_ = json.Unmarshal(body, &req)
+		c.Set(auth.BeaconNodeIDKey, req.NodeID)
+	}
+}
+
 // createTestNode creates a test node in the database
 func createTestNode(t *testing.T, ctx context.Context, pool *pgxpool.Pool) uuid.UUID {
 	t.Helper()
@@ -39,7 +39,7 @@ func setupTestRouter(t *testing.T) (*gin.Engine, *pgxpool.Pool, *api.CacheManage
 
 	router := gin.New()
 	healthChecker := health.New(nil, nil) // No scheduler in tests
-	cacheManager := api.SetupRoutes(router, healthChecker, pool)
+	cacheManager := api.SetupRoutes(router, healthChecker, pool, nil, nil, nil)
 
 	// Defer cache cleanup for test cleanup
 	t.Cleanup(func() {
@@ -0,0 +1,139 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kevin/node-pulse/pulse-api/internal/cache"
+	"github.com/kevin/node-pulse/pulse-api/internal/db"
+	"github.com/kevin/node-pulse/pulse-api/internal/testutil"
+)
+
+// TestBatchWriter_DeletedProbe_Integration tests that records of a probe deleted
+// in Pulse are dropped without failing the other records of the batch
+func TestBatchWriter_DeletedProbe_Integration(t *testing.T) {
+	pool, err := pgxpool.New(context.Background(), testutil.GetTestDBURL())
+	if err != nil {
+		t.Skip("No database connection")
+		return
+	}
+	defer pool.Close()
+
+	ctx := context.Background()
+	if err := pool.Ping(ctx); err != nil {
+		t.Skipf("Database not ready: %v", err)
+		return
+	}
+
+	// Arrange - one existing probe, one that was deleted meanwhile
+	testNodeID := uuid.New()
+	testProbeID := uuid.New()
+	deletedProbeID := uuid.New()
+
+	_, err = pool.Exec(ctx, `
+		INSERT INTO nodes (id, name, ip, region, tags, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+	`, testNodeID, "batch-writer-test-node", "192.168.1.101", "us-east", "{}")
+	require.NoError(t, err)
+	defer pool.Exec(ctx, "DELETE FROM nodes WHERE id = $1", testNodeID)
+
+	_, err = pool.Exec(ctx, `
+		INSERT INTO probes (id, node_id, type, target, port, interval_seconds, count, timeout_seconds, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+	`, testProbeID, testNodeID, "TCP", "example.com", 80, 60, 5, 5)
+	require.NoError(t, err)
+
+	writer := cache.NewBatchWriter(pool, 10, 10)
+	writer.Start()
+
+	// Act
+	for _, probeID := range []uuid.UUID{deletedProbeID, testProbeID} {
+		require.NoError(t, writer.Write(&cache.MetricRecord{
+			NodeID:    testNodeID.String(),
+			ProbeID:   probeID.String(),
+			Timestamp: time.Now(),
+			LatencyMs: 12.5,
+		}))
+	}
+	writer.Stop()
+
+	// Assert - the existing probe's record was stored, the other one dropped
+	var stored, dropped int
+	require.NoError(t, pool.QueryRow(ctx, "SELECT COUNT(*) FROM metrics WHERE probe_id = $1", testProbeID).Scan(&stored))
+	require.NoError(t, pool.QueryRow(ctx, "SELECT COUNT(*) FROM metrics WHERE probe_id = $1", deletedProbeID).Scan(&dropped))
+	assert.Equal(t, 1, stored, "Record of the existing probe should be stored")
+	assert.Equal(t, 0, dropped, "Record of the deleted probe should be dropped")
+}
+
+// TestBatchWriter_Certificate_Integration tests that the latest certificate of
+// a tls_probe configured only in the beacon is stored and listed as expiring
+func TestBatchWriter_Certificate_Integration(t *testing.T) {
+	pool, err := pgxpool.New(context.Background(), testutil.GetTestDBURL())
+	if err != nil {
+		t.Skip("No database connection")
+		return
+	}
+	defer pool.Close()
+
+	ctx := context.Background()
+	if err := pool.Ping(ctx); err != nil {
+		t.Skipf("Database not ready: %v", err)
+		return
+	}
+
+	// Arrange
+	testNodeID := uuid.New()
+	_, err = pool.Exec(ctx, `
+		INSERT INTO nodes (id, name, ip, region, tags, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+	`, testNodeID, "certificate-test-node", "192.168.1.102", "us-east", "{}")
+	require.NoError(t, err)
+	defer pool.Exec(ctx, "DELETE FROM nodes WHERE id = $1", testNodeID)
+
+	now := time.Now().Truncate(time.Second)
+	probeID := "tls_probe:certificate-test.example.com:443"
+	renewed := now.Add(90 * 24 * time.Hour)
+	expiring := now.Add(5 * 24 * time.Hour)
+
+	writer := cache.NewBatchWriter(pool, 10, 10)
+	writer.Start()
+
+	// Act - the later heartbeat carries the renewed certificate, the older
+	// one arrives last and must not replace it
+	for _, record := range []struct {
+		seen     time.Time
+		notAfter time.Time
+	}{{now, renewed}, {now.Add(-time.Minute), expiring}} {
+		require.NoError(t, writer.Write(&cache.MetricRecord{
+			NodeID:       testNodeID.String(),
+			ProbeID:      probeID,
+			Timestamp:    record.seen,
+			Target:       "certificate-test.example.com",
+			Certificate:  &cache.CertificateInfo{NotAfter: record.notAfter, TLSVersion: "TLS 1.3"},
+			Unregistered: true,
+		}))
+	}
+	writer.Stop()
+
+	// Assert
+	var notAfter time.Time
+	require.NoError(t, pool.QueryRow(ctx, "SELECT not_after FROM certificate_status WHERE node_id = $1 AND probe_id = $2", testNodeID, probeID).Scan(&notAfter))
+	assert.True(t, renewed.Equal(notAfter), "Latest certificate should be kept, got %s", notAfter)
+
+	certificates, err := db.GetExpiringCertificates(ctx, pool, now.Add(100*24*time.Hour))
+	require.NoError(t, err)
+	found := false
+	for _, certificate := range certificates {
+		if certificate.NodeID == testNodeID.String() && certificate.ProbeID == probeID {
+			found = true
+			assert.Equal(t, "TLS 1.3", certificate.TLSVersion)
+		}
+	}
+	assert.True(t, found, "Stored certificate should be listed as expiring")
+}
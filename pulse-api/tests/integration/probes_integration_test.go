@@ -77,7 +77,7 @@ func TestCreateProbe_Integration(t *testing.T) {
 			Target:          "example.com",
 			Port:            80,
 			IntervalSeconds: 60,
-			Count:           5,
+			Count:           10,
 			TimeoutSeconds:  10,
 		}
 		reqBody, _ := json.Marshal(createProbeReq)
@@ -101,7 +101,7 @@ func TestCreateProbe_Integration(t *testing.T) {
 		assert.Equal(t, "example.com", resp.Data.Probe.Target)
 		assert.Equal(t, 80, resp.Data.Probe.Port)
 		assert.Equal(t, 60, resp.Data.Probe.IntervalSeconds)
-		assert.Equal(t, 5, resp.Data.Probe.Count)
+		assert.Equal(t, 10, resp.Data.Probe.Count)
 		assert.Equal(t, 10, resp.Data.Probe.TimeoutSeconds)
 		assert.NotEmpty(t, resp.Data.Probe.ID)
 		assert.NotEmpty(t, resp.Timestamp)
@@ -145,7 +145,7 @@ func TestCreateProbe_Integration(t *testing.T) {
 			"target":           "example.com",
 			"port":             80,
 			"interval_seconds": 60,
-			"count":            5,
+			"count":            10,
 			"timeout_seconds":  10,
 		}
 		reqBody, _ := json.Marshal(createProbeReq)
@@ -174,7 +174,7 @@ func TestCreateProbe_Integration(t *testing.T) {
 			Target:          "example.com",
 			Port:            80,
 			IntervalSeconds: 60,
-			Count:           5,
+			Count:           10,
 			TimeoutSeconds:  10,
 		}
 		reqBody, _ := json.Marshal(createProbeReq)
@@ -496,7 +496,7 @@ func TestProbeConstraints_Integration(t *testing.T) {
 			Target:          "example.com",
 			Port:            80,
 			IntervalSeconds: 30, // Below minimum 60
-			Count:           5,
+			Count:           10,
 			TimeoutSeconds:  10,
 		}
 		reqBody, _ := json.Marshal(createProbeReq)
@@ -519,7 +519,7 @@ func TestProbeConstraints_Integration(t *testing.T) {
 			Target:          "example.com",
 			Port:            80,
 			IntervalSeconds: 400, // Above maximum 300
-			Count:           5,
+			Count:           10,
 			TimeoutSeconds:  10,
 		}
 		reqBody, _ := json.Marshal(createProbeReq)
@@ -565,7 +565,7 @@ func TestProbeConstraints_Integration(t *testing.T) {
 			Target:          "example.com",
 			Port:            80,
 			IntervalSeconds: 60,
-			Count:           5,
+			Count:           10,
 			TimeoutSeconds:  60, // Above maximum 30
 		}
 		reqBody, _ := json.Marshal(createProbeReq)
@@ -588,7 +588,7 @@ func TestProbeConstraints_Integration(t *testing.T) {
 			Target:          "example.com",
 			Port:            70000, // Above maximum 65535
 			IntervalSeconds: 60,
-			Count:           5,
+			Count:           10,
 			TimeoutSeconds:  10,
 		}
 		reqBody, _ := json.Marshal(createProbeReq)